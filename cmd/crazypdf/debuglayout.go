@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/export"
+)
+
+func runDebugLayoutCommand(args []string) {
+	fs := flag.NewFlagSet("debug-layout", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Draw the layout analysis of a page as SVG.
+
+Word boxes, lines, typed blocks, detected columns and tables are drawn
+over the page text and graphics, one SVG group per layer.
+
+Usage:
+  crazypdf debug-layout [options] <input.pdf> [output.svg]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf debug-layout -page 3 document.pdf page3.svg
+  crazypdf debug-layout -scale 2 document.pdf > page1.svg
+`)
+	}
+
+	pageNum := fs.Int("page", 1, "Page to draw (1-based)")
+	scale := fs.Float64("scale", 2, "Output size relative to the page size in points")
+	password := fs.String("password", "", "Password for encrypted PDF")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) < 1 || len(remaining) > 2 {
+		fmt.Fprintln(os.Stderr, "Error: input PDF file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	inputFile := remaining[0]
+
+	doc, err := openDocument(inputFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	if *pageNum < 1 || *pageNum > doc.NumPages() {
+		fmt.Fprintf(os.Stderr, "Error: page %d out of range (document has %d pages)\n", *pageNum, doc.NumPages())
+		os.Exit(1)
+	}
+	page, err := doc.Page(*pageNum - 1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error accessing page %d: %v\n", *pageNum, err)
+		os.Exit(1)
+	}
+
+	write := func(w io.Writer) error {
+		return export.LayoutSVG(page, w, export.WithDPI(*scale*72))
+	}
+	if len(remaining) == 1 {
+		err = write(os.Stdout)
+	} else {
+		_, err = crazypdf.WriteFile(remaining[1], write)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error drawing layout: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ayushanand18/crazypdf/pkg/diff"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+func runDiffCommand(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Compare the text of two PDF files page by page.
+
+Usage:
+  crazypdf diff [options] <old.pdf> <new.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf diff old.pdf new.pdf
+  crazypdf diff -context 0 old.pdf new.pdf
+  crazypdf diff -normalize old.pdf new.pdf
+  crazypdf diff -json old.pdf new.pdf > changes.json
+`)
+	}
+
+	jsonOut := fs.Bool("json", false, "Output the comparison as JSON")
+	context := fs.Int("context", 3, "Lines of unchanged context around each change")
+	layout := fs.Bool("layout", false, "Compare physical layout text instead of simple text")
+	normalize := fs.Bool("normalize", false, "Ignore whitespace, ligature, quote and text order differences")
+	password := fs.String("password", "", "Password for encrypted PDFs (applied to both files)")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) != 2 {
+		fmt.Fprintln(os.Stderr, "Error: two input PDF files are required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	oldFile, newFile := remaining[0], remaining[1]
+
+	oldDoc, err := openDocument(oldFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening %s: %v\n", oldFile, err)
+		os.Exit(1)
+	}
+	defer oldDoc.Close()
+
+	newDoc, err := openDocument(newFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening %s: %v\n", newFile, err)
+		os.Exit(1)
+	}
+	defer newDoc.Close()
+
+	layoutMode := extract.LayoutSimple
+	if *layout {
+		layoutMode = extract.LayoutPhysical
+	}
+
+	result, err := diff.Documents(oldDoc, newDoc,
+		diff.WithContext(*context),
+		diff.WithExtractOptions(extract.WithLayout(layoutMode)),
+		diff.WithNormalization(*normalize),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error comparing documents: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	for _, page := range result.Pages {
+		fmt.Print(page.Unified(oldFile, newFile))
+	}
+
+	s := result.Stats
+	fmt.Printf("\n%d of %d pages changed, %d added, %d removed; +%d words, -%d words\n",
+		s.PagesChanged, s.PagesCompared, s.PagesAdded, s.PagesRemoved, s.WordsAdded, s.WordsRemoved)
+}
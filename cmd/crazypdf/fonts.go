@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/validate"
+)
+
+func runFontsCommand(args []string) {
+	fs := flag.NewFlagSet("fonts", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `List the fonts of a PDF file and report non-embedded fonts.
+
+For each non-embedded font, the installed font that would be substituted
+at render time is shown with any characters it cannot render. The exit
+status is 2 when a font is not embedded.
+
+Usage:
+  crazypdf fonts [options] <input.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf fonts document.pdf
+  crazypdf fonts -json document.pdf
+  crazypdf fonts -font-dir /opt/press/fonts document.pdf
+`)
+	}
+
+	jsonOut := fs.Bool("json", false, "Output the report as JSON")
+	fontDir := fs.String("font-dir", "", "Search this directory for substitute fonts instead of the system font directories")
+	password := fs.String("password", "", "Password for encrypted PDF")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: one input PDF file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	inputFile := remaining[0]
+
+	doc, err := openDocument(inputFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	var opts []validate.Option
+	if *fontDir != "" {
+		opts = append(opts, validate.WithFontDirs(*fontDir))
+	}
+	audit, err := validate.FontReport(doc, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error checking fonts: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(audit); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		for _, f := range audit.Fonts {
+			status := "embedded"
+			if f.Subset {
+				status = "embedded subset"
+			}
+			if !f.Embedded {
+				status = "NOT EMBEDDED"
+			}
+			fmt.Printf("%-40s %-10s %-16s pages %s\n", f.Name, f.Type, status, formatPages(f.Pages))
+			if f.Embedded {
+				continue
+			}
+			sub := f.Substitute
+			if f.SubstituteFile == "" {
+				sub += " (not installed)"
+			} else {
+				sub += " (" + f.SubstituteFile + ")"
+			}
+			fmt.Printf("    substitute: %s\n", sub)
+			if len(f.MissingGlyphs) > 0 {
+				fmt.Printf("    missing glyphs: %s\n", string(f.MissingGlyphs))
+			}
+		}
+	}
+
+	if !audit.OK() {
+		os.Exit(2)
+	}
+}
+
+// formatPages formats a sorted page list compactly, e.g. "1-3,7".
+func formatPages(pages []int) string {
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, fmt.Sprint(pages[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
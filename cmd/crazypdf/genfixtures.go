@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rc4"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// fixture is a PDF of the edge-case corpus written by gen-fixtures.
+type fixture struct {
+	name        string
+	description string
+	build       func() []byte
+}
+
+// fixtures lists the corpus in the order it is generated.
+var fixtures = []fixture{
+	{"rotated.pdf", "four pages with /Rotate 0, 90, 180 and 270", rotatedFixture},
+	{"cid-font.pdf", "Type0 font with Identity-H encoding and a ToUnicode CMap", cidFontFixture},
+	{"multi-column.pdf", "two columns of text sharing baselines, between a full-width title and footnote", multiColumnFixture},
+	{"encrypted.pdf", `RC4 128-bit, user password "user", owner password "owner"`, encryptedFixture},
+	{"broken-xref.pdf", "cross-reference table with wrong offsets and startxref", brokenXrefFixture},
+}
+
+func runGenFixturesCommand(args []string) {
+	fs := flag.NewFlagSet("gen-fixtures", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Generate the edge-case PDF corpus.
+
+The files are written byte for byte the same on every run, so they can
+be regenerated instead of checked in. They are for checking commands
+by hand; no automated test reads them. Give fixture names after the
+output directory to write only those.
+
+Usage:
+  crazypdf gen-fixtures [options] <output-dir> [name...]
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf gen-fixtures testdata/
+  crazypdf gen-fixtures testdata/ encrypted.pdf broken-xref.pdf
+  crazypdf gen-fixtures -list
+`)
+	}
+
+	list := fs.Bool("list", false, "List the fixtures without writing them")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *list {
+		for _, f := range fixtures {
+			fmt.Printf("%-18s %s\n", f.name, f.description)
+		}
+		return
+	}
+
+	remaining := fs.Args()
+	if len(remaining) < 1 {
+		fmt.Fprintln(os.Stderr, "Error: output directory is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	selected := fixtures
+	if names := remaining[1:]; len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			found := false
+			for _, f := range fixtures {
+				if f.name == name || strings.TrimSuffix(f.name, ".pdf") == name {
+					selected = append(selected, f)
+					found = true
+				}
+			}
+			if !found {
+				fmt.Fprintf(os.Stderr, "Error: unknown fixture %q (see -list)\n", name)
+				os.Exit(1)
+			}
+		}
+	}
+
+	dir := remaining[0]
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, f := range selected {
+		path := filepath.Join(dir, f.name)
+		if _, err := crazypdf.WriteFile(path, writeString(string(f.build()))); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	}
+}
+
+// fixtureID is the fixed /ID of every fixture, which keeps the output
+// and the keys of encrypted.pdf the same on every run.
+const fixtureID = "crazypdf-fixture"
+
+// fixtureWriter assembles a PDF from numbered object bodies.
+type fixtureWriter struct {
+	objects [][]byte // body of object i+1
+}
+
+// add appends an object and returns its number.
+func (w *fixtureWriter) add(body string) int {
+	w.objects = append(w.objects, []byte(body))
+	return len(w.objects)
+}
+
+// set replaces the body of object num, for objects that refer to
+// objects added after them.
+func (w *fixtureWriter) set(num int, body string) {
+	w.objects[num-1] = []byte(body)
+}
+
+// stream returns the body of a stream object holding data.
+func stream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s/Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+// bytes writes the file with object 1 as the catalog and trailer
+// entries extra. When shift is not 0, every cross-reference offset and
+// startxref are moved by shift bytes, so readers have to rebuild the
+// table.
+func (w *fixtureWriter) bytes(extra string, shift int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(w.objects))
+	for i, body := range w.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off+shift)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /ID [<%x> <%x>] %s>>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.objects)+1, fixtureID, fixtureID, extra, xref+shift)
+	return buf.Bytes()
+}
+
+// textPages writes a document with one page per content stream, all
+// using Helvetica as /F1. pageExtra, if set, adds entries to each page
+// dictionary.
+func textPages(w *fixtureWriter, contents []string, pageExtra func(i int) string) {
+	w.add("<< /Type /Catalog /Pages 2 0 R >>")
+	pages := w.add("")
+	font := w.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	var kids []string
+	for i, content := range contents {
+		c := w.add(stream("", []byte(content)))
+		extra := ""
+		if pageExtra != nil {
+			extra = pageExtra(i)
+		}
+		page := w.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R %s>>",
+			pages, font, c, extra))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	w.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 612 792] >>",
+		strings.Join(kids, " "), len(kids)))
+}
+
+func rotatedFixture() []byte {
+	var w fixtureWriter
+	var contents []string
+	for _, rotate := range []int{0, 90, 180, 270} {
+		contents = append(contents, fmt.Sprintf("BT /F1 24 Tf 72 700 Td (Page rotated %d degrees) Tj ET", rotate))
+	}
+	textPages(&w, contents, func(i int) string { return fmt.Sprintf("/Rotate %d ", i*90) })
+	return w.bytes("", 0)
+}
+
+func cidFontFixture() []byte {
+	const text = "Grüße aus Köln — Καλημέρα κόσμε"
+
+	// Each distinct character gets the next CID from 1, written as two
+	// bytes under Identity-H and mapped back by the ToUnicode CMap.
+	cids := make(map[rune]int)
+	var order []rune
+	var shown strings.Builder
+	for _, r := range text {
+		if _, ok := cids[r]; !ok {
+			order = append(order, r)
+			cids[r] = len(order)
+		}
+		fmt.Fprintf(&shown, "%04X", cids[r])
+	}
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	fmt.Fprintf(&cmap, "%d beginbfchar\n", len(order))
+	for _, r := range order {
+		fmt.Fprintf(&cmap, "<%04X> <", cids[r])
+		for _, u := range utf16.Encode([]rune{r}) {
+			fmt.Fprintf(&cmap, "%04X", u)
+		}
+		cmap.WriteString(">\n")
+	}
+	cmap.WriteString("endbfchar\nendcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+
+	var w fixtureWriter
+	w.add("<< /Type /Catalog /Pages 2 0 R >>")
+	w.add("<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 612 792] >>")
+	w.add("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 8 0 R >>")
+	w.add("<< /Type /Font /Subtype /Type0 /BaseFont /ArialUnicodeMS /Encoding /Identity-H /DescendantFonts [5 0 R] /ToUnicode 7 0 R >>")
+	w.add("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /ArialUnicodeMS " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor 6 0 R /DW 600 /CIDToGIDMap /Identity >>")
+	w.add("<< /Type /FontDescriptor /FontName /ArialUnicodeMS /Flags 32 /FontBBox [-1011 -330 2260 1078] " +
+		"/ItalicAngle 0 /Ascent 1069 /Descent -271 /CapHeight 716 /StemV 80 >>")
+	w.add(stream("", []byte(cmap.String())))
+	w.add(stream("", []byte(fmt.Sprintf("BT /F1 18 Tf 72 700 Td <%s> Tj ET", shown.String()))))
+	return w.bytes("", 0)
+}
+
+// multiColumnFixture lays out a page that LayoutColumns reads as title,
+// left column, right column and footnote, while layouts reading across
+// the page interleave the columns. The right column ends two lines
+// early, and the lines are positioned with Td and T* as pdfTeX does.
+func multiColumnFixture() []byte {
+	var content strings.Builder
+	content.WriteString("BT /F1 12 Tf 150 740 Td (A title spanning both columns of the page) Tj ET\n")
+	content.WriteString("BT /F1 11 Tf 16 TL 72 704 Td\n")
+	for i := 1; i <= 12; i++ {
+		fmt.Fprintf(&content, "(Left column line %d) Tj T*\n", i)
+	}
+	content.WriteString("ET\nBT /F1 11 Tf 16 TL 330 704 Td\n")
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&content, "(Right column line %d) Tj T*\n", i)
+	}
+	content.WriteString("ET\nBT /F1 9 Tf 72 480 Td (A footnote that runs across the page, below both of the columns) Tj ET\n")
+	var w fixtureWriter
+	textPages(&w, []string{content.String()}, nil)
+	return w.bytes("", 0)
+}
+
+func brokenXrefFixture() []byte {
+	var w fixtureWriter
+	textPages(&w, []string{"BT /F1 24 Tf 72 700 Td (Recovered from a broken xref) Tj ET"}, nil)
+	return w.bytes("", 7)
+}
+
+// encryptedFixture encrypts with revision 3 of the standard security
+// handler (PDF 32000-1:2008, algorithms 2 to 5).
+func encryptedFixture() []byte {
+	const (
+		user, owner = "user", "owner"
+		perms       = -3132 // print, fill forms and accessibility only
+		keyLen      = 16
+	)
+	pad := []byte{
+		0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+		0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+	}
+	padded := func(pw string) []byte { return append([]byte(pw), pad...)[:32] }
+	rc4Bytes := func(key, data []byte) []byte {
+		c, _ := rc4.NewCipher(key)
+		out := make([]byte, len(data))
+		c.XORKeyStream(out, data)
+		return out
+	}
+	rounds := func(key, data []byte) []byte {
+		k := make([]byte, len(key))
+		for i := 0; i < 20; i++ {
+			for j := range key {
+				k[j] = key[j] ^ byte(i)
+			}
+			data = rc4Bytes(k, data)
+		}
+		return data
+	}
+	stretch := func(sum []byte) []byte {
+		for i := 0; i < 50; i++ {
+			s := md5.Sum(sum[:keyLen])
+			sum = s[:]
+		}
+		return sum[:keyLen]
+	}
+
+	ownerSum := md5.Sum(padded(owner))
+	o := rounds(stretch(ownerSum[:]), padded(user))
+
+	h := md5.New()
+	h.Write(padded(user))
+	h.Write(o)
+	var p int32 = perms
+	h.Write([]byte{byte(p), byte(p >> 8), byte(p >> 16), byte(p >> 24)})
+	h.Write([]byte(fixtureID))
+	key := stretch(h.Sum(nil))
+
+	h = md5.New()
+	h.Write(pad)
+	h.Write([]byte(fixtureID))
+	u := append(rounds(key, h.Sum(nil)), pad[:16]...)
+
+	objectKey := func(num int) []byte {
+		m := md5.New()
+		m.Write(key)
+		m.Write([]byte{byte(num), byte(num >> 8), byte(num >> 16), 0, 0})
+		return m.Sum(nil)[:min(keyLen+5, 16)]
+	}
+
+	var w fixtureWriter
+	textPages(&w, []string{"BT /F1 24 Tf 72 700 Td (Encrypted with RC4) Tj ET"}, nil)
+	// textPages writes the content stream as object 4
+	content := []byte("BT /F1 24 Tf 72 700 Td (Encrypted with RC4) Tj ET")
+	w.set(4, stream("", rc4Bytes(objectKey(4), content)))
+	info := w.add(fmt.Sprintf("<< /Title <%x> >>", rc4Bytes(objectKey(len(w.objects)+1), []byte("Encrypted fixture"))))
+	enc := w.add(fmt.Sprintf("<< /Filter /Standard /V 2 /R 3 /Length %d /O <%x> /U <%x> /P %d >>", keyLen*8, o, u, perms))
+	return w.bytes(fmt.Sprintf("/Info %d 0 R /Encrypt %d 0 R ", info, enc), 0)
+}
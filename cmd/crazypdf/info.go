@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/metadata"
+)
+
+func runInfoCommand(args []string) {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Show the metadata, encryption and file structure of a PDF file.
+
+Standard fields come from the document information dictionary, falling
+back to the XMP metadata stream. Use -xmp to list every XMP property.
+
+Usage:
+  crazypdf info [options] <input.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf info document.pdf
+  crazypdf info -xmp document.pdf
+  crazypdf info -json document.pdf
+`)
+	}
+
+	jsonOut := fs.Bool("json", false, "Output the metadata as JSON")
+	showXMP := fs.Bool("xmp", false, "List all XMP properties")
+	password := fs.String("password", "", "Password for encrypted PDF")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: one input PDF file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	doc, err := openDocument(remaining[0], *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	md, err := metadata.Read(doc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading metadata: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(md); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Printf("%-14s %s\n", name+":", value)
+		}
+	}
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	field("Title", md.Title)
+	field("Author", md.Author)
+	field("Subject", md.Subject)
+	field("Keywords", md.Keywords)
+	field("Creator", md.Creator)
+	field("Producer", md.Producer)
+	field("CreationDate", date(md.CreationDate))
+	field("ModDate", date(md.ModDate))
+	for _, key := range sortedKeys(md.Custom) {
+		field(key, md.Custom[key])
+	}
+	field("Pages", fmt.Sprint(doc.NumPages()))
+	if enc, err := doc.Encryption(); err == nil && enc.Encrypted {
+		field("Encryption", enc.String())
+	}
+	if fi, err := doc.FileInfo(); err == nil {
+		field("Version", fi.Version)
+		objects := fmt.Sprint(fi.Objects)
+		if fi.CompressedObjects > 0 {
+			objects += fmt.Sprintf(" (%d in object streams)", fi.CompressedObjects)
+		}
+		field("Objects", objects)
+		xref := "table"
+		if fi.XRefStreams {
+			xref = "stream"
+		}
+		field("XRef", xref)
+		field("Revisions", fmt.Sprint(fi.Revisions))
+		if lin, err := doc.Linearization(); err == nil {
+			switch {
+			case lin == nil:
+				field("Linearized", "no")
+			case lin.Valid:
+				field("Linearized", "yes")
+			default:
+				field("Linearized", "no (updated after linearization)")
+			}
+		}
+		field("ID", strings.Join(fi.ID, " "))
+	}
+	if st, err := doc.Stats(); err == nil {
+		field("Streams", fmt.Sprintf("%d (%s stored)", st.Streams, byteSize(st.StreamBytes)))
+		field("Images", fmt.Sprintf("%d (%s stored)", st.Images, byteSize(st.ImageBytes)))
+		field("Fonts", fmt.Sprint(st.Fonts))
+		field("Annotations", fmt.Sprint(st.Annotations))
+		field("Content", byteSize(st.ContentBytes)+" decoded")
+	}
+	if fp, err := doc.Fingerprint(); err == nil {
+		field("Fingerprint", fp)
+	}
+	if layers, err := doc.Layers(); err == nil {
+		names := make([]string, len(layers))
+		for i, l := range layers {
+			names[i] = strconv.Quote(l.Name)
+			if !l.Visible {
+				names[i] += " (hidden)"
+			}
+		}
+		field("Layers", strings.Join(names, ", "))
+	}
+	if view, err := doc.InitialView(); err == nil {
+		if view.OpenAction != nil {
+			field("OpenAction", describeAction(view.OpenAction))
+		}
+		if view.AutoExecutes() {
+			field("AutoExecutes", "yes")
+		}
+	}
+
+	if *showXMP && len(md.XMP) > 0 {
+		fmt.Println("\nXMP:")
+		for _, key := range sortedKeys(md.XMP) {
+			fmt.Printf("  %s = %s\n", key, strings.Join(md.XMP[key], " | "))
+		}
+	}
+}
+
+// byteSize formats a size in bytes with a binary unit, such as "1.5 MiB".
+func byteSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, unit := float64(n)/1024, "KiB"
+	for _, u := range []string{"MiB", "GiB"} {
+		if size < 1024 {
+			break
+		}
+		size, unit = size/1024, u
+	}
+	return fmt.Sprintf("%.1f %s", size, unit)
+}
+
+// describeAction summarizes an action and the actions chained to it,
+// such as "GoTo page 1, then JavaScript".
+func describeAction(a *crazypdf.OpenAction) string {
+	desc := a.Type
+	switch {
+	case a.Dest != nil:
+		desc += fmt.Sprintf(" page %d", a.Dest.Page)
+	case a.Target != "" && a.Type != "JavaScript":
+		desc += " " + strconv.Quote(a.Target)
+	}
+	for _, next := range a.Next {
+		desc += ", then " + describeAction(next)
+	}
+	return desc
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
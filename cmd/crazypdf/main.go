@@ -7,17 +7,34 @@
 // Commands:
 //
 //	text       Extract text from PDF
+//	diff       Compare the text of two PDFs
+//	search     Find regular expression matches in a PDF
+//	redact     Permanently remove regular expression matches from a PDF
+//	fonts      List fonts and report non-embedded fonts
+//	info       Show document metadata
+//	view       Browse extracted text interactively
+//	debug-layout  Draw the layout analysis of a page as SVG
+//	disasm     List the content stream operations of a page
+//	split      Split a PDF into one file per outline section
+//	gen-fixtures  Write the edge-case PDF corpus
+//	xcheck     Compare the text extraction backends on a PDF
 //
 // Use "crazypdf <command> -h" for help on a specific command.
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/export"
 	"github.com/ayushanand18/crazypdf/pkg/extract"
 )
 
@@ -28,6 +45,17 @@ Usage:
 
 Commands:
   text       Extract text from a PDF file
+  diff       Compare the text of two PDF files
+  search     Find regular expression matches in a PDF file
+  redact     Permanently remove regular expression matches from a PDF file
+  fonts      List fonts and report non-embedded fonts
+  info       Show the title, author, dates and XMP metadata of a PDF file
+  view       Browse the extracted text of a PDF file interactively
+  debug-layout  Draw word, line, block and column boxes of a page as SVG
+  disasm     List the content stream operations of a page, annotated
+  split      Split a PDF file into one file per outline section
+  gen-fixtures  Write the edge-case PDF corpus to a directory
+  xcheck     Report pages where the text extraction backends disagree
 
 Options vary by command. Use "crazypdf <command> -h" for help.
 
@@ -36,6 +64,17 @@ Examples:
   crazypdf text -layout document.pdf output.txt
   crazypdf text -raw -pages 1-3 document.pdf
   crazypdf text -password secret encrypted.pdf
+  crazypdf diff old.pdf new.pdf
+  crazypdf search -e 'pattern' document.pdf
+  crazypdf redact -e 'pattern' document.pdf redacted.pdf
+  crazypdf fonts document.pdf
+  crazypdf info document.pdf
+  crazypdf view document.pdf
+  crazypdf debug-layout -page 3 document.pdf page3.svg
+  crazypdf disasm -page 2 document.pdf
+  crazypdf split -depth 2 report.pdf chapters/
+  crazypdf gen-fixtures testdata/
+  crazypdf xcheck document.pdf
 `
 
 func main() {
@@ -49,10 +88,32 @@ func main() {
 	switch command {
 	case "text":
 		runTextCommand(os.Args[2:])
+	case "diff":
+		runDiffCommand(os.Args[2:])
+	case "search":
+		runSearchCommand(os.Args[2:])
+	case "redact":
+		runRedactCommand(os.Args[2:])
+	case "fonts":
+		runFontsCommand(os.Args[2:])
+	case "info":
+		runInfoCommand(os.Args[2:])
+	case "view":
+		runViewCommand(os.Args[2:])
+	case "debug-layout":
+		runDebugLayoutCommand(os.Args[2:])
+	case "disasm":
+		runDisasmCommand(os.Args[2:])
+	case "split":
+		runSplitCommand(os.Args[2:])
+	case "gen-fixtures":
+		runGenFixturesCommand(os.Args[2:])
+	case "xcheck":
+		runXcheckCommand(os.Args[2:])
 	case "-h", "--help", "help":
 		fmt.Print(usage)
 	case "-v", "--version", "version":
-		fmt.Println("crazypdf v0.1.0")
+		fmt.Println("crazypdf v" + crazypdf.Version)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", command)
 		fmt.Fprint(os.Stderr, usage)
@@ -60,6 +121,15 @@ func main() {
 	}
 }
 
+// writeString returns a write function for crazypdf.WriteFile that
+// writes s.
+func writeString(s string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
 func runTextCommand(args []string) {
 	fs := flag.NewFlagSet("text", flag.ExitOnError)
 	fs.Usage = func() {
@@ -76,15 +146,66 @@ Examples:
   crazypdf text document.pdf
   crazypdf text -layout document.pdf output.txt
   crazypdf text -raw document.pdf
+  crazypdf text -normalize document.pdf v1.txt
+  crazypdf text -paragraphs report.pdf
+  crazypdf text -columns paper.pdf
   crazypdf text -password secret encrypted.pdf
   crazypdf text -pages 1,3,5 document.pdf
+  crazypdf text -skip-empty scanned.pdf
+  crazypdf text -image-placeholder "[IMAGE: %%dx%%d]" report.pdf
+  crazypdf text -figure-alt "[FIGURE: %%s]" tagged.pdf
+  crazypdf text -min-font-size 7 -exclude-region 0,720,612,792 letter.pdf
+  crazypdf text -exclude-font "*Barcode*,Watermark*" invoice.pdf
+  crazypdf text -visible-layers brochure.pdf
+  crazypdf text -layer Deutsch brochure.pdf
+  crazypdf text -dict document.pdf pages.json
+  crazypdf text -dict -schema 1.0 document.pdf pages.json
+  crazypdf text -profile invoices.json invoice.pdf
+  crazypdf text -profile auto -profiles tuned/ paper.pdf
+  crazypdf text -trace -pages 57 document.pdf 2> trace.txt
 `)
 	}
 
 	layout := fs.Bool("layout", false, "Preserve physical layout of text")
 	raw := fs.Bool("raw", false, "Extract text in content stream order")
+	normalize := fs.Bool("normalize", false, "Normalize text for comparing versions (position order, collapsed whitespace, normalized Unicode)")
+	columns := fs.Bool("columns", false, "Read multi-column pages column by column, with columns found by XY-cut")
+	paragraphs := fs.Bool("paragraphs", false, "Write each paragraph as one line, with blank lines between paragraphs")
+	skipEmpty := fs.Bool("skip-empty", false, "Leave out pages without text and list them on stderr")
+	imagePlaceholder := fs.String("image-placeholder", "", "Marker written where images occur, given their pixel width and height (e.g., '[IMAGE: %dx%d]')")
+	figureAlt := fs.String("figure-alt", "", "Format of the alternate text written where tagged figures occur (e.g., '[FIGURE: %s]')")
+	minFontSize := fs.Float64("min-font-size", 0, "Drop text set in smaller font sizes, in points")
+	maxFontSize := fs.Float64("max-font-size", 0, "Drop text set in larger font sizes, in points (0 for no limit)")
+	var excludeRegions []crazypdf.Rect
+	fs.Func("exclude-region", "Drop text inside a region 'x0,y0,x1,y1' in PDF points (repeatable)", func(s string) error {
+		r, err := parseRect(s)
+		if err == nil {
+			excludeRegions = append(excludeRegions, r)
+		}
+		return err
+	})
+	var includeFonts, excludeFonts []string
+	fs.Func("include-font", "Keep only text in fonts matching these comma-separated name patterns (e.g., 'Arial*')", func(s string) error {
+		includeFonts = append(includeFonts, strings.Split(s, ",")...)
+		return nil
+	})
+	fs.Func("exclude-font", "Drop text in fonts matching these comma-separated name patterns (e.g., '*Barcode*')", func(s string) error {
+		excludeFonts = append(excludeFonts, strings.Split(s, ",")...)
+		return nil
+	})
+	visibleLayers := fs.Bool("visible-layers", false, "Drop text on layers hidden by default")
+	var layers []string
+	fs.Func("layer", "Keep only the text of these comma-separated layers, besides text outside layers (repeatable)", func(s string) error {
+		layers = append(layers, strings.Split(s, ",")...)
+		return nil
+	})
 	password := fs.String("password", "", "Password for encrypted PDF")
 	pagesFlag := fs.String("pages", "", "Page range (e.g., '1-5' or '1,3,5')")
+	dict := fs.Bool("dict", false, "Output blocks, lines and spans with coordinates as JSON in PyMuPDF's \"dict\" format")
+	schema := fs.String("schema", export.SchemaVersion, "Schema version of -dict output")
+	profilePath := fs.String("profile", "", "Extraction profile: a JSON file, a family (generic, latex-paper, office-export, scanned) or 'auto' to pick the family; other options given override it")
+	trace := fs.Bool("trace", false, "Write each layout decision, such as a space inserted between words, to stderr")
+	profilesDir := fs.String("profiles", "", "Directory of JSON profiles named <family>.json that replace the built-in ones with -profile auto")
 
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
@@ -110,10 +231,40 @@ Examples:
 		layoutMode = extract.LayoutPhysical
 	case *raw:
 		layoutMode = extract.LayoutRaw
+	case *normalize:
+		layoutMode = extract.LayoutNormalized
+	case *paragraphs:
+		layoutMode = extract.LayoutParagraphs
+	case *columns:
+		layoutMode = extract.LayoutColumns
 	default:
 		layoutMode = extract.LayoutSimple
 	}
 
+	var profile *extract.Profile
+	if *profilePath != "" && *profilePath != "auto" {
+		var err error
+		if profile, err = loadProfile(*profilePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	// given reports whether the setting of the named flags applies:
+	// always without a profile, and over one only when a flag was set.
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	given := func(names ...string) bool {
+		if profile == nil {
+			return true
+		}
+		for _, name := range names {
+			if set[name] {
+				return true
+			}
+		}
+		return false
+	}
+
 	// open document
 	var docOpts []crazypdf.Option
 	if *password != "" {
@@ -127,6 +278,16 @@ Examples:
 	}
 	defer doc.Close()
 
+	if *profilePath == "auto" {
+		if profile, err = autoProfile(doc, *profilesDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error choosing profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if profile != nil && !set["skip-empty"] {
+		*skipEmpty = profile.SkipEmptyPages
+	}
+
 	// parse page range
 	pageIndices, err := parsePageRange(*pagesFlag, doc.NumPages())
 	if err != nil {
@@ -134,13 +295,48 @@ Examples:
 		os.Exit(1)
 	}
 
+	if *dict {
+		writePyMuPDFDict(doc, pageIndices, outputFile, *schema)
+		return
+	}
+
 	// extract text
-	extractOpts := []extract.Option{
-		extract.WithLayout(layoutMode),
+	var extractOpts []extract.Option
+	if profile != nil {
+		extractOpts = append(extractOpts, extract.WithProfile(profile))
+	}
+	if given("layout", "raw", "normalize", "paragraphs", "columns") {
+		extractOpts = append(extractOpts, extract.WithLayout(layoutMode))
+	}
+	if given("image-placeholder") {
+		extractOpts = append(extractOpts, extract.WithImagePlaceholder(*imagePlaceholder))
+	}
+	if given("figure-alt") {
+		extractOpts = append(extractOpts, extract.WithFigureAltText(*figureAlt))
+	}
+	if given("min-font-size", "max-font-size") {
+		extractOpts = append(extractOpts, extract.WithFontSizeRange(*minFontSize, *maxFontSize))
+	}
+	if given("exclude-region") {
+		extractOpts = append(extractOpts, extract.WithExcludeRegions(excludeRegions))
+	}
+	if given("include-font", "exclude-font") {
+		extractOpts = append(extractOpts, extract.WithFontFilter(includeFonts, excludeFonts))
+	}
+	switch {
+	case layers != nil:
+		extractOpts = append(extractOpts, extract.WithLayers(layers...))
+	case *visibleLayers:
+		extractOpts = append(extractOpts, extract.WithVisibleLayersOnly())
+	}
+	if *trace {
+		extractOpts = append(extractOpts, extract.WithTrace(os.Stderr))
 	}
 
 	var result strings.Builder
-	for i, pageIdx := range pageIndices {
+	var skipped []string
+	written := 0
+	for _, pageIdx := range pageIndices {
 		page, err := doc.Page(pageIdx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error accessing page %d: %v\n", pageIdx+1, err)
@@ -153,15 +349,23 @@ Examples:
 			os.Exit(1)
 		}
 
-		result.WriteString(text)
-		if i < len(pageIndices)-1 {
+		if *skipEmpty && strings.TrimSpace(text) == "" {
+			skipped = append(skipped, strconv.Itoa(pageIdx+1))
+			continue
+		}
+		if written > 0 {
 			result.WriteString("\n\n")
 		}
+		result.WriteString(text)
+		written++
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped empty pages: %s\n", strings.Join(skipped, ", "))
 	}
 
 	output := result.String()
 	if outputFile != "" {
-		if err := os.WriteFile(outputFile, []byte(output), 0644); err != nil {
+		if _, err := crazypdf.WriteFile(outputFile, writeString(output)); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
 			os.Exit(1)
 		}
@@ -174,6 +378,66 @@ Examples:
 	}
 }
 
+// writePyMuPDFDict writes the selected pages as a JSON array in PyMuPDF's
+// "dict" format to outputFile, or to stdout when it is empty.
+func writePyMuPDFDict(doc *crazypdf.Document, pageIndices []int, outputFile, schema string) {
+	opts := []export.Option{export.WithSchemaVersion(schema)}
+	var prov *export.Provenance
+	if schema != export.SchemaVersion10 {
+		var err error
+		if prov, err = export.NewProvenance(doc, opts...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading PDF: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	pages := make([]*export.PyMuPDFPage, 0, len(pageIndices))
+	for _, pageIdx := range pageIndices {
+		page, err := doc.Page(pageIdx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error accessing page %d: %v\n", pageIdx+1, err)
+			os.Exit(1)
+		}
+		p, err := export.PyMuPDFDict(page, opts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error extracting page %d: %v\n", pageIdx+1, err)
+			os.Exit(1)
+		}
+		p.Provenance = prov
+		pages = append(pages, p)
+	}
+
+	write := func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pages)
+	}
+	if outputFile == "" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if _, err := crazypdf.WriteFile(outputFile, write); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Text extracted to %s (%d pages)\n", outputFile, len(pageIndices))
+}
+
+// openDocument opens a PDF, applying the password when one is given. A
+// path of "-" reads the PDF from standard input.
+func openDocument(path, password string) (*crazypdf.Document, error) {
+	var opts []crazypdf.Option
+	if password != "" {
+		opts = append(opts, crazypdf.WithPassword(password))
+	}
+	if path == "-" {
+		return crazypdf.OpenReader(os.Stdin, opts...)
+	}
+	return crazypdf.Open(path, opts...)
+}
+
 // parsePageRange parses a page range string like "1-5" or "1,3,5" into
 // 0-based page indices.
 func parsePageRange(pagesStr string, totalPages int) ([]int, error) {
@@ -225,3 +489,56 @@ func parsePageRange(pagesStr string, totalPages int) ([]int, error) {
 
 	return indices, nil
 }
+
+// loadProfile returns the built-in profile of the family called name,
+// or else the JSON profile at the path name.
+func loadProfile(name string) (*extract.Profile, error) {
+	if p := extract.FamilyProfile(extract.Family(name)); p != nil {
+		return p, nil
+	}
+	return extract.LoadProfile(name)
+}
+
+// autoProfile returns the profile of the family of doc, taken from
+// <family>.json in dir when there is one, and notes the family on stderr.
+func autoProfile(doc *crazypdf.Document, dir string) (*extract.Profile, error) {
+	overrides := make(map[extract.Family]*extract.Profile)
+	if dir != "" {
+		for _, family := range extract.Families {
+			p, err := extract.LoadProfile(filepath.Join(dir, string(family)+".json"))
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("%s: %w", family, err)
+			}
+			overrides[family] = p
+		}
+	}
+	profile, c, err := extract.AutoProfile(doc, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Reasons) > 0 {
+		fmt.Fprintf(os.Stderr, "Profile: %s (%s)\n", c.Family, strings.Join(c.Reasons, ", "))
+	} else {
+		fmt.Fprintf(os.Stderr, "Profile: %s\n", c.Family)
+	}
+	return profile, nil
+}
+
+// parseRect parses a rectangle string like "0,720,612,792" into a Rect
+// with its corners in order.
+func parseRect(s string) (crazypdf.Rect, error) {
+	var r crazypdf.Rect
+	if _, err := fmt.Sscanf(strings.ReplaceAll(s, " ", ""), "%g,%g,%g,%g", &r.X0, &r.Y0, &r.X1, &r.Y1); err != nil {
+		return r, fmt.Errorf("invalid region: %q (want x0,y0,x1,y1)", s)
+	}
+	if r.X0 > r.X1 {
+		r.X0, r.X1 = r.X1, r.X0
+	}
+	if r.Y0 > r.Y1 {
+		r.Y0, r.Y1 = r.Y1, r.Y0
+	}
+	return r, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/pii"
+	"github.com/ayushanand18/crazypdf/pkg/redact"
+	"github.com/ayushanand18/crazypdf/pkg/search"
+)
+
+func runRedactCommand(args []string) {
+	fs := flag.NewFlagSet("redact", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Permanently remove regular expression matches from a PDF file.
+
+Matched text is deleted from the page content, not just covered, and a
+black box is drawn in its place.
+
+Usage:
+  crazypdf redact -e <pattern> [options] <input.pdf> <output.pdf>
+  crazypdf redact -pii [options] <input.pdf> <output.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf redact -e '\b\d{3}-\d{2}-\d{4}\b' input.pdf out.pdf
+  crazypdf redact -e '(?i)confidential' -report input.pdf out.pdf
+  crazypdf redact -e 'ACME Corp' -no-fill input.pdf out.pdf
+  crazypdf redact -pii -report input.pdf out.pdf
+  crazypdf redact -pii -checksum input.pdf out.pdf
+  crazypdf redact -pii -strip-metadata input.pdf out.pdf
+`)
+	}
+
+	expr := fs.String("e", "", "Regular expression to redact (Go RE2 syntax)")
+	detectPII := fs.Bool("pii", false, "Redact detected personal information (emails, phone numbers, SSNs, IBANs, card numbers)")
+	report := fs.Bool("report", false, "Print what was removed and where")
+	noFill := fs.Bool("no-fill", false, "Do not draw boxes over redacted areas")
+	padding := fs.Float64("padding", 1, "Margin in points around each match")
+	stripMetadata := fs.Bool("strip-metadata", false, "Remove author, producer and other document metadata")
+	checksum := fs.Bool("checksum", false, "Print the SHA-256 of the output file")
+	password := fs.String("password", "", "Password for encrypted PDF")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if (*expr == "" && !*detectPII) || len(remaining) != 2 {
+		fmt.Fprintln(os.Stderr, "Error: a pattern (-e) or -pii, an input PDF file and an output PDF file are required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	inputFile, outputFile := remaining[0], remaining[1]
+
+	doc, err := openDocument(inputFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	opts := []redact.Option{redact.WithPadding(*padding)}
+	if *noFill {
+		opts = append(opts, redact.WithoutFill())
+	}
+	if *stripMetadata {
+		opts = append(opts, redact.WithMetadata(crazypdf.MetadataStrip))
+	}
+	if *checksum {
+		opts = append(opts, redact.WithWriteOptions(crazypdf.WithChecksum()))
+	}
+
+	var areas []redact.Area
+	if *expr != "" {
+		pattern, err := regexp.Compile(*expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing pattern: %v\n", err)
+			os.Exit(1)
+		}
+		matches, err := search.Find(doc, pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching PDF: %v\n", err)
+			os.Exit(1)
+		}
+		areas = append(areas, redact.MatchAreas(matches)...)
+	}
+	if *detectPII {
+		matches, err := pii.Scan(doc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error detecting personal information: %v\n", err)
+			os.Exit(1)
+		}
+		if *report {
+			for _, m := range matches {
+				fmt.Printf("%s:%d: %s %q\n", inputFile, m.Page, m.Kind, m.Text)
+			}
+		}
+		areas = append(areas, pii.Areas(matches)...)
+	}
+
+	result, err := redact.ApplyFile(doc, areas, outputFile, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error redacting PDF: %v\n", err)
+		os.Exit(1)
+	}
+	if *checksum {
+		fmt.Printf("%s  %s\n", result.Output.SHA256, outputFile)
+	}
+
+	if *report {
+		for _, p := range result.Pages {
+			for _, a := range p.Areas {
+				fmt.Printf("%s:%d: [%.0f,%.0f,%.0f,%.0f] %q\n", inputFile, a.Page,
+					a.Rect.X0, a.Rect.Y0, a.Rect.X1, a.Rect.Y1, a.Text)
+			}
+			fmt.Printf("%s:%d: removed %d glyphs, %d images, %d annotations\n", inputFile, p.Page,
+				p.Glyphs, p.Images, p.Annotations)
+		}
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	applied := 0
+	for _, p := range result.Pages {
+		applied += len(p.Areas)
+	}
+	fmt.Fprintf(os.Stderr, "%d areas redacted on %d pages, %d glyphs removed\n", applied, len(result.Pages), result.Glyphs())
+}
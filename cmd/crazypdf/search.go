@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/search"
+)
+
+func runSearchCommand(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Search a PDF file for a regular expression, or for words and phrases
+near each other.
+
+Queries (-q) are words or quoted phrases joined by NEAR/k, which matches
+terms in either order with at most k words between them. A synonyms
+file expands query terms: each line lists a term, a colon and its
+alternatives separated by commas, such as "acetaminophen: paracetamol,
+APAP". Lines starting with # are ignored.
+
+Usage:
+  crazypdf search -e <pattern> [options] <input.pdf>
+  crazypdf search -q <query> [options] <input.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf search -e 'invoice\s+#\d+' document.pdf
+  crazypdf search -e '(?i)total' -pages 1-3 document.pdf
+  crazypdf search -e 'ACME' -render-matches out/ document.pdf
+  crazypdf search -fold -e 'resume' document.pdf
+  crazypdf search -q 'indemnify NEAR/10 liability' contract.pdf
+  crazypdf search -q '"limitation of liability"' contract.pdf
+  crazypdf search -q 'acetaminophen NEAR/5 dose' -synonyms drugs.txt label.pdf
+`)
+	}
+
+	expr := fs.String("e", "", "Regular expression to search for (Go RE2 syntax)")
+	queryFlag := fs.String("q", "", "Phrase and proximity query, such as 'indemnify NEAR/10 liability'")
+	synonymsFile := fs.String("synonyms", "", "File of query term synonyms (with -q)")
+	fold := fs.Bool("fold", false, "Ignore case and diacritics (\"resume\" matches \"Résumé\")")
+	renderDir := fs.String("render-matches", "", "Directory to write a cropped SVG preview of each match")
+	password := fs.String("password", "", "Password for encrypted PDF")
+	pagesFlag := fs.String("pages", "", "Page range (e.g., '1-5' or '1,3,5')")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if (*expr == "") == (*queryFlag == "") || len(remaining) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: a pattern (-e) or a query (-q), and one input PDF file are required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	inputFile := remaining[0]
+
+	opts := []search.Option{search.WithFolding(*fold)}
+	if *synonymsFile != "" {
+		synonyms, err := readSynonyms(*synonymsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading synonyms: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, search.WithSynonyms(synonyms))
+	}
+	var find func(*crazypdf.Page) ([]search.Match, error)
+	if *queryFlag != "" {
+		query, err := search.ParseQuery(*queryFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing query: %v\n", err)
+			os.Exit(1)
+		}
+		find = func(page *crazypdf.Page) ([]search.Match, error) {
+			return search.FindQueryPage(page, query, opts...)
+		}
+	} else {
+		pattern, err := regexp.Compile(*expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing pattern: %v\n", err)
+			os.Exit(1)
+		}
+		find = func(page *crazypdf.Page) ([]search.Match, error) {
+			return search.FindPage(page, pattern, opts...)
+		}
+	}
+
+	doc, err := openDocument(inputFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	pageIndices, err := parsePageRange(*pagesFlag, doc.NumPages())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing page range: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *renderDir != "" {
+		if err := os.MkdirAll(*renderDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	total := 0
+	for _, pageIdx := range pageIndices {
+		page, err := doc.Page(pageIdx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error accessing page %d: %v\n", pageIdx+1, err)
+			os.Exit(1)
+		}
+
+		matches, err := find(page)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching page %d: %v\n", pageIdx+1, err)
+			os.Exit(1)
+		}
+
+		for i, m := range matches {
+			total++
+			where := strconv.Itoa(m.Page)
+			if m.Label != where {
+				where += " (" + m.Label + ")"
+			}
+			fmt.Printf("%s:%s: [%.0f,%.0f,%.0f,%.0f] %s\n", inputFile, where,
+				m.BBox.X0, m.BBox.Y0, m.BBox.X1, m.BBox.Y1, m.Context)
+
+			if *renderDir == "" {
+				continue
+			}
+			name := filepath.Join(*renderDir, fmt.Sprintf("match-p%04d-%03d.svg", m.Page, i+1))
+			if err := writeMatchPreview(page, m, name); err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering match on page %d: %v\n", m.Page, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if total == 1 {
+		fmt.Fprintln(os.Stderr, "1 match")
+	} else {
+		fmt.Fprintf(os.Stderr, "%d matches\n", total)
+	}
+}
+
+// readSynonyms reads a synonyms file: one "term: alt, alt" entry per
+// line, with blank lines and lines starting with # ignored.
+func readSynonyms(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	synonyms := make(map[string][]string)
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		term, alts, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected \"term: alternatives\"", path, n+1)
+		}
+		term = strings.TrimSpace(term)
+		for _, alt := range strings.Split(alts, ",") {
+			if alt = strings.TrimSpace(alt); alt != "" {
+				synonyms[term] = append(synonyms[term], alt)
+			}
+		}
+	}
+	return synonyms, nil
+}
+
+// previewMargin is the space around a match included in its preview, in points.
+const previewMargin = 48
+
+// writeMatchPreview writes an SVG crop of the page area around a match,
+// drawing nearby words and images with the match highlighted.
+func writeMatchPreview(page *crazypdf.Page, m search.Match, path string) error {
+	box, err := page.MediaBox()
+	if err != nil {
+		return err
+	}
+	words, err := page.Words()
+	if err != nil {
+		return err
+	}
+	graphics, err := page.Graphics()
+	if err != nil {
+		return err
+	}
+
+	crop := crazypdf.Rect{
+		X0: math.Max(box.X0, m.BBox.X0-previewMargin),
+		Y0: math.Max(box.Y0, m.BBox.Y0-previewMargin),
+		X1: math.Min(box.X1, m.BBox.X1+previewMargin),
+		Y1: math.Min(box.Y1, m.BBox.Y1+previewMargin),
+	}
+	// SVG uses a top-left origin
+	flipY := func(y float64) float64 { return crop.Y1 - y }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.2f %.2f">`+"\n",
+		crop.Width()*2, crop.Height()*2, crop.Width(), crop.Height())
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(&sb, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="yellow" fill-opacity="0.5"/>`+"\n",
+		m.BBox.X0-crop.X0, flipY(m.BBox.Y1), m.BBox.Width(), m.BBox.Height())
+
+	for _, g := range graphics {
+		if g.Kind != crazypdf.GraphicImage || !g.BBox.Intersects(crop) {
+			continue
+		}
+		fmt.Fprintf(&sb, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#ddd" stroke="#999"/>`+"\n",
+			g.BBox.X0-crop.X0, flipY(g.BBox.Y1), g.BBox.Width(), g.BBox.Height())
+	}
+	for _, w := range words {
+		if !w.BBox.Intersects(crop) {
+			continue
+		}
+		size := w.FontSize
+		if size <= 0 {
+			size = w.BBox.Height()
+		}
+		baseline := w.BBox.Y0 + size*0.2
+		fmt.Fprintf(&sb, `<text x="%.2f" y="%.2f" font-family="sans-serif" font-size="%.2f" textLength="%.2f">%s</text>`+"\n",
+			w.BBox.X0-crop.X0, flipY(baseline), size, w.BBox.Width(), html.EscapeString(w.S))
+	}
+	sb.WriteString("</svg>\n")
+
+	_, err = crazypdf.WriteFile(path, writeString(sb.String()))
+	return err
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ayushanand18/crazypdf/pkg/split"
+)
+
+func runSplitCommand(args []string) {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Split a PDF into one file per outline section.
+
+Each bookmark down to the given depth starts a section that runs until
+the next one. Files are named after the bookmark titles, numbered in
+order. Pages before the first bookmark are written as front matter.
+
+Usage:
+  crazypdf split [options] <input.pdf> <output-dir>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf split report.pdf chapters/
+  crazypdf split -depth 2 -dry-run report.pdf chapters/
+`)
+	}
+
+	depth := fs.Int("depth", 1, "Outline levels that start a section")
+	frontMatter := fs.String("front-matter", "Front Matter", "Title of the section before the first bookmark; empty to leave those pages out")
+	dryRun := fs.Bool("dry-run", false, "List the sections without writing files")
+	password := fs.String("password", "", "Password for encrypted PDF")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) != 2 {
+		fmt.Fprintln(os.Stderr, "Error: input PDF file and output directory are required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	doc, err := openDocument(remaining[0], *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	opts := []split.Option{split.WithDepth(*depth), split.WithFrontMatter(*frontMatter)}
+	var sections []split.Section
+	if *dryRun {
+		sections, err = split.Sections(doc, opts...)
+	} else {
+		sections, err = split.BySections(doc, remaining[1], opts...)
+	}
+	for _, s := range sections {
+		name := s.Path
+		if name == "" {
+			name = s.Title
+		}
+		fmt.Printf("pages %d-%d\t%s\n", s.FirstPage, s.LastPage, name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+func runViewCommand(args []string) {
+	fs := flag.NewFlagSet("view", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Browse the extracted text of a PDF file page by page.
+
+The viewer is line-based rather than a full-screen terminal UI, which
+keeps it free of terminal dependencies and lets commands be piped in. It
+prints a page and reads one command per line from standard input:
+
+  n, <enter>   next page            p            previous page
+  g <N>, <N>   go to page N         l            cycle layout mode
+  /<regexp>    search forward       /            repeat last search
+  h            help                 q            quit
+
+Usage:
+  crazypdf view [options] <input.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf view document.pdf
+  crazypdf view -page 12 -layout physical document.pdf
+`)
+	}
+
+	startPage := fs.Int("page", 1, "Page to show first")
+	layoutFlag := fs.String("layout", "simple", "Initial layout mode: simple, physical or raw")
+	password := fs.String("password", "", "Password for encrypted PDF")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: one input PDF file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	inputFile := remaining[0]
+
+	layout := -1
+	for i, m := range viewLayouts {
+		if m.name == *layoutFlag {
+			layout = i
+		}
+	}
+	if layout < 0 {
+		fmt.Fprintf(os.Stderr, "Error: unknown layout mode %q\n", *layoutFlag)
+		os.Exit(1)
+	}
+
+	doc, err := openDocument(inputFile, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	if *startPage < 1 || *startPage > doc.NumPages() {
+		fmt.Fprintf(os.Stderr, "Error: page %d out of range (document has %d pages)\n", *startPage, doc.NumPages())
+		os.Exit(1)
+	}
+
+	v := &viewer{
+		doc:    doc,
+		name:   inputFile,
+		page:   *startPage - 1,
+		layout: layout,
+		out:    os.Stdout,
+		ansi:   isTerminal(os.Stdout),
+		texts:  make(map[viewKey]string),
+	}
+	if err := v.run(os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// viewLayouts lists the layout modes the viewer cycles through.
+var viewLayouts = []struct {
+	name string
+	mode extract.LayoutMode
+}{
+	{"simple", extract.LayoutSimple},
+	{"physical", extract.LayoutPhysical},
+	{"raw", extract.LayoutRaw},
+}
+
+// viewKey identifies extracted text by page index and layout.
+type viewKey struct {
+	page, layout int
+}
+
+// viewer is the state of an interactive view session.
+type viewer struct {
+	doc    *crazypdf.Document
+	name   string
+	page   int // 0-based
+	layout int // index into viewLayouts
+	out    io.Writer
+	ansi   bool // clear the screen and highlight matches with escape codes
+
+	pattern *regexp.Regexp
+	status  string
+	texts   map[viewKey]string
+}
+
+// run shows the current page and executes commands read from in until
+// "q" or end of input.
+func (v *viewer) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		if err := v.show(); err != nil {
+			return err
+		}
+		fmt.Fprint(v.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(v.out)
+			return scanner.Err()
+		}
+		if quit := v.execute(strings.TrimSpace(scanner.Text())); quit {
+			return nil
+		}
+	}
+}
+
+// execute applies one command and reports whether the viewer should exit.
+func (v *viewer) execute(cmd string) bool {
+	v.status = ""
+	last := v.doc.NumPages() - 1
+
+	switch {
+	case cmd == "q" || cmd == "quit":
+		return true
+	case cmd == "" || cmd == "n":
+		if v.page < last {
+			v.page++
+		} else {
+			v.status = "already at the last page"
+		}
+	case cmd == "p":
+		if v.page > 0 {
+			v.page--
+		} else {
+			v.status = "already at the first page"
+		}
+	case cmd == "l":
+		v.layout = (v.layout + 1) % len(viewLayouts)
+	case cmd == "h" || cmd == "?":
+		v.status = "n next, p previous, g N go to page, l layout, /re search, / repeat, q quit"
+	case strings.HasPrefix(cmd, "/"):
+		v.search(cmd[1:])
+	default:
+		arg := strings.TrimSpace(strings.TrimPrefix(cmd, "g"))
+		n, err := strconv.Atoi(arg)
+		switch {
+		case err != nil:
+			v.status = fmt.Sprintf("unknown command %q (h for help)", cmd)
+		case n < 1 || n > last+1:
+			v.status = fmt.Sprintf("page %d out of range (document has %d pages)", n, last+1)
+		default:
+			v.page = n - 1
+		}
+	}
+	return false
+}
+
+// search moves to the next page, after the current one and wrapping
+// around, whose text matches expr. An empty expr repeats the last search.
+func (v *viewer) search(expr string) {
+	if expr != "" {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			v.status = fmt.Sprintf("invalid pattern: %v", err)
+			return
+		}
+		v.pattern = re
+	}
+	if v.pattern == nil {
+		v.status = "no previous search"
+		return
+	}
+
+	n := v.doc.NumPages()
+	for step := 1; step <= n; step++ {
+		idx := (v.page + step) % n
+		text, err := v.text(idx)
+		if err != nil {
+			v.status = fmt.Sprintf("error extracting page %d: %v", idx+1, err)
+			return
+		}
+		if v.pattern.MatchString(text) {
+			if idx <= v.page {
+				v.status = "search wrapped"
+			}
+			v.page = idx
+			return
+		}
+	}
+	v.status = fmt.Sprintf("pattern not found: %s", v.pattern)
+}
+
+// text returns the extracted text of a page in the current layout,
+// caching it so navigation back and forth stays fast.
+func (v *viewer) text(idx int) (string, error) {
+	key := viewKey{idx, v.layout}
+	if text, ok := v.texts[key]; ok {
+		return text, nil
+	}
+	page, err := v.doc.Page(idx)
+	if err != nil {
+		return "", err
+	}
+	text, err := extract.PageText(page, extract.WithLayout(viewLayouts[v.layout].mode))
+	if err != nil {
+		return "", err
+	}
+	v.texts[key] = text
+	return text, nil
+}
+
+// show draws the current page with a header and status line.
+func (v *viewer) show() error {
+	text, err := v.text(v.page)
+	if err != nil {
+		return fmt.Errorf("failed to extract page %d: %w", v.page+1, err)
+	}
+
+	if v.ansi {
+		fmt.Fprint(v.out, "\x1b[H\x1b[2J")
+	}
+	header := fmt.Sprintf("%s — page %d/%d — layout: %s", v.name, v.page+1, v.doc.NumPages(), viewLayouts[v.layout].name)
+	if v.pattern != nil {
+		header += fmt.Sprintf(" — search: %s (%d on page)", v.pattern, len(v.pattern.FindAllStringIndex(text, -1)))
+	}
+	fmt.Fprintln(v.out, header)
+	fmt.Fprintln(v.out, strings.Repeat("─", 72))
+
+	if v.ansi && v.pattern != nil {
+		text = v.pattern.ReplaceAllStringFunc(text, func(s string) string {
+			return "\x1b[7m" + s + "\x1b[0m"
+		})
+	}
+	fmt.Fprint(v.out, text)
+	if !strings.HasSuffix(text, "\n") {
+		fmt.Fprintln(v.out)
+	}
+
+	fmt.Fprintln(v.out, strings.Repeat("─", 72))
+	if v.status != "" {
+		fmt.Fprintln(v.out, v.status)
+	}
+	return nil
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
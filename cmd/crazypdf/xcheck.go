@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+// xcheckBackend is a way of extracting the text of a page.
+type xcheckBackend struct {
+	name    string
+	extract func(page *crazypdf.Page) (string, error)
+}
+
+// xcheckReference is the backend the others are compared with: the
+// glyphs of the content stream, before any grouping into rows or words.
+var xcheckReference = xcheckBackend{"glyphs", func(page *crazypdf.Page) (string, error) {
+	glyphs, err := page.Glyphs()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, g := range glyphs {
+		b.WriteString(g.S)
+	}
+	return b.String(), nil
+}}
+
+var xcheckBackends = []xcheckBackend{
+	{"simple", func(page *crazypdf.Page) (string, error) {
+		return extract.PageText(page, extract.WithLayout(extract.LayoutSimple))
+	}},
+	{"raw", func(page *crazypdf.Page) (string, error) {
+		return extract.PageText(page, extract.WithLayout(extract.LayoutRaw))
+	}},
+	{"layout", func(page *crazypdf.Page) (string, error) {
+		return extract.PageText(page, extract.WithLayout(extract.LayoutPhysical))
+	}},
+	{"words", func(page *crazypdf.Page) (string, error) {
+		words, err := page.Words()
+		if err != nil {
+			return "", err
+		}
+		var b strings.Builder
+		for _, w := range words {
+			b.WriteString(w.S)
+		}
+		return b.String(), nil
+	}},
+}
+
+// xcheckDivergence is how the text of one backend differs from the
+// reference on a page. Missing and Extra hold the characters, other than
+// whitespace, that the backend lost or added, in the order they occur.
+type xcheckDivergence struct {
+	Backend string `json:"backend"`
+	Missing string `json:"missing,omitempty"`
+	Extra   string `json:"extra,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// xcheckPage is the comparison of the backends on one page.
+type xcheckPage struct {
+	Page        int                `json:"page"`
+	Characters  int                `json:"characters"`
+	Divergences []xcheckDivergence `json:"divergences,omitempty"`
+}
+
+func runXcheckCommand(args []string) {
+	fs := flag.NewFlagSet("xcheck", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Extract the text of a PDF file with each extraction backend and report,
+page by page, the characters a backend loses or adds compared with the
+glyphs of the content stream. Whitespace and the order of the text are
+ignored.
+
+Backends: simple, raw, layout (the text command's default, -raw and
+-layout modes) and words (Page.Words).
+
+Usage:
+  crazypdf xcheck [options] <input.pdf>
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  crazypdf xcheck document.pdf
+  crazypdf xcheck -pages 1-3 document.pdf
+  crazypdf xcheck -json document.pdf > divergences.json
+`)
+	}
+
+	jsonOut := fs.Bool("json", false, "Output the divergences as JSON")
+	password := fs.String("password", "", "Password for encrypted PDF")
+	pagesFlag := fs.String("pages", "", "Page range (e.g., '1-5' or '1,3,5')")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	remaining := fs.Args()
+	if len(remaining) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: input PDF file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	doc, err := openDocument(remaining[0], *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening PDF: %v\n", err)
+		os.Exit(1)
+	}
+	defer doc.Close()
+
+	pageIndices, err := parsePageRange(*pagesFlag, doc.NumPages())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing page range: %v\n", err)
+		os.Exit(1)
+	}
+
+	var pages []xcheckPage
+	for _, pageIdx := range pageIndices {
+		page, err := doc.Page(pageIdx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error accessing page %d: %v\n", pageIdx+1, err)
+			os.Exit(1)
+		}
+		reference, err := xcheckReference.extract(page)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading glyphs of page %d: %v\n", pageIdx+1, err)
+			os.Exit(1)
+		}
+		result := xcheckPage{Page: pageIdx + 1, Characters: len(visibleRunes(reference))}
+		for _, backend := range xcheckBackends {
+			text, err := backend.extract(page)
+			if err != nil {
+				result.Divergences = append(result.Divergences, xcheckDivergence{Backend: backend.name, Error: err.Error()})
+				continue
+			}
+			missing, extra := runeDifference(reference, text)
+			if missing != "" || extra != "" {
+				result.Divergences = append(result.Divergences, xcheckDivergence{Backend: backend.name, Missing: missing, Extra: extra})
+			}
+		}
+		pages = append(pages, result)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(pages); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	diverged := 0
+	for _, p := range pages {
+		if len(p.Divergences) == 0 {
+			continue
+		}
+		diverged++
+		fmt.Printf("Page %d (%d characters):\n", p.Page, p.Characters)
+		for _, d := range p.Divergences {
+			if d.Error != "" {
+				fmt.Printf("  %-7s error: %s\n", d.Backend, d.Error)
+				continue
+			}
+			if d.Missing != "" {
+				fmt.Printf("  %-7s missing %d: %s\n", d.Backend, len([]rune(d.Missing)), abbreviate(d.Missing, 60))
+			}
+			if d.Extra != "" {
+				fmt.Printf("  %-7s extra %d: %s\n", d.Backend, len([]rune(d.Extra)), abbreviate(d.Extra, 60))
+			}
+		}
+	}
+	fmt.Printf("%d of %d pages diverge from the content stream glyphs\n", diverged, len(pages))
+}
+
+// visibleRunes returns the characters of s other than whitespace.
+func visibleRunes(s string) []rune {
+	var runes []rune
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// runeDifference compares the characters of two texts as multisets,
+// ignoring whitespace. It returns the characters of want that got lacks
+// and those of got that want lacks, each in the order of its text.
+func runeDifference(want, got string) (missing, extra string) {
+	counts := make(map[rune]int)
+	for _, r := range visibleRunes(got) {
+		counts[r]++
+	}
+	var m strings.Builder
+	for _, r := range visibleRunes(want) {
+		if counts[r] > 0 {
+			counts[r]--
+			continue
+		}
+		m.WriteRune(r)
+	}
+	var e strings.Builder
+	for _, r := range visibleRunes(got) {
+		if counts[r] > 0 {
+			counts[r]--
+			e.WriteRune(r)
+		}
+	}
+	return m.String(), e.String()
+}
+
+// abbreviate shortens s to at most n characters, marking the cut with an
+// ellipsis.
+func abbreviate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
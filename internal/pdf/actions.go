@@ -0,0 +1,235 @@
+package pdf
+
+// StrippedAction records an action removed by StripActions.
+type StrippedAction struct {
+	// Type is the action type, such as JavaScript or Launch.
+	Type Name
+
+	// Object is the number of the indirect object that referred to the
+	// action, such as a page, an annotation or the catalog.
+	Object int
+
+	// Key is the entry that held the action: A, OpenAction, Next, an
+	// additional-actions trigger such as O or K, or JavaScript for
+	// document-level scripts in the /Names tree.
+	Key Name
+}
+
+// StripActions removes every action of the given types reachable from the
+// catalog: open actions, the /A actions of annotations and outline items,
+// the /AA additional actions of the catalog, pages, annotations and form
+// fields, and actions chained through /Next. When JavaScript is among the
+// types, the document-level scripts of the /Names tree are removed too.
+// Everything else is left as it is.
+func (e *Editor) StripActions(types ...Name) []StrippedAction {
+	s := &actionStripper{e: e, types: make(map[Name]bool, len(types))}
+	for _, t := range types {
+		s.types[t] = true
+	}
+
+	catalog := e.Catalog()
+	if names, ok := e.Resolve(catalog["Names"]).(Dict); ok && s.types["JavaScript"] && names["JavaScript"] != nil {
+		root, _ := e.Trailer["Root"].(Ref)
+		tree, _ := e.Resolve(names["JavaScript"]).(Dict)
+		walkNameTree(tree, e.Resolve, 0, func(String, Object) {
+			s.stripped = append(s.stripped, StrippedAction{Type: "JavaScript", Object: root.Num, Key: "JavaScript"})
+		})
+		names = copyDict(names)
+		delete(names, "JavaScript")
+		ref, indirect := catalog["Names"].(Ref)
+		switch {
+		case len(names) == 0:
+			e.SetCatalogEntry("Names", nil)
+		case indirect:
+			e.Set(ref, names)
+		default:
+			e.SetCatalogEntry("Names", names)
+		}
+	}
+
+	seen := make(map[int]bool)
+	var queue []Ref
+	enqueue := func(r Ref) { queue = append(queue, r) }
+	collectRefs(e.Trailer["Root"], enqueue)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if seen[ref.Num] {
+			continue
+		}
+		seen[ref.Num] = true
+
+		obj := e.Object(ref.Num)
+		s.num = ref.Num
+		if out, changed := s.object(obj); changed {
+			e.Set(ref, out)
+			obj = out
+		}
+		collectRefs(obj, enqueue)
+	}
+	return s.stripped
+}
+
+// actionStripper removes actions from the objects of an Editor.
+type actionStripper struct {
+	e        *Editor
+	types    map[Name]bool
+	num      int // indirect object being processed
+	stripped []StrippedAction
+}
+
+// object returns o with matching actions removed from it and from the
+// dictionaries and arrays it contains directly. Referenced objects are
+// left to the caller. The second result reports whether o changed, in
+// which case the first is a copy.
+func (s *actionStripper) object(o Object) (Object, bool) {
+	switch v := o.(type) {
+	case Dict:
+		var out Dict
+		for _, k := range sortedKeys(v) {
+			var val Object
+			var changed bool
+			switch k {
+			case "A", "OpenAction":
+				if s.remove(v[k], k) {
+					changed = true
+				} else {
+					val, changed = s.object(v[k])
+				}
+			case "AA":
+				val, changed = s.triggers(v[k])
+			case "Next":
+				val, changed = s.next(v[k])
+			default:
+				val, changed = s.object(v[k])
+			}
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = copyDict(v)
+			}
+			if val == nil {
+				delete(out, k)
+			} else {
+				out[k] = val
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case Array:
+		var out Array
+		for i, el := range v {
+			val, changed := s.object(el)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = append(Array(nil), v...)
+			}
+			out[i] = val
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	}
+	return o, false
+}
+
+// remove reports whether o is an action of a stripped type, recording it
+// under key when it is.
+func (s *actionStripper) remove(o Object, key Name) bool {
+	action, ok := s.e.Resolve(o).(Dict)
+	if !ok {
+		return false
+	}
+	typ, _ := s.e.Resolve(action["S"]).(Name)
+	if !s.types[typ] {
+		return false
+	}
+	s.stripped = append(s.stripped, StrippedAction{Type: typ, Object: s.num, Key: key})
+	return true
+}
+
+// triggers filters an /AA additional-actions dictionary. It returns nil
+// when no trigger is left.
+func (s *actionStripper) triggers(o Object) (Object, bool) {
+	aa, ok := s.e.Resolve(o).(Dict)
+	if !ok {
+		return o, false
+	}
+	var out Dict
+	for _, trigger := range sortedKeys(aa) {
+		var val Object
+		var changed bool
+		if s.remove(aa[trigger], trigger) {
+			changed = true
+		} else {
+			val, changed = s.object(aa[trigger])
+		}
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = copyDict(aa)
+		}
+		if val == nil {
+			delete(out, trigger)
+		} else {
+			out[trigger] = val
+		}
+	}
+	switch {
+	case out == nil:
+		return o, false
+	case len(out) == 0:
+		return nil, true
+	}
+	return out, true
+}
+
+// next filters the /Next entry of an action, which holds one action or
+// an array of them. It returns nil when no action is left.
+func (s *actionStripper) next(o Object) (Object, bool) {
+	arr, ok := s.e.Resolve(o).(Array)
+	if !ok {
+		if s.remove(o, "Next") {
+			return nil, true
+		}
+		return s.object(o)
+	}
+	var out Array
+	changed := false
+	for _, el := range arr {
+		if s.remove(el, "Next") {
+			changed = true
+			continue
+		}
+		val, c := s.object(el)
+		changed = changed || c
+		out = append(out, val)
+	}
+	switch {
+	case !changed:
+		return o, false
+	case len(out) == 0:
+		return nil, true
+	}
+	return out, true
+}
+
+// DocumentScripts returns the names of the document-level JavaScript
+// actions in the catalog's /Names /JavaScript tree, in tree order. Viewers
+// run them when the document is opened.
+func DocumentScripts(catalog Dict, resolve func(Object) Object) []string {
+	names, _ := resolve(catalog["Names"]).(Dict)
+	tree, _ := resolve(names["JavaScript"]).(Dict)
+	var scripts []string
+	walkNameTree(tree, resolve, 0, func(name String, _ Object) {
+		scripts = append(scripts, DecodeTextString(name))
+	})
+	return scripts
+}
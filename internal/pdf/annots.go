@@ -0,0 +1,121 @@
+package pdf
+
+import "time"
+
+// Annot is an annotation of a page (PDF 32000-1:2008, 12.5).
+type Annot struct {
+	// Index is the position of the annotation in the page's /Annots
+	// array.
+	Index int
+
+	Subtype Name
+	Rect    Rect
+
+	// Contents is the text of the annotation, such as the comment of a
+	// Text (sticky note) or FreeText annotation.
+	Contents string
+
+	// Author is the /T entry of markup annotations and Subject their
+	// /Subj. Name is the annotation name (/NM).
+	Author  string
+	Subject string
+	Name    string
+
+	Modified, Created time.Time
+
+	// Color is the /C color: 1 (gray), 3 (RGB) or 4 (CMYK) components.
+	Color []float64
+
+	// Flags is the /F annotation flags bit field.
+	Flags int
+
+	// QuadPoints are the /QuadPoints of text markup and link annotations.
+	QuadPoints []Quad
+
+	// ReplyTo is the Index of the annotation this one replies to (/IRT),
+	// or -1.
+	ReplyTo int
+
+	// URI is the target of a URI action, and Dest the destination of a
+	// /Dest entry or GoTo action, to be resolved with ResolveDest.
+	URI  string
+	Dest Object
+}
+
+// PageAnnots returns the annotations of a page in /Annots order.
+func (f *File) PageAnnots(page Dict) []Annot {
+	entries, _ := f.Resolve(page["Annots"]).(Array)
+
+	// map annotation object numbers to indices for /IRT
+	indices := make(map[int]int)
+	for i, a := range entries {
+		if ref, ok := a.(Ref); ok {
+			indices[ref.Num] = i
+		}
+	}
+
+	var annots []Annot
+	for i, a := range entries {
+		d, ok := f.Resolve(a).(Dict)
+		if !ok {
+			continue
+		}
+		annot := readAnnot(d, f.Resolve)
+		annot.Index = i
+		if ref, ok := d["IRT"].(Ref); ok {
+			if j, ok := indices[ref.Num]; ok {
+				annot.ReplyTo = j
+			}
+		}
+		annots = append(annots, annot)
+	}
+	return annots
+}
+
+// readAnnot reads the entries of an annotation dictionary.
+func readAnnot(d Dict, resolve func(Object) Object) Annot {
+	text := func(key Name) string {
+		s, _ := resolve(d[key]).(String)
+		return DecodeTextString(s)
+	}
+	a := Annot{
+		Contents: text("Contents"),
+		Author:   text("T"),
+		Subject:  text("Subj"),
+		Name:     text("NM"),
+		ReplyTo:  -1,
+		Dest:     d["Dest"],
+	}
+	a.Subtype, _ = resolve(d["Subtype"]).(Name)
+	a.Rect, _ = rectFromObject(resolve(d["Rect"]), resolve)
+	if s := text("M"); s != "" {
+		a.Modified, _ = ParseDate(s)
+	}
+	if s := text("CreationDate"); s != "" {
+		a.Created, _ = ParseDate(s)
+	}
+	if c, ok := resolve(d["C"]).(Array); ok {
+		a.Color, _ = toFloats(resolveAll(c, resolve))
+	}
+	if flags, ok := resolve(d["F"]).(int64); ok {
+		a.Flags = int(flags)
+	}
+	if qp, ok := resolve(d["QuadPoints"]).(Array); ok {
+		if vals, ok := toFloats(resolveAll(qp, resolve)); ok {
+			for i := 0; i+8 <= len(vals); i += 8 {
+				a.QuadPoints = append(a.QuadPoints, Quad(vals[i:i+8]))
+			}
+		}
+	}
+	if action, ok := resolve(d["A"]).(Dict); ok {
+		switch kind, _ := resolve(action["S"]).(Name); kind {
+		case "URI":
+			if uri, ok := resolve(action["URI"]).(String); ok {
+				a.URI = string(uri)
+			}
+		case "GoTo":
+			a.Dest = action["D"]
+		}
+	}
+	return a
+}
@@ -0,0 +1,122 @@
+package pdf
+
+// Appearance is the normal appearance stream of an annotation: a form
+// XObject that viewers draw in the annotation rectangle.
+type Appearance struct {
+	// Index is the position of the annotation in the page's /Annots
+	// array.
+	Index int
+
+	Subtype Name
+
+	// Name is the annotation name (/NM), if any.
+	Name string
+
+	// State is the appearance state (/AS) selecting the stream among the
+	// annotation's states, such as On or Off for a check box.
+	State Name
+
+	// Rect is the annotation rectangle on the page.
+	Rect Rect
+
+	// Form is the appearance stream and BBox and Matrix its bounding box
+	// and form matrix. Placement maps the bounding box, transformed by
+	// Matrix, onto Rect.
+	Form      *Stream
+	BBox      Rect
+	Matrix    Matrix
+	Placement Matrix
+
+	// ref is the appearance entry as stored, usually a reference to Form.
+	ref Object
+}
+
+// PageAppearances returns the normal appearance of every annotation on a
+// page that has one, in /Annots order. Annotations without an appearance
+// stream, or with an empty bounding box, are skipped.
+func (f *File) PageAppearances(page Dict) []Appearance {
+	annots, _ := f.Resolve(page["Annots"]).(Array)
+	var out []Appearance
+	for i, a := range annots {
+		annot, ok := f.Resolve(a).(Dict)
+		if !ok {
+			continue
+		}
+		app, ok := annotAppearance(annot, f.Resolve)
+		if !ok {
+			continue
+		}
+		app.Index = i
+		out = append(out, app)
+	}
+	return out
+}
+
+// AppearanceDrawing interprets an appearance stream placed in its
+// annotation rectangle and returns the paths and images it paints, in
+// page user space. Text is not included.
+func (f *File) AppearanceDrawing(app Appearance) []DrawItem {
+	d := &drawer{resolve: f.Resolve}
+	d.form(app.Form, nil, drawState{
+		ctm:         app.Placement,
+		fillAlpha:   1,
+		strokeAlpha: 1,
+		lineWidth:   1,
+	}, 0)
+	return d.items
+}
+
+// annotAppearance returns the normal appearance of an annotation with the
+// matrix that maps it onto the annotation rectangle (PDF 32000-1:2008,
+// 12.5.5). It returns false when the annotation has no drawable
+// appearance.
+func annotAppearance(annot Dict, resolve func(Object) Object) (Appearance, bool) {
+	ap, _ := resolve(annot["AP"]).(Dict)
+	normal := ap["N"]
+	state, _ := resolve(annot["AS"]).(Name)
+	if states, ok := resolve(normal).(Dict); ok {
+		normal = states[state]
+	} else {
+		state = ""
+	}
+	stream, ok := resolve(normal).(*Stream)
+	if !ok {
+		return Appearance{}, false
+	}
+	rect, ok := rectFromObject(resolve(annot["Rect"]), resolve)
+	if !ok {
+		return Appearance{}, false
+	}
+	bbox, ok := rectFromObject(resolve(stream.Dict["BBox"]), resolve)
+	if !ok {
+		return Appearance{}, false
+	}
+	formMatrix := identityMatrix
+	if arr, ok := resolve(stream.Dict["Matrix"]).(Array); ok {
+		if vals, ok := toFloats(resolveAll(arr, resolve)); ok && len(vals) == 6 {
+			formMatrix = Matrix{vals[0], vals[1], vals[2], vals[3], vals[4], vals[5]}
+		}
+	}
+	box := formMatrix.TransformRect(bbox)
+	if box.Width() == 0 || box.Height() == 0 {
+		return Appearance{}, false
+	}
+	sx, sy := rect.Width()/box.Width(), rect.Height()/box.Height()
+
+	subtype, _ := resolve(annot["Subtype"]).(Name)
+	var name string
+	if s, ok := resolve(annot["NM"]).(String); ok {
+		name = DecodeTextString(s)
+	}
+	return Appearance{
+		Subtype:   subtype,
+		Name:      name,
+		State:     state,
+		Rect:      rect,
+		Form:      stream,
+		BBox:      bbox,
+		Matrix:    formMatrix,
+		Placement: Matrix{sx, 0, 0, sy, rect.X0 - box.X0*sx, rect.Y0 - box.Y0*sy},
+		ref:       normal,
+	}, true
+}
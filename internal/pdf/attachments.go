@@ -0,0 +1,91 @@
+package pdf
+
+import "time"
+
+// EmbeddedFile is an entry of the document's /EmbeddedFiles name tree.
+type EmbeddedFile struct {
+	// Name is the key of the entry in the name tree.
+	Name string
+
+	// FileName is the file name from /UF, falling back to /F.
+	FileName    string
+	Description string
+
+	// Subtype is the MIME type of the embedded file stream, such as
+	// "text/xml", or "" when it is not given.
+	Subtype string
+
+	// Relationship is the /AFRelationship of the file to the document,
+	// such as Source, Data or Alternative (PDF 2.0 and PDF/A-3).
+	Relationship string
+
+	// Size is the uncompressed size from /Params, or -1 when it is not
+	// given.
+	Size int64
+
+	Created, Modified time.Time
+
+	// Stream is the embedded file stream, or nil when the file
+	// specification has none.
+	Stream *Stream
+}
+
+// EmbeddedFiles returns the entries of the catalog's /Names
+// /EmbeddedFiles tree in tree order.
+func EmbeddedFiles(catalog Dict, resolve func(Object) Object) []EmbeddedFile {
+	names, _ := resolve(catalog["Names"]).(Dict)
+	tree, _ := resolve(names["EmbeddedFiles"]).(Dict)
+	var files []EmbeddedFile
+	walkNameTree(tree, resolve, 0, func(name String, value Object) {
+		spec, ok := resolve(value).(Dict)
+		if !ok {
+			return
+		}
+		files = append(files, embeddedFile(DecodeTextString(name), spec, resolve))
+	})
+	return files
+}
+
+// embeddedFile reads a file specification dictionary.
+func embeddedFile(name string, spec Dict, resolve func(Object) Object) EmbeddedFile {
+	text := func(d Dict, key Name) string {
+		s, _ := resolve(d[key]).(String)
+		return DecodeTextString(s)
+	}
+	f := EmbeddedFile{
+		Name:        name,
+		FileName:    text(spec, "UF"),
+		Description: text(spec, "Desc"),
+		Size:        -1,
+	}
+	if f.FileName == "" {
+		f.FileName = text(spec, "F")
+	}
+	if rel, ok := resolve(spec["AFRelationship"]).(Name); ok {
+		f.Relationship = string(rel)
+	}
+
+	ef, _ := resolve(spec["EF"]).(Dict)
+	stream, ok := resolve(ef["UF"]).(*Stream)
+	if !ok {
+		stream, _ = resolve(ef["F"]).(*Stream)
+	}
+	if stream == nil {
+		return f
+	}
+	f.Stream = stream
+	if subtype, ok := resolve(stream.Dict["Subtype"]).(Name); ok {
+		f.Subtype = string(subtype)
+	}
+	params, _ := resolve(stream.Dict["Params"]).(Dict)
+	if size, ok := resolve(params["Size"]).(int64); ok && size >= 0 {
+		f.Size = size
+	}
+	if s := text(params, "CreationDate"); s != "" {
+		f.Created, _ = ParseDate(s)
+	}
+	if s := text(params, "ModDate"); s != "" {
+		f.Modified, _ = ParseDate(s)
+	}
+	return f
+}
@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultRowCacheSize is the number of pages whose glyphs a Reader keeps
+// by default.
+const DefaultRowCacheSize = 16
+
+// rowCache is a least-recently-used cache of the glyphs of pages, from
+// which their text rows and words are built, keyed by page number. It is
+// safe for concurrent use. Cached glyphs are shared and must not be
+// modified.
+type rowCache struct {
+	mu       sync.Mutex
+	capacity int
+	maxBytes int64      // estimated size the cached glyphs may take; 0 for no limit
+	bytes    int64      // estimated size of the cached glyphs
+	order    *list.List // front is most recently used; values are *rowEntry
+	entries  map[int]*list.Element
+}
+
+// rowEntry is a cached page.
+type rowEntry struct {
+	page   int
+	glyphs []Glyph
+	size   int64
+}
+
+// glyphsSize estimates the memory the glyphs of a page take.
+func glyphsSize(glyphs []Glyph) int64 {
+	size := int64(0)
+	for _, g := range glyphs {
+		size += 80 + int64(len(g.S)+len(g.Font))
+	}
+	return size
+}
+
+// newRowCache returns a cache holding up to capacity pages.
+func newRowCache(capacity int) *rowCache {
+	return &rowCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[int]*list.Element),
+	}
+}
+
+// get returns the cached glyphs of a page.
+func (c *rowCache) get(page int) ([]Glyph, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[page]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*rowEntry).glyphs, true
+}
+
+// put stores the glyphs of a page, evicting the least recently used pages
+// beyond the capacity.
+func (c *rowCache) put(page int, glyphs []Glyph) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity <= 0 {
+		return
+	}
+	size := glyphsSize(glyphs)
+	if el, ok := c.entries[page]; ok {
+		entry := el.Value.(*rowEntry)
+		c.bytes += size - entry.size
+		entry.glyphs, entry.size = glyphs, size
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[page] = c.order.PushFront(&rowEntry{page: page, glyphs: glyphs, size: size})
+		c.bytes += size
+	}
+	c.trim()
+}
+
+// resize changes the capacity, evicting pages as needed. A capacity of 0
+// disables caching.
+func (c *rowCache) resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = capacity
+	c.trim()
+}
+
+// setMaxBytes sets the estimated size the cached glyphs may take, evicting
+// pages as needed. A size of 0 means no limit.
+func (c *rowCache) setMaxBytes(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = n
+	c.trim()
+}
+
+// trim evicts the least recently used pages beyond the capacity or the
+// size limit.
+func (c *rowCache) trim() {
+	for c.order.Len() > 0 && (c.order.Len() > c.capacity || (c.maxBytes > 0 && c.bytes > c.maxBytes)) {
+		el := c.order.Back()
+		c.order.Remove(el)
+		entry := el.Value.(*rowEntry)
+		delete(c.entries, entry.page)
+		c.bytes -= entry.size
+	}
+}
+
+// SetRowCacheSize sets how many pages' glyphs the Reader keeps, so that
+// repeated extractions from a page interpret its content only once. A
+// size of 0 disables caching. Default is DefaultRowCacheSize.
+func (r *Reader) SetRowCacheSize(n int) {
+	r.rows.resize(n)
+}
@@ -0,0 +1,105 @@
+package pdf
+
+// PageUsage summarizes what the content of a page paints.
+type PageUsage struct {
+	// ShowsText reports whether the content shows a non-empty string
+	// with a text operator (Tj, TJ, ' or ").
+	ShowsText bool
+
+	// Images are the images painted, image XObjects once each and inline
+	// images, with the entries of their dictionaries resolved. Stencil
+	// masks, painted as a color rather than an image, are left out.
+	Images []*Stream
+}
+
+// PageUsage scans the content of a page and the form XObjects it paints.
+func (f *File) PageUsage(page Dict) (PageUsage, error) {
+	var usage PageUsage
+	data, err := f.PageContent(page)
+	if err != nil {
+		return usage, err
+	}
+	resources, _ := f.Resolve(f.InheritedAttr(page, "Resources")).(Dict)
+	visited := make(map[int]bool)
+
+	addImage := func(s *Stream) {
+		if mask, _ := f.Resolve(s.Dict["ImageMask"]).(bool); mask {
+			return
+		}
+		resolved := &Stream{Dict: make(Dict, len(s.Dict)), Data: s.Data}
+		for k, v := range s.Dict {
+			resolved.Dict[k] = resolveDeep(v, f.Resolve, 4)
+		}
+		usage.Images = append(usage.Images, resolved)
+	}
+
+	var scan func(data []byte, resources Dict, depth int)
+	scan = func(data []byte, resources Dict, depth int) {
+		ops, _ := ParseContent(data)
+		for _, op := range ops {
+			switch op.Name {
+			case "Tj", "'", "\"", "TJ":
+				if len(op.Operands) > 0 && showsString(op.Operands[len(op.Operands)-1]) {
+					usage.ShowsText = true
+				}
+			case "BI":
+				if len(op.Operands) == 2 {
+					dict, _ := op.Operands[0].(Dict)
+					data, _ := op.Operands[1].(String)
+					addImage(&Stream{Dict: expandInlineImageDict(dict), Data: []byte(data)})
+				}
+			case "Do":
+				if len(op.Operands) != 1 {
+					continue
+				}
+				name, _ := op.Operands[0].(Name)
+				xobjects, _ := f.Resolve(resources["XObject"]).(Dict)
+				if r, ok := xobjects[name].(Ref); ok {
+					if visited[r.Num] {
+						continue
+					}
+					visited[r.Num] = true
+				}
+				xobj, ok := f.Resolve(xobjects[name]).(*Stream)
+				if !ok {
+					continue
+				}
+				switch f.Resolve(xobj.Dict["Subtype"]) {
+				case Name("Image"):
+					addImage(xobj)
+				case Name("Form"):
+					if depth >= maxFormDepth {
+						continue
+					}
+					formData, err := f.DecodeStream(xobj)
+					if err != nil {
+						continue
+					}
+					formResources, ok := f.Resolve(xobj.Dict["Resources"]).(Dict)
+					if !ok {
+						formResources = resources
+					}
+					scan(formData, formResources, depth+1)
+				}
+			}
+		}
+	}
+	scan(data, resources, 0)
+	return usage, nil
+}
+
+// showsString reports whether the operand of a text-showing operator
+// holds a non-empty string.
+func showsString(o Object) bool {
+	switch v := o.(type) {
+	case String:
+		return len(v) > 0
+	case Array:
+		for _, e := range v {
+			if s, ok := e.(String); ok && len(s) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
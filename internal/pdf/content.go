@@ -0,0 +1,39 @@
+package pdf
+
+import "bytes"
+
+// SerializeContent writes a sequence of operations back to content stream
+// syntax, one operation per line. It is the inverse of ParseContent.
+func SerializeContent(ops []Op) []byte {
+	var buf bytes.Buffer
+	for _, op := range ops {
+		if op.Name == "BI" && len(op.Operands) == 2 {
+			writeInlineImage(&buf, op)
+			continue
+		}
+		for _, operand := range op.Operands {
+			writeObject(&buf, operand, nil)
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(op.Name)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// writeInlineImage writes a BI operation as BI <entries> ID <data> EI.
+func writeInlineImage(buf *bytes.Buffer, op Op) {
+	dict, _ := op.Operands[0].(Dict)
+	data, _ := op.Operands[1].(String)
+
+	buf.WriteString("BI")
+	for _, k := range sortedKeys(dict) {
+		buf.WriteByte(' ')
+		writeName(buf, k)
+		buf.WriteByte(' ')
+		writeObject(buf, dict[k], nil)
+	}
+	buf.WriteString(" ID ")
+	buf.WriteString(string(data))
+	buf.WriteString("\nEI\n")
+}
@@ -0,0 +1,432 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// ErrPassword indicates the password opens neither as the user nor as
+// the owner password of an encrypted document.
+var ErrPassword = errors.New("incorrect password")
+
+// passwordPad pads passwords to 32 bytes (PDF 32000-1:2008, 7.6.3.3).
+var passwordPad = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// cryptMethod is how strings or streams are encrypted.
+type cryptMethod int
+
+const (
+	cryptNone cryptMethod = iota
+	cryptRC4
+	cryptAESV2 // AES-128 with per-object keys
+	cryptAESV3 // AES-256 with the file key
+)
+
+// securityHandler decrypts the strings and streams of a document
+// encrypted with the standard security handler.
+type securityHandler struct {
+	key             []byte
+	strings         cryptMethod
+	streams         cryptMethod
+	encryptMetadata bool
+	encryptNum      int // the encryption dictionary, which is not encrypted
+}
+
+// Decrypt prepares f for reading an encrypted document: objects loaded
+// afterwards have their strings and streams decrypted. password may be
+// the user or the owner password; the empty password opens documents that
+// only restrict permissions. RC4 (40 to 128 bit), AES-128 and AES-256
+// (revisions 2 to 6 of the standard security handler) are supported;
+// passwords of revisions 5 and 6 are UTF-8 and prepared with saslPrep. It
+// returns ErrPassword when the password is wrong.
+func (f *File) Decrypt(password string) error {
+	encRef, _ := f.trailer["Encrypt"].(Ref)
+	enc, ok := f.Resolve(f.trailer["Encrypt"]).(Dict)
+	if !ok {
+		return fmt.Errorf("missing encryption dictionary")
+	}
+	info := encryptionInfo(enc, f.Resolve)
+	if info.Filter != "Standard" {
+		return fmt.Errorf("unsupported security handler %s", info.Filter)
+	}
+
+	h := &securityHandler{encryptMetadata: info.EncryptMetadata, encryptNum: encRef.Num}
+	switch info.V {
+	case 1, 2:
+		h.strings, h.streams = cryptRC4, cryptRC4
+	case 4, 5:
+		h.strings = cryptFilterMethod(enc, "StrF", f.Resolve)
+		h.streams = cryptFilterMethod(enc, "StmF", f.Resolve)
+	default:
+		return fmt.Errorf("unsupported encryption version %d", info.V)
+	}
+
+	var err error
+	if info.R >= 5 {
+		h.key, err = aes256Key(enc, password, info.R, f.Resolve)
+	} else {
+		id, _ := f.Resolve(f.trailer["ID"]).(Array)
+		var first String
+		if len(id) > 0 {
+			first, _ = f.Resolve(id[0]).(String)
+		}
+		h.key, err = rc4Key(enc, info, []byte(first), password, f.Resolve)
+	}
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.security = h
+	f.cache = make(map[int]Object)
+	f.objStms = make(map[int]*objectStream)
+	f.mu.Unlock()
+	return nil
+}
+
+// Encryption returns the encryption dictionary of the file, or false when
+// it is not encrypted.
+func (f *File) Encryption() (EncryptionInfo, bool) {
+	enc, ok := f.Resolve(f.trailer["Encrypt"]).(Dict)
+	if !ok {
+		return EncryptionInfo{}, false
+	}
+	return encryptionInfo(enc, f.Resolve), true
+}
+
+// DecryptedCopy returns the document as an unencrypted PDF. The file
+// must have been prepared with Decrypt.
+func (f *File) DecryptedCopy() ([]byte, error) {
+	e, err := NewEditor(f)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cryptFilterMethod returns the method of the crypt filter named by the
+// StrF or StmF entry of an encryption dictionary.
+func cryptFilterMethod(enc Dict, key Name, resolve func(Object) Object) cryptMethod {
+	name, _ := resolve(enc[key]).(Name)
+	if name == "" || name == "Identity" {
+		return cryptNone
+	}
+	filters, _ := resolve(enc["CF"]).(Dict)
+	filter, _ := resolve(filters[name]).(Dict)
+	method, _ := resolve(filter["CFM"]).(Name)
+	switch method {
+	case "V2":
+		return cryptRC4
+	case "AESV2":
+		return cryptAESV2
+	case "AESV3":
+		return cryptAESV3
+	}
+	return cryptNone
+}
+
+// rc4Key computes the file key of revisions 2 to 4, trying password as
+// the user password and then as the owner password.
+func rc4Key(enc Dict, info EncryptionInfo, id []byte, password string, resolve func(Object) Object) ([]byte, error) {
+	o, _ := resolve(enc["O"]).(String)
+	u, _ := resolve(enc["U"]).(String)
+	if len(o) < 32 || len(u) < 32 {
+		return nil, fmt.Errorf("malformed encryption dictionary: missing O or U")
+	}
+	n := info.Length / 8
+	if info.R == 2 || n == 0 {
+		n = 5
+	}
+	if info.V >= 4 {
+		n = 16
+	}
+	if n < 5 || n > 16 {
+		return nil, fmt.Errorf("unsupported %d-bit key", n*8)
+	}
+
+	fileKey := func(padded []byte) []byte {
+		h := md5.New()
+		h.Write(padded)
+		h.Write([]byte(o[:32]))
+		p := uint32(info.P)
+		h.Write([]byte{byte(p), byte(p >> 8), byte(p >> 16), byte(p >> 24)})
+		h.Write(id)
+		if info.R >= 4 && !info.EncryptMetadata {
+			h.Write([]byte{0xff, 0xff, 0xff, 0xff})
+		}
+		key := h.Sum(nil)
+		if info.R >= 3 {
+			for i := 0; i < 50; i++ {
+				sum := md5.Sum(key[:n])
+				key = sum[:]
+			}
+		}
+		return key[:n]
+	}
+	userKey := func(padded []byte) ([]byte, bool) {
+		key := fileKey(padded)
+		if info.R == 2 {
+			return key, bytes.Equal(rc4Crypt(key, passwordPad), []byte(u[:32]))
+		}
+		h := md5.New()
+		h.Write(passwordPad)
+		h.Write(id)
+		check := rc4Rounds(key, h.Sum(nil), false)
+		return key, bytes.Equal(check, []byte(u[:16]))
+	}
+
+	if key, ok := userKey(padPassword([]byte(password))); ok {
+		return key, nil
+	}
+
+	// The owner password decrypts O to the padded user password
+	sum := md5.Sum(padPassword([]byte(password)))
+	ownerKey := sum[:]
+	if info.R >= 3 {
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(ownerKey[:n])
+			ownerKey = sum[:]
+		}
+	}
+	ownerKey = ownerKey[:n]
+	var userPassword []byte
+	if info.R == 2 {
+		userPassword = rc4Crypt(ownerKey, []byte(o[:32]))
+	} else {
+		userPassword = rc4Rounds(ownerKey, []byte(o[:32]), true)
+	}
+	if key, ok := userKey(userPassword); ok {
+		return key, nil
+	}
+	return nil, ErrPassword
+}
+
+// padPassword pads or truncates a password to 32 bytes.
+func padPassword(pw []byte) []byte {
+	return append(append([]byte{}, pw[:min(len(pw), 32)]...), passwordPad...)[:32]
+}
+
+// rc4Crypt encrypts or decrypts data with RC4.
+func rc4Crypt(key, data []byte) []byte {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		return nil
+	}
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+// rc4Rounds applies the 20 RC4 passes of revision 3 and later, each with
+// the key XORed with the pass number; reverse runs them from 19 down to 0.
+func rc4Rounds(key, data []byte, reverse bool) []byte {
+	k := make([]byte, len(key))
+	for pass := 0; pass < 20; pass++ {
+		i := pass
+		if reverse {
+			i = 19 - pass
+		}
+		for j := range key {
+			k[j] = key[j] ^ byte(i)
+		}
+		data = rc4Crypt(k, data)
+	}
+	return data
+}
+
+// aes256Key computes the file key of revisions 5 and 6, trying password
+// as the user password and then as the owner password.
+func aes256Key(enc Dict, password string, revision int, resolve func(Object) Object) ([]byte, error) {
+	o, _ := resolve(enc["O"]).(String)
+	u, _ := resolve(enc["U"]).(String)
+	oe, _ := resolve(enc["OE"]).(String)
+	ue, _ := resolve(enc["UE"]).(String)
+	if len(o) < 48 || len(u) < 48 || len(oe) < 32 || len(ue) < 32 {
+		return nil, fmt.Errorf("malformed encryption dictionary: missing O, U, OE or UE")
+	}
+
+	// Passwords are prepared with SASLprep; writers that skip that step
+	// hash the password as typed, so it is tried as well
+	candidates := []string{saslPrep(password)}
+	if candidates[0] != password {
+		candidates = append(candidates, password)
+	}
+	var intermediate, wrapped []byte
+	for _, candidate := range candidates {
+		pw := []byte(candidate)
+		if len(pw) > 127 {
+			pw = pw[:127]
+		}
+		switch {
+		case bytes.Equal(hashR6(pw, []byte(u[32:40]), nil, revision), []byte(u[:32])):
+			intermediate = hashR6(pw, []byte(u[40:48]), nil, revision)
+			wrapped = []byte(ue[:32])
+		case bytes.Equal(hashR6(pw, []byte(o[32:40]), []byte(u[:48]), revision), []byte(o[:32])):
+			intermediate = hashR6(pw, []byte(o[40:48]), []byte(u[:48]), revision)
+			wrapped = []byte(oe[:32])
+		default:
+			continue
+		}
+		break
+	}
+	if intermediate == nil {
+		return nil, ErrPassword
+	}
+
+	block, err := aes.NewCipher(intermediate)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(key, wrapped)
+	return key, nil
+}
+
+// saslPrep prepares a password of revision 5 or 6 with the SASLprep
+// profile of stringprep (RFC 4013), as ISO 32000-2, 7.6.4.3.3 requires:
+// non-ASCII spaces become U+0020 and characters commonly mapped to
+// nothing, such as soft hyphens and zero-width joiners, are removed. The
+// NFKC normalization step is not applied, so passwords are expected in
+// composed form, as keyboards and most writers produce them.
+func saslPrep(password string) string {
+	var b strings.Builder
+	for _, r := range password {
+		switch {
+		case r == 0x00A0 || r == 0x1680 || (r >= 0x2000 && r <= 0x200B) ||
+			r == 0x202F || r == 0x205F || r == 0x3000:
+			b.WriteRune(' ')
+		case r == 0x00AD || r == 0x034F || r == 0x1806 || (r >= 0x180B && r <= 0x180D) ||
+			r == 0x200C || r == 0x200D || r == 0x2060 || (r >= 0xFE00 && r <= 0xFE0F) ||
+			r == 0xFEFF:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// hashR6 is the password hash of revision 6 (ISO 32000-2, algorithm 2.B),
+// or the plain SHA-256 of revision 5.
+func hashR6(pw, salt, udata []byte, revision int) []byte {
+	h := sha256.New()
+	h.Write(pw)
+	h.Write(salt)
+	h.Write(udata)
+	k := h.Sum(nil)
+	if revision == 5 {
+		return k
+	}
+
+	for round := 0; ; round++ {
+		seq := make([]byte, 0, len(pw)+len(k)+len(udata))
+		seq = append(append(append(seq, pw...), k...), udata...)
+		k1 := bytes.Repeat(seq, 64)
+
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		sum := 0
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(e)
+		k = next.Sum(nil)
+
+		if round >= 63 && int(e[len(e)-1]) <= round-31 {
+			break
+		}
+	}
+	return k[:32]
+}
+
+// decryptObject decrypts the strings and stream data of an indirect
+// object loaded from the file. It modifies o, which must not be shared
+// yet.
+func (h *securityHandler) decryptObject(o Object, ref Ref) Object {
+	if ref.Num == h.encryptNum {
+		return o
+	}
+	switch v := o.(type) {
+	case String:
+		return String(h.decrypt(h.strings, []byte(v), ref))
+	case Array:
+		for i, el := range v {
+			v[i] = h.decryptObject(el, ref)
+		}
+	case Dict:
+		for k, el := range v {
+			v[k] = h.decryptObject(el, ref)
+		}
+	case *Stream:
+		typ, _ := v.Dict["Type"].(Name)
+		for k, el := range v.Dict {
+			v.Dict[k] = h.decryptObject(el, ref)
+		}
+		if typ == "XRef" || (typ == "Metadata" && !h.encryptMetadata) {
+			return v
+		}
+		v.Data = h.decrypt(h.streams, v.Data, ref)
+	}
+	return o
+}
+
+// decrypt decrypts the data of a string or stream of object ref.
+func (h *securityHandler) decrypt(method cryptMethod, data []byte, ref Ref) []byte {
+	if method == cryptNone {
+		return data
+	}
+	key := h.key
+	if method != cryptAESV3 {
+		m := md5.New()
+		m.Write(h.key)
+		m.Write([]byte{byte(ref.Num), byte(ref.Num >> 8), byte(ref.Num >> 16), byte(ref.Gen), byte(ref.Gen >> 8)})
+		if method == cryptAESV2 {
+			m.Write([]byte("sAlT"))
+		}
+		key = m.Sum(nil)[:min(len(h.key)+5, 16)]
+	}
+	if method == cryptRC4 {
+		return rc4Crypt(key, data)
+	}
+
+	// AES-CBC with the IV in the first block and PKCS#5 padding
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil
+	}
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	if pad := int(out[len(out)-1]); pad >= 1 && pad <= aes.BlockSize {
+		out = out[:len(out)-pad]
+	}
+	return out
+}
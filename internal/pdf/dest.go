@@ -0,0 +1,178 @@
+package pdf
+
+import (
+	"math"
+)
+
+// Dest is an explicit destination: a page and how it is fitted in the
+// viewer window.
+type Dest struct {
+	// Page is the 1-based page number.
+	Page int
+
+	// Fit is the fit type, such as XYZ, Fit or FitH.
+	Fit Name
+
+	// Params are the numeric parameters of the fit type, such as left,
+	// top and zoom for XYZ. NaN stands for null, which keeps the current
+	// value.
+	Params []float64
+}
+
+// ResolveDest resolves a destination to a page and fit. The destination
+// may be an explicit array, a name or string looked up in the catalog's
+// /Dests dictionary or /Names /Dests tree, or a dictionary holding the
+// array under /D. pageRefs lists the document's pages in order. It
+// returns false when the destination cannot be resolved to a page of the
+// document.
+func ResolveDest(catalog Dict, dest Object, pageRefs []Ref, resolve func(Object) Object) (Dest, bool) {
+	for depth := 0; depth < 8; depth++ {
+		switch v := resolve(dest).(type) {
+		case Name:
+			dests, _ := resolve(catalog["Dests"]).(Dict)
+			dest = dests[v]
+		case String:
+			names, _ := resolve(catalog["Names"]).(Dict)
+			tree, _ := resolve(names["Dests"]).(Dict)
+			dest = lookupNameTree(tree, string(v), resolve, 0)
+			if dest == nil {
+				// PDF 1.1 files keep string-named destinations in /Dests
+				dests, _ := resolve(catalog["Dests"]).(Dict)
+				dest = dests[Name(v)]
+			}
+		case Dict:
+			dest = v["D"]
+		case Array:
+			return explicitDest(v, pageRefs, resolve)
+		default:
+			return Dest{}, false
+		}
+	}
+	return Dest{}, false
+}
+
+// explicitDest converts a [page /Fit params...] array.
+func explicitDest(arr Array, pageRefs []Ref, resolve func(Object) Object) (Dest, bool) {
+	if len(arr) == 0 {
+		return Dest{}, false
+	}
+	var d Dest
+	switch p := arr[0].(type) {
+	case Ref:
+		for i, ref := range pageRefs {
+			if ref == p {
+				d.Page = i + 1
+				break
+			}
+		}
+	case int64:
+		// Remote destinations give a 0-based page index
+		d.Page = int(p) + 1
+	}
+	if d.Page < 1 || d.Page > len(pageRefs) {
+		return Dest{}, false
+	}
+	d.Fit = "Fit"
+	if len(arr) > 1 {
+		if fit, ok := resolve(arr[1]).(Name); ok {
+			d.Fit = fit
+		}
+	}
+	for _, o := range arr[min(2, len(arr)):] {
+		if v, ok := toFloat(resolve(o)); ok {
+			d.Params = append(d.Params, v)
+		} else {
+			d.Params = append(d.Params, math.NaN())
+		}
+	}
+	return d, true
+}
+
+// NamedDests returns the named destinations of a document that resolve
+// to one of its pages, from both the catalog's /Dests dictionary and the
+// /Names /Dests tree.
+func NamedDests(catalog Dict, pageRefs []Ref, resolve func(Object) Object) map[string]Dest {
+	out := make(map[string]Dest)
+	add := func(name string, dest Object) {
+		if d, ok := ResolveDest(catalog, dest, pageRefs, resolve); ok {
+			out[name] = d
+		}
+	}
+	if dests, ok := resolve(catalog["Dests"]).(Dict); ok {
+		for name, dest := range dests {
+			add(string(name), dest)
+		}
+	}
+	names, _ := resolve(catalog["Names"]).(Dict)
+	tree, _ := resolve(names["Dests"]).(Dict)
+	walkNameTree(tree, resolve, 0, func(name String, dest Object) {
+		add(string(name), dest)
+	})
+	return out
+}
+
+// DestArray builds an explicit destination array for a page object.
+// NaN parameters are written as null.
+func DestArray(page Ref, fit Name, params []float64) Array {
+	arr := Array{page, fit}
+	for _, v := range params {
+		if math.IsNaN(v) {
+			arr = append(arr, nil)
+		} else {
+			arr = append(arr, v)
+		}
+	}
+	return arr
+}
+
+// lookupNameTree finds key in a name tree, descending through /Kids
+// whose /Limits include the key.
+func lookupNameTree(node Dict, key string, resolve func(Object) Object, depth int) Object {
+	if node == nil || depth > 32 {
+		return nil
+	}
+	if names, ok := resolve(node["Names"]).(Array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			if name, ok := resolve(names[i]).(String); ok && string(name) == key {
+				return names[i+1]
+			}
+		}
+	}
+	kids, _ := resolve(node["Kids"]).(Array)
+	for _, kid := range kids {
+		child, ok := resolve(kid).(Dict)
+		if !ok {
+			continue
+		}
+		if limits, ok := resolve(child["Limits"]).(Array); ok && len(limits) == 2 {
+			lo, _ := resolve(limits[0]).(String)
+			hi, _ := resolve(limits[1]).(String)
+			if key < string(lo) || key > string(hi) {
+				continue
+			}
+		}
+		if v := lookupNameTree(child, key, resolve, depth+1); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// walkNameTree calls fn for every key and value of a name tree.
+func walkNameTree(node Dict, resolve func(Object) Object, depth int, fn func(String, Object)) {
+	if node == nil || depth > 32 {
+		return
+	}
+	if names, ok := resolve(node["Names"]).(Array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			if key, ok := resolve(names[i]).(String); ok {
+				fn(key, names[i+1])
+			}
+		}
+	}
+	kids, _ := resolve(node["Kids"]).(Array)
+	for _, kid := range kids {
+		child, _ := resolve(kid).(Dict)
+		walkNameTree(child, resolve, depth+1, fn)
+	}
+}
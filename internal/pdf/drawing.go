@@ -0,0 +1,651 @@
+package pdf
+
+import "math"
+
+// Segment is one step of a path in user space. M and L use the first two
+// coordinates, C uses all six (two control points and the end point) and
+// Z closes the current subpath.
+type Segment struct {
+	Op  byte
+	Pts [6]float64
+}
+
+// Clip is a clipping path. Clips nest: the effective clip region is the
+// intersection of a clip and all of its parents.
+type Clip struct {
+	Segments []Segment
+	EvenOdd  bool
+	Parent   *Clip
+}
+
+// DrawItem is a painted path or image with the graphics state it was
+// painted with, in painting order.
+type DrawItem struct {
+	// Image is set for images; Segments and the path style are unused.
+	Image bool
+
+	Segments []Segment
+	Fill     bool
+	Stroke   bool
+	EvenOdd  bool
+
+	// Colors are RGB components in [0, 1]. Patterns and shadings are
+	// approximated by a neutral gray.
+	FillColor   [3]float64
+	StrokeColor [3]float64
+	FillAlpha   float64
+	StrokeAlpha float64
+
+	// LineWidth and Dash are in user space; a zero width is the thinnest
+	// line the device can render.
+	LineWidth float64
+	LineCap   int
+	LineJoin  int
+	Dash      []float64
+
+	// ImageStream is the image XObject or, for inline images, a stream
+	// built from the inline image dictionary and data, with indirect
+	// objects in its dictionary resolved. Matrix maps the unit square
+	// onto the image's placement in user space.
+	ImageStream *Stream
+	Matrix      Matrix
+
+	Clip *Clip
+
+	// MCID is the marked-content identifier of the innermost page-level
+	// marked-content sequence enclosing the item, which links it to the
+	// structure tree, or -1 when it is in none.
+	MCID int
+}
+
+// BBox returns the bounding box of the area the item paints, ignoring
+// line widths and clipping. Curves are bounded by their control points.
+func (it DrawItem) BBox() Rect {
+	if it.Image {
+		return it.Matrix.TransformRect(Rect{X0: 0, Y0: 0, X1: 1, Y1: 1})
+	}
+	box := Rect{X0: math.Inf(1), Y0: math.Inf(1), X1: math.Inf(-1), Y1: math.Inf(-1)}
+	for _, seg := range it.Segments {
+		n := 1
+		switch seg.Op {
+		case 'C':
+			n = 3
+		case 'Z':
+			n = 0
+		}
+		for i := 0; i < n; i++ {
+			x, y := seg.Pts[2*i], seg.Pts[2*i+1]
+			box.X0, box.Y0 = math.Min(box.X0, x), math.Min(box.Y0, y)
+			box.X1, box.Y1 = math.Max(box.X1, x), math.Max(box.Y1, y)
+		}
+	}
+	if box.X0 > box.X1 {
+		return Rect{}
+	}
+	return box
+}
+
+// PageDrawing interprets the content of a page and returns the paths and
+// images it paints, including those drawn by form XObjects. Text is not
+// included; shading operators are ignored.
+func (f *File) PageDrawing(page Dict) ([]DrawItem, error) {
+	data, err := f.PageContent(page)
+	if err != nil {
+		return nil, err
+	}
+	resources, _ := f.Resolve(f.InheritedAttr(page, "Resources")).(Dict)
+
+	d := &drawer{resolve: f.Resolve, limit: f.limit}
+	d.run(data, resources, drawState{
+		ctm:         identityMatrix,
+		fillAlpha:   1,
+		strokeAlpha: 1,
+		lineWidth:   1,
+	}, 0)
+	return d.items, nil
+}
+
+// drawState is the subset of the graphics state tracked by drawer.
+type drawState struct {
+	ctm                    Matrix
+	fill, stroke           [3]float64
+	fillSpace, strokeSpace Object
+	fillAlpha, strokeAlpha float64
+	lineWidth              float64
+	lineCap, lineJoin      int
+	dash                   []float64
+	clip                   *Clip
+}
+
+// drawer interprets content streams into DrawItems.
+type drawer struct {
+	resolve func(Object) Object
+	limit   int64 // most bytes a form or lookup stream may decode to; 0 for no limit
+	items   []DrawItem
+	marked  []int // MCIDs of the open marked-content sequences, -1 for those without one
+}
+
+// mcid returns the MCID of the innermost open marked-content sequence
+// that has one, or -1.
+func (d *drawer) mcid() int {
+	for i := len(d.marked) - 1; i >= 0; i-- {
+		if d.marked[i] >= 0 {
+			return d.marked[i]
+		}
+	}
+	return -1
+}
+
+func (d *drawer) run(data []byte, resources Dict, gs drawState, depth int) {
+	ops, _ := ParseContent(data)
+	var stack []drawState
+	var path []Segment
+	var cx, cy float64 // current point in user space, for v
+	pendingClip, clipEvenOdd := false, false
+
+	pt := func(x, y float64) (float64, float64) { return gs.ctm.Apply(x, y) }
+
+	paint := func(fill, stroke, evenOdd bool) {
+		if len(path) > 0 && (fill || stroke) {
+			scale := math.Sqrt(math.Abs(gs.ctm[0]*gs.ctm[3] - gs.ctm[1]*gs.ctm[2]))
+			item := DrawItem{
+				Segments:    path,
+				Fill:        fill,
+				Stroke:      stroke,
+				EvenOdd:     evenOdd,
+				FillColor:   gs.fill,
+				StrokeColor: gs.stroke,
+				FillAlpha:   gs.fillAlpha,
+				StrokeAlpha: gs.strokeAlpha,
+				LineWidth:   gs.lineWidth * scale,
+				LineCap:     gs.lineCap,
+				LineJoin:    gs.lineJoin,
+				Clip:        gs.clip,
+				MCID:        d.mcid(),
+			}
+			for _, v := range gs.dash {
+				item.Dash = append(item.Dash, v*scale)
+			}
+			d.items = append(d.items, item)
+		}
+		if pendingClip && len(path) > 0 {
+			gs.clip = &Clip{Segments: path, EvenOdd: clipEvenOdd, Parent: gs.clip}
+		}
+		path, pendingClip = nil, false
+	}
+
+	for _, op := range ops {
+		nums, numeric := toFloats(op.Operands)
+		switch op.Name {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case "cm":
+			if numeric && len(nums) == 6 {
+				gs.ctm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}.Multiply(gs.ctm)
+			}
+		case "w":
+			if numeric && len(nums) == 1 {
+				gs.lineWidth = nums[0]
+			}
+		case "J":
+			if numeric && len(nums) == 1 {
+				gs.lineCap = int(nums[0])
+			}
+		case "j":
+			if numeric && len(nums) == 1 {
+				gs.lineJoin = int(nums[0])
+			}
+		case "d":
+			if len(op.Operands) == 2 {
+				arr, _ := op.Operands[0].(Array)
+				gs.dash, _ = toFloats(arr)
+			}
+		case "gs":
+			if len(op.Operands) == 1 {
+				name, _ := op.Operands[0].(Name)
+				states, _ := d.resolve(resources["ExtGState"]).(Dict)
+				d.extGState(&gs, states[name])
+			}
+
+		case "g":
+			if numeric && len(nums) == 1 {
+				gs.fill, gs.fillSpace = gray(nums[0]), nil
+			}
+		case "G":
+			if numeric && len(nums) == 1 {
+				gs.stroke, gs.strokeSpace = gray(nums[0]), nil
+			}
+		case "rg":
+			if numeric && len(nums) == 3 {
+				gs.fill, gs.fillSpace = [3]float64{nums[0], nums[1], nums[2]}, nil
+			}
+		case "RG":
+			if numeric && len(nums) == 3 {
+				gs.stroke, gs.strokeSpace = [3]float64{nums[0], nums[1], nums[2]}, nil
+			}
+		case "k":
+			if numeric && len(nums) == 4 {
+				gs.fill, gs.fillSpace = cmyk(nums), nil
+			}
+		case "K":
+			if numeric && len(nums) == 4 {
+				gs.stroke, gs.strokeSpace = cmyk(nums), nil
+			}
+		case "cs", "CS":
+			if len(op.Operands) != 1 {
+				continue
+			}
+			space := d.colorSpace(op.Operands[0], resources)
+			initial := d.color(space, nil)
+			if op.Name == "cs" {
+				gs.fillSpace, gs.fill = space, initial
+			} else {
+				gs.strokeSpace, gs.stroke = space, initial
+			}
+		case "sc", "scn":
+			gs.fill = d.color(gs.fillSpace, op.Operands)
+		case "SC", "SCN":
+			gs.stroke = d.color(gs.strokeSpace, op.Operands)
+
+		case "m":
+			if numeric && len(nums) == 2 {
+				x, y := pt(nums[0], nums[1])
+				path = append(path, Segment{Op: 'M', Pts: [6]float64{x, y}})
+				cx, cy = nums[0], nums[1]
+			}
+		case "l":
+			if numeric && len(nums) == 2 {
+				x, y := pt(nums[0], nums[1])
+				path = append(path, Segment{Op: 'L', Pts: [6]float64{x, y}})
+				cx, cy = nums[0], nums[1]
+			}
+		case "c", "v", "y":
+			var c [6]float64
+			switch {
+			case op.Name == "c" && numeric && len(nums) == 6:
+				copy(c[:], nums)
+			case op.Name == "v" && numeric && len(nums) == 4:
+				c = [6]float64{cx, cy, nums[0], nums[1], nums[2], nums[3]}
+			case op.Name == "y" && numeric && len(nums) == 4:
+				c = [6]float64{nums[0], nums[1], nums[2], nums[3], nums[2], nums[3]}
+			default:
+				continue
+			}
+			seg := Segment{Op: 'C'}
+			for i := 0; i < 6; i += 2 {
+				seg.Pts[i], seg.Pts[i+1] = pt(c[i], c[i+1])
+			}
+			path = append(path, seg)
+			cx, cy = c[4], c[5]
+		case "re":
+			if numeric && len(nums) == 4 {
+				x, y, w, h := nums[0], nums[1], nums[2], nums[3]
+				path = append(path, quadSegments(gs.ctm, [4][2]float64{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}})...)
+				cx, cy = x, y
+			}
+		case "h":
+			path = append(path, Segment{Op: 'Z'})
+
+		case "W", "W*":
+			pendingClip, clipEvenOdd = true, op.Name == "W*"
+		case "S":
+			paint(false, true, false)
+		case "s":
+			path = append(path, Segment{Op: 'Z'})
+			paint(false, true, false)
+		case "f", "F":
+			paint(true, false, false)
+		case "f*":
+			paint(true, false, true)
+		case "B", "B*":
+			paint(true, true, op.Name == "B*")
+		case "b", "b*":
+			path = append(path, Segment{Op: 'Z'})
+			paint(true, true, op.Name == "b*")
+		case "n":
+			paint(false, false, false)
+
+		case "BMC":
+			d.marked = append(d.marked, -1)
+		case "BDC":
+			// MCIDs in forms refer to the form's own structure parents;
+			// only those of the page content are tracked
+			id := -1
+			if depth == 0 && len(op.Operands) == 2 {
+				props, ok := d.resolve(op.Operands[1]).(Dict)
+				if name, isName := op.Operands[1].(Name); isName {
+					properties, _ := d.resolve(resources["Properties"]).(Dict)
+					props, ok = d.resolve(properties[name]).(Dict)
+				}
+				if n, isInt := d.resolve(props["MCID"]).(int64); ok && isInt {
+					id = int(n)
+				}
+			}
+			d.marked = append(d.marked, id)
+		case "EMC":
+			if n := len(d.marked); n > 0 {
+				d.marked = d.marked[:n-1]
+			}
+
+		case "BI":
+			if len(op.Operands) != 2 {
+				continue
+			}
+			dict, _ := op.Operands[0].(Dict)
+			data, _ := op.Operands[1].(String)
+			d.image(&Stream{Dict: expandInlineImageDict(dict), Data: []byte(data)}, gs)
+		case "Do":
+			if len(op.Operands) != 1 {
+				continue
+			}
+			name, _ := op.Operands[0].(Name)
+			xobjects, _ := d.resolve(resources["XObject"]).(Dict)
+			xobj, ok := d.resolve(xobjects[name]).(*Stream)
+			if !ok {
+				continue
+			}
+			switch xobj.Dict["Subtype"] {
+			case Name("Image"):
+				d.image(xobj, gs)
+			case Name("Form"):
+				if depth >= maxFormDepth {
+					continue
+				}
+				d.form(xobj, resources, gs, depth)
+			}
+		}
+	}
+}
+
+// form draws a form XObject clipped to its bounding box.
+func (d *drawer) form(xobj *Stream, parentResources Dict, gs drawState, depth int) {
+	data, err := DecodeStreamLimit(xobj, d.resolve, d.limit)
+	if err != nil {
+		return
+	}
+	if arr, ok := d.resolve(xobj.Dict["Matrix"]).(Array); ok {
+		if m, ok := toFloats(resolveAll(arr, d.resolve)); ok && len(m) == 6 {
+			gs.ctm = Matrix{m[0], m[1], m[2], m[3], m[4], m[5]}.Multiply(gs.ctm)
+		}
+	}
+	if bbox, ok := rectFromObject(xobj.Dict["BBox"], d.resolve); ok {
+		corners := [4][2]float64{{bbox.X0, bbox.Y0}, {bbox.X1, bbox.Y0}, {bbox.X1, bbox.Y1}, {bbox.X0, bbox.Y1}}
+		gs.clip = &Clip{Segments: quadSegments(gs.ctm, corners), Parent: gs.clip}
+	}
+	resources, ok := d.resolve(xobj.Dict["Resources"]).(Dict)
+	if !ok {
+		resources = parentResources
+	}
+	d.run(data, resources, gs, depth+1)
+}
+
+// image records an image painted in the unit square of the current CTM.
+// Stencil masks are painted in the fill color and are recorded as a
+// filled rectangle.
+func (d *drawer) image(s *Stream, gs drawState) {
+	if mask, _ := d.resolve(s.Dict["ImageMask"]).(bool); mask {
+		d.items = append(d.items, DrawItem{
+			Segments:  quadSegments(gs.ctm, [4][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}),
+			Fill:      true,
+			FillColor: gs.fill,
+			FillAlpha: gs.fillAlpha,
+			Clip:      gs.clip,
+			MCID:      d.mcid(),
+		})
+		return
+	}
+	resolved := &Stream{Dict: make(Dict, len(s.Dict)), Data: s.Data}
+	for k, v := range s.Dict {
+		resolved.Dict[k] = resolveDeep(v, d.resolve, 4)
+	}
+	d.items = append(d.items, DrawItem{
+		Image:       true,
+		ImageStream: resolved,
+		Matrix:      gs.ctm,
+		FillAlpha:   gs.fillAlpha,
+		Clip:        gs.clip,
+		MCID:        d.mcid(),
+	})
+}
+
+// extGState applies the line width and constant alpha entries of an
+// ExtGState dictionary.
+func (d *drawer) extGState(gs *drawState, o Object) {
+	state, ok := d.resolve(o).(Dict)
+	if !ok {
+		return
+	}
+	if v, ok := toFloat(d.resolve(state["LW"])); ok {
+		gs.lineWidth = v
+	}
+	if v, ok := toFloat(d.resolve(state["ca"])); ok {
+		gs.fillAlpha = v
+	}
+	if v, ok := toFloat(d.resolve(state["CA"])); ok {
+		gs.strokeAlpha = v
+	}
+}
+
+// colorSpace resolves a color space operand through the page's
+// ColorSpace resources. Device spaces are returned as names.
+func (d *drawer) colorSpace(o Object, resources Dict) Object {
+	if name, ok := o.(Name); ok {
+		switch name {
+		case "DeviceGray", "DeviceRGB", "DeviceCMYK", "Pattern":
+			return name
+		}
+		spaces, _ := d.resolve(resources["ColorSpace"]).(Dict)
+		if cs := d.resolve(spaces[name]); cs != nil {
+			return cs
+		}
+	}
+	return d.resolve(o)
+}
+
+// color converts color operands in a color space to RGB. With no
+// operands it returns the initial color of the space.
+func (d *drawer) color(space Object, operands []Object) [3]float64 {
+	comps, ok := toFloats(operands)
+	if !ok {
+		// A pattern name, possibly preceded by components
+		return [3]float64{0.5, 0.5, 0.5}
+	}
+
+	family, _ := space.(Name)
+	arr, _ := space.(Array)
+	if len(arr) > 0 {
+		family, _ = arr[0].(Name)
+	}
+	switch family {
+	case "Separation", "DeviceN":
+		// Tints darken from white; approximate with gray.
+		if len(comps) == 0 {
+			return [3]float64{0, 0, 0}
+		}
+		return gray(1 - comps[0])
+	case "Indexed":
+		if len(arr) == 4 && len(comps) == 1 {
+			return d.indexedColor(arr, int(comps[0]))
+		}
+	case "Pattern":
+		return [3]float64{0.5, 0.5, 0.5}
+	}
+
+	switch len(comps) {
+	case 0:
+		return [3]float64{0, 0, 0}
+	case 1:
+		return gray(comps[0])
+	case 3:
+		return [3]float64{comps[0], comps[1], comps[2]}
+	case 4:
+		return cmyk(comps)
+	}
+	return [3]float64{0, 0, 0}
+}
+
+// indexedColor looks up an entry of an /Indexed color space whose base
+// is an RGB, gray or CMYK space.
+func (d *drawer) indexedColor(space Array, index int) [3]float64 {
+	var lookup []byte
+	switch v := d.resolve(space[3]).(type) {
+	case String:
+		lookup = []byte(v)
+	case *Stream:
+		lookup, _ = DecodeStreamLimit(v, d.resolve, d.limit)
+	}
+	n := 3
+	switch base := d.resolve(space[1]).(type) {
+	case Name:
+		n = map[Name]int{"DeviceGray": 1, "DeviceRGB": 3, "DeviceCMYK": 4}[base]
+	case Array:
+		if len(base) == 2 {
+			if icc, ok := d.resolve(base[1]).(*Stream); ok {
+				if v, ok := toFloat(d.resolve(icc.Dict["N"])); ok {
+					n = int(v)
+				}
+			}
+		}
+	}
+	if n == 0 || (index+1)*n > len(lookup) || index < 0 {
+		return [3]float64{0, 0, 0}
+	}
+	comps := make([]float64, n)
+	for i := range comps {
+		comps[i] = float64(lookup[index*n+i]) / 255
+	}
+	switch n {
+	case 1:
+		return gray(comps[0])
+	case 4:
+		return cmyk(comps)
+	}
+	return [3]float64{comps[0], comps[1], comps[2]}
+}
+
+// resolveDeep resolves o and the indirect objects nested in it, down to
+// the given depth. Stream data is shared, not copied.
+func resolveDeep(o Object, resolve func(Object) Object, depth int) Object {
+	o = resolve(o)
+	if depth == 0 {
+		return o
+	}
+	switch v := o.(type) {
+	case Array:
+		arr := make(Array, len(v))
+		for i, el := range v {
+			arr[i] = resolveDeep(el, resolve, depth-1)
+		}
+		return arr
+	case Dict:
+		dict := make(Dict, len(v))
+		for k, el := range v {
+			dict[k] = resolveDeep(el, resolve, depth-1)
+		}
+		return dict
+	case *Stream:
+		dict := make(Dict, len(v.Dict))
+		for k, el := range v.Dict {
+			dict[k] = resolveDeep(el, resolve, depth-1)
+		}
+		return &Stream{Dict: dict, Data: v.Data}
+	}
+	return o
+}
+
+// quadSegments returns the closed path through four corners transformed
+// by m.
+func quadSegments(m Matrix, corners [4][2]float64) []Segment {
+	segs := make([]Segment, 0, 5)
+	for i, p := range corners {
+		seg := Segment{Op: 'L'}
+		if i == 0 {
+			seg.Op = 'M'
+		}
+		seg.Pts[0], seg.Pts[1] = m.Apply(p[0], p[1])
+		segs = append(segs, seg)
+	}
+	return append(segs, Segment{Op: 'Z'})
+}
+
+func gray(v float64) [3]float64 {
+	return [3]float64{v, v, v}
+}
+
+// cmyk converts CMYK components to RGB without color management.
+func cmyk(c []float64) [3]float64 {
+	k := c[3]
+	return [3]float64{
+		(1 - c[0]) * (1 - k),
+		(1 - c[1]) * (1 - k),
+		(1 - c[2]) * (1 - k),
+	}
+}
+
+// inlineImageKeys maps the abbreviated keys of inline image dictionaries
+// to their full names.
+var inlineImageKeys = map[Name]Name{
+	"BPC": "BitsPerComponent",
+	"CS":  "ColorSpace",
+	"D":   "Decode",
+	"DP":  "DecodeParms",
+	"F":   "Filter",
+	"H":   "Height",
+	"IM":  "ImageMask",
+	"I":   "Interpolate",
+	"W":   "Width",
+}
+
+// inlineImageValues maps abbreviated filter and color space names.
+var inlineImageValues = map[Name]Name{
+	"AHx":  "ASCIIHexDecode",
+	"A85":  "ASCII85Decode",
+	"LZW":  "LZWDecode",
+	"Fl":   "FlateDecode",
+	"RL":   "RunLengthDecode",
+	"CCF":  "CCITTFaxDecode",
+	"DCT":  "DCTDecode",
+	"G":    "DeviceGray",
+	"RGB":  "DeviceRGB",
+	"CMYK": "DeviceCMYK",
+	"I":    "Indexed",
+}
+
+// expandInlineImageDict returns an inline image dictionary with
+// abbreviated keys and names replaced by their full forms, so it can be
+// treated like an image XObject dictionary.
+func expandInlineImageDict(dict Dict) Dict {
+	out := Dict{"Type": Name("XObject"), "Subtype": Name("Image")}
+	expand := func(o Object) Object {
+		switch v := o.(type) {
+		case Name:
+			if full, ok := inlineImageValues[v]; ok {
+				return full
+			}
+		case Array:
+			arr := make(Array, len(v))
+			for i, e := range v {
+				arr[i] = e
+				if n, ok := e.(Name); ok {
+					if full, ok := inlineImageValues[n]; ok {
+						arr[i] = full
+					}
+				}
+			}
+			return arr
+		}
+		return o
+	}
+	for k, v := range dict {
+		if full, ok := inlineImageKeys[k]; ok {
+			k = full
+		}
+		out[k] = expand(v)
+	}
+	return out
+}
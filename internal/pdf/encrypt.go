@@ -0,0 +1,65 @@
+package pdf
+
+// EncryptionInfo holds the entries of a document's encryption dictionary.
+type EncryptionInfo struct {
+	Filter    string // security handler, usually Standard
+	SubFilter string
+	V         int // algorithm version
+	R         int // standard security handler revision
+	Length    int // key length in bits
+	P         int32
+
+	// StreamMethod is the crypt filter method used for streams with V 4
+	// and 5: V2 (RC4), AESV2, AESV3 or None.
+	StreamMethod string
+
+	// EncryptMetadata reports whether the XMP metadata stream is
+	// encrypted.
+	EncryptMetadata bool
+}
+
+// Encryption returns the encryption dictionary of the document, or false
+// when the document is not encrypted.
+func (r *Reader) Encryption() (EncryptionInfo, bool) {
+	if r.encryption == nil {
+		return EncryptionInfo{}, false
+	}
+	return *r.encryption, true
+}
+
+// encryptionInfo reads an encryption dictionary.
+func encryptionInfo(enc Dict, resolve func(Object) Object) EncryptionInfo {
+	name := func(key Name) string {
+		v, _ := resolve(enc[key]).(Name)
+		return string(v)
+	}
+	number := func(key Name) int64 {
+		v, _ := resolve(enc[key]).(int64)
+		return v
+	}
+	info := EncryptionInfo{
+		Filter:          name("Filter"),
+		SubFilter:       name("SubFilter"),
+		V:               int(number("V")),
+		R:               int(number("R")),
+		Length:          int(number("Length")),
+		P:               int32(number("P")),
+		EncryptMetadata: true,
+	}
+	if v, ok := resolve(enc["EncryptMetadata"]).(bool); ok {
+		info.EncryptMetadata = v
+	}
+	if info.V >= 4 {
+		stmF, _ := resolve(enc["StmF"]).(Name)
+		switch stmF {
+		case "", "Identity":
+			info.StreamMethod = "None"
+		default:
+			filters, _ := resolve(enc["CF"]).(Dict)
+			filter, _ := resolve(filters[stmF]).(Dict)
+			method, _ := resolve(filter["CFM"]).(Name)
+			info.StreamMethod = string(method)
+		}
+	}
+	return info
+}
@@ -0,0 +1,621 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrEncrypted indicates an operation requires decrypted access to an
+// encrypted document that the raw object layer cannot provide.
+var ErrEncrypted = errors.New("document is encrypted")
+
+// xrefEntry locates an indirect object in the file.
+type xrefEntry struct {
+	kind   int // 0 free, 1 in use at offset, 2 compressed in an object stream
+	offset int64
+	gen    int
+	stream int // object stream number for kind 2
+	index  int // index within the object stream for kind 2
+}
+
+// File is a low-level view of a PDF file's object graph. Unlike the
+// ledongthuc/pdf reader it exposes raw syntax, undecoded stream data and
+// object numbers, which are needed to rewrite documents. A File may be
+// read from several goroutines at once; parsed objects are shared and must
+// not be modified.
+type File struct {
+	data    []byte
+	version string
+	xref    map[int]xrefEntry
+	trailer Dict
+
+	// mu guards the caches of parsed objects and object streams.
+	mu      sync.RWMutex
+	cache   map[int]Object
+	objStms map[int]*objectStream
+
+	// xrefStreams records whether any cross-reference section is a stream.
+	xrefStreams bool
+
+	// sections counts the cross-reference sections in the /Prev chain,
+	// one per revision of the file.
+	sections int
+
+	// security decrypts objects as they are loaded once Decrypt has
+	// succeeded.
+	security *securityHandler
+
+	// limit is the most bytes a stream may decode to, or 0 for no limit.
+	limit int64
+}
+
+// objectStream is a decoded /Type /ObjStm stream.
+type objectStream struct {
+	data    []byte
+	offsets []int // offset of each object relative to data
+	nums    []int
+}
+
+// ParseFile parses the cross-reference structure of a PDF held in memory.
+// Objects are parsed lazily on first access.
+func ParseFile(data []byte) (*File, error) {
+	return ParseFileLimit(data, 0)
+}
+
+// ParseFileLimit is ParseFile with the memory limit of SetMemoryLimit
+// applied from the start, to the cross-reference streams too.
+func ParseFileLimit(data []byte, limit int64) (*File, error) {
+	f := &File{
+		data:    data,
+		xref:    make(map[int]xrefEntry),
+		cache:   make(map[int]Object),
+		objStms: make(map[int]*objectStream),
+		limit:   limit,
+	}
+
+	f.version = headerVersion(data)
+
+	start, err := findStartXref(data)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool)
+	for offset := start; offset >= 0; {
+		if seen[offset] {
+			break // cyclic /Prev chain
+		}
+		seen[offset] = true
+
+		trailer, err := f.parseXrefSection(offset)
+		if err != nil {
+			if f.trailer == nil {
+				return nil, fmt.Errorf("invalid cross-reference section at offset %d: %w", offset, err)
+			}
+			break
+		}
+		f.sections++
+		if f.trailer == nil {
+			f.trailer = trailer
+		}
+
+		// Hybrid-reference files point at an additional xref stream
+		if stm, ok := trailer["XRefStm"].(int64); ok && !seen[stm] {
+			seen[stm] = true
+			f.parseXrefSection(stm)
+		}
+
+		prev, ok := trailer["Prev"].(int64)
+		if !ok {
+			break
+		}
+		offset = prev
+	}
+	if f.trailer == nil {
+		return nil, fmt.Errorf("missing trailer")
+	}
+	return f, nil
+}
+
+// findStartXref locates the offset recorded after the last startxref keyword.
+func findStartXref(data []byte) (int64, error) {
+	i := bytes.LastIndex(data, []byte("startxref"))
+	if i < 0 {
+		return 0, fmt.Errorf("startxref not found")
+	}
+	l := newLexer(data)
+	l.pos = i + len("startxref")
+	obj, _, err := l.readObject()
+	if err != nil {
+		return 0, fmt.Errorf("invalid startxref: %w", err)
+	}
+	offset, ok := obj.(int64)
+	if !ok || offset < 0 || offset >= int64(len(data)) {
+		return 0, fmt.Errorf("invalid startxref offset")
+	}
+	return offset, nil
+}
+
+// parseXrefSection parses a classic table or cross-reference stream at
+// offset, recording entries not already defined by a newer section.
+func (f *File) parseXrefSection(offset int64) (Dict, error) {
+	if offset < 0 || offset >= int64(len(f.data)) {
+		return nil, fmt.Errorf("offset out of range")
+	}
+	l := newLexer(f.data)
+	l.pos = int(offset)
+	l.skipSpace()
+	if bytes.HasPrefix(f.data[l.pos:], []byte("xref")) {
+		l.pos += len("xref")
+		return f.parseXrefTable(l)
+	}
+	return f.parseXrefStream(l)
+}
+
+// parseXrefTable parses a classic "xref" table followed by its trailer.
+func (f *File) parseXrefTable(l *lexer) (Dict, error) {
+	for {
+		obj, kw, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		if kw == "trailer" {
+			break
+		}
+		first, ok := obj.(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid subsection header")
+		}
+		countObj, _, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		count, ok := countObj.(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid subsection count")
+		}
+		for i := int64(0); i < count; i++ {
+			offObj, _, err := l.readObject()
+			if err != nil {
+				return nil, err
+			}
+			genObj, _, err := l.readObject()
+			if err != nil {
+				return nil, err
+			}
+			_, kind, err := l.readObject()
+			if err != nil {
+				return nil, err
+			}
+			off, _ := offObj.(int64)
+			gen, _ := genObj.(int64)
+			num := int(first + i)
+			if _, exists := f.xref[num]; exists {
+				continue
+			}
+			if kind == "n" {
+				f.xref[num] = xrefEntry{kind: 1, offset: off, gen: int(gen)}
+			} else {
+				f.xref[num] = xrefEntry{kind: 0, gen: int(gen)}
+			}
+		}
+	}
+
+	obj, _, err := l.readObject()
+	if err != nil {
+		return nil, err
+	}
+	trailer, ok := obj.(Dict)
+	if !ok {
+		return nil, fmt.Errorf("invalid trailer")
+	}
+	return trailer, nil
+}
+
+// parseXrefStream parses a /Type /XRef stream object.
+func (f *File) parseXrefStream(l *lexer) (Dict, error) {
+	_, obj, err := f.parseIndirect(l)
+	if err != nil {
+		return nil, err
+	}
+	stm, ok := obj.(*Stream)
+	if !ok {
+		return nil, fmt.Errorf("expected cross-reference stream")
+	}
+	f.xrefStreams = true
+
+	data, err := DecodeStreamLimit(stm, nil, f.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	w, ok := toFloats(asArray(stm.Dict["W"]))
+	if !ok || len(w) != 3 {
+		return nil, fmt.Errorf("invalid /W in cross-reference stream")
+	}
+	widths := [3]int{int(w[0]), int(w[1]), int(w[2])}
+	rowLen := widths[0] + widths[1] + widths[2]
+	if rowLen <= 0 {
+		return nil, fmt.Errorf("invalid /W in cross-reference stream")
+	}
+
+	index, ok := toFloats(asArray(stm.Dict["Index"]))
+	if !ok || len(index) == 0 {
+		size, _ := stm.Dict["Size"].(int64)
+		index = []float64{0, float64(size)}
+	}
+
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		first, count := int(index[i]), int(index[i+1])
+		for j := 0; j < count && pos+rowLen <= len(data); j++ {
+			fields := [3]int64{}
+			p := pos
+			for k := 0; k < 3; k++ {
+				for b := 0; b < widths[k]; b++ {
+					fields[k] = fields[k]<<8 | int64(data[p])
+					p++
+				}
+			}
+			if widths[0] == 0 {
+				fields[0] = 1 // type defaults to 1 when omitted
+			}
+			pos += rowLen
+
+			num := first + j
+			if _, exists := f.xref[num]; exists {
+				continue
+			}
+			switch fields[0] {
+			case 0:
+				f.xref[num] = xrefEntry{kind: 0}
+			case 1:
+				f.xref[num] = xrefEntry{kind: 1, offset: fields[1], gen: int(fields[2])}
+			case 2:
+				f.xref[num] = xrefEntry{kind: 2, stream: int(fields[1]), index: int(fields[2])}
+			}
+		}
+	}
+	return stm.Dict, nil
+}
+
+// parseIndirect parses "num gen obj ... endobj" at the lexer position.
+func (f *File) parseIndirect(l *lexer) (Ref, Object, error) {
+	numObj, _, err := l.readObject()
+	if err != nil {
+		return Ref{}, nil, err
+	}
+	genObj, _, err := l.readObject()
+	if err != nil {
+		return Ref{}, nil, err
+	}
+	_, kw, err := l.readObject()
+	if err != nil {
+		return Ref{}, nil, err
+	}
+	num, ok1 := numObj.(int64)
+	gen, ok2 := genObj.(int64)
+	if !ok1 || !ok2 || kw != "obj" {
+		return Ref{}, nil, fmt.Errorf("expected indirect object header")
+	}
+	ref := Ref{Num: int(num), Gen: int(gen)}
+
+	obj, kw, err := l.readObject()
+	if err != nil {
+		return ref, nil, err
+	}
+	if kw == "endobj" {
+		return ref, nil, nil
+	}
+	if kw != "" && kw != "R" {
+		return ref, nil, fmt.Errorf("unexpected keyword %q in object %d", kw, num)
+	}
+
+	// Peek for a stream body
+	save := l.pos
+	tok, err := l.next()
+	if err != nil || tok.kind != tokKeyword || tok.keyword != "stream" {
+		l.pos = save
+		return ref, obj, nil
+	}
+	dict, ok := obj.(Dict)
+	if !ok {
+		return ref, nil, fmt.Errorf("stream without dictionary in object %d", num)
+	}
+
+	// The keyword is followed by CRLF or LF before the data
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+
+	length := -1
+	switch v := dict["Length"].(type) {
+	case int64:
+		length = int(v)
+	case Ref:
+		if v.Num != ref.Num {
+			if n, ok := f.Resolve(v).(int64); ok {
+				length = int(n)
+			}
+		}
+	}
+	end := start + length
+	if length < 0 || end > len(l.data) || !bytes.HasPrefix(bytes.TrimLeft(l.data[end:min(end+32, len(l.data))], "\r\n \t"), []byte("endstream")) {
+		// Missing or wrong /Length: scan for the endstream keyword
+		i := bytes.Index(l.data[start:], []byte("endstream"))
+		if i < 0 {
+			return ref, nil, fmt.Errorf("unterminated stream in object %d", num)
+		}
+		end = start + i
+		for end > start && (l.data[end-1] == '\n' || l.data[end-1] == '\r') {
+			end--
+		}
+	}
+	l.pos = end
+	return ref, &Stream{Dict: dict, Data: l.data[start:end]}, nil
+}
+
+// Version returns the version string from the file header, e.g. "1.7".
+func (f *File) Version() string {
+	return f.version
+}
+
+// Trailer returns the trailer dictionary of the newest revision.
+func (f *File) Trailer() Dict {
+	return f.trailer
+}
+
+// Data returns the underlying file bytes.
+func (f *File) Data() []byte {
+	return f.data
+}
+
+// UsesXrefStreams reports whether any cross-reference section is a
+// cross-reference stream (PDF 1.5+).
+func (f *File) UsesXrefStreams() bool {
+	return f.xrefStreams
+}
+
+// Sections returns the number of cross-reference sections in the file,
+// one for the original revision plus one per incremental update.
+func (f *File) Sections() int {
+	return f.sections
+}
+
+// Structure summarizes the file structure of a PDF.
+type Structure struct {
+	// HeaderVersion is the version in the %PDF header.
+	HeaderVersion string
+
+	// Objects is the number of in-use indirect objects, of which
+	// CompressedObjects are stored in object streams.
+	Objects           int
+	CompressedObjects int
+
+	// TrailerSize is the /Size entry of the newest trailer: one more than
+	// the highest object number.
+	TrailerSize int
+
+	XrefStreams bool
+	Sections    int
+
+	// ID is the file identifier pair from the trailer, hex encoded.
+	ID []string
+
+	FileSize int64
+}
+
+// Structure returns a summary of the file structure.
+func (f *File) Structure() Structure {
+	s := Structure{
+		HeaderVersion: f.version,
+		XrefStreams:   f.xrefStreams,
+		Sections:      f.sections,
+		FileSize:      int64(len(f.data)),
+	}
+	for num, e := range f.xref {
+		if e.kind == 0 || num == 0 {
+			continue
+		}
+		s.Objects++
+		if e.kind == 2 {
+			s.CompressedObjects++
+		}
+	}
+	if size, ok := f.trailer["Size"].(int64); ok {
+		s.TrailerSize = int(size)
+	}
+	if id, ok := f.Resolve(f.trailer["ID"]).(Array); ok {
+		for _, o := range id {
+			if v, ok := f.Resolve(o).(String); ok {
+				s.ID = append(s.ID, hex.EncodeToString([]byte(v)))
+			}
+		}
+	}
+	return s
+}
+
+// ObjectNumbers returns the numbers of all in-use objects, sorted.
+func (f *File) ObjectNumbers() []int {
+	nums := make([]int, 0, len(f.xref))
+	for num, e := range f.xref {
+		if e.kind != 0 && num > 0 {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// Object returns the indirect object with the given number, or nil when
+// it does not exist or cannot be parsed.
+func (f *File) Object(num int) (Object, error) {
+	f.mu.RLock()
+	obj, ok := f.cache[num]
+	f.mu.RUnlock()
+	if ok {
+		return obj, nil
+	}
+	entry, ok := f.xref[num]
+	if !ok || entry.kind == 0 {
+		return nil, nil
+	}
+
+	var err error
+	switch entry.kind {
+	case 1:
+		l := newLexer(f.data)
+		if entry.offset < 0 || entry.offset >= int64(len(f.data)) {
+			return nil, fmt.Errorf("object %d offset out of range", num)
+		}
+		l.pos = int(entry.offset)
+		var ref Ref
+		ref, obj, err = f.parseIndirect(l)
+		if err == nil && ref.Num != num {
+			err = fmt.Errorf("object %d: found object %d at recorded offset", num, ref.Num)
+		}
+		f.mu.RLock()
+		security := f.security
+		f.mu.RUnlock()
+		if err == nil && security != nil {
+			obj = security.decryptObject(obj, ref)
+		}
+	case 2:
+		obj, err = f.compressedObject(entry.stream, entry.index, num)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.cache[num] = obj
+	f.mu.Unlock()
+	return obj, nil
+}
+
+// compressedObject parses an object stored in an object stream.
+func (f *File) compressedObject(stmNum, index, num int) (Object, error) {
+	objStm, err := f.objectStream(stmNum)
+	if err != nil {
+		return nil, err
+	}
+	// Prefer the index, but fall back to a lookup by number
+	if index < 0 || index >= len(objStm.nums) || objStm.nums[index] != num {
+		index = -1
+		for i, n := range objStm.nums {
+			if n == num {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("object %d not found in object stream %d", num, stmNum)
+		}
+	}
+	l := newLexer(objStm.data)
+	l.pos = objStm.offsets[index]
+	obj, _, err := l.readObject()
+	return obj, err
+}
+
+// objectStream loads and indexes a /Type /ObjStm stream.
+func (f *File) objectStream(num int) (*objectStream, error) {
+	f.mu.RLock()
+	objStm, ok := f.objStms[num]
+	f.mu.RUnlock()
+	if ok {
+		return objStm, nil
+	}
+	obj, err := f.Object(num)
+	if err != nil {
+		return nil, err
+	}
+	stm, ok := obj.(*Stream)
+	if !ok {
+		return nil, fmt.Errorf("object %d is not an object stream", num)
+	}
+	data, err := f.DecodeStream(stm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode object stream %d: %w", num, err)
+	}
+	n, _ := f.Resolve(stm.Dict["N"]).(int64)
+	first, _ := f.Resolve(stm.Dict["First"]).(int64)
+
+	objStm = &objectStream{data: data}
+	l := newLexer(data)
+	for i := int64(0); i < n; i++ {
+		numObj, _, err1 := l.readObject()
+		offObj, _, err2 := l.readObject()
+		if err1 != nil || err2 != nil {
+			break
+		}
+		objNum, _ := numObj.(int64)
+		off, _ := offObj.(int64)
+		objStm.nums = append(objStm.nums, int(objNum))
+		objStm.offsets = append(objStm.offsets, int(first+off))
+	}
+	f.mu.Lock()
+	f.objStms[num] = objStm
+	f.mu.Unlock()
+	return objStm, nil
+}
+
+// headerVersion returns the version in the %PDF-x.y header of data, or ""
+// when there is none.
+func headerVersion(data []byte) string {
+	i := bytes.Index(data, []byte("%PDF-"))
+	if i < 0 || i+8 > len(data) {
+		return ""
+	}
+	end := i + 5
+	for end < len(data) && (data[end] == '.' || (data[end] >= '0' && data[end] <= '9')) {
+		end++
+	}
+	return string(data[i+5 : end])
+}
+
+// SetMemoryLimit sets the most bytes a stream of the file may decode to;
+// decoding a larger one fails with ErrResourceLimit. A limit of 0, the
+// default, means no limit. It must be set before the file is shared
+// between goroutines.
+func (f *File) SetMemoryLimit(limit int64) {
+	f.limit = limit
+}
+
+// DecodeStream decodes a stream of the file, resolving its filter
+// parameters and applying the memory limit.
+func (f *File) DecodeStream(s *Stream) ([]byte, error) {
+	return DecodeStreamLimit(s, f.Resolve, f.limit)
+}
+
+// Resolve follows indirect references until a direct object is reached.
+// Unresolvable references yield nil.
+func (f *File) Resolve(o Object) Object {
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := o.(Ref)
+		if !ok {
+			return o
+		}
+		obj, err := f.Object(ref.Num)
+		if err != nil {
+			return nil
+		}
+		o = obj
+	}
+	return nil
+}
+
+// asArray returns o as an Array, or nil.
+func asArray(o Object) []Object {
+	if a, ok := o.(Array); ok {
+		return a
+	}
+	return nil
+}
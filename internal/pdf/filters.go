@@ -0,0 +1,448 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnsupportedFilter indicates a stream uses a filter that this package
+// cannot decode, such as DCTDecode or JBIG2Decode image compression.
+var ErrUnsupportedFilter = errors.New("unsupported stream filter")
+
+// ErrResourceLimit indicates that decoding a stream would take more
+// memory than the limit set with SetMemoryLimit.
+var ErrResourceLimit = errors.New("crazypdf: resource limit exceeded")
+
+// limitError returns the error for data exceeding limit bytes.
+func limitError(limit int64) error {
+	return fmt.Errorf("%w: decoded data exceeds %d bytes", ErrResourceLimit, limit)
+}
+
+// DecodeStream applies the stream's filters to its raw data and returns
+// the decoded bytes. resolve is used to dereference indirect /Filter and
+// /DecodeParms entries and may be nil for direct objects only.
+func DecodeStream(s *Stream, resolve func(Object) Object) ([]byte, error) {
+	return DecodeStreamLimit(s, resolve, 0)
+}
+
+// DecodeStreamLimit is DecodeStream failing with ErrResourceLimit as soon
+// as the output of a filter exceeds limit bytes, before it is held in
+// full, so that a small stream cannot expand into gigabytes. A limit of 0
+// means no limit.
+func DecodeStreamLimit(s *Stream, resolve func(Object) Object, limit int64) ([]byte, error) {
+	if resolve == nil {
+		resolve = func(o Object) Object { return o }
+	}
+
+	filters := filterList(resolve(s.Dict["Filter"]), resolve)
+	params := paramList(resolve(s.Dict["DecodeParms"]), resolve, len(filters))
+
+	data := s.Data
+	for i, name := range filters {
+		var err error
+		data, err = applyDecodeFilter(name, data, params[i], resolve, limit)
+		if err == nil && limit > 0 && int64(len(data)) > limit {
+			err = limitError(limit)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return data, nil
+}
+
+// filterList normalizes a /Filter entry to a list of names.
+func filterList(f Object, resolve func(Object) Object) []Name {
+	switch v := f.(type) {
+	case Name:
+		return []Name{v}
+	case Array:
+		out := make([]Name, 0, len(v))
+		for _, el := range v {
+			if n, ok := resolve(el).(Name); ok {
+				out = append(out, n)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// paramList normalizes a /DecodeParms entry to one dictionary per filter.
+func paramList(p Object, resolve func(Object) Object, n int) []Dict {
+	out := make([]Dict, n)
+	switch v := p.(type) {
+	case Dict:
+		if n > 0 {
+			out[0] = v
+		}
+	case Array:
+		for i := 0; i < n && i < len(v); i++ {
+			if d, ok := resolve(v[i]).(Dict); ok {
+				out[i] = d
+			}
+		}
+	}
+	return out
+}
+
+// applyDecodeFilter decodes data with a single named filter.
+func applyDecodeFilter(name Name, data []byte, params Dict, resolve func(Object) Object, limit int64) ([]byte, error) {
+	switch name {
+	case "FlateDecode", "Fl":
+		out, err := inflate(data, limit)
+		if err != nil {
+			return nil, err
+		}
+		return applyPredictor(out, params, resolve)
+	case "LZWDecode", "LZW":
+		early := true
+		if v, ok := resolve(params["EarlyChange"]).(int64); ok && v == 0 {
+			early = false
+		}
+		out, err := lzwDecode(data, early, limit)
+		if err != nil {
+			return nil, err
+		}
+		return applyPredictor(out, params, resolve)
+	case "ASCIIHexDecode", "AHx":
+		return asciiHexDecode(data)
+	case "ASCII85Decode", "A85":
+		return ascii85Decode(data)
+	case "RunLengthDecode", "RL":
+		return runLengthDecode(data, limit)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, name)
+}
+
+// filtersSupported reports whether DecodeStream can apply every filter.
+func filtersSupported(filters []Name) bool {
+	for _, name := range filters {
+		switch name {
+		case "FlateDecode", "Fl", "LZWDecode", "LZW", "ASCIIHexDecode", "AHx",
+			"ASCII85Decode", "A85", "RunLengthDecode", "RL":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// inflate decompresses zlib data, falling back to raw deflate and
+// returning whatever could be recovered from truncated streams. It fails
+// with ErrResourceLimit once the output exceeds limit bytes, unless limit
+// is 0.
+func inflate(data []byte, limit int64) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err == nil {
+		out, err := readLimit(zr, limit)
+		zr.Close()
+		if err == nil || len(out) > 0 {
+			return out, nil
+		}
+		if errors.Is(err, ErrResourceLimit) {
+			return nil, err
+		}
+	}
+
+	fr := flate.NewReader(bytes.NewReader(data))
+	defer fr.Close()
+	out, err := readLimit(fr, limit)
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+// readLimit reads r to the end, failing with ErrResourceLimit once more
+// than limit bytes are read, unless limit is 0. On other errors the data
+// read so far is returned with the error.
+func readLimit(r io.Reader, limit int64) ([]byte, error) {
+	var buf bytes.Buffer
+	if limit <= 0 {
+		_, err := io.Copy(&buf, r)
+		return buf.Bytes(), err
+	}
+	n, err := io.CopyN(&buf, r, limit+1)
+	if n > limit {
+		return nil, limitError(limit)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return buf.Bytes(), err
+}
+
+// applyPredictor reverses PNG and TIFF predictors described by DecodeParms.
+func applyPredictor(data []byte, params Dict, resolve func(Object) Object) ([]byte, error) {
+	if params == nil {
+		return data, nil
+	}
+	intParam := func(key Name, def int) int {
+		if v, ok := resolve(params[key]).(int64); ok {
+			return int(v)
+		}
+		return def
+	}
+
+	predictor := intParam("Predictor", 1)
+	if predictor <= 1 {
+		return data, nil
+	}
+	colors := intParam("Colors", 1)
+	bpc := intParam("BitsPerComponent", 8)
+	columns := intParam("Columns", 1)
+
+	bpp := (colors*bpc + 7) / 8
+	rowLen := (colors*bpc*columns + 7) / 8
+
+	if predictor == 2 {
+		// TIFF predictor 2, supported for 8-bit components
+		if bpc != 8 {
+			return data, nil
+		}
+		out := append([]byte(nil), data...)
+		for row := 0; row+rowLen <= len(out); row += rowLen {
+			for i := bpp; i < rowLen; i++ {
+				out[row+i] += out[row+i-bpp]
+			}
+		}
+		return out, nil
+	}
+
+	// PNG predictors: each row is prefixed with a filter type byte
+	var out bytes.Buffer
+	prev := make([]byte, rowLen)
+	for pos := 0; pos < len(data); pos += rowLen + 1 {
+		if pos+1 > len(data) {
+			break
+		}
+		ft := data[pos]
+		end := pos + 1 + rowLen
+		if end > len(data) {
+			end = len(data)
+		}
+		cur := make([]byte, rowLen)
+		copy(cur, data[pos+1:end])
+		for i := 0; i < rowLen; i++ {
+			var left, up, upLeft byte
+			if i >= bpp {
+				left = cur[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up = prev[i]
+			switch ft {
+			case 1:
+				cur[i] += left
+			case 2:
+				cur[i] += up
+			case 3:
+				cur[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				cur[i] += paeth(left, up, upLeft)
+			}
+		}
+		out.Write(cur)
+		prev = cur
+	}
+	return out.Bytes(), nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(float64(p-int(a))), abs(float64(p-int(b))), abs(float64(p-int(c)))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+// asciiHexDecode decodes ASCIIHexDecode data up to the '>' terminator.
+func asciiHexDecode(data []byte) ([]byte, error) {
+	clean := make([]byte, 0, len(data))
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if isWhitespace(c) {
+			continue
+		}
+		clean = append(clean, c)
+	}
+	if len(clean)%2 == 1 {
+		clean = append(clean, '0')
+	}
+	out := make([]byte, len(clean)/2)
+	if _, err := hex.Decode(out, clean); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ascii85Decode decodes ASCII85Decode data up to the "~>" terminator.
+func ascii85Decode(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	var group [5]byte
+	n := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if isWhitespace(c) {
+			continue
+		}
+		if c == '~' {
+			break
+		}
+		if c == 'z' && n == 0 {
+			out.Write([]byte{0, 0, 0, 0})
+			continue
+		}
+		if c < '!' || c > 'u' {
+			return nil, fmt.Errorf("invalid ASCII85 byte %q", c)
+		}
+		group[n] = c - '!'
+		n++
+		if n == 5 {
+			v := uint32(0)
+			for _, g := range group {
+				v = v*85 + uint32(g)
+			}
+			out.Write([]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+			n = 0
+		}
+	}
+	if n > 1 {
+		for i := n; i < 5; i++ {
+			group[i] = 84
+		}
+		v := uint32(0)
+		for _, g := range group {
+			v = v*85 + uint32(g)
+		}
+		full := []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		out.Write(full[:n-1])
+	}
+	return out.Bytes(), nil
+}
+
+// runLengthDecode decodes RunLengthDecode data.
+func runLengthDecode(data []byte, limit int64) ([]byte, error) {
+	var out bytes.Buffer
+	for i := 0; i < len(data); {
+		if limit > 0 && int64(out.Len()) > limit {
+			return nil, limitError(limit)
+		}
+		n := int(data[i])
+		i++
+		switch {
+		case n == 128:
+			return out.Bytes(), nil
+		case n < 128:
+			end := i + n + 1
+			if end > len(data) {
+				end = len(data)
+			}
+			out.Write(data[i:end])
+			i = end
+		default:
+			if i < len(data) {
+				out.Write(bytes.Repeat(data[i:i+1], 257-n))
+			}
+			i++
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// lzwDecode decodes PDF LZW data, which uses MSB-first codes and, by
+// default, switches code width one code early.
+func lzwDecode(data []byte, earlyChange bool, limit int64) ([]byte, error) {
+	const clearCode, eodCode = 256, 257
+
+	var out bytes.Buffer
+	table := make([][]byte, 258, 4096)
+	reset := func() {
+		table = table[:258]
+		for i := 0; i < 256; i++ {
+			table[i] = []byte{byte(i)}
+		}
+	}
+	reset()
+
+	width := 9
+	var bitBuf uint32
+	bits := 0
+	var prev []byte
+	early := 0
+	if earlyChange {
+		early = 1
+	}
+
+	for pos := 0; ; {
+		for bits < width && pos < len(data) {
+			bitBuf = bitBuf<<8 | uint32(data[pos])
+			bits += 8
+			pos++
+		}
+		if bits < width {
+			break
+		}
+		code := int(bitBuf>>(bits-width)) & (1<<width - 1)
+		bits -= width
+
+		switch {
+		case code == clearCode:
+			reset()
+			width = 9
+			prev = nil
+			continue
+		case code == eodCode:
+			return out.Bytes(), nil
+		}
+
+		var entry []byte
+		switch {
+		case code < len(table):
+			entry = table[code]
+		case code == len(table) && prev != nil:
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return out.Bytes(), fmt.Errorf("invalid LZW code %d", code)
+		}
+		out.Write(entry)
+		if limit > 0 && int64(out.Len()) > limit {
+			return nil, limitError(limit)
+		}
+
+		if prev != nil && len(table) < 4096 {
+			table = append(table, append(append([]byte(nil), prev...), entry[0]))
+		}
+		prev = entry
+
+		switch {
+		case len(table)+early >= 2048:
+			width = 12
+		case len(table)+early >= 1024:
+			width = 11
+		case len(table)+early >= 512:
+			width = 10
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// deflate compresses data with zlib for FlateDecode streams.
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	zw, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,130 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Fingerprint returns a hex-encoded SHA-256 of the page content in a
+// canonical form: the page count and geometry, the content operations
+// with fonts named by their base font (without subset prefix) instead of
+// resource names, images by the hash of their decoded data and form
+// XObjects inlined. Object numbers, compression, incremental updates,
+// metadata and the trailer /ID do not contribute, so rewriting a file
+// with another tool leaves it unchanged while any change to what the
+// pages show changes it.
+func (f *File) Fingerprint() (string, error) {
+	refs, err := f.PageRefs()
+	if err != nil {
+		return "", err
+	}
+	fp := &fingerprinter{f: f, images: make(map[int]string)}
+	fmt.Fprintf(&fp.buf, "pages %d\n", len(refs))
+	for _, ref := range refs {
+		page, _ := f.Resolve(ref).(Dict)
+		box, _ := rectFromObject(f.Resolve(f.InheritedAttr(page, "MediaBox")), f.Resolve)
+		rotate, _ := f.Resolve(f.InheritedAttr(page, "Rotate")).(int64)
+		fmt.Fprintf(&fp.buf, "page %g %g %g %g %d\n", box.X0, box.Y0, box.X1, box.Y1, ((rotate%360)+360)%360)
+
+		data, err := f.PageContent(page)
+		if err != nil {
+			fp.buf.WriteString("undecodable\n")
+			continue
+		}
+		resources, _ := f.Resolve(f.InheritedAttr(page, "Resources")).(Dict)
+		fp.content(data, resources, 0)
+	}
+	sum := sha256.Sum256(fp.buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fingerprinter accumulates the canonical form of the pages. images
+// caches the hashes of image XObjects by object number.
+type fingerprinter struct {
+	f      *File
+	buf    bytes.Buffer
+	images map[int]string
+}
+
+// content writes the canonical form of a content stream.
+func (fp *fingerprinter) content(data []byte, resources Dict, depth int) {
+	ops, _ := ParseContent(data)
+	for _, op := range ops {
+		switch {
+		case op.Name == "Tf" && len(op.Operands) == 2:
+			name, _ := op.Operands[0].(Name)
+			fonts, _ := fp.f.Resolve(resources["Font"]).(Dict)
+			font, _ := fp.f.Resolve(fonts[name]).(Dict)
+			base, _ := fp.f.Resolve(font["BaseFont"]).(Name)
+			if i := strings.IndexByte(string(base), '+'); i == 6 {
+				base = base[i+1:]
+			}
+			fmt.Fprintf(&fp.buf, "font %s ", base)
+			writeObject(&fp.buf, op.Operands[1], nil)
+			fp.buf.WriteString(" Tf\n")
+		case op.Name == "Do" && len(op.Operands) == 1:
+			name, _ := op.Operands[0].(Name)
+			xobjects, _ := fp.f.Resolve(resources["XObject"]).(Dict)
+			fp.xobject(xobjects[name], resources, depth)
+		default:
+			fp.buf.Write(SerializeContent([]Op{op}))
+		}
+	}
+}
+
+// xobject writes the canonical form of a painted XObject.
+func (fp *fingerprinter) xobject(o Object, resources Dict, depth int) {
+	xobj, ok := fp.f.Resolve(o).(*Stream)
+	if !ok {
+		fp.buf.WriteString("missing Do\n")
+		return
+	}
+	switch fp.f.Resolve(xobj.Dict["Subtype"]) {
+	case Name("Image"):
+		fmt.Fprintf(&fp.buf, "image %s Do\n", fp.image(o, xobj))
+	case Name("Form"):
+		if depth >= maxFormDepth {
+			return
+		}
+		data, err := fp.f.DecodeStream(xobj)
+		if err != nil {
+			fp.buf.WriteString("undecodable form Do\n")
+			return
+		}
+		formResources, ok := fp.f.Resolve(xobj.Dict["Resources"]).(Dict)
+		if !ok {
+			formResources = resources
+		}
+		fp.buf.WriteString("form ")
+		writeObject(&fp.buf, resolveDeep(xobj.Dict["Matrix"], fp.f.Resolve, 2), nil)
+		fp.buf.WriteString(" {\n")
+		fp.content(data, formResources, depth+1)
+		fp.buf.WriteString("}\n")
+	}
+}
+
+// image returns the hash of an image's size and decoded data, or of its
+// encoded data when it uses a filter DecodeStream does not support.
+func (fp *fingerprinter) image(o Object, s *Stream) string {
+	ref, isRef := o.(Ref)
+	if sum, ok := fp.images[ref.Num]; isRef && ok {
+		return sum
+	}
+	h := sha256.New()
+	width, _ := fp.f.Resolve(s.Dict["Width"]).(int64)
+	height, _ := fp.f.Resolve(s.Dict["Height"]).(int64)
+	fmt.Fprintf(h, "%dx%d\n", width, height)
+	if data, err := fp.f.DecodeStream(s); err == nil {
+		h.Write(data)
+	} else {
+		h.Write(s.Data)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if isRef {
+		fp.images[ref.Num] = sum
+	}
+	return sum
+}
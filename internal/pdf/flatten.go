@@ -0,0 +1,161 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// FlattenStats counts the annotations handled by FlattenPage.
+type FlattenStats struct {
+	// Flattened is the number of annotations drawn into the page content
+	// and removed.
+	Flattened int
+
+	// Removed is the number of annotations removed without drawing:
+	// hidden annotations and the pop-ups of flattened annotations.
+	Removed int
+
+	// Skipped is the number of selected annotations kept because they
+	// have no appearance stream to draw.
+	Skipped int
+}
+
+// Annotation flags (PDF 32000-1:2008, table 165).
+const (
+	annotHidden = 1 << 1
+	annotNoView = 1 << 5
+)
+
+// FlattenPage draws the normal appearance of the page's annotations whose
+// subtype is selected by include into the page content and removes them.
+// Pop-up annotations belonging to a flattened annotation are removed with
+// it. The existing content is wrapped in q/Q so that the appearances are
+// drawn in the default graphics state.
+func (e *Editor) FlattenPage(ref Ref, include func(subtype Name) bool) (FlattenStats, error) {
+	var stats FlattenStats
+	page, ok := e.Resolve(ref).(Dict)
+	if !ok {
+		return stats, fmt.Errorf("object %d is not a page", ref.Num)
+	}
+	annots, _ := e.Resolve(page["Annots"]).(Array)
+	if len(annots) == 0 {
+		return stats, nil
+	}
+
+	type drawing struct {
+		form   Object
+		matrix Matrix
+	}
+	var draws []drawing
+	var kept, popups Array
+	flattened := make(map[Ref]bool)
+	for _, a := range annots {
+		annot, _ := e.Resolve(a).(Dict)
+		subtype, _ := e.Resolve(annot["Subtype"]).(Name)
+		switch {
+		case subtype == "Popup":
+			popups = append(popups, a)
+			continue
+		case annot == nil || !include(subtype):
+			kept = append(kept, a)
+			continue
+		}
+
+		flags, _ := e.Resolve(annot["F"]).(int64)
+		if flags&(annotHidden|annotNoView) != 0 {
+			stats.Removed++
+		} else if form, m, ok := e.appearance(annot); ok {
+			draws = append(draws, drawing{form, m})
+			stats.Flattened++
+		} else {
+			stats.Skipped++
+			kept = append(kept, a)
+			continue
+		}
+		if r, ok := a.(Ref); ok {
+			flattened[r] = true
+		}
+	}
+	for _, a := range popups {
+		popup, _ := e.Resolve(a).(Dict)
+		if parent, ok := popup["Parent"].(Ref); ok && flattened[parent] {
+			stats.Removed++
+			continue
+		}
+		kept = append(kept, a)
+	}
+	if stats.Flattened == 0 && stats.Removed == 0 {
+		return stats, nil
+	}
+
+	newPage := copyDict(page)
+	if len(kept) == 0 {
+		delete(newPage, "Annots")
+	} else {
+		newPage["Annots"] = kept
+	}
+
+	if len(draws) > 0 {
+		resources, _ := e.Resolve(e.InheritedAttr(page, "Resources")).(Dict)
+		resources = copyDict(resources)
+		xobjects, _ := e.Resolve(resources["XObject"]).(Dict)
+		xobjects = copyDict(xobjects)
+
+		var buf bytes.Buffer
+		buf.WriteString("\nQ\n")
+		n := 0
+		for _, d := range draws {
+			var name Name
+			for {
+				n++
+				name = Name(fmt.Sprintf("FlatAnnot%d", n))
+				if _, taken := xobjects[name]; !taken {
+					break
+				}
+			}
+			xobjects[name] = d.form
+			m := d.matrix
+			fmt.Fprintf(&buf, "q %s %s %s %s %s %s cm ", formatReal(round3(m[0])), formatReal(round3(m[1])),
+				formatReal(round3(m[2])), formatReal(round3(m[3])), formatReal(round3(m[4])), formatReal(round3(m[5])))
+			writeName(&buf, name)
+			buf.WriteString(" Do Q\n")
+		}
+		resources["XObject"] = xobjects
+		newPage["Resources"] = resources
+
+		contents := Array{e.Add(NewFlateStream(Dict{}, []byte("q\n")))}
+		switch c := e.Resolve(page["Contents"]).(type) {
+		case Array:
+			contents = append(contents, c...)
+		case *Stream:
+			contents = append(contents, page["Contents"])
+		}
+		newPage["Contents"] = append(contents, e.Add(NewFlateStream(Dict{}, buf.Bytes())))
+	}
+
+	e.Set(ref, newPage)
+	return stats, nil
+}
+
+// appearance returns the normal appearance of an annotation as a form
+// XObject, with the matrix that maps it onto the annotation rectangle. It
+// returns false when the annotation has no drawable appearance.
+func (e *Editor) appearance(annot Dict) (Object, Matrix, bool) {
+	app, ok := annotAppearance(annot, e.Resolve)
+	if !ok {
+		return nil, Matrix{}, false
+	}
+
+	// Appearance streams are form XObjects but often omit the entries
+	// that Do requires
+	form := app.ref
+	if t, _ := e.Resolve(app.Form.Dict["Subtype"]).(Name); t != "Form" {
+		dict := copyDict(app.Form.Dict)
+		dict["Type"] = Name("XObject")
+		dict["Subtype"] = Name("Form")
+		form = e.Add(&Stream{Dict: dict, Data: app.Form.Data})
+	} else if _, ok := app.ref.(Ref); !ok {
+		form = e.Add(app.Form)
+	}
+	return form, app.Placement, true
+}
@@ -0,0 +1,150 @@
+package pdf
+
+import "fmt"
+
+// FontInfo describes a font resource used by the document.
+type FontInfo struct {
+	// BaseFont is the PostScript name of the font, including any subset
+	// prefix such as "ABCDEF+".
+	BaseFont string
+
+	// Subtype is the font type: Type1, TrueType, Type0, Type3, ...
+	Subtype string
+
+	// Encoding is the name of the font encoding, "custom" for an encoding
+	// dictionary with differences, or empty when the font's built-in
+	// encoding is used.
+	Encoding string
+
+	// Embedded reports whether the font program is included in the file.
+	Embedded bool
+
+	// ToUnicode reports whether the font has a /ToUnicode map from
+	// character codes to text.
+	ToUnicode bool
+
+	// Flags are the FontDescriptor flags (serif, fixed pitch, symbolic, ...).
+	Flags int
+
+	// Pages lists the 1-based pages whose resources use the font.
+	Pages []int
+}
+
+// Fonts returns every font referenced from page resources, including the
+// resources of nested form XObjects, in order of first use.
+func (f *File) Fonts() ([]FontInfo, error) {
+	refs, err := f.PageRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	var fonts []FontInfo
+	index := make(map[string]int) // font key -> position in fonts
+	for i, ref := range refs {
+		page, ok := f.Resolve(ref).(Dict)
+		if !ok {
+			continue
+		}
+		pageNum := i + 1
+		seen := make(map[string]bool)
+		visited := make(map[int]bool)
+
+		var walk func(resources Dict, depth int)
+		walk = func(resources Dict, depth int) {
+			fontDicts, _ := f.Resolve(resources["Font"]).(Dict)
+			for _, name := range sortedKeys(fontDicts) {
+				entry := fontDicts[name]
+				font, ok := f.Resolve(entry).(Dict)
+				if !ok {
+					continue
+				}
+				key := fmt.Sprintf("p%d/%s", pageNum, name)
+				if r, ok := entry.(Ref); ok {
+					key = fmt.Sprintf("obj%d", r.Num)
+				}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				if pos, ok := index[key]; ok {
+					fonts[pos].Pages = append(fonts[pos].Pages, pageNum)
+					continue
+				}
+				info := f.fontInfo(font)
+				info.Pages = []int{pageNum}
+				index[key] = len(fonts)
+				fonts = append(fonts, info)
+			}
+
+			if depth >= maxFormDepth {
+				return
+			}
+			xobjects, _ := f.Resolve(resources["XObject"]).(Dict)
+			for _, name := range sortedKeys(xobjects) {
+				if r, ok := xobjects[name].(Ref); ok {
+					if visited[r.Num] {
+						continue
+					}
+					visited[r.Num] = true
+				}
+				xobj, ok := f.Resolve(xobjects[name]).(*Stream)
+				if !ok || f.Resolve(xobj.Dict["Subtype"]) != Name("Form") {
+					continue
+				}
+				if res, ok := f.Resolve(xobj.Dict["Resources"]).(Dict); ok {
+					walk(res, depth+1)
+				}
+			}
+		}
+
+		if resources, ok := f.Resolve(f.InheritedAttr(page, "Resources")).(Dict); ok {
+			walk(resources, 0)
+		}
+	}
+	return fonts, nil
+}
+
+// fontInfo extracts the descriptive fields of a font dictionary.
+func (f *File) fontInfo(font Dict) FontInfo {
+	var info FontInfo
+	base, _ := f.Resolve(font["BaseFont"]).(Name)
+	subtype, _ := f.Resolve(font["Subtype"]).(Name)
+	info.BaseFont = string(base)
+	info.Subtype = string(subtype)
+
+	switch enc := f.Resolve(font["Encoding"]).(type) {
+	case Name:
+		info.Encoding = string(enc)
+	case Dict:
+		info.Encoding = "custom"
+		if b, ok := f.Resolve(enc["BaseEncoding"]).(Name); ok && enc["Differences"] == nil {
+			info.Encoding = string(b)
+		}
+	}
+
+	_, info.ToUnicode = f.Resolve(font["ToUnicode"]).(*Stream)
+
+	descriptorOwner := font
+	if subtype == "Type0" {
+		if descendants, ok := f.Resolve(font["DescendantFonts"]).(Array); ok && len(descendants) > 0 {
+			if cid, ok := f.Resolve(descendants[0]).(Dict); ok {
+				descriptorOwner = cid
+			}
+		}
+	}
+	if desc, ok := f.Resolve(descriptorOwner["FontDescriptor"]).(Dict); ok {
+		if flags, ok := f.Resolve(desc["Flags"]).(int64); ok {
+			info.Flags = int(flags)
+		}
+		for _, key := range []Name{"FontFile", "FontFile2", "FontFile3"} {
+			if _, ok := f.Resolve(desc[key]).(*Stream); ok {
+				info.Embedded = true
+			}
+		}
+	}
+	// Type3 glyphs are defined by content streams inside the font itself.
+	if subtype == "Type3" {
+		info.Embedded = true
+	}
+	return info
+}
@@ -0,0 +1,145 @@
+package pdf
+
+import "strings"
+
+// fontMetrics holds the glyph advance widths of a font, as needed to
+// position individual glyphs when interpreting a content stream.
+type fontMetrics struct {
+	// twoByte is set for composite (Type0) fonts, whose codes are read as
+	// two bytes each.
+	twoByte bool
+
+	first     int
+	widths    []float64
+	cidWidths map[int]float64
+	missing   float64
+
+	// scale converts glyph space widths to text space: 1/1000 for all
+	// font types except Type3, which defines its own FontMatrix.
+	scale float64
+}
+
+// newFontMetrics reads the width information of a font dictionary.
+// A nil or malformed dictionary yields a generic proportional font.
+func newFontMetrics(font Dict, resolve func(Object) Object) *fontMetrics {
+	fm := &fontMetrics{missing: 500, scale: 0.001}
+	if font == nil {
+		return fm
+	}
+
+	if base, ok := resolve(font["BaseFont"]).(Name); ok && strings.Contains(string(base), "Courier") {
+		fm.missing = 600
+	}
+
+	switch resolve(font["Subtype"]) {
+	case Name("Type0"):
+		fm.twoByte = true
+		fm.missing = 1000
+		descendants, _ := resolve(font["DescendantFonts"]).(Array)
+		if len(descendants) == 0 {
+			return fm
+		}
+		cid, _ := resolve(descendants[0]).(Dict)
+		if dw, ok := toFloat(resolve(cid["DW"])); ok {
+			fm.missing = dw
+		}
+		fm.cidWidths = parseCIDWidths(resolve(cid["W"]), resolve)
+		return fm
+	case Name("Type3"):
+		if m, ok := resolve(font["FontMatrix"]).(Array); ok && len(m) == 6 {
+			if a, ok := toFloat(resolve(m[0])); ok {
+				fm.scale = a
+			}
+		}
+		fm.missing = 0
+	}
+
+	if desc, ok := resolve(font["FontDescriptor"]).(Dict); ok {
+		if mw, ok := toFloat(resolve(desc["MissingWidth"])); ok && mw > 0 {
+			fm.missing = mw
+		}
+	}
+	if first, ok := resolve(font["FirstChar"]).(int64); ok {
+		fm.first = int(first)
+	}
+	if widths, ok := resolve(font["Widths"]).(Array); ok {
+		fm.widths = make([]float64, len(widths))
+		for i, w := range widths {
+			fm.widths[i], _ = toFloat(resolve(w))
+		}
+	}
+	return fm
+}
+
+// parseCIDWidths decodes a CIDFont /W array, which mixes the forms
+// "c [w1 w2 ...]" and "cfirst clast w".
+func parseCIDWidths(w Object, resolve func(Object) Object) map[int]float64 {
+	arr, _ := w.(Array)
+	out := make(map[int]float64)
+	for i := 0; i < len(arr); {
+		start, ok := toFloat(resolve(arr[i]))
+		if !ok || i+1 >= len(arr) {
+			break
+		}
+		if list, ok := resolve(arr[i+1]).(Array); ok {
+			for j, v := range list {
+				out[int(start)+j], _ = toFloat(resolve(v))
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(arr) {
+			break
+		}
+		end, _ := toFloat(resolve(arr[i+1]))
+		width, _ := toFloat(resolve(arr[i+2]))
+		for c := int(start); c <= int(end) && c-int(start) < 65536; c++ {
+			out[c] = width
+		}
+		i += 3
+	}
+	return out
+}
+
+// codes splits a shown string into character codes.
+func (fm *fontMetrics) codes(s string) []int {
+	if !fm.twoByte {
+		out := make([]int, len(s))
+		for i := 0; i < len(s); i++ {
+			out[i] = int(s[i])
+		}
+		return out
+	}
+	out := make([]int, 0, (len(s)+1)/2)
+	for i := 0; i < len(s); i += 2 {
+		c := int(s[i]) << 8
+		if i+1 < len(s) {
+			c |= int(s[i+1])
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// width returns the advance width of a character code in text space units
+// for a font size of 1.
+func (fm *fontMetrics) width(code int) float64 {
+	if fm.cidWidths != nil {
+		if w, ok := fm.cidWidths[code]; ok {
+			return w * fm.scale
+		}
+		return fm.missing * fm.scale
+	}
+	if i := code - fm.first; i >= 0 && i < len(fm.widths) {
+		return fm.widths[i] * fm.scale
+	}
+	return fm.missing * fm.scale
+}
+
+// encodeCode returns the string bytes of a single character code.
+func (fm *fontMetrics) encodeCode(code int) string {
+	if fm.twoByte {
+		return string([]byte{byte(code >> 8), byte(code)})
+	}
+	return string([]byte{byte(code)})
+}
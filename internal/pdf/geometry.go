@@ -0,0 +1,150 @@
+package pdf
+
+import "math"
+
+// Rect is an axis-aligned rectangle in PDF user space (points, origin at
+// the bottom-left of the page). X0,Y0 is the lower-left corner and X1,Y1
+// the upper-right corner.
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Width returns the horizontal extent of the rectangle.
+func (r Rect) Width() float64 {
+	return r.X1 - r.X0
+}
+
+// Height returns the vertical extent of the rectangle.
+func (r Rect) Height() float64 {
+	return r.Y1 - r.Y0
+}
+
+// Area returns the area of the rectangle.
+func (r Rect) Area() float64 {
+	return r.Width() * r.Height()
+}
+
+// IsEmpty reports whether the rectangle has no area.
+func (r Rect) IsEmpty() bool {
+	return r.X1 <= r.X0 || r.Y1 <= r.Y0
+}
+
+// Union returns the smallest rectangle containing both r and o.
+// An empty rectangle is treated as the identity.
+func (r Rect) Union(o Rect) Rect {
+	if r.IsEmpty() {
+		return o
+	}
+	if o.IsEmpty() {
+		return r
+	}
+	return Rect{
+		X0: math.Min(r.X0, o.X0),
+		Y0: math.Min(r.Y0, o.Y0),
+		X1: math.Max(r.X1, o.X1),
+		Y1: math.Max(r.Y1, o.Y1),
+	}
+}
+
+// Intersect returns the overlap of r and o, which may be empty.
+func (r Rect) Intersect(o Rect) Rect {
+	return Rect{
+		X0: math.Max(r.X0, o.X0),
+		Y0: math.Max(r.Y0, o.Y0),
+		X1: math.Min(r.X1, o.X1),
+		Y1: math.Min(r.Y1, o.Y1),
+	}
+}
+
+// Intersects reports whether r and o overlap.
+func (r Rect) Intersects(o Rect) bool {
+	return !r.Intersect(o).IsEmpty()
+}
+
+// Contains reports whether the point (x, y) lies inside r.
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.X0 && x <= r.X1 && y >= r.Y0 && y <= r.Y1
+}
+
+// normalize returns r with its corners ordered.
+func (r Rect) normalize() Rect {
+	if r.X0 > r.X1 {
+		r.X0, r.X1 = r.X1, r.X0
+	}
+	if r.Y0 > r.Y1 {
+		r.Y0, r.Y1 = r.Y1, r.Y0
+	}
+	return r
+}
+
+// Quad is a quadrilateral in PDF user space given by the coordinates of
+// its corners in annotation QuadPoints order (PDF 32000-1:2008, 12.5.6.10):
+// upper-left, upper-right, lower-left, lower-right, as x, y pairs.
+type Quad [8]float64
+
+// RectQuad returns the quadrilateral covering r.
+func RectQuad(r Rect) Quad {
+	return Quad{r.X0, r.Y1, r.X1, r.Y1, r.X0, r.Y0, r.X1, r.Y0}
+}
+
+// BBox returns the smallest rectangle containing q.
+func (q Quad) BBox() Rect {
+	r := Rect{X0: q[0], Y0: q[1], X1: q[0], Y1: q[1]}
+	for i := 2; i < len(q); i += 2 {
+		r.X0 = math.Min(r.X0, q[i])
+		r.X1 = math.Max(r.X1, q[i])
+		r.Y0 = math.Min(r.Y0, q[i+1])
+		r.Y1 = math.Max(r.Y1, q[i+1])
+	}
+	return r
+}
+
+// Matrix is a PDF transformation matrix [a b c d e f].
+type Matrix [6]float64
+
+// identityMatrix is the identity transformation.
+var identityMatrix = Matrix{1, 0, 0, 1, 0, 0}
+
+// Multiply returns m × n, i.e. the transformation that applies m first
+// and then n, following the PDF row-vector convention.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+// TransformRect returns the bounding box of r after transformation by m.
+func (m Matrix) TransformRect(r Rect) Rect {
+	xs := [4]float64{}
+	ys := [4]float64{}
+	xs[0], ys[0] = m.Apply(r.X0, r.Y0)
+	xs[1], ys[1] = m.Apply(r.X1, r.Y0)
+	xs[2], ys[2] = m.Apply(r.X0, r.Y1)
+	xs[3], ys[3] = m.Apply(r.X1, r.Y1)
+	out := Rect{X0: xs[0], Y0: ys[0], X1: xs[0], Y1: ys[0]}
+	for i := 1; i < 4; i++ {
+		out.X0 = math.Min(out.X0, xs[i])
+		out.Y0 = math.Min(out.Y0, ys[i])
+		out.X1 = math.Max(out.X1, xs[i])
+		out.Y1 = math.Max(out.Y1, ys[i])
+	}
+	return out
+}
+
+// rectFromArray converts a PDF rectangle array into a normalized Rect.
+func rectFromArray(vals []float64) (Rect, bool) {
+	if len(vals) != 4 {
+		return Rect{}, false
+	}
+	return Rect{X0: vals[0], Y0: vals[1], X1: vals[2], Y1: vals[3]}.normalize(), true
+}
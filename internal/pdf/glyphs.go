@@ -0,0 +1,320 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	gopdf "github.com/ledongthuc/pdf"
+)
+
+// Glyph is a character shown on a page, at the origin of its baseline.
+type Glyph struct {
+	S        string
+	X, Y     float64
+	W        float64
+	Font     string
+	FontSize float64
+}
+
+// PageGlyphs returns the characters shown on a page (1-based) in content
+// stream order, unlike PageWords, which orders them by position. The
+// origins of consecutive glyphs trace the baselines of the text, whatever
+// their angle.
+func (r *Reader) PageGlyphs(pageNum int) ([]Glyph, error) {
+	glyphs, err := r.pageGlyphs(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(glyphs), nil
+}
+
+// pageGlyphs returns the glyphs of a page (1-based), from the cache when
+// possible. The glyphs are shared and must not be modified.
+func (r *Reader) pageGlyphs(pageNum int) (glyphs []Glyph, err error) {
+	if glyphs, ok := r.rows.get(pageNum); ok {
+		return glyphs, nil
+	}
+	gpage := r.reader.Page(pageNum)
+	if gpage.V.IsNull() {
+		return nil, fmt.Errorf("page %d is null", pageNum)
+	}
+	if err := r.checkContentSize(gpage); err != nil {
+		return nil, fmt.Errorf("failed to read content stream for page %d: %w", pageNum, err)
+	}
+	data, err := streamBytes(gpage.V.Key("Contents"), r.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content stream for page %d: %w", pageNum, err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			glyphs, err = nil, fmt.Errorf("failed to interpret content stream for page %d: %v", pageNum, p)
+		}
+	}()
+	fonts := gpage.Resources().Key("Font")
+	glyphs = textGlyphs(data, func(name Name) (Dict, gopdf.TextEncoding) {
+		font, _ := valueObject(fonts.Key(string(name)), 0).(Dict)
+		return font, gpage.Font(string(name)).Encoder()
+	})
+	r.rows.put(pageNum, glyphs)
+	return glyphs, nil
+}
+
+// textGlyphs interprets the text operators of a content stream and
+// returns a glyph for every character code shown, positioned by the text
+// and graphics state with the advance widths of its font, the way the
+// redactor lays out the glyphs it removes. Codes are decoded with the
+// ledongthuc/pdf encoder of their font; a code that decodes to nothing
+// still yields a glyph, with an empty string. Text in form XObjects is
+// not included. fonts returns the dictionary and encoder of a font
+// resource by name.
+func textGlyphs(data []byte, fonts func(Name) (Dict, gopdf.TextEncoding)) []Glyph {
+	ops, _ := ParseContent(data)
+	resolve := func(o Object) Object { return o }
+
+	type textFont struct {
+		metrics *fontMetrics
+		name    string
+		enc     gopdf.TextEncoding
+	}
+	loaded := make(map[Name]*textFont)
+	font := &textFont{metrics: newFontMetrics(nil, nil), enc: nopEncoding{}}
+
+	gs := redactGState{ctm: identityMatrix, text: textState{font: font.metrics, scale: 1}}
+	var stack []redactGState
+	var fontStack []*textFont
+	tm, tlm := identityMatrix, identityMatrix
+
+	nextLine := func(tx, ty float64) {
+		tlm = Matrix{1, 0, 0, 1, tx, ty}.Multiply(tlm)
+		tm = tlm
+	}
+
+	var out []Glyph
+	show := func(elems []Object) {
+		ts := gs.text
+		for _, el := range elems {
+			if k, ok := toFloat(el); ok {
+				tm = Matrix{1, 0, 0, 1, -k / 1000 * ts.size * ts.scale, 0}.Multiply(tm)
+				continue
+			}
+			s, ok := el.(String)
+			if !ok {
+				continue
+			}
+			for _, code := range ts.font.codes(string(s)) {
+				w0 := ts.font.width(code)
+				advance := w0*ts.size + ts.charSpace
+				if !ts.font.twoByte && code == ' ' {
+					advance += ts.wordSpace
+				}
+				trm := Matrix{ts.size * ts.scale, 0, 0, ts.size, 0, ts.rise}.Multiply(tm).Multiply(gs.ctm)
+				out = append(out, Glyph{
+					S:        font.enc.Decode(ts.font.encodeCode(code)),
+					X:        trm[4],
+					Y:        trm[5],
+					W:        w0 * math.Hypot(trm[0], trm[1]),
+					Font:     font.name,
+					FontSize: math.Hypot(trm[2], trm[3]),
+				})
+				tm = Matrix{1, 0, 0, 1, advance * ts.scale, 0}.Multiply(tm)
+			}
+		}
+	}
+
+	for _, op := range ops {
+		nums, _ := toFloats(op.Operands)
+		switch op.Name {
+		case "q":
+			stack = append(stack, gs)
+			fontStack = append(fontStack, font)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs, font = stack[n-1], fontStack[n-1]
+				stack, fontStack = stack[:n-1], fontStack[:n-1]
+			}
+		case "cm":
+			if len(nums) == 6 {
+				gs.ctm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}.Multiply(gs.ctm)
+			}
+		case "BT":
+			tm, tlm = identityMatrix, identityMatrix
+		case "Tf":
+			if len(op.Operands) == 2 {
+				name, _ := op.Operands[0].(Name)
+				tf, ok := loaded[name]
+				if !ok {
+					fontDict, enc := fonts(name)
+					tf = &textFont{metrics: newFontMetrics(fontDict, resolve), enc: enc}
+					if base, ok := fontDict["BaseFont"].(Name); ok {
+						tf.name = string(base)
+						if i := strings.Index(tf.name, "+"); i >= 0 {
+							tf.name = tf.name[i+1:]
+						}
+					}
+					if tf.enc == nil {
+						tf.enc = nopEncoding{}
+					}
+					loaded[name] = tf
+				}
+				font = tf
+				gs.text.font = tf.metrics
+				gs.text.size, _ = toFloat(op.Operands[1])
+			}
+		case "Tc":
+			if len(nums) == 1 {
+				gs.text.charSpace = nums[0]
+			}
+		case "Tw":
+			if len(nums) == 1 {
+				gs.text.wordSpace = nums[0]
+			}
+		case "Tz":
+			if len(nums) == 1 {
+				gs.text.scale = nums[0] / 100
+			}
+		case "TL":
+			if len(nums) == 1 {
+				gs.text.leading = nums[0]
+			}
+		case "Ts":
+			if len(nums) == 1 {
+				gs.text.rise = nums[0]
+			}
+		case "Td":
+			if len(nums) == 2 {
+				nextLine(nums[0], nums[1])
+			}
+		case "TD":
+			if len(nums) == 2 {
+				gs.text.leading = -nums[1]
+				nextLine(nums[0], nums[1])
+			}
+		case "Tm":
+			if len(nums) == 6 {
+				tlm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}
+				tm = tlm
+			}
+		case "T*":
+			nextLine(0, -gs.text.leading)
+		case "Tj":
+			show(op.Operands)
+		case "'":
+			nextLine(0, -gs.text.leading)
+			show(op.Operands)
+		case "\"":
+			if len(op.Operands) == 3 {
+				gs.text.wordSpace, _ = toFloat(op.Operands[0])
+				gs.text.charSpace, _ = toFloat(op.Operands[1])
+				nextLine(0, -gs.text.leading)
+				show(op.Operands[2:])
+			}
+		case "TJ":
+			if len(op.Operands) == 1 {
+				arr, _ := op.Operands[0].(Array)
+				show(arr)
+			}
+		}
+	}
+	return out
+}
+
+// glyphLines groups glyphs by baseline, rounded to a point, into lines
+// ordered from top to bottom, with the glyphs of each line ordered by X.
+func glyphLines(glyphs []Glyph) [][]Glyph {
+	byY := make(map[int64][]Glyph)
+	var ys []int64
+	for _, g := range glyphs {
+		y := int64(math.Round(g.Y))
+		if _, ok := byY[y]; !ok {
+			ys = append(ys, y)
+		}
+		byY[y] = append(byY[y], g)
+	}
+	slices.SortFunc(ys, func(a, b int64) int { return int(b - a) })
+
+	lines := make([][]Glyph, len(ys))
+	for i, y := range ys {
+		line := byY[y]
+		slices.SortStableFunc(line, func(a, b Glyph) int {
+			switch {
+			case a.X < b.X:
+				return -1
+			case a.X > b.X:
+				return 1
+			}
+			return 0
+		})
+		lines[i] = line
+	}
+	return lines
+}
+
+// glyphRuns merges the X-ordered glyphs of a line into runs: glyphs of the
+// same font and size, each starting within a tenth of the font size of
+// where the previous one ends. A run is returned as a Glyph spanning its
+// glyphs.
+func glyphRuns(line []Glyph) []Glyph {
+	var runs []Glyph
+	for _, g := range line {
+		if g.S == "" {
+			continue
+		}
+		if n := len(runs); n > 0 {
+			run := &runs[n-1]
+			end := run.X + run.W
+			if run.Font == g.Font && run.FontSize == g.FontSize && math.Abs(g.X-end) <= g.FontSize*0.1 {
+				run.S += g.S
+				run.W = math.Max(end, g.X+g.W) - run.X
+				continue
+			}
+		}
+		runs = append(runs, g)
+	}
+	return runs
+}
+
+// maxValueDepth bounds how deep valueObject follows nested values.
+const maxValueDepth = 8
+
+// valueObject converts a ledongthuc/pdf value into an Object, reading its
+// indirect parts lazily through the xref as they are reached. Streams and
+// values nested deeper than maxValueDepth become null, which keeps font
+// programs and reference cycles out of the result.
+func valueObject(v gopdf.Value, depth int) Object {
+	if depth > maxValueDepth {
+		return nil
+	}
+	switch v.Kind() {
+	case gopdf.Bool:
+		return v.Bool()
+	case gopdf.Integer:
+		return v.Int64()
+	case gopdf.Real:
+		return v.Float64()
+	case gopdf.String:
+		return String(v.RawString())
+	case gopdf.Name:
+		return Name(v.Name())
+	case gopdf.Array:
+		arr := make(Array, v.Len())
+		for i := range arr {
+			arr[i] = valueObject(v.Index(i), depth+1)
+		}
+		return arr
+	case gopdf.Dict:
+		dict := make(Dict)
+		for _, key := range v.Keys() {
+			dict[Name(key)] = valueObject(v.Key(key), depth+1)
+		}
+		return dict
+	}
+	return nil
+}
+
+// nopEncoding decodes strings unchanged, as ledongthuc/pdf does for
+// fonts without an encoding.
+type nopEncoding struct{}
+
+func (nopEncoding) Decode(raw string) string { return raw }
@@ -0,0 +1,318 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+
+	gopdf "github.com/ledongthuc/pdf"
+)
+
+// GraphicKind identifies the type of a painted graphic element.
+type GraphicKind int
+
+const (
+	// GraphicPath is a stroked and/or filled vector path.
+	GraphicPath GraphicKind = iota
+
+	// GraphicImage is an image XObject or inline image.
+	GraphicImage
+)
+
+// Graphic is a painted, non-text element on a page with its bounding box
+// in user space.
+type Graphic struct {
+	Kind    GraphicKind
+	BBox    Rect
+	Filled  bool
+	Stroked bool
+
+	// Name is the XObject resource name for images drawn with Do; empty
+	// for inline images and paths.
+	Name string
+}
+
+// maxFormDepth bounds recursion into nested form XObjects.
+const maxFormDepth = 8
+
+// PageMediaBox returns the MediaBox of a page (1-based), resolving the
+// value inherited from ancestor page tree nodes when necessary.
+func (r *Reader) PageMediaBox(pageNum int) (Rect, error) {
+	page := r.reader.Page(pageNum)
+	if page.V.IsNull() {
+		return Rect{}, fmt.Errorf("page %d is null", pageNum)
+	}
+
+	box, ok := rectFromValue(inheritedValue(page.V, "MediaBox"))
+	if !ok {
+		// US Letter is the conventional fallback for a missing MediaBox.
+		return Rect{X0: 0, Y0: 0, X1: 612, Y1: 792}, nil
+	}
+	return box, nil
+}
+
+// PageCropBox returns the CropBox of a page (1-based), the region viewers
+// display. It is inherited like the MediaBox, defaults to the MediaBox and
+// is clipped to it.
+func (r *Reader) PageCropBox(pageNum int) (Rect, error) {
+	media, err := r.PageMediaBox(pageNum)
+	if err != nil {
+		return Rect{}, err
+	}
+	page := r.reader.Page(pageNum)
+	crop, ok := rectFromValue(inheritedValue(page.V, "CropBox"))
+	if !ok {
+		return media, nil
+	}
+	if crop = crop.Intersect(media); crop.IsEmpty() {
+		return media, nil
+	}
+	return crop, nil
+}
+
+// PageRotation returns the clockwise rotation of a page (1-based) when
+// displayed, in degrees: 0, 90, 180 or 270. The /Rotate entry is
+// inherited; values that are not multiples of 90 are treated as 0.
+func (r *Reader) PageRotation(pageNum int) (int, error) {
+	page := r.reader.Page(pageNum)
+	if page.V.IsNull() {
+		return 0, fmt.Errorf("page %d is null", pageNum)
+	}
+	v := inheritedValue(page.V, "Rotate")
+	if v.Kind() != gopdf.Integer {
+		return 0, nil
+	}
+	rotate := int(v.Int64())
+	if rotate%90 != 0 {
+		return 0, nil
+	}
+	return (rotate%360 + 360) % 360, nil
+}
+
+// PageGraphics returns the vector paths and images painted on a page
+// (1-based), including those drawn by nested form XObjects.
+func (r *Reader) PageGraphics(pageNum int) ([]Graphic, error) {
+	page := r.reader.Page(pageNum)
+	if page.V.IsNull() {
+		return nil, fmt.Errorf("page %d is null", pageNum)
+	}
+
+	data, err := streamBytes(page.V.Key("Contents"), r.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content stream for page %d: %w", pageNum, err)
+	}
+
+	var result []Graphic
+	scanGraphics(data, page.Resources(), identityMatrix, 0, r.limit, &result)
+	return result, nil
+}
+
+// scanGraphics interprets the path painting and XObject operators of a
+// content stream, appending every painted element to out. Forms that
+// decode to more than limit bytes are skipped, unless limit is 0.
+func scanGraphics(data []byte, resources gopdf.Value, ctm Matrix, depth int, limit int64, out *[]Graphic) {
+	ops, _ := ParseContent(data)
+
+	var stack []Matrix
+	var path Rect
+	hasPath := false
+
+	addPoint := func(x, y float64) {
+		tx, ty := ctm.Apply(x, y)
+		pt := Rect{X0: tx, Y0: ty, X1: tx, Y1: ty}
+		if !hasPath {
+			path = pt
+			hasPath = true
+			return
+		}
+		if tx < path.X0 {
+			path.X0 = tx
+		}
+		if ty < path.Y0 {
+			path.Y0 = ty
+		}
+		if tx > path.X1 {
+			path.X1 = tx
+		}
+		if ty > path.Y1 {
+			path.Y1 = ty
+		}
+	}
+
+	paint := func(filled, stroked bool) {
+		if hasPath && (filled || stroked) {
+			*out = append(*out, Graphic{
+				Kind:    GraphicPath,
+				BBox:    path,
+				Filled:  filled,
+				Stroked: stroked,
+			})
+		}
+		hasPath = false
+	}
+
+	for _, op := range ops {
+		nums, numeric := toFloats(op.Operands)
+		switch op.Name {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if n := len(stack); n > 0 {
+				ctm = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case "cm":
+			if numeric && len(nums) == 6 {
+				ctm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}.Multiply(ctm)
+			}
+		case "m", "l":
+			if numeric && len(nums) == 2 {
+				addPoint(nums[0], nums[1])
+			}
+		case "c":
+			if numeric && len(nums) == 6 {
+				addPoint(nums[0], nums[1])
+				addPoint(nums[2], nums[3])
+				addPoint(nums[4], nums[5])
+			}
+		case "v", "y":
+			if numeric && len(nums) == 4 {
+				addPoint(nums[0], nums[1])
+				addPoint(nums[2], nums[3])
+			}
+		case "re":
+			if numeric && len(nums) == 4 {
+				addPoint(nums[0], nums[1])
+				addPoint(nums[0]+nums[2], nums[1]+nums[3])
+				addPoint(nums[0]+nums[2], nums[1])
+				addPoint(nums[0], nums[1]+nums[3])
+			}
+		case "S", "s":
+			paint(false, true)
+		case "f", "F", "f*":
+			paint(true, false)
+		case "B", "B*", "b", "b*":
+			paint(true, true)
+		case "n":
+			paint(false, false)
+		case "BI":
+			*out = append(*out, Graphic{
+				Kind: GraphicImage,
+				BBox: ctm.TransformRect(Rect{X0: 0, Y0: 0, X1: 1, Y1: 1}),
+			})
+		case "Do":
+			if len(op.Operands) != 1 {
+				continue
+			}
+			name, ok := op.Operands[0].(Name)
+			if !ok {
+				continue
+			}
+			xobj := resources.Key("XObject").Key(string(name))
+			switch xobj.Key("Subtype").Name() {
+			case "Image":
+				*out = append(*out, Graphic{
+					Kind: GraphicImage,
+					BBox: ctm.TransformRect(Rect{X0: 0, Y0: 0, X1: 1, Y1: 1}),
+					Name: string(name),
+				})
+			case "Form":
+				if depth >= maxFormDepth {
+					continue
+				}
+				formData, err := streamBytes(xobj, limit)
+				if err != nil {
+					continue
+				}
+				formCTM := ctm
+				if m, ok := matrixFromValue(xobj.Key("Matrix")); ok {
+					formCTM = m.Multiply(ctm)
+				}
+				formRes := xobj.Key("Resources")
+				if formRes.IsNull() {
+					formRes = resources
+				}
+				scanGraphics(formData, formRes, formCTM, depth+1, limit, out)
+			}
+		}
+	}
+}
+
+// streamBytes returns the decoded bytes of a stream value, or the
+// concatenation of an array of streams as used by page /Contents. It
+// fails with ErrResourceLimit once they exceed limit bytes, unless limit
+// is 0.
+func streamBytes(v gopdf.Value, limit int64) ([]byte, error) {
+	switch v.Kind() {
+	case gopdf.Null:
+		return nil, nil
+	case gopdf.Array:
+		var buf bytes.Buffer
+		for i := 0; i < v.Len(); i++ {
+			partLimit := limit
+			if limit > 0 {
+				if partLimit = limit - int64(buf.Len()); partLimit <= 0 {
+					return nil, limitError(limit)
+				}
+			}
+			part, err := streamBytes(v.Index(i), partLimit)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(part)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	case gopdf.Stream:
+		rc := v.Reader()
+		defer rc.Close()
+		return readLimit(rc, limit)
+	}
+	return nil, fmt.Errorf("unexpected object kind %v for stream", v.Kind())
+}
+
+// inheritedValue looks up key on a page dictionary, walking up the /Parent
+// chain for attributes that may be inherited from the page tree.
+func inheritedValue(page gopdf.Value, key string) gopdf.Value {
+	node := page
+	for depth := 0; depth < 64 && !node.IsNull(); depth++ {
+		if v := node.Key(key); !v.IsNull() {
+			return v
+		}
+		node = node.Key("Parent")
+	}
+	return gopdf.Value{}
+}
+
+// valueFloats converts an array value of numbers into float64 values.
+func valueFloats(v gopdf.Value) ([]float64, bool) {
+	if v.Kind() != gopdf.Array {
+		return nil, false
+	}
+	out := make([]float64, v.Len())
+	for i := range out {
+		el := v.Index(i)
+		if el.Kind() != gopdf.Integer && el.Kind() != gopdf.Real {
+			return nil, false
+		}
+		out[i] = el.Float64()
+	}
+	return out, true
+}
+
+// rectFromValue converts a rectangle array value into a normalized Rect.
+func rectFromValue(v gopdf.Value) (Rect, bool) {
+	vals, ok := valueFloats(v)
+	if !ok {
+		return Rect{}, false
+	}
+	return rectFromArray(vals)
+}
+
+// matrixFromValue converts a six-element array value into a Matrix.
+func matrixFromValue(v gopdf.Value) (Matrix, bool) {
+	vals, ok := valueFloats(v)
+	if !ok || len(vals) != 6 {
+		return Matrix{}, false
+	}
+	return Matrix{vals[0], vals[1], vals[2], vals[3], vals[4], vals[5]}, true
+}
@@ -0,0 +1,199 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// ErrUnsupportedImage is returned by EncodeImage for images whose
+// encoding or color space cannot be converted.
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// EncodeImage converts an image XObject to a format viewers understand.
+// JPEG (DCTDecode) data is passed through unchanged; 8-bit gray, RGB and
+// indexed images are decoded and re-encoded as PNG. It returns the
+// encoded data and its MIME type. Indirect objects in the image
+// dictionary must already be resolved, as they are for images returned
+// by PageDrawing.
+func EncodeImage(s *Stream) ([]byte, string, error) {
+	identity := func(o Object) Object { return o }
+	filters := filterList(s.Dict["Filter"], identity)
+
+	if n := len(filters); n > 0 && (filters[n-1] == "DCTDecode" || filters[n-1] == "DCT") {
+		pre := &Stream{Dict: copyDict(s.Dict), Data: s.Data}
+		pre.Dict["Filter"] = nameArray(filters[:n-1])
+		if params, ok := s.Dict["DecodeParms"].(Array); ok && len(params) >= n {
+			pre.Dict["DecodeParms"] = params[:n-1]
+		}
+		data, err := DecodeStream(pre, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, "image/jpeg", nil
+	}
+
+	width, _ := toFloat(s.Dict["Width"])
+	height, _ := toFloat(s.Dict["Height"])
+	bpc, ok := toFloat(s.Dict["BitsPerComponent"])
+	if !ok {
+		bpc = 8
+	}
+	w, h := int(width), int(height)
+	if w <= 0 || h <= 0 || bpc != 8 {
+		return nil, "", fmt.Errorf("%w: %dx%d at %g bits per component", ErrUnsupportedImage, w, h, bpc)
+	}
+
+	pixel, n, err := pixelReader(s.Dict["ColorSpace"])
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := DecodeStream(s, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) < w*h*n {
+		return nil, "", fmt.Errorf("%w: image data is truncated", ErrUnsupportedImage)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			off := (y*w + x) * n
+			img.SetNRGBA(x, y, pixel(data[off:off+n]))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// ImageSupported reports whether EncodeImage can convert an image, as
+// far as can be told without decoding it. Indirect objects in the image
+// dictionary must already be resolved, as for EncodeImage.
+func ImageSupported(s *Stream) bool {
+	identity := func(o Object) Object { return o }
+	filters := filterList(s.Dict["Filter"], identity)
+	if n := len(filters); n > 0 && (filters[n-1] == "DCTDecode" || filters[n-1] == "DCT") {
+		return filtersSupported(filters[:n-1])
+	}
+	if !filtersSupported(filters) {
+		return false
+	}
+	width, _ := toFloat(s.Dict["Width"])
+	height, _ := toFloat(s.Dict["Height"])
+	bpc, ok := toFloat(s.Dict["BitsPerComponent"])
+	if !ok {
+		bpc = 8
+	}
+	if width <= 0 || height <= 0 || bpc != 8 {
+		return false
+	}
+	_, _, err := pixelReader(s.Dict["ColorSpace"])
+	return err == nil
+}
+
+// pixelReader returns a function converting the components of one pixel
+// in an 8-bit color space to a color, and the number of components.
+func pixelReader(cs Object) (func([]byte) color.NRGBA, int, error) {
+	gray := func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[0], p[0], 255} }
+	rgb := func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], p[2], 255} }
+	cmyk := func(p []byte) color.NRGBA {
+		r, g, b := color.CMYKToRGB(p[0], p[1], p[2], p[3])
+		return color.NRGBA{r, g, b, 255}
+	}
+
+	switch v := cs.(type) {
+	case Name:
+		switch v {
+		case "DeviceGray", "CalGray":
+			return gray, 1, nil
+		case "DeviceRGB", "CalRGB":
+			return rgb, 3, nil
+		case "DeviceCMYK":
+			return cmyk, 4, nil
+		}
+	case Array:
+		if len(v) == 0 {
+			break
+		}
+		family, _ := v[0].(Name)
+		switch family {
+		case "CalGray":
+			return gray, 1, nil
+		case "CalRGB", "Lab":
+			return rgb, 3, nil
+		case "ICCBased":
+			if len(v) < 2 {
+				break
+			}
+			profile, _ := v[1].(*Stream)
+			if profile == nil {
+				break
+			}
+			switch n, _ := toFloat(profile.Dict["N"]); n {
+			case 1:
+				return gray, 1, nil
+			case 3:
+				return rgb, 3, nil
+			case 4:
+				return cmyk, 4, nil
+			}
+		case "Indexed":
+			if len(v) != 4 {
+				break
+			}
+			base, n, err := pixelReader(v[1])
+			if err != nil {
+				return nil, 0, err
+			}
+			var lookup []byte
+			switch l := v[3].(type) {
+			case String:
+				lookup = []byte(l)
+			case *Stream:
+				lookup, _ = DecodeStream(l, nil)
+			}
+			return func(p []byte) color.NRGBA {
+				off := int(p[0]) * n
+				if off+n > len(lookup) {
+					return color.NRGBA{0, 0, 0, 255}
+				}
+				return base(lookup[off : off+n])
+			}, 1, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: color space %v", ErrUnsupportedImage, cs)
+}
+
+// nameArray converts a list of names to an Array, or nil when empty.
+func nameArray(names []Name) Object {
+	if len(names) == 0 {
+		return nil
+	}
+	arr := make(Array, len(names))
+	for i, n := range names {
+		arr[i] = n
+	}
+	return arr
+}
+
+// PageThumbnail returns the thumbnail image of a page, with the indirect
+// objects in its dictionary resolved for EncodeImage, or nil when the
+// page has none.
+func (f *File) PageThumbnail(page Dict) *Stream {
+	thumb, ok := f.Resolve(page["Thumb"]).(*Stream)
+	if !ok {
+		return nil
+	}
+	resolved := &Stream{Dict: make(Dict, len(thumb.Dict)), Data: thumb.Data}
+	for k, v := range thumb.Dict {
+		resolved.Dict[k] = resolveDeep(v, f.Resolve, 4)
+	}
+	return resolved
+}
@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// labelRange is a page label range: the pages from Start (0-based) up to
+// the next range are numbered in Style, from First, after Prefix.
+type labelRange struct {
+	Start  int
+	Style  Name
+	Prefix string
+	First  int
+}
+
+// PageLabels returns the label of each of the first n pages from a
+// catalog /PageLabels number tree (PDF 32000-1:2008, 12.4.2), or nil when
+// the document has no page labels. Pages before the first range are
+// labeled with their 1-based page number.
+func PageLabels(catalog Dict, n int, resolve func(Object) Object) []string {
+	tree, ok := resolve(catalog["PageLabels"]).(Dict)
+	if !ok {
+		return nil
+	}
+	var ranges []labelRange
+	walkNumberTree(tree, resolve, 0, func(key int, value Object) {
+		d, ok := resolve(value).(Dict)
+		if !ok || key < 0 {
+			return
+		}
+		r := labelRange{Start: key, First: 1}
+		r.Style, _ = resolve(d["S"]).(Name)
+		if p, ok := resolve(d["P"]).(String); ok {
+			r.Prefix = DecodeTextString(p)
+		}
+		if st, ok := resolve(d["St"]).(int64); ok && st > 0 {
+			r.First = int(st)
+		}
+		ranges = append(ranges, r)
+	})
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = strconv.Itoa(i + 1)
+	}
+	for k, r := range ranges {
+		end := n
+		if k+1 < len(ranges) {
+			end = min(ranges[k+1].Start, n)
+		}
+		for i := r.Start; i < end; i++ {
+			labels[i] = r.Prefix + formatPageNumber(r.Style, r.First+i-r.Start)
+		}
+	}
+	return labels
+}
+
+// formatPageNumber formats the numeric part of a page label. An empty
+// style means the label is the prefix alone.
+func formatPageNumber(style Name, n int) string {
+	switch style {
+	case "D":
+		return strconv.Itoa(n)
+	case "R":
+		return roman(n)
+	case "r":
+		return strings.ToLower(roman(n))
+	case "A":
+		return letters(n)
+	case "a":
+		return strings.ToLower(letters(n))
+	}
+	return ""
+}
+
+// roman returns n as an uppercase Roman numeral.
+func roman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var sb strings.Builder
+	for i, v := range values {
+		for n >= v {
+			sb.WriteString(symbols[i])
+			n -= v
+		}
+	}
+	return sb.String()
+}
+
+// letters returns n in the page label letter style: A to Z, then AA to
+// ZZ, AAA to ZZZ and so on.
+func letters(n int) string {
+	if n < 1 {
+		return ""
+	}
+	return strings.Repeat(string(rune('A'+(n-1)%26)), (n-1)/26+1)
+}
+
+// walkNumberTree calls fn for every key and value of a number tree.
+func walkNumberTree(node Dict, resolve func(Object) Object, depth int, fn func(int, Object)) {
+	if node == nil || depth > 32 {
+		return
+	}
+	if nums, ok := resolve(node["Nums"]).(Array); ok {
+		for i := 0; i+1 < len(nums); i += 2 {
+			if key, ok := resolve(nums[i]).(int64); ok {
+				fn(int(key), nums[i+1])
+			}
+		}
+	}
+	kids, _ := resolve(node["Kids"]).(Array)
+	for _, kid := range kids {
+		child, _ := resolve(kid).(Dict)
+		walkNumberTree(child, resolve, depth+1, fn)
+	}
+}
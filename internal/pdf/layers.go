@@ -0,0 +1,239 @@
+package pdf
+
+import "fmt"
+
+// Layer is an optional content group (PDF 32000-1:2008, 8.11.2): a
+// named layer whose content viewers show or hide as a whole, such as a
+// translation or a stamp.
+type Layer struct {
+	Name string
+
+	// Visible reports whether the layer is shown by default, under the
+	// default configuration of the document.
+	Visible bool
+
+	// Locked reports whether the default configuration asks viewers not
+	// to let users change the visibility of the layer.
+	Locked bool
+
+	num int // object number of the group, 0 for a direct object
+}
+
+// Layers returns the optional content groups of the document in the
+// order of the catalog /OCProperties /OCGs array, or nil when it has
+// none.
+func (f *File) Layers() []Layer {
+	props := f.ocProperties()
+	if props == nil {
+		return nil
+	}
+	groups, _ := f.Resolve(props["OCGs"]).(Array)
+	config, _ := f.Resolve(props["D"]).(Dict)
+	base, _ := f.Resolve(config["BaseState"]).(Name)
+	on, off := f.refSet(config["ON"]), f.refSet(config["OFF"])
+	locked := f.refSet(config["Locked"])
+
+	var layers []Layer
+	for _, g := range groups {
+		dict, ok := f.Resolve(g).(Dict)
+		if !ok {
+			continue
+		}
+		ref, _ := g.(Ref)
+		l := Layer{Visible: base != "OFF", Locked: locked[ref.Num], num: ref.Num}
+		if s, ok := f.Resolve(dict["Name"]).(String); ok {
+			l.Name = DecodeTextString(s)
+		}
+		switch {
+		case on[ref.Num]:
+			l.Visible = true
+		case off[ref.Num]:
+			l.Visible = false
+		}
+		layers = append(layers, l)
+	}
+	return layers
+}
+
+// ocProperties returns the catalog /OCProperties dictionary, or nil.
+func (f *File) ocProperties() Dict {
+	root, _ := f.Resolve(f.trailer["Root"]).(Dict)
+	props, _ := f.Resolve(root["OCProperties"]).(Dict)
+	return props
+}
+
+// refSet returns the object numbers of the references in an array.
+func (f *File) refSet(o Object) map[int]bool {
+	set := make(map[int]bool)
+	arr, _ := f.Resolve(o).(Array)
+	for _, el := range arr {
+		if r, ok := el.(Ref); ok {
+			set[r.Num] = true
+		}
+	}
+	return set
+}
+
+// layerState decides whether optional content is shown.
+type layerState struct {
+	f      *File
+	layers map[int]Layer
+	on     func(Layer) bool
+}
+
+// shown reports whether content marked with the optional content group
+// or membership dictionary o is shown. A membership dictionary
+// (8.11.2.2) is shown according to its /P policy over its /OCGs, AnyOn
+// by default; its /VE visibility expressions are not evaluated. Content
+// marked with anything else is shown.
+func (s *layerState) shown(o Object) bool {
+	dict, ok := s.f.Resolve(o).(Dict)
+	if !ok {
+		return true
+	}
+	if t, _ := s.f.Resolve(dict["Type"]).(Name); t != "OCMD" {
+		return s.group(o)
+	}
+
+	var groups []Object
+	switch g := s.f.Resolve(dict["OCGs"]).(type) {
+	case Dict:
+		groups = []Object{dict["OCGs"]}
+	case Array:
+		groups = g
+	}
+	if len(groups) == 0 {
+		return true
+	}
+	policy, _ := s.f.Resolve(dict["P"]).(Name)
+	anyOn, allOn := false, true
+	for _, g := range groups {
+		if s.group(g) {
+			anyOn = true
+		} else {
+			allOn = false
+		}
+	}
+	switch policy {
+	case "AllOn":
+		return allOn
+	case "AnyOff":
+		return !allOn
+	case "AllOff":
+		return !anyOn
+	default:
+		return anyOn
+	}
+}
+
+// group reports whether the optional content group o is on. Groups not
+// listed in /OCProperties are on, as viewers ignore them.
+func (s *layerState) group(o Object) bool {
+	ref, ok := o.(Ref)
+	if !ok {
+		return true
+	}
+	l, ok := s.layers[ref.Num]
+	return !ok || s.on(l)
+}
+
+// PageGlyphsShown reports, for each glyph PageGlyphs returns for a page
+// (1-based), whether it is shown when on reports which layers are on.
+// Glyphs outside optional content are always shown; glyphs inside
+// several nested sequences of optional content are shown only when all
+// of them are.
+func (r *Reader) PageGlyphsShown(pageNum int, on func(Layer) bool) (shown []bool, err error) {
+	f, page, err := r.rawPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	data, err := f.PageContent(page)
+	if err != nil {
+		return nil, err
+	}
+	ops, _ := ParseContent(data)
+	defer func() {
+		if p := recover(); p != nil {
+			shown, err = nil, fmt.Errorf("failed to interpret content stream for page %d: %v", pageNum, p)
+		}
+	}()
+
+	state := &layerState{f: f, layers: make(map[int]Layer), on: on}
+	for _, l := range f.Layers() {
+		if l.num != 0 {
+			state.layers[l.num] = l
+		}
+	}
+	resources, _ := f.Resolve(f.InheritedAttr(page, "Resources")).(Dict)
+	properties, _ := f.Resolve(resources["Properties"]).(Dict)
+
+	// Glyphs are counted the way PageGlyphs emits them: one per character
+	// code, split as the metrics of the current font read them.
+	fontDicts, _ := f.Resolve(resources["Font"]).(Dict)
+	font := newFontMetrics(nil, nil)
+	var fontStack []*fontMetrics
+	hidden := []bool{false} // whether each open marked-content sequence is hidden
+	showText := func(s string) {
+		for range font.codes(s) {
+			shown = append(shown, !hidden[len(hidden)-1])
+		}
+	}
+	for _, op := range ops {
+		switch op.Name {
+		case "BMC":
+			hidden = append(hidden, hidden[len(hidden)-1])
+		case "BDC":
+			h := hidden[len(hidden)-1]
+			if len(op.Operands) == 2 && op.Operands[0] == Name("OC") && !h {
+				props := op.Operands[1]
+				if name, ok := props.(Name); ok {
+					props = properties[name]
+				}
+				h = !state.shown(props)
+			}
+			hidden = append(hidden, h)
+		case "EMC":
+			if len(hidden) > 1 {
+				hidden = hidden[:len(hidden)-1]
+			}
+		case "q":
+			fontStack = append(fontStack, font)
+		case "Q":
+			if n := len(fontStack); n > 0 {
+				font = fontStack[n-1]
+				fontStack = fontStack[:n-1]
+			}
+		case "Tf":
+			if len(op.Operands) == 2 {
+				name, _ := op.Operands[0].(Name)
+				fontDict, _ := f.Resolve(fontDicts[name]).(Dict)
+				font = newFontMetrics(fontDict, f.Resolve)
+			}
+		case "Tj", "'", "\"":
+			if n := len(op.Operands); n > 0 {
+				s, _ := op.Operands[n-1].(String)
+				showText(string(s))
+			}
+		case "TJ":
+			if len(op.Operands) == 1 {
+				arr, _ := op.Operands[0].(Array)
+				for _, el := range arr {
+					if s, ok := el.(String); ok {
+						showText(string(s))
+					}
+				}
+			}
+		}
+	}
+	return shown, nil
+}
+
+// Layers returns the optional content groups of the document, as
+// File.Layers does.
+func (r *Reader) Layers() ([]Layer, error) {
+	f, err := r.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	return f.Layers(), nil
+}
@@ -0,0 +1,463 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// tokenKind identifies the lexical class of a token.
+type tokenKind int
+
+const (
+	tokEOF        tokenKind = iota
+	tokObject               // a complete scalar object (number, string, name)
+	tokKeyword              // a bare keyword such as an operator, "R" or "obj"
+	tokArrayOpen            // [
+	tokArrayClose           // ]
+	tokDictOpen             // <<
+	tokDictClose            // >>
+)
+
+// token is a single lexical unit of PDF syntax.
+type token struct {
+	kind    tokenKind
+	obj     Object
+	keyword string
+}
+
+// lexer tokenizes PDF syntax from an in-memory buffer. It is shared by the
+// content stream parser and the file-level object parser.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func newLexer(data []byte) *lexer {
+	return &lexer{data: data}
+}
+
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace advances past whitespace and comments.
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// next returns the next token in the input.
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return token{kind: tokEOF}, nil
+	}
+
+	c := l.data[l.pos]
+	switch c {
+	case '(':
+		s, err := l.readLiteralString()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokObject, obj: String(s)}, nil
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return token{kind: tokDictOpen}, nil
+		}
+		s, err := l.readHexString()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokObject, obj: String(s)}, nil
+	case '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return token{kind: tokDictClose}, nil
+		}
+		l.pos++
+		return token{}, fmt.Errorf("unexpected '>' at offset %d", l.pos-1)
+	case '[':
+		l.pos++
+		return token{kind: tokArrayOpen}, nil
+	case ']':
+		l.pos++
+		return token{kind: tokArrayClose}, nil
+	case '{', '}':
+		// PostScript calculator braces; surfaced as keywords.
+		l.pos++
+		return token{kind: tokKeyword, keyword: string(c)}, nil
+	case '/':
+		return token{kind: tokObject, obj: l.readName()}, nil
+	case ')':
+		l.pos++
+		return token{}, fmt.Errorf("unexpected ')' at offset %d", l.pos-1)
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isWhitespace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+
+	if num, ok := parseNumber(word); ok {
+		return token{kind: tokObject, obj: num}, nil
+	}
+	switch word {
+	case "true":
+		return token{kind: tokObject, obj: true}, nil
+	case "false":
+		return token{kind: tokObject, obj: false}, nil
+	case "null":
+		return token{kind: tokObject, obj: nil}, nil
+	}
+	return token{kind: tokKeyword, keyword: word}, nil
+}
+
+// parseNumber parses an integer or real number token.
+func parseNumber(word string) (Object, bool) {
+	if word == "" {
+		return nil, false
+	}
+	c := word[0]
+	if !(c >= '0' && c <= '9') && c != '+' && c != '-' && c != '.' {
+		return nil, false
+	}
+	if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, true
+	}
+	// Tolerate malformed reals such as "--1" or "1.2.3" seen in the wild.
+	trimmed := word
+	for len(trimmed) > 1 && (trimmed[0] == '-' || trimmed[0] == '+') && (trimmed[1] == '-' || trimmed[1] == '+') {
+		trimmed = trimmed[1:]
+	}
+	if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// readName reads a name token starting at '/', decoding #xx escapes.
+func (l *lexer) readName() Name {
+	l.pos++ // skip '/'
+	var buf bytes.Buffer
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isWhitespace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				buf.WriteByte(byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		buf.WriteByte(c)
+		l.pos++
+	}
+	return Name(buf.String())
+}
+
+// readLiteralString reads a parenthesized string, handling escapes and
+// balanced nested parentheses.
+func (l *lexer) readLiteralString() (string, error) {
+	l.pos++ // skip '('
+	var buf bytes.Buffer
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			buf.WriteByte(c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return buf.String(), nil
+			}
+			buf.WriteByte(c)
+		case '\r':
+			// Unescaped end-of-line sequences are normalized to \n.
+			if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+				l.pos++
+			}
+			buf.WriteByte('\n')
+		case '\\':
+			if l.pos >= len(l.data) {
+				return buf.String(), nil
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case 'b':
+				buf.WriteByte('\b')
+			case 'f':
+				buf.WriteByte('\f')
+			case '\r':
+				// Line continuation.
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+				// Line continuation.
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for n := 0; n < 2 && l.pos < len(l.data); n++ {
+						d := l.data[l.pos]
+						if d < '0' || d > '7' {
+							break
+						}
+						v = v*8 + int(d-'0')
+						l.pos++
+					}
+					buf.WriteByte(byte(v))
+				} else {
+					buf.WriteByte(e)
+				}
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String(), fmt.Errorf("unterminated string")
+}
+
+// readHexString reads a <...> hexadecimal string.
+func (l *lexer) readHexString() (string, error) {
+	l.pos++ // skip '<'
+	var buf bytes.Buffer
+	var hi byte
+	haveHi := false
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			if haveHi {
+				buf.WriteByte(hi << 4)
+			}
+			return buf.String(), nil
+		}
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue // whitespace and junk are ignored
+		}
+		if haveHi {
+			buf.WriteByte(hi<<4 | v)
+			haveHi = false
+		} else {
+			hi = v
+			haveHi = true
+		}
+	}
+	return buf.String(), fmt.Errorf("unterminated hex string")
+}
+
+// readObject reads a complete object, including arrays and dictionaries.
+// When it encounters a keyword that does not belong to an object it
+// returns the keyword with a nil object.
+func (l *lexer) readObject() (Object, string, error) {
+	tok, err := l.next()
+	if err != nil {
+		return nil, "", err
+	}
+	return l.readObjectFrom(tok)
+}
+
+func (l *lexer) readObjectFrom(tok token) (Object, string, error) {
+	switch tok.kind {
+	case tokEOF:
+		return nil, "", fmt.Errorf("unexpected end of data")
+	case tokObject:
+		return tok.obj, "", nil
+	case tokKeyword:
+		return nil, tok.keyword, nil
+	case tokArrayOpen:
+		items, err := l.readSequence(tokArrayClose)
+		if err != nil {
+			return nil, "", err
+		}
+		return Array(items), "", nil
+	case tokDictOpen:
+		items, err := l.readSequence(tokDictClose)
+		if err != nil {
+			return nil, "", err
+		}
+		dict := make(Dict, len(items)/2)
+		for i := 0; i+1 < len(items); i += 2 {
+			key, ok := items[i].(Name)
+			if !ok {
+				continue
+			}
+			dict[key] = items[i+1]
+		}
+		return dict, "", nil
+	}
+	return nil, "", fmt.Errorf("unexpected token at offset %d", l.pos)
+}
+
+// readSequence reads objects until the closing token, folding "a b R"
+// triples into Ref values.
+func (l *lexer) readSequence(closing tokenKind) ([]Object, error) {
+	var items []Object
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == closing {
+			return items, nil
+		}
+		if tok.kind == tokEOF {
+			return items, fmt.Errorf("unexpected end of data in composite object")
+		}
+		if tok.kind == tokArrayClose || tok.kind == tokDictClose {
+			// Mismatched closer; treat as end of the sequence.
+			return items, nil
+		}
+		obj, kw, err := l.readObjectFrom(tok)
+		if err != nil {
+			return nil, err
+		}
+		if kw == "R" && len(items) >= 2 {
+			num, ok1 := items[len(items)-2].(int64)
+			gen, ok2 := items[len(items)-1].(int64)
+			if ok1 && ok2 {
+				items = append(items[:len(items)-2], Ref{Num: int(num), Gen: int(gen)})
+				continue
+			}
+		}
+		if kw != "" {
+			continue // stray keyword inside a composite object
+		}
+		items = append(items, obj)
+	}
+}
+
+// ParseContent parses a content stream into a sequence of operations.
+// Malformed trailing data is tolerated: the operations parsed so far are
+// returned together with the error.
+func ParseContent(data []byte) ([]Op, error) {
+	l := newLexer(data)
+	var ops []Op
+	var operands []Object
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return ops, err
+		}
+		if tok.kind == tokEOF {
+			return ops, nil
+		}
+		obj, kw, err := l.readObjectFrom(tok)
+		if err != nil {
+			return ops, err
+		}
+		if kw == "" {
+			operands = append(operands, obj)
+			continue
+		}
+		if kw == "BI" {
+			op, err := l.readInlineImage()
+			if err != nil {
+				return ops, err
+			}
+			ops = append(ops, op)
+			operands = nil
+			continue
+		}
+		ops = append(ops, Op{Name: kw, Operands: operands})
+		operands = nil
+	}
+}
+
+// readInlineImage reads the body of a BI ... ID ... EI inline image.
+func (l *lexer) readInlineImage() (Op, error) {
+	dict := Dict{}
+	for {
+		obj, kw, err := l.readObject()
+		if err != nil {
+			return Op{}, fmt.Errorf("invalid inline image: %w", err)
+		}
+		if kw == "ID" {
+			break
+		}
+		key, ok := obj.(Name)
+		if !ok {
+			continue
+		}
+		val, _, err := l.readObject()
+		if err != nil {
+			return Op{}, fmt.Errorf("invalid inline image: %w", err)
+		}
+		dict[key] = val
+	}
+
+	// A single whitespace byte separates ID from the image data.
+	if l.pos < len(l.data) && isWhitespace(l.data[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	end := len(l.data)
+	for i := start; i+1 < len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' &&
+			(i == start || isWhitespace(l.data[i-1])) &&
+			(i+2 == len(l.data) || isWhitespace(l.data[i+2]) || isDelimiter(l.data[i+2])) {
+			end = i
+			break
+		}
+	}
+	data := l.data[start:end]
+	if n := len(data); n > 0 && isWhitespace(data[n-1]) {
+		data = data[:n-1]
+	}
+	l.pos = end + 2
+	if l.pos > len(l.data) {
+		l.pos = len(l.data)
+	}
+	return Op{Name: "BI", Operands: []Object{dict, String(data)}}, nil
+}
@@ -0,0 +1,86 @@
+package pdf
+
+// linearizationWindow is the part of the file the linearization parameter
+// dictionary must lie in: the first object, within the first 1024 bytes.
+const linearizationWindow = 1024
+
+// Linearization holds the linearization parameter dictionary of a file,
+// which linearized ("fast web view") files have as their first object.
+type Linearization struct {
+	// Version is the /Linearized value, 1 in current files.
+	Version float64
+
+	// Length is the /L entry: the length of the file when it was
+	// linearized.
+	Length int64
+
+	// Pages is the /N entry, the page count, and FirstPage the /P entry,
+	// the 0-based index of the page the file is organized to show first.
+	Pages     int
+	FirstPage int
+
+	// FirstPageObject is the /O entry, the object number of the first
+	// page, and FirstPageEnd the /E entry, the offset of the end of that
+	// page's objects.
+	FirstPageObject int
+	FirstPageEnd    int64
+
+	// HintOffset and HintLength are the primary hint stream's offset and
+	// length from /H.
+	HintOffset int64
+	HintLength int64
+
+	// MainXrefOffset is the /T entry, the offset of the first entry of the
+	// main cross-reference table.
+	MainXrefOffset int64
+}
+
+// ParseLinearization returns the linearization parameter dictionary of a
+// file, or nil when the first object is not one.
+func ParseLinearization(data []byte) *Linearization {
+	l := newLexer(data[:min(len(data), linearizationWindow)])
+	num, _, err := l.readObject()
+	if err != nil {
+		return nil
+	}
+	gen, _, err := l.readObject()
+	if err != nil {
+		return nil
+	}
+	_, kw, err := l.readObject()
+	if err != nil {
+		return nil
+	}
+	if _, ok := num.(int64); !ok || kw != "obj" {
+		return nil
+	}
+	if _, ok := gen.(int64); !ok {
+		return nil
+	}
+	obj, _, err := l.readObject()
+	if err != nil {
+		return nil
+	}
+	dict, ok := obj.(Dict)
+	if !ok {
+		return nil
+	}
+	version, ok := toFloat(dict["Linearized"])
+	if !ok {
+		return nil
+	}
+
+	lin := &Linearization{Version: version}
+	lin.Length, _ = dict["L"].(int64)
+	n, _ := dict["N"].(int64)
+	p, _ := dict["P"].(int64)
+	o, _ := dict["O"].(int64)
+	lin.Pages, lin.FirstPage, lin.FirstPageObject = int(n), int(p), int(o)
+	lin.FirstPageEnd, _ = dict["E"].(int64)
+	lin.MainXrefOffset, _ = dict["T"].(int64)
+	if h := asArray(dict["H"]); len(h) >= 2 {
+		lin.HintOffset, _ = h[0].(int64)
+		lin.HintLength, _ = h[1].(int64)
+	}
+	return lin
+}
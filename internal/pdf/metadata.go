@@ -0,0 +1,179 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// StripMetadata removes the document information dictionary and the XMP
+// metadata stream referenced from the catalog.
+func (e *Editor) StripMetadata() {
+	delete(e.Trailer, "Info")
+	e.updateCatalog(func(catalog Dict) {
+		delete(catalog, "Metadata")
+	})
+}
+
+// SetInfo replaces the document information dictionary. The XMP metadata
+// stream is removed because it would no longer agree with the new values.
+func (e *Editor) SetInfo(info Dict) {
+	e.Trailer["Info"] = e.Add(info)
+	e.updateCatalog(func(catalog Dict) {
+		delete(catalog, "Metadata")
+	})
+}
+
+// updateCatalog applies fn to a copy of the catalog and stores the result.
+func (e *Editor) updateCatalog(fn func(Dict)) {
+	ref, ok := e.Trailer["Root"].(Ref)
+	if !ok {
+		return
+	}
+	catalog, ok := e.Resolve(ref).(Dict)
+	if !ok {
+		return
+	}
+	catalog = copyDict(catalog)
+	fn(catalog)
+	e.Set(ref, catalog)
+}
+
+// TextString encodes s as a PDF text string: unchanged when it is plain
+// ASCII, otherwise as UTF-16BE with a byte order mark.
+func TextString(s string) String {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return String(s)
+	}
+	units := utf16.Encode([]rune(s))
+	buf := make([]byte, 2, 2+2*len(units))
+	buf[0], buf[1] = 0xFE, 0xFF
+	for _, u := range units {
+		buf = append(buf, byte(u>>8), byte(u))
+	}
+	return String(buf)
+}
+
+// DateString formats t as a PDF date string, D:YYYYMMDDHHmmSSOHH'mm'.
+func DateString(t time.Time) String {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return String(t.Format("D:20060102150405") + "Z")
+	}
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return String(fmt.Sprintf("%s%c%02d'%02d'", t.Format("D:20060102150405"), sign, offset/3600, offset%3600/60))
+}
+
+// DecodeTextString decodes a PDF text string: UTF-16BE or UTF-8 when it
+// starts with the matching byte order mark, PDFDocEncoding otherwise.
+func DecodeTextString(s String) string {
+	switch {
+	case len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF:
+		units := make([]uint16, 0, (len(s)-2)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	case len(s) >= 3 && s[0] == 0xEF && s[1] == 0xBB && s[2] == 0xBF:
+		return string(s[3:])
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		sb.WriteRune(pdfDocRune(s[i]))
+	}
+	return sb.String()
+}
+
+// pdfDocHigh maps PDFDocEncoding bytes 0x80 to 0xA0 to Unicode; the
+// remaining bytes above 0x1F match Latin-1.
+var pdfDocHigh = [...]rune{
+	0x2022, 0x2020, 0x2021, 0x2026, 0x2014, 0x2013, 0x0192, 0x2044,
+	0x2039, 0x203A, 0x2212, 0x2030, 0x201E, 0x201C, 0x201D, 0x2018,
+	0x2019, 0x201A, 0x2122, 0xFB01, 0xFB02, 0x0141, 0x0152, 0x0160,
+	0x0178, 0x017D, 0x0131, 0x0142, 0x0153, 0x0161, 0x017E, 0xFFFD,
+	0x20AC,
+}
+
+// pdfDocLow maps PDFDocEncoding bytes 0x18 to 0x1F, which hold spacing
+// accents.
+var pdfDocLow = [...]rune{0x02D8, 0x02C7, 0x02C6, 0x02D9, 0x02DD, 0x02DB, 0x02DA, 0x02DC}
+
+// pdfDocRune converts a PDFDocEncoding byte to a rune.
+func pdfDocRune(b byte) rune {
+	switch {
+	case b >= 0x18 && b <= 0x1F:
+		return pdfDocLow[b-0x18]
+	case b >= 0x80 && b <= 0xA0:
+		return pdfDocHigh[b-0x80]
+	case b == 0xAD:
+		return 0xFFFD
+	}
+	return rune(b)
+}
+
+// ParseDate parses a PDF date string, D:YYYYMMDDHHmmSSOHH'mm'. Every
+// field after the year is optional, as is the "D:" prefix; dates without
+// a time zone are taken as UTC.
+func ParseDate(s string) (time.Time, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(s), "D:")
+	field := func(width, def int) (int, error) {
+		if len(v) == 0 || v[0] < '0' || v[0] > '9' {
+			return def, nil
+		}
+		if len(v) < width {
+			return 0, fmt.Errorf("invalid PDF date %q", s)
+		}
+		n, err := strconv.Atoi(v[:width])
+		if err != nil {
+			return 0, fmt.Errorf("invalid PDF date %q", s)
+		}
+		v = v[width:]
+		return n, nil
+	}
+
+	var parts [6]int
+	defaults := [6]int{-1, 1, 1, 0, 0, 0}
+	widths := [6]int{4, 2, 2, 2, 2, 2}
+	for i := range parts {
+		n, err := field(widths[i], defaults[i])
+		if err != nil {
+			return time.Time{}, err
+		}
+		parts[i] = n
+	}
+	if parts[0] < 0 {
+		return time.Time{}, fmt.Errorf("invalid PDF date %q", s)
+	}
+
+	loc := time.UTC
+	if len(v) > 0 && (v[0] == '+' || v[0] == '-') {
+		sign := 1
+		if v[0] == '-' {
+			sign = -1
+		}
+		v = strings.ReplaceAll(v[1:], "'", "")
+		hours, err := field(2, 0)
+		if err != nil {
+			return time.Time{}, err
+		}
+		minutes, err := field(2, 0)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = time.FixedZone("", sign*(hours*3600+minutes*60))
+	}
+	return time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, loc), nil
+}
@@ -0,0 +1,66 @@
+package pdf
+
+// Object is a parsed PDF object. The concrete type is one of:
+// nil (null), bool, int64, float64, String, Name, Array, Dict, *Stream or Ref.
+//
+// These types are produced by the package's own lexer and are independent
+// of the ledongthuc/pdf value model, which does not expose raw syntax.
+type Object interface{}
+
+// String is a PDF string object holding the decoded bytes of a literal
+// or hexadecimal string.
+type String string
+
+// Name is a PDF name object without the leading slash.
+type Name string
+
+// Array is a PDF array object.
+type Array []Object
+
+// Dict is a PDF dictionary object.
+type Dict map[Name]Object
+
+// Stream is a PDF stream object. Data holds the raw, still-encoded bytes.
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// Ref is an indirect object reference such as "12 0 R".
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Op is a single content stream operation: an operator and its operands.
+// Inline images are represented as a "BI" operation whose operands are the
+// image dictionary followed by the raw image data as a String.
+type Op struct {
+	Name     string
+	Operands []Object
+}
+
+// toFloat converts a numeric object to float64.
+func toFloat(o Object) (float64, bool) {
+	switch v := o.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toFloats converts a slice of numeric objects to float64 values.
+// It returns false if any element is not numeric.
+func toFloats(objs []Object) ([]float64, bool) {
+	out := make([]float64, len(objs))
+	for i, o := range objs {
+		f, ok := toFloat(o)
+		if !ok {
+			return nil, false
+		}
+		out[i] = f
+	}
+	return out, true
+}
@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+
+	gopdf "github.com/ledongthuc/pdf"
+)
+
+// TextDirections counts the text shown on a page in each baseline
+// direction, in bytes of string operands.
+type TextDirections [4]int
+
+// Total returns the amount of text in all directions.
+func (d TextDirections) Total() int {
+	return d[0] + d[1] + d[2] + d[3]
+}
+
+// PageTextDirections returns how much of the text on a page (1-based)
+// runs in each direction in user space, ignoring the page /Rotate entry:
+// index 0 for left to right, 1 for bottom to top, 2 for right to left
+// (upside down) and 3 for top to bottom, that is the baseline angle
+// counterclockwise in quarter turns. Text at other angles counts towards
+// the nearest direction; invisible text, such as the OCR layer of a scan,
+// counts too.
+func (r *Reader) PageTextDirections(pageNum int) (TextDirections, error) {
+	page := r.reader.Page(pageNum)
+	if page.V.IsNull() {
+		return TextDirections{}, fmt.Errorf("page %d is null", pageNum)
+	}
+	data, err := streamBytes(page.V.Key("Contents"), r.limit)
+	if err != nil {
+		return TextDirections{}, fmt.Errorf("failed to read content stream for page %d: %w", pageNum, err)
+	}
+	var dirs TextDirections
+	scanTextDirections(data, page.Resources(), identityMatrix, 0, r.limit, &dirs)
+	return dirs, nil
+}
+
+// scanTextDirections interprets the text operators of a content stream
+// that affect the baseline direction, adding the text shown to dirs.
+// Forms that decode to more than limit bytes are skipped, unless limit is
+// 0.
+func scanTextDirections(data []byte, resources gopdf.Value, ctm Matrix, depth int, limit int64, dirs *TextDirections) {
+	ops, _ := ParseContent(data)
+
+	type state struct {
+		ctm         Matrix
+		size, scale float64
+	}
+	gs := state{ctm: ctm, size: 1, scale: 1}
+	var stack []state
+	tm := identityMatrix
+
+	for _, op := range ops {
+		nums, numeric := toFloats(op.Operands)
+		switch op.Name {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case "cm":
+			if numeric && len(nums) == 6 {
+				gs.ctm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}.Multiply(gs.ctm)
+			}
+		case "BT":
+			tm = identityMatrix
+		case "Tm":
+			if numeric && len(nums) == 6 {
+				tm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}
+			}
+		case "Tf":
+			if len(op.Operands) == 2 {
+				if size, ok := toFloat(op.Operands[1]); ok {
+					gs.size = size
+				}
+			}
+		case "Tz":
+			if numeric && len(nums) == 1 {
+				gs.scale = nums[0] / 100
+			}
+		case "Tj", "'", "\"", "TJ":
+			n := 0
+			for _, el := range op.Operands {
+				switch v := el.(type) {
+				case String:
+					n += len(v)
+				case Array:
+					for _, s := range v {
+						if s, ok := s.(String); ok {
+							n += len(s)
+						}
+					}
+				}
+			}
+			trm := Matrix{gs.size * gs.scale, 0, 0, gs.size, 0, 0}.Multiply(tm).Multiply(gs.ctm)
+			if n > 0 && (trm[0] != 0 || trm[1] != 0) {
+				quarter := int(math.Round(math.Atan2(trm[1], trm[0]) / (math.Pi / 2)))
+				dirs[(quarter%4+4)%4] += n
+			}
+		case "Do":
+			if len(op.Operands) != 1 || depth >= maxFormDepth {
+				continue
+			}
+			name, ok := op.Operands[0].(Name)
+			if !ok {
+				continue
+			}
+			xobj := resources.Key("XObject").Key(string(name))
+			if xobj.Key("Subtype").Name() != "Form" {
+				continue
+			}
+			formData, err := streamBytes(xobj, limit)
+			if err != nil {
+				continue
+			}
+			formCTM := gs.ctm
+			if m, ok := matrixFromValue(xobj.Key("Matrix")); ok {
+				formCTM = m.Multiply(gs.ctm)
+			}
+			formRes := xobj.Key("Resources")
+			if formRes.IsNull() {
+				formRes = resources
+			}
+			scanTextDirections(formData, formRes, formCTM, depth+1, limit, dirs)
+		}
+	}
+}
+
+// SetPageRotation sets the /Rotate entry of a page, the clockwise angle
+// in degrees at which it is displayed, replacing an inherited one.
+func (e *Editor) SetPageRotation(ref Ref, degrees int) {
+	page, _ := e.Resolve(ref).(Dict)
+	page = copyDict(page)
+	page["Rotate"] = int64((degrees%360 + 360) % 360)
+	e.Set(ref, page)
+}
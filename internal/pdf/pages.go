@@ -0,0 +1,165 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PageRefs returns references to the page objects in document order.
+func (f *File) PageRefs() ([]Ref, error) {
+	root, _ := f.Resolve(f.trailer["Root"]).(Dict)
+	return pageRefs(root, f.Resolve)
+}
+
+// InheritedAttr looks up an inheritable page attribute (Resources,
+// MediaBox, CropBox, Rotate), walking up the /Parent chain.
+func (f *File) InheritedAttr(page Dict, key Name) Object {
+	return inheritedAttr(page, key, f.Resolve)
+}
+
+// PageAttrs are the inheritable attributes of a page (PDF 32000-1:2008,
+// 7.7.3.4), resolved from the page or the nearest ancestor Pages node
+// that defines them. Many files set MediaBox or Resources only on the
+// root of the page tree.
+type PageAttrs struct {
+	// Resources is the resource dictionary, nil when no node has one.
+	Resources Dict
+
+	// MediaBox defaults to US Letter when it is missing or invalid.
+	MediaBox Rect
+
+	// CropBox defaults to the media box and is clipped to it.
+	CropBox Rect
+
+	// Rotate is the clockwise display rotation in degrees: 0, 90, 180 or
+	// 270. Values that are not multiples of 90 are treated as 0.
+	Rotate int
+
+	// Inherited lists the attributes taken from an ancestor rather than
+	// the page itself, in the order Resources, MediaBox, CropBox, Rotate.
+	Inherited []Name
+}
+
+// PageAttrs resolves the inheritable attributes of a page. As with
+// Reader.PageMediaBox, the nearest node defining an attribute wins, and
+// an invalid value there, such as a MediaBox that is not an array of four
+// numbers, yields the default.
+func (f *File) PageAttrs(page Dict) PageAttrs {
+	var attrs PageAttrs
+	lookup := func(key Name) Object {
+		node := page
+		for depth := 0; depth < 64 && node != nil; depth++ {
+			if v, ok := node[key]; ok {
+				if depth > 0 {
+					attrs.Inherited = append(attrs.Inherited, key)
+				}
+				return f.Resolve(v)
+			}
+			node, _ = f.Resolve(node["Parent"]).(Dict)
+		}
+		return nil
+	}
+
+	attrs.Resources, _ = lookup("Resources").(Dict)
+	media, ok := rectFromObject(lookup("MediaBox"), f.Resolve)
+	if !ok {
+		// US Letter is the conventional fallback for a missing MediaBox.
+		media = Rect{X0: 0, Y0: 0, X1: 612, Y1: 792}
+	}
+	attrs.MediaBox, attrs.CropBox = media, media
+	if crop, ok := rectFromObject(lookup("CropBox"), f.Resolve); ok {
+		if crop = crop.Intersect(media); !crop.IsEmpty() {
+			attrs.CropBox = crop
+		}
+	}
+	if rotate, ok := lookup("Rotate").(int64); ok && rotate%90 == 0 {
+		attrs.Rotate = int((rotate%360 + 360) % 360)
+	}
+	return attrs
+}
+
+// PageContent returns the decoded content of a page, concatenating the
+// streams of a /Contents array.
+func (f *File) PageContent(page Dict) ([]byte, error) {
+	return pageContent(page, f.Resolve, f.limit)
+}
+
+// pageRefs walks the page tree below a catalog and returns references to
+// the leaf page objects in document order.
+func pageRefs(root Dict, resolve func(Object) Object) ([]Ref, error) {
+	if root == nil {
+		return nil, fmt.Errorf("missing document catalog")
+	}
+	var refs []Ref
+	visited := make(map[int]bool)
+	var walk func(o Object, depth int) error
+	walk = func(o Object, depth int) error {
+		ref, ok := o.(Ref)
+		if !ok {
+			return fmt.Errorf("page tree node is not an indirect object")
+		}
+		if visited[ref.Num] || depth > 64 {
+			return fmt.Errorf("cycle in page tree at object %d", ref.Num)
+		}
+		visited[ref.Num] = true
+		node, ok := resolve(ref).(Dict)
+		if !ok {
+			return fmt.Errorf("page tree node %d is not a dictionary", ref.Num)
+		}
+		kids, isTree := resolve(node["Kids"]).(Array)
+		if node["Type"] == Name("Page") || !isTree {
+			refs = append(refs, ref)
+			return nil
+		}
+		for _, kid := range kids {
+			if err := walk(kid, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root["Pages"], 0); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// inheritedAttr looks up an inheritable page attribute, walking up the
+// /Parent chain.
+func inheritedAttr(page Dict, key Name, resolve func(Object) Object) Object {
+	node := page
+	for depth := 0; depth < 64 && node != nil; depth++ {
+		if v, ok := node[key]; ok {
+			return v
+		}
+		node, _ = resolve(node["Parent"]).(Dict)
+	}
+	return nil
+}
+
+// pageContent decodes and concatenates the content streams of a page,
+// each to at most limit bytes unless limit is 0.
+func pageContent(page Dict, resolve func(Object) Object, limit int64) ([]byte, error) {
+	var streams []Object
+	switch c := resolve(page["Contents"]).(type) {
+	case *Stream:
+		streams = []Object{c}
+	case Array:
+		streams = c
+	}
+
+	var buf bytes.Buffer
+	for _, s := range streams {
+		stream, ok := resolve(s).(*Stream)
+		if !ok {
+			continue
+		}
+		data, err := DecodeStreamLimit(stream, resolve, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode content stream: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
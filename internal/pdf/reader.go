@@ -4,44 +4,413 @@ package pdf
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"sort"
+	"strings"
+	"sync"
 
 	gopdf "github.com/ledongthuc/pdf"
 )
 
 // Reader wraps the ledongthuc/pdf reader and manages the underlying file handle.
+//
+// A Reader is safe for concurrent use. The ledongthuc/pdf reader is not
+// modified after it is opened and reads through io.ReaderAt, which
+// allows concurrent calls; the lazily parsed raw file and the row cache
+// are guarded by their own locks. Data returned from caches is shared and
+// must be treated as read-only.
 type Reader struct {
 	file   *os.File
 	reader *gopdf.Reader
+
+	// src and size give access to the raw bytes for the package's own
+	// object parser, which is loaded lazily by RawFile.
+	src   io.ReaderAt
+	size  int64
+	rawMu sync.Mutex
+	raw   *File
+
+	// rows caches the glyphs of recently used pages.
+	rows *rowCache
+
+	// limit is the memory limit set with SetMemoryLimit, 0 for none.
+	limit int64
+
+	// tempPath is the temporary copy made by OpenReader, removed on Close.
+	tempPath string
+
+	// encryption, structure and sum describe the original document when
+	// the Reader works on a decrypted or repaired copy, and original
+	// holds its bytes.
+	encryption *EncryptionInfo
+	structure  *Structure
+	sum        string
+	original   []byte
+
+	// repair describes how the document was recovered when it was opened
+	// with OpenRepaired.
+	repair *RepairReport
 }
 
-// OpenFile opens a PDF file from disk and returns a Reader.
-func OpenFile(filePath string) (*Reader, error) {
-	f, r, err := gopdf.Open(filePath)
+// OpenFile opens a PDF file from disk and returns a Reader. Encrypted
+// files are decrypted with password, as they are by the other Open
+// functions.
+func OpenFile(filePath, password string) (*Reader, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat PDF: %w", err)
+	}
+	r, err := open(f, info.Size(), password)
 	if err != nil {
+		f.Close()
 		return nil, fmt.Errorf("failed to open PDF: %w", err)
 	}
-	return &Reader{file: f, reader: r}, nil
+	if r.encryption != nil {
+		// The Reader works on the decrypted copy
+		f.Close()
+	} else {
+		r.file = f
+	}
+	return r, nil
 }
 
 // OpenBytes opens a PDF from a byte slice and returns a Reader.
-func OpenBytes(data []byte) (*Reader, error) {
-	r, err := gopdf.NewReader(bytes.NewReader(data), int64(len(data)))
+func OpenBytes(data []byte, password string) (*Reader, error) {
+	r, err := open(bytes.NewReader(data), int64(len(data)), password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PDF from bytes: %w", err)
 	}
-	return &Reader{file: nil, reader: r}, nil
+	return r, nil
+}
+
+// OpenReaderAt opens a PDF from random-access storage of the given size.
+// Only the parts of the file that are needed are read. The caller keeps
+// ownership of src; Close does not close it.
+func OpenReaderAt(src io.ReaderAt, size int64, password string) (*Reader, error) {
+	r, err := open(src, size, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	return r, nil
+}
+
+// OpenReader opens a PDF from a sequential stream. PDFs are read from the
+// end, so the stream is first copied to a temporary file in dir, or the
+// default temporary directory when dir is empty. The file is removed on
+// Close.
+func OpenReader(src io.Reader, dir, password string) (*Reader, error) {
+	f, err := os.CreateTemp(dir, "crazypdf-*.pdf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	discard := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	size, err := io.Copy(f, src)
+	if err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	r, err := open(f, size, password)
+	if err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	if r.encryption != nil {
+		discard()
+	} else {
+		r.file, r.tempPath = f, f.Name()
+	}
+	return r, nil
+}
+
+// open opens the PDF in src. Encrypted documents are read into memory,
+// decrypted with password and rewritten unencrypted, and the Reader is
+// backed by that copy instead of src, so that text extraction and the raw
+// object layer both see plain objects.
+func open(src io.ReaderAt, size int64, password string) (*Reader, error) {
+	r, err := newReader(src, size)
+	if err == nil && r.Trailer().Key("Encrypt").IsNull() {
+		return &Reader{reader: r, src: src, size: size, rows: newRowCache(DefaultRowCacheSize)}, nil
+	}
+
+	// Encrypted documents, including those the reader cannot decrypt
+	// itself, go through the package's own parser
+	data := make([]byte, size)
+	if _, rerr := src.ReadAt(data, 0); rerr != nil && rerr != io.EOF {
+		return nil, fmt.Errorf("failed to read PDF: %w", rerr)
+	}
+	raw, perr := ParseFile(data)
+	if perr != nil || raw.trailer["Encrypt"] == nil {
+		if err == nil {
+			err = fmt.Errorf("failed to parse encrypted PDF: %w", perr)
+		}
+		return nil, err
+	}
+	info, _ := raw.Encryption()
+	structure := raw.Structure()
+	sum := sha256.Sum256(data)
+	if err := raw.Decrypt(password); err != nil {
+		return nil, err
+	}
+	plain, err := raw.DecryptedCopy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt PDF: %w", err)
+	}
+	r, err = gopdf.NewReader(bytes.NewReader(plain), int64(len(plain)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decrypted PDF: %w", err)
+	}
+	return &Reader{
+		reader:     r,
+		src:        bytes.NewReader(plain),
+		size:       int64(len(plain)),
+		encryption: &info,
+		structure:  &structure,
+		sum:        hex.EncodeToString(sum[:]),
+		original:   data,
+		rows:       newRowCache(DefaultRowCacheSize),
+	}, nil
+}
+
+// newReader opens src with the ledongthuc/pdf reader and loads the root
+// of the page tree of unencrypted documents, returning an error instead
+// of the panics the reader raises for some damaged files, such as a
+// startxref offset past the end or a page tree root that is not an
+// object.
+func newReader(src io.ReaderAt, size int64) (r *gopdf.Reader, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			r, err = nil, fmt.Errorf("malformed PDF: %v", p)
+		}
+	}()
+	r, err = gopdf.NewReader(src, size)
+	if err == nil && r.Trailer().Key("Encrypt").IsNull() {
+		r.NumPage()
+	}
+	return r, err
 }
 
-// Close closes the underlying file handle.
+// IsPasswordError reports whether err means the document is encrypted and
+// the password given, if any, does not open it.
+func IsPasswordError(err error) bool {
+	return errors.Is(err, ErrPassword) || errors.Is(err, gopdf.ErrInvalidPassword)
+}
+
+// HeaderVersion returns the PDF version in the header of src, such as
+// "1.7". Only the first kilobyte is read.
+func HeaderVersion(src io.ReaderAt) (string, error) {
+	buf := make([]byte, 1024)
+	n, err := src.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read PDF header: %w", err)
+	}
+	version := headerVersion(buf[:n])
+	if version == "" {
+		return "", errors.New("missing %PDF header")
+	}
+	return version, nil
+}
+
+// Version returns the PDF version of the document: the header version,
+// or the catalog /Version when that is later.
+func (r *Reader) Version() (string, error) {
+	version, err := HeaderVersion(r.src)
+	if err != nil {
+		return "", err
+	}
+	if v := r.CatalogVersion(); v > version {
+		version = v
+	}
+	return version, nil
+}
+
+// CatalogVersion returns the /Version entry of the document catalog, or
+// "" when there is none.
+func (r *Reader) CatalogVersion() string {
+	return r.reader.Trailer().Key("Root").Key("Version").Name()
+}
+
+// RepairReport returns how the document was recovered, or nil when it
+// was not opened with OpenRepaired.
+func (r *Reader) RepairReport() *RepairReport {
+	return r.repair
+}
+
+// Encrypted reports whether the document has an encryption dictionary.
+func (r *Reader) Encrypted() bool {
+	return r.encryption != nil
+}
+
+// InfoText returns a text entry of the document information dictionary,
+// such as "Title", decoded to UTF-8.
+func (r *Reader) InfoText(key string) string {
+	return r.reader.Trailer().Key("Info").Key(key).Text()
+}
+
+// RawFile returns the document parsed by the package's own object parser,
+// which exposes the raw object graph for rewriting. The file is read and
+// parsed on first use and cached.
+func (r *Reader) RawFile() (*File, error) {
+	r.rawMu.Lock()
+	defer r.rawMu.Unlock()
+	if r.raw != nil {
+		return r.raw, nil
+	}
+	data := make([]byte, r.size)
+	if _, err := r.src.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	f, err := ParseFileLimit(data, r.limit)
+	if err != nil {
+		return nil, err
+	}
+	r.raw = f
+	return f, nil
+}
+
+// SetMemoryLimit caps the memory the Reader uses for decoded data, as a
+// defence against decompression bombs: a content or other stream that
+// decodes to more than limit bytes fails with ErrResourceLimit instead of
+// being expanded in full, and the row cache evicts pages once the text it
+// holds is estimated to exceed limit bytes. A limit of 0, the default,
+// means no limit. Streams decoded while opening, such as those of
+// encrypted documents decrypted into memory, are not limited. It must be
+// set before the Reader is shared between goroutines.
+func (r *Reader) SetMemoryLimit(limit int64) {
+	r.limit = limit
+	r.rows.setMaxBytes(limit)
+	r.rawMu.Lock()
+	if r.raw != nil {
+		r.raw.SetMemoryLimit(limit)
+	}
+	r.rawMu.Unlock()
+}
+
+// checkContentSize fails with ErrResourceLimit when the content streams
+// of a page decode to more than the memory limit, before they are handed
+// to the ledongthuc/pdf interpreter, which reads them without a limit.
+func (r *Reader) checkContentSize(page gopdf.Page) error {
+	if r.limit <= 0 {
+		return nil
+	}
+	_, err := streamBytes(page.V.Key("Contents"), r.limit)
+	return err
+}
+
+// PageDrawing returns the paths and images painted on a page (1-based),
+// interpreted from the raw content streams.
+func (r *Reader) PageDrawing(pageNum int) ([]DrawItem, error) {
+	f, page, err := r.rawPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	return f.PageDrawing(page)
+}
+
+// PageAttrs returns the inheritable attributes of a page (1-based),
+// resolved from the raw page tree.
+func (r *Reader) PageAttrs(pageNum int) (*File, PageAttrs, error) {
+	f, page, err := r.rawPage(pageNum)
+	if err != nil {
+		return nil, PageAttrs{}, err
+	}
+	return f, f.PageAttrs(page), nil
+}
+
+// rawPage returns the parsed file and the dictionary of a page (1-based).
+func (r *Reader) rawPage(pageNum int) (*File, Dict, error) {
+	f, err := r.RawFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, err := f.PageRefs()
+	if err != nil {
+		return nil, nil, err
+	}
+	if pageNum < 1 || pageNum > len(refs) {
+		return nil, nil, fmt.Errorf("page %d out of range", pageNum)
+	}
+	page, ok := f.Resolve(refs[pageNum-1]).(Dict)
+	if !ok {
+		return nil, nil, fmt.Errorf("page %d is not a dictionary", pageNum)
+	}
+	return f, page, nil
+}
+
+// Structure returns a summary of the file structure of the document. For
+// encrypted documents it describes the original file, not the decrypted
+// copy the Reader works on.
+func (r *Reader) Structure() (Structure, error) {
+	if r.structure != nil {
+		return *r.structure, nil
+	}
+	f, err := r.RawFile()
+	if err != nil {
+		return Structure{}, err
+	}
+	return f.Structure(), nil
+}
+
+// SHA256 returns the hex-encoded SHA-256 of the document's bytes. For
+// encrypted documents it is the hash of the original file, not of the
+// decrypted copy the Reader works on.
+func (r *Reader) SHA256() (string, error) {
+	if r.sum != "" {
+		return r.sum, nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(r.src, 0, r.size)); err != nil {
+		return "", fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Original returns the bytes of the document as stored, rather than
+// those of the decrypted or repaired copy the Reader may work on. The
+// slice is shared and must not be modified.
+func (r *Reader) Original() ([]byte, error) {
+	if r.original != nil {
+		return r.original, nil
+	}
+	f, err := r.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	return f.Data(), nil
+}
+
+// Close closes the underlying file handle and removes any temporary copy.
 func (r *Reader) Close() error {
+	var err error
 	if r.file != nil {
-		return r.file.Close()
+		err = r.file.Close()
 	}
-	return nil
+	if r.tempPath != "" {
+		err = errors.Join(err, os.Remove(r.tempPath))
+	}
+	return err
+}
+
+// Size returns the size of the document file in bytes. For encrypted
+// documents it is the size of the original file.
+func (r *Reader) Size() int64 {
+	if r.structure != nil {
+		return r.structure.FileSize
+	}
+	return r.size
 }
 
 // NumPages returns the total number of pages in the PDF.
@@ -51,6 +420,13 @@ func (r *Reader) NumPages() int {
 
 // PlainText extracts all plain text from the entire document.
 func (r *Reader) PlainText() (string, error) {
+	if r.limit > 0 {
+		for i := 1; i <= r.NumPages(); i++ {
+			if err := r.checkContentSize(r.reader.Page(i)); err != nil {
+				return "", fmt.Errorf("failed to read content stream for page %d: %w", i, err)
+			}
+		}
+	}
 	textReader, err := r.reader.GetPlainText()
 	if err != nil {
 		return "", fmt.Errorf("failed to get plain text: %w", err)
@@ -67,19 +443,69 @@ func (r *Reader) PlainText() (string, error) {
 // glyph groups that belong to the same word, only inserting spaces where
 // there is a genuine gap between words.
 func (r *Reader) PagePlainText(pageNum int) (string, error) {
-	page := r.reader.Page(pageNum)
-	if page.V.IsNull() {
-		return "", fmt.Errorf("page %d is null", pageNum)
+	rows, err := r.PageTextByRow(pageNum)
+	if err != nil {
+		return "", err
 	}
+	return PlainTextOfRows(rows), nil
+}
 
-	rows, err := page.GetTextByRow()
-	if err != nil {
-		return "", fmt.Errorf("failed to get text for page %d: %w", pageNum, err)
+// Tracer receives a description of each decision taken while laying out
+// text, such as a space inserted between two glyph groups. A nil Tracer
+// discards them.
+type Tracer func(format string, args ...any)
+
+// Printf calls t when it is not nil.
+func (t Tracer) Printf(format string, args ...any) {
+	if t != nil {
+		t(format, args...)
 	}
+}
+
+// LayoutThresholds are the thresholds with which PlainTextOfRows spaces
+// glyph groups and PhysicalLayout places texts.
+type LayoutThresholds struct {
+	// WordGap is the gap between glyph groups, in estimated character
+	// widths, beyond which a space is inserted.
+	WordGap float64
+
+	// WordGapFontSize is the gap, in font sizes, used instead of WordGap
+	// after a glyph group whose width is known.
+	WordGapFontSize float64
+
+	// LineTolerance is how far apart, in points, the baselines of texts
+	// may be for the physical layout to place them on one line.
+	LineTolerance float64
+
+	// Columns is the number of character columns the physical layout
+	// spreads the page width over.
+	Columns int
+}
+
+// DefaultLayoutThresholds are the thresholds of PlainTextOfRows and
+// PhysicalLayout.
+var DefaultLayoutThresholds = LayoutThresholds{
+	WordGap:         0.5,
+	WordGapFontSize: 0.2,
+	LineTolerance:   2,
+	Columns:         80,
+}
+
+// PlainTextOfRows joins rows of text as PagePlainText does: one line per
+// row, with spaces only where the gap between glyph groups is wider than
+// half a character, or than a fifth of the font size after a group whose
+// width is known.
+func PlainTextOfRows(rows []TextRow) string {
+	return TracePlainTextOfRows(rows, DefaultLayoutThresholds, nil)
+}
 
+// TracePlainTextOfRows is PlainTextOfRows with the word gaps of th,
+// reporting to trace whether each pair of adjacent glyph groups is joined
+// or separated by a space.
+func TracePlainTextOfRows(rows []TextRow, th LayoutThresholds, trace Tracer) string {
 	var buf bytes.Buffer
 	for i, row := range rows {
-		if len(row.Content) == 0 {
+		if len(row.Words) == 0 {
 			if i < len(rows)-1 {
 				buf.WriteString("\n")
 			}
@@ -87,8 +513,8 @@ func (r *Reader) PagePlainText(pageNum int) (string, error) {
 		}
 
 		// Sort content items by X position within this row
-		items := make([]gopdf.Text, len(row.Content))
-		copy(items, row.Content)
+		items := make([]TextWord, len(row.Words))
+		copy(items, row.Words)
 		sort.Slice(items, func(a, b int) bool {
 			return items[a].X < items[b].X
 		})
@@ -124,16 +550,42 @@ func (r *Reader) PagePlainText(pageNum int) (string, error) {
 			prev := items[j-1]
 			curr := items[j]
 
-			// Expected end position of previous item if characters were contiguous
+			// Expected end position of previous item if characters were
+			// contiguous. If the gap exceeds th.WordGap character widths,
+			// it's a word space. This threshold accounts for kerning
+			// variations while still catching genuine word separations.
+			// Items whose width is known end where their glyphs do and are
+			// spaced, as words are, by a gap wider than th.WordGapFontSize
+			// font sizes.
 			prevEndX := prev.X + float64(len(prev.S))*minCharWidth
+			threshold := minCharWidth * th.WordGap
+			knownWidth := prev.W > 0 && prev.FontSize > 0
+			if knownWidth {
+				prevEndX = prev.X + prev.W
+				threshold = prev.FontSize * th.WordGapFontSize
+			}
 			gap := curr.X - prevEndX
-
-			// If the gap exceeds half a character width, it's a word space.
-			// This threshold accounts for kerning variations while still
-			// catching genuine word separations.
-			if gap > minCharWidth*0.5 {
+			spaced := strings.HasSuffix(prev.S, " ") || strings.HasPrefix(curr.S, " ")
+			if !spaced && gap > threshold {
 				buf.WriteString(" ")
 			}
+
+			// The decision is described only when traced, so untraced
+			// layout does not format a reason for every pair
+			if trace != nil {
+				reason := fmt.Sprintf("word_gap × character width %.2f", minCharWidth)
+				if knownWidth {
+					reason = fmt.Sprintf("word_gap_font_size × font size %.2f", prev.FontSize)
+				}
+				switch {
+				case spaced:
+					trace.Printf("row y=%d: no space added between %q and %q at x=%.2f: already spaced", row.Position, prev.S, curr.S, curr.X)
+				case gap > threshold:
+					trace.Printf("row y=%d: space between %q and %q at x=%.2f: gap %.2f > %.2f (%s)", row.Position, prev.S, curr.S, curr.X, gap, threshold, reason)
+				default:
+					trace.Printf("row y=%d: joined %q and %q at x=%.2f: gap %.2f <= %.2f (%s)", row.Position, prev.S, curr.S, curr.X, gap, threshold, reason)
+				}
+			}
 			buf.WriteString(curr.S)
 		}
 
@@ -141,7 +593,7 @@ func (r *Reader) PagePlainText(pageNum int) (string, error) {
 			buf.WriteString("\n")
 		}
 	}
-	return buf.String(), nil
+	return buf.String()
 }
 
 // TextRow represents a row of text with its vertical position.
@@ -155,32 +607,32 @@ type TextWord struct {
 	S        string
 	X        float64
 	Y        float64
+	W        float64
 	Font     string
 	FontSize float64
 }
 
 // PageTextByRow returns text organized by rows for a specific page (1-based index).
+// Rows hold the glyphs on a baseline, rounded to a point, from top to
+// bottom; each word of a row is a run of adjacent glyphs of one font and
+// size.
 func (r *Reader) PageTextByRow(pageNum int) ([]TextRow, error) {
-	page := r.reader.Page(pageNum)
-	if page.V.IsNull() {
-		return nil, fmt.Errorf("page %d is null", pageNum)
-	}
-
-	rows, err := page.GetTextByRow()
+	glyphs, err := r.pageGlyphs(pageNum)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get text rows for page %d: %w", pageNum, err)
+		return nil, err
 	}
 
 	var result []TextRow
-	for _, row := range rows {
-		tr := TextRow{Position: row.Position}
-		for _, word := range row.Content {
+	for _, line := range glyphLines(glyphs) {
+		tr := TextRow{Position: int64(math.Round(line[0].Y))}
+		for _, run := range glyphRuns(line) {
 			tr.Words = append(tr.Words, TextWord{
-				S:        word.S,
-				X:        word.X,
-				Y:        word.Y,
-				Font:     word.Font,
-				FontSize: word.FontSize,
+				S:        run.S,
+				X:        run.X,
+				Y:        run.Y,
+				W:        run.W,
+				Font:     run.Font,
+				FontSize: run.FontSize,
 			})
 		}
 		result = append(result, tr)
@@ -193,6 +645,7 @@ type StyledText struct {
 	Text     string
 	X        float64
 	Y        float64
+	W        float64
 	Font     string
 	FontSize float64
 }
@@ -200,25 +653,21 @@ type StyledText struct {
 // PageStyledTexts returns styled text elements for a specific page (1-based index).
 // The returned texts include position and font information.
 func (r *Reader) PageStyledTexts(pageNum int) ([]StyledText, error) {
-	page := r.reader.Page(pageNum)
-	if page.V.IsNull() {
-		return nil, fmt.Errorf("page %d is null", pageNum)
-	}
-
-	rows, err := page.GetTextByRow()
+	glyphs, err := r.pageGlyphs(pageNum)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get styled texts for page %d: %w", pageNum, err)
+		return nil, err
 	}
 
 	var result []StyledText
-	for _, row := range rows {
-		for _, word := range row.Content {
+	for _, line := range glyphLines(glyphs) {
+		for _, run := range glyphRuns(line) {
 			result = append(result, StyledText{
-				Text:     word.S,
-				X:        word.X,
-				Y:        word.Y,
-				Font:     word.Font,
-				FontSize: word.FontSize,
+				Text:     run.S,
+				X:        run.X,
+				Y:        run.Y,
+				W:        run.W,
+				Font:     run.Font,
+				FontSize: run.FontSize,
 			})
 		}
 	}
@@ -232,17 +681,11 @@ func (r *Reader) PageContentStream(pageNum int) ([]byte, error) {
 		return nil, fmt.Errorf("page %d is null", pageNum)
 	}
 
-	content := page.V.Key("Contents")
-	if content.Kind() == gopdf.Null {
-		return nil, nil
-	}
-
-	reader := content.Reader()
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, reader); err != nil {
+	data, err := streamBytes(page.V.Key("Contents"), r.limit)
+	if err != nil {
 		return nil, fmt.Errorf("failed to read content stream for page %d: %w", pageNum, err)
 	}
-	return buf.Bytes(), nil
+	return data, nil
 }
 
 // PhysicalLayoutText extracts text preserving physical positioning for a page (1-based).
@@ -252,9 +695,21 @@ func (r *Reader) PhysicalLayoutText(pageNum int, pageWidth float64) (string, err
 	if err != nil {
 		return "", err
 	}
+	return PhysicalLayout(styledTexts, pageWidth), nil
+}
+
+// PhysicalLayout lays out styled texts as PhysicalLayoutText does, on a
+// grid of 80 columns across pageWidth. The texts are sorted in place.
+func PhysicalLayout(styledTexts []StyledText, pageWidth float64) string {
+	return TracePhysicalLayout(styledTexts, pageWidth, DefaultLayoutThresholds, nil)
+}
 
+// TracePhysicalLayout is PhysicalLayout with the line tolerance and
+// columns of th, reporting to trace where each line starts and the column
+// each text is placed at.
+func TracePhysicalLayout(styledTexts []StyledText, pageWidth float64, th LayoutThresholds, trace Tracer) string {
 	if len(styledTexts) == 0 {
-		return "", nil
+		return ""
 	}
 
 	// Sort by Y (descending — PDF origin is bottom-left), then by X
@@ -266,7 +721,7 @@ func (r *Reader) PhysicalLayoutText(pageNum int, pageWidth float64) (string, err
 	})
 
 	// Group texts by approximate Y position (same line if within tolerance)
-	const yTolerance = 2.0
+	yTolerance := th.LineTolerance
 	type line struct {
 		y     float64
 		texts []StyledText
@@ -277,6 +732,13 @@ func (r *Reader) PhysicalLayoutText(pageNum int, pageWidth float64) (string, err
 
 	for _, st := range styledTexts {
 		if currentLine == nil || abs(currentLine.y-st.Y) > yTolerance {
+			switch {
+			case trace == nil:
+			case currentLine == nil:
+				trace.Printf("line at y=%.2f starts with %q", st.Y, st.Text)
+			default:
+				trace.Printf("line at y=%.2f starts with %q: %.2f below the line at y=%.2f > tolerance %.2f", st.Y, st.Text, currentLine.y-st.Y, currentLine.y, yTolerance)
+			}
 			lines = append(lines, line{y: st.Y})
 			currentLine = &lines[len(lines)-1]
 		}
@@ -287,7 +749,7 @@ func (r *Reader) PhysicalLayoutText(pageNum int, pageWidth float64) (string, err
 	if pageWidth <= 0 {
 		pageWidth = 612 // default US Letter width in points
 	}
-	charsPerLine := 80
+	charsPerLine := th.Columns
 	charWidth := pageWidth / float64(charsPerLine)
 
 	var buf bytes.Buffer
@@ -311,12 +773,24 @@ func (r *Reader) PhysicalLayoutText(pageNum int, pageWidth float64) (string, err
 			if col >= charsPerLine {
 				col = charsPerLine - 1
 			}
+			if trace != nil {
+				trace.Printf("line at y=%.2f: %q at x=%.2f placed at column %d of %d (%.2f points each)", ln.y, st.Text, st.X, col, charsPerLine, charWidth)
+			}
+			overwritten, clipped := 0, 0
 			for ci, ch := range []byte(st.Text) {
 				pos := col + ci
 				if pos < charsPerLine {
+					if lineChars[pos] != ' ' {
+						overwritten++
+					}
 					lineChars[pos] = ch
+				} else {
+					clipped++
 				}
 			}
+			if trace != nil && (overwritten > 0 || clipped > 0) {
+				trace.Printf("line at y=%.2f: %q overwrote %d characters and lost %d past the last column", ln.y, st.Text, overwritten, clipped)
+			}
 		}
 
 		// Trim trailing spaces and write
@@ -328,7 +802,7 @@ func (r *Reader) PhysicalLayoutText(pageNum int, pageWidth float64) (string, err
 		}
 	}
 
-	return buf.String(), nil
+	return buf.String()
 }
 
 func abs(x float64) float64 {
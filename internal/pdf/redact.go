@@ -0,0 +1,493 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// RedactStats summarizes what a redaction pass removed from a page.
+type RedactStats struct {
+	// Glyphs is the number of glyphs removed from text showing operators.
+	Glyphs int
+
+	// AreaGlyphs holds, for each redaction area in the order given, the
+	// number of removed glyphs whose center falls inside it. A glyph inside
+	// several overlapping areas counts for each of them.
+	AreaGlyphs []int
+
+	// Images is the number of images removed because they were fully
+	// covered by a redaction area.
+	Images int
+
+	// PartialImages is the number of images that overlap a redaction area
+	// without being fully covered. They are left in place, with their
+	// pixels unchanged, and only hidden by the fill box if one is drawn.
+	PartialImages int
+
+	// Annotations is the number of annotations removed because their
+	// rectangle overlaps a redaction area.
+	Annotations int
+}
+
+// RedactPage removes the content of a page that lies inside any of the
+// given rectangles, in default user space. Glyphs whose center falls in an
+// area are deleted from the content stream (later glyphs keep their
+// positions), fully covered images are dropped and overlapping annotations
+// are removed. Form XObjects are redacted through page-specific copies.
+// When fill is non-nil, an opaque box of that RGB color is painted over
+// every area.
+func (e *Editor) RedactPage(ref Ref, rects []Rect, fill *[3]float64) (RedactStats, error) {
+	page, ok := e.Resolve(ref).(Dict)
+	if !ok {
+		return RedactStats{}, fmt.Errorf("object %d is not a page", ref.Num)
+	}
+	data, err := e.PageContent(page)
+	if err != nil {
+		return RedactStats{}, err
+	}
+
+	rd := &redactor{e: e, rects: rects}
+	rd.stats.AreaGlyphs = make([]int, len(rects))
+	resources, _ := e.Resolve(e.InheritedAttr(page, "Resources")).(Dict)
+	newData, newResources, changed := rd.content(data, resources, identityMatrix, 0)
+
+	newPage := copyDict(page)
+	if changed {
+		if newResources != nil {
+			newPage["Resources"] = newResources
+		}
+	} else {
+		newData = data
+	}
+
+	if annots, ok := e.Resolve(page["Annots"]).(Array); ok {
+		var kept Array
+		for _, a := range annots {
+			annot, _ := e.Resolve(a).(Dict)
+			box, ok := rectFromObject(e.Resolve(annot["Rect"]), e.Resolve)
+			if ok && rd.intersects(box) {
+				rd.stats.Annotations++
+				continue
+			}
+			kept = append(kept, a)
+		}
+		if len(kept) == 0 {
+			delete(newPage, "Annots")
+		} else {
+			newPage["Annots"] = kept
+		}
+	}
+
+	if !changed && fill == nil && rd.stats.Annotations == 0 {
+		return rd.stats, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("q\n")
+	buf.Write(newData)
+	buf.WriteString("\nQ\n")
+	if fill != nil {
+		fmt.Fprintf(&buf, "q %s %s %s rg\n", formatReal(fill[0]), formatReal(fill[1]), formatReal(fill[2]))
+		for _, r := range rects {
+			fmt.Fprintf(&buf, "%s %s %s %s re\n", formatReal(round3(r.X0)), formatReal(round3(r.Y0)),
+				formatReal(round3(r.Width())), formatReal(round3(r.Height())))
+		}
+		buf.WriteString("f Q\n")
+	}
+	newPage["Contents"] = e.Add(NewFlateStream(Dict{}, buf.Bytes()))
+	e.Set(ref, newPage)
+	return rd.stats, nil
+}
+
+// PageContent returns the decoded content of a page, concatenating the
+// streams of a /Contents array.
+func (e *Editor) PageContent(page Dict) ([]byte, error) {
+	return pageContent(page, e.Resolve, 0)
+}
+
+// redactor carries the state of a single page redaction.
+type redactor struct {
+	e       *Editor
+	rects   []Rect
+	stats   RedactStats
+	renamed int
+}
+
+// textState is the part of the graphics state that affects text layout.
+type textState struct {
+	font      *fontMetrics
+	size      float64
+	charSpace float64
+	wordSpace float64
+	scale     float64
+	leading   float64
+	rise      float64
+}
+
+type redactGState struct {
+	ctm  Matrix
+	text textState
+}
+
+// hit reports whether the center of box lies inside a redaction area,
+// counting the glyph for every area it lies in.
+func (rd *redactor) hit(box Rect) bool {
+	cx, cy := (box.X0+box.X1)/2, (box.Y0+box.Y1)/2
+	found := false
+	for i, r := range rd.rects {
+		if r.Contains(cx, cy) {
+			rd.stats.AreaGlyphs[i]++
+			found = true
+		}
+	}
+	return found
+}
+
+// covered reports whether box lies entirely inside a redaction area.
+func (rd *redactor) covered(box Rect) bool {
+	for _, r := range rd.rects {
+		if r.Contains(box.X0, box.Y0) && r.Contains(box.X1, box.Y1) {
+			return true
+		}
+	}
+	return false
+}
+
+// intersects reports whether box overlaps any redaction area.
+func (rd *redactor) intersects(box Rect) bool {
+	for _, r := range rd.rects {
+		if r.Intersects(box) {
+			return true
+		}
+	}
+	return false
+}
+
+// content redacts a content stream drawn with the given resources and
+// initial CTM. It returns the rewritten stream, a copy of the resources
+// when XObjects had to be replaced, and whether anything changed.
+func (rd *redactor) content(data []byte, resources Dict, ctm Matrix, depth int) ([]byte, Dict, bool) {
+	ops, _ := ParseContent(data)
+	fonts := make(map[Name]*fontMetrics)
+	fontDicts, _ := rd.e.Resolve(resources["Font"]).(Dict)
+	xobjects, _ := rd.e.Resolve(resources["XObject"]).(Dict)
+	var newXObjects Dict
+	dropped := make(map[Name]bool) // images removed by at least one Do
+	used := make(map[Name]bool)    // XObjects still drawn
+
+	gs := redactGState{ctm: ctm, text: textState{font: newFontMetrics(nil, nil), scale: 1}}
+	var stack []redactGState
+	tm, tlm := identityMatrix, identityMatrix
+	changed := false
+
+	nextLine := func(tx, ty float64) {
+		tlm = Matrix{1, 0, 0, 1, tx, ty}.Multiply(tlm)
+		tm = tlm
+	}
+
+	var out []Op
+	for _, op := range ops {
+		nums, _ := toFloats(op.Operands)
+		switch op.Name {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case "cm":
+			if len(nums) == 6 {
+				gs.ctm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}.Multiply(gs.ctm)
+			}
+		case "BT":
+			tm, tlm = identityMatrix, identityMatrix
+		case "Tf":
+			if len(op.Operands) == 2 {
+				name, _ := op.Operands[0].(Name)
+				fm, ok := fonts[name]
+				if !ok {
+					fontDict, _ := rd.e.Resolve(fontDicts[name]).(Dict)
+					fm = newFontMetrics(fontDict, rd.e.Resolve)
+					fonts[name] = fm
+				}
+				gs.text.font = fm
+				gs.text.size, _ = toFloat(op.Operands[1])
+			}
+		case "Tc":
+			if len(nums) == 1 {
+				gs.text.charSpace = nums[0]
+			}
+		case "Tw":
+			if len(nums) == 1 {
+				gs.text.wordSpace = nums[0]
+			}
+		case "Tz":
+			if len(nums) == 1 {
+				gs.text.scale = nums[0] / 100
+			}
+		case "TL":
+			if len(nums) == 1 {
+				gs.text.leading = nums[0]
+			}
+		case "Ts":
+			if len(nums) == 1 {
+				gs.text.rise = nums[0]
+			}
+		case "Td":
+			if len(nums) == 2 {
+				nextLine(nums[0], nums[1])
+			}
+		case "TD":
+			if len(nums) == 2 {
+				gs.text.leading = -nums[1]
+				nextLine(nums[0], nums[1])
+			}
+		case "Tm":
+			if len(nums) == 6 {
+				tlm = Matrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}
+				tm = tlm
+			}
+		case "T*":
+			nextLine(0, -gs.text.leading)
+		case "Tj", "TJ", "'", "\"":
+			var shown []Object
+			switch op.Name {
+			case "TJ":
+				if len(op.Operands) == 1 {
+					shown, _ = op.Operands[0].(Array)
+				}
+			case "\"":
+				if len(op.Operands) == 3 {
+					gs.text.wordSpace, _ = toFloat(op.Operands[0])
+					gs.text.charSpace, _ = toFloat(op.Operands[1])
+					shown = op.Operands[2:]
+				}
+			default:
+				shown = op.Operands
+			}
+			if op.Name == "'" || op.Name == "\"" {
+				nextLine(0, -gs.text.leading)
+			}
+
+			arr, removed := rd.show(shown, &gs, &tm)
+			if removed {
+				changed = true
+				if op.Name == "\"" {
+					out = append(out,
+						Op{Name: "Tw", Operands: []Object{op.Operands[0]}},
+						Op{Name: "Tc", Operands: []Object{op.Operands[1]}})
+				}
+				if op.Name == "'" || op.Name == "\"" {
+					out = append(out, Op{Name: "T*"})
+				}
+				out = append(out, Op{Name: "TJ", Operands: []Object{arr}})
+				continue
+			}
+		case "BI":
+			box := gs.ctm.TransformRect(Rect{X0: 0, Y0: 0, X1: 1, Y1: 1})
+			if rd.covered(box) {
+				rd.stats.Images++
+				changed = true
+				continue
+			}
+			if rd.intersects(box) {
+				rd.stats.PartialImages++
+			}
+		case "Do":
+			if len(op.Operands) != 1 {
+				break
+			}
+			name, _ := op.Operands[0].(Name)
+			used[name] = true
+			xobj, ok := rd.e.Resolve(xobjects[name]).(*Stream)
+			if !ok {
+				break
+			}
+			switch rd.e.Resolve(xobj.Dict["Subtype"]) {
+			case Name("Image"):
+				box := gs.ctm.TransformRect(Rect{X0: 0, Y0: 0, X1: 1, Y1: 1})
+				if rd.covered(box) {
+					rd.stats.Images++
+					delete(used, name)
+					dropped[name] = true
+					changed = true
+					continue
+				}
+				if rd.intersects(box) {
+					rd.stats.PartialImages++
+				}
+			case Name("Form"):
+				if depth >= maxFormDepth {
+					break
+				}
+				replacement, ok := rd.form(xobj, resources, gs.ctm, depth)
+				if !ok {
+					break
+				}
+				if newXObjects == nil {
+					newXObjects = copyDict(xobjects)
+				}
+				rd.renamed++
+				newName := Name(string(name) + "_r" + strconv.Itoa(rd.renamed))
+				newXObjects[newName] = replacement
+				out = append(out, Op{Name: "Do", Operands: []Object{newName}})
+				changed = true
+				continue
+			}
+		}
+		out = append(out, op)
+	}
+
+	if !changed {
+		return data, nil, false
+	}
+	// Unreference images that are no longer drawn so that the writer
+	// does not keep their data
+	for name := range dropped {
+		if used[name] {
+			continue
+		}
+		if newXObjects == nil {
+			newXObjects = copyDict(xobjects)
+		}
+		delete(newXObjects, name)
+	}
+	var newResources Dict
+	if newXObjects != nil {
+		newResources = copyDict(resources)
+		newResources["XObject"] = newXObjects
+	}
+	return SerializeContent(out), newResources, true
+}
+
+// form redacts a form XObject drawn with the given CTM and returns a
+// reference to a redacted copy, or false when nothing was removed.
+func (rd *redactor) form(xobj *Stream, parentResources Dict, ctm Matrix, depth int) (Ref, bool) {
+	data, err := DecodeStream(xobj, rd.e.Resolve)
+	if err != nil {
+		return Ref{}, false
+	}
+	formCTM := ctm
+	if m, ok := rd.e.Resolve(xobj.Dict["Matrix"]).(Array); ok {
+		if vals, ok := toFloats(resolveAll(m, rd.e.Resolve)); ok && len(vals) == 6 {
+			formCTM = Matrix{vals[0], vals[1], vals[2], vals[3], vals[4], vals[5]}.Multiply(ctm)
+		}
+	}
+	resources, ownResources := rd.e.Resolve(xobj.Dict["Resources"]).(Dict)
+	if !ownResources {
+		resources = parentResources
+	}
+
+	newData, newResources, changed := rd.content(data, resources, formCTM, depth+1)
+	if !changed {
+		return Ref{}, false
+	}
+	dict := copyDict(xobj.Dict)
+	if newResources != nil {
+		dict["Resources"] = newResources
+	} else if !ownResources && resources != nil {
+		dict["Resources"] = resources
+	}
+	return rd.e.Add(NewFlateStream(dict, newData)), true
+}
+
+// show lays out the operands of a text showing operator, advancing the
+// text matrix, and returns them as a TJ array with every redacted glyph
+// replaced by an equivalent displacement.
+func (rd *redactor) show(elems []Object, gs *redactGState, tm *Matrix) (Array, bool) {
+	ts := gs.text
+	var out Array
+	removed := false
+
+	appendString := func(s string) {
+		if n := len(out); n > 0 {
+			if prev, ok := out[n-1].(String); ok {
+				out[n-1] = prev + String(s)
+				return
+			}
+		}
+		out = append(out, String(s))
+	}
+	appendKern := func(k float64) {
+		if n := len(out); n > 0 {
+			if prev, ok := out[n-1].(float64); ok {
+				out[n-1] = round3(prev + k)
+				return
+			}
+		}
+		out = append(out, round3(k))
+	}
+
+	for _, el := range elems {
+		if k, ok := toFloat(el); ok {
+			tx := -k / 1000 * ts.size * ts.scale
+			*tm = Matrix{1, 0, 0, 1, tx, 0}.Multiply(*tm)
+			appendKern(k)
+			continue
+		}
+		s, ok := el.(String)
+		if !ok {
+			continue
+		}
+		for _, code := range ts.font.codes(string(s)) {
+			w0 := ts.font.width(code)
+			advance := w0*ts.size + ts.charSpace
+			if !ts.font.twoByte && code == ' ' {
+				advance += ts.wordSpace
+			}
+
+			trm := Matrix{ts.size * ts.scale, 0, 0, ts.size, 0, ts.rise}.Multiply(*tm).Multiply(gs.ctm)
+			box := trm.TransformRect(Rect{X0: 0, Y0: -0.2, X1: w0, Y1: 0.8})
+			if rd.hit(box) {
+				removed = true
+				rd.stats.Glyphs++
+				if ts.size != 0 {
+					appendKern(-advance * 1000 / ts.size)
+				}
+			} else {
+				appendString(ts.font.encodeCode(code))
+			}
+			*tm = Matrix{1, 0, 0, 1, advance * ts.scale, 0}.Multiply(*tm)
+		}
+	}
+	return out, removed
+}
+
+// copyDict returns a shallow copy of d.
+func copyDict(d Dict) Dict {
+	out := make(Dict, len(d)+1)
+	for k, v := range d {
+		out[k] = v
+	}
+	return out
+}
+
+// resolveAll resolves every element of an array.
+func resolveAll(arr Array, resolve func(Object) Object) []Object {
+	out := make([]Object, len(arr))
+	for i, el := range arr {
+		out[i] = resolve(el)
+	}
+	return out
+}
+
+// rectFromObject converts a rectangle array object into a Rect.
+func rectFromObject(o Object, resolve func(Object) Object) (Rect, bool) {
+	arr, ok := o.(Array)
+	if !ok {
+		return Rect{}, false
+	}
+	vals, ok := toFloats(resolveAll(arr, resolve))
+	if !ok {
+		return Rect{}, false
+	}
+	return rectFromArray(vals)
+}
+
+// round3 rounds v to three decimal places.
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,247 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	gopdf "github.com/ledongthuc/pdf"
+)
+
+// RepairReport describes how a damaged file was recovered.
+type RepairReport struct {
+	// Objects is the number of objects recovered and SkippedObjects the
+	// numbers of objects whose definitions could not be parsed.
+	Objects        int
+	SkippedObjects []int
+
+	// Pages is the number of pages recovered and SkippedPages the number
+	// of page tree entries left out because their page object was
+	// missing or damaged.
+	Pages        int
+	SkippedPages int
+
+	// RebuiltTrailer reports that the trailer named no usable catalog and
+	// one was found by scanning or created; RebuiltPageTree that the page
+	// tree was unreadable and rebuilt from the page objects found, in
+	// object number order.
+	RebuiltTrailer  bool
+	RebuiltPageTree bool
+}
+
+// objHeader matches the "num gen obj" header of an indirect object at the
+// start of a line or after whitespace.
+var objHeader = regexp.MustCompile(`(?:^|[\r\n\t \f\x00])(\d{1,10})[\r\n\t \f\x00]+(\d{1,5})[\r\n\t \f\x00]+obj\b`)
+
+// OpenRepaired opens a PDF whose cross-reference table or page tree is
+// missing or damaged. The objects are recovered by scanning data for
+// their headers, the trailer and page tree are rebuilt where needed and
+// the Reader is backed by a rewritten copy, as for encrypted documents.
+func OpenRepaired(data []byte, password string) (*Reader, error) {
+	raw, report, err := RepairFile(data)
+	if err != nil {
+		return nil, err
+	}
+	var info *EncryptionInfo
+	if raw.trailer["Encrypt"] != nil {
+		enc, _ := raw.Encryption()
+		info = &enc
+		if err := raw.Decrypt(password); err != nil {
+			return nil, err
+		}
+	}
+	structure := raw.Structure()
+	sum := sha256.Sum256(data)
+
+	plain, err := raw.repairedCopy(report)
+	if err != nil {
+		return nil, err
+	}
+	r, err := gopdf.NewReader(bytes.NewReader(plain), int64(len(plain)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repaired PDF: %w", err)
+	}
+	return &Reader{
+		reader:     r,
+		src:        bytes.NewReader(plain),
+		size:       int64(len(plain)),
+		encryption: info,
+		structure:  &structure,
+		sum:        hex.EncodeToString(sum[:]),
+		original:   data,
+		repair:     report,
+		rows:       newRowCache(DefaultRowCacheSize),
+	}, nil
+}
+
+// RepairFile parses a PDF without relying on its cross-reference data:
+// every indirect object whose header is found is recovered, later
+// definitions replacing earlier ones as incremental updates do, and the
+// trailer is merged from all trailer dictionaries and cross-reference
+// streams. A catalog is looked up by type when the trailer names none.
+func RepairFile(data []byte) (*File, *RepairReport, error) {
+	f := &File{
+		data:    data,
+		version: headerVersion(data),
+		xref:    make(map[int]xrefEntry),
+		cache:   make(map[int]Object),
+		objStms: make(map[int]*objectStream),
+	}
+	report := &RepairReport{}
+
+	skipped := make(map[int]bool)
+	var xrefStreams []Dict
+	for pos := 0; pos < len(data); {
+		m := objHeader.FindSubmatchIndex(data[pos:])
+		if m == nil {
+			break
+		}
+		start := pos + m[2]
+		num, _ := strconv.Atoi(string(data[pos+m[2] : pos+m[3]]))
+		l := newLexer(data)
+		l.pos = start
+		ref, obj, err := f.parseIndirect(l)
+		if err != nil || ref.Num != num {
+			skipped[num] = true
+			pos += m[1]
+			continue
+		}
+		delete(skipped, num)
+		f.xref[num] = xrefEntry{kind: 1, offset: int64(start), gen: ref.Gen}
+		f.cache[num] = obj
+		if stm, ok := obj.(*Stream); ok && stm.Dict["Type"] == Name("XRef") {
+			xrefStreams = append(xrefStreams, stm.Dict)
+		}
+		// Continue after the object so stream data is not scanned
+		pos = max(l.pos, pos+m[1])
+	}
+	if len(f.xref) == 0 {
+		return nil, nil, fmt.Errorf("no objects found")
+	}
+
+	f.trailer = Dict{}
+	for _, d := range xrefStreams {
+		mergeTrailer(f.trailer, d)
+	}
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("trailer"))
+		if j < 0 {
+			break
+		}
+		l := newLexer(data)
+		l.pos = i + j + len("trailer")
+		if d, _, err := l.readObject(); err == nil {
+			if d, ok := d.(Dict); ok {
+				mergeTrailer(f.trailer, d)
+			}
+		}
+		i += j + len("trailer")
+	}
+
+	// Objects in object streams, unless defined directly. The objects
+	// parsed so far are cached undecrypted, so encrypted files are left to
+	// be parsed again on access, without their object streams.
+	if f.trailer["Encrypt"] != nil {
+		f.cache = make(map[int]Object)
+	} else {
+		for _, num := range f.ObjectNumbers() {
+			stm, ok := f.cache[num].(*Stream)
+			if !ok || stm.Dict["Type"] != Name("ObjStm") {
+				continue
+			}
+			objStm, err := f.objectStream(num)
+			if err != nil {
+				continue
+			}
+			for i, n := range objStm.nums {
+				if _, ok := f.xref[n]; !ok {
+					f.xref[n] = xrefEntry{kind: 2, stream: num, index: i}
+					delete(skipped, n)
+				}
+			}
+		}
+	}
+
+	if catalog, ok := f.Resolve(f.trailer["Root"]).(Dict); !ok || catalog["Pages"] == nil {
+		delete(f.trailer, "Root")
+		nums := f.ObjectNumbers()
+		for i := len(nums) - 1; i >= 0; i-- {
+			if d, ok := f.Resolve(Ref{Num: nums[i]}).(Dict); ok && d["Type"] == Name("Catalog") {
+				f.trailer["Root"] = Ref{Num: nums[i], Gen: f.xref[nums[i]].gen}
+				break
+			}
+		}
+		report.RebuiltTrailer = true
+	}
+
+	report.Objects = len(f.xref)
+	for num := range skipped {
+		report.SkippedObjects = append(report.SkippedObjects, num)
+	}
+	sort.Ints(report.SkippedObjects)
+	return f, report, nil
+}
+
+// mergeTrailer copies the document-level entries of a trailer dictionary
+// or cross-reference stream into dst, later calls overriding earlier.
+func mergeTrailer(dst, src Dict) {
+	for _, key := range []Name{"Root", "Info", "ID", "Encrypt"} {
+		if v, ok := src[key]; ok {
+			dst[key] = v
+		}
+	}
+}
+
+// repairedCopy writes the recovered file as a new PDF with a page tree of
+// the pages that could be read, rebuilding the tree and catalog from the
+// page objects found when they are unreadable.
+func (f *File) repairedCopy(report *RepairReport) ([]byte, error) {
+	e, err := NewEditor(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []Ref
+	refs, err := e.PageRefs()
+	if err == nil {
+		for _, ref := range refs {
+			if _, ok := e.Resolve(ref).(Dict); ok {
+				pages = append(pages, ref)
+			} else {
+				report.SkippedPages++
+			}
+		}
+	}
+	if err != nil || len(pages) == 0 {
+		report.SkippedPages = 0
+		report.RebuiltPageTree = true
+		pages = nil
+		for _, num := range f.ObjectNumbers() {
+			if d, ok := e.Resolve(Ref{Num: num}).(Dict); ok && d["Type"] == Name("Page") {
+				pages = append(pages, Ref{Num: num, Gen: f.xref[num].gen})
+			}
+		}
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found")
+	}
+	report.Pages = len(pages)
+
+	if report.RebuiltPageTree || report.SkippedPages > 0 {
+		if e.Catalog() == nil {
+			e.Trailer["Root"] = e.Add(Dict{"Type": Name("Catalog")})
+			report.RebuiltTrailer = true
+		}
+		e.setFlatPageTree(pages)
+	}
+
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,162 @@
+package pdf
+
+// resourceCategories are the resource dictionary entries PageResources
+// lists, in order.
+var resourceCategories = []Name{"Font", "XObject", "ExtGState", "ColorSpace", "Pattern", "Shading"}
+
+// Resource is a named resource in the resources of a page or of a form
+// XObject it paints.
+type Resource struct {
+	// Category is the resource dictionary entry that holds the resource:
+	// Font, XObject, ExtGState, ColorSpace, Pattern or Shading.
+	Category string
+
+	// Name is the name content streams use for the resource, such as F1
+	// or Im3.
+	Name string
+
+	// Subtype is the kind of resource within its category, such as
+	// TrueType or Type0 for fonts, Image or Form for XObjects and the
+	// color space family, such as ICCBased, for color spaces.
+	Subtype string
+
+	// Object is the object number of the resource, or 0 for a resource
+	// written directly into the resource dictionary.
+	Object int
+
+	// Form is the name of the form XObject whose resources hold the
+	// resource, or empty for the page's own resources.
+	Form string
+
+	// Size is the size in bytes of the resource and the objects it refers
+	// to, such as font programs, ICC profiles and soft masks, with stream
+	// data counted as stored in the file. The resources of a form XObject
+	// are listed on their own and not included in its size.
+	Size int64
+}
+
+// resourceSizeSkip are the entries whose objects are not part of the
+// size of a resource: resources listed on their own and back references.
+var resourceSizeSkip = map[Name]bool{"Resources": true, "Parent": true, "P": true}
+
+// PageResources returns the resources of a page, including the resources
+// of the form XObjects it holds, form by form. Each resource of a
+// resource dictionary is listed once, whether or not the content paints
+// it.
+func (f *File) PageResources(page Dict) []Resource {
+	var out []Resource
+	visited := make(map[int]bool)
+
+	var walk func(resources Dict, form string, depth int)
+	walk = func(resources Dict, form string, depth int) {
+		for _, category := range resourceCategories {
+			entries, _ := f.Resolve(resources[category]).(Dict)
+			for _, name := range sortedKeys(entries) {
+				entry := entries[name]
+				res := Resource{
+					Category: string(category),
+					Name:     string(name),
+					Subtype:  resourceSubtype(category, f.Resolve(entry)),
+					Form:     form,
+					Size:     f.resourceSize(entry, make(map[int]bool)),
+				}
+				if r, ok := entry.(Ref); ok {
+					res.Object = r.Num
+				}
+				out = append(out, res)
+
+				if res.Subtype != "Form" || depth >= maxFormDepth {
+					continue
+				}
+				if res.Object != 0 {
+					if visited[res.Object] {
+						continue
+					}
+					visited[res.Object] = true
+				}
+				if xobj, ok := f.Resolve(entry).(*Stream); ok {
+					if formResources, ok := f.Resolve(xobj.Dict["Resources"]).(Dict); ok {
+						walk(formResources, string(name), depth+1)
+					}
+				}
+			}
+		}
+	}
+
+	if resources, ok := f.Resolve(f.InheritedAttr(page, "Resources")).(Dict); ok {
+		walk(resources, "", 0)
+	}
+	return out
+}
+
+// resourceSubtype returns the kind of a resource within its category.
+func resourceSubtype(category Name, o Object) string {
+	var dict Dict
+	switch v := o.(type) {
+	case Dict:
+		dict = v
+	case *Stream:
+		dict = v.Dict
+	case Name:
+		if category == "ColorSpace" {
+			return string(v)
+		}
+	case Array:
+		if category == "ColorSpace" && len(v) > 0 {
+			family, _ := v[0].(Name)
+			return string(family)
+		}
+	}
+	switch category {
+	case "Pattern":
+		switch dict["PatternType"] {
+		case int64(1):
+			return "Tiling"
+		case int64(2):
+			return "Shading"
+		}
+		return ""
+	case "Shading":
+		if t, ok := dict["ShadingType"].(int64); ok {
+			return shadingTypes[t]
+		}
+		return ""
+	}
+	subtype, _ := dict["Subtype"].(Name)
+	return string(subtype)
+}
+
+// shadingTypes names the shading types (PDF 32000-1:2008, table 78).
+var shadingTypes = map[int64]string{
+	1: "Function", 2: "Axial", 3: "Radial", 4: "FreeForm",
+	5: "Lattice", 6: "Coons", 7: "TensorProduct",
+}
+
+// resourceSize returns the size in bytes of o and the objects it refers
+// to, counting each object once.
+func (f *File) resourceSize(o Object, seen map[int]bool) int64 {
+	if r, ok := o.(Ref); ok {
+		if seen[r.Num] {
+			return 0
+		}
+		seen[r.Num] = true
+		o = f.Resolve(r)
+	}
+	var size int64
+	if s, ok := o.(*Stream); ok {
+		size += int64(len(s.Data))
+		o = s.Dict
+	}
+	size += int64(len(SerializeObject(o)))
+	if d, ok := o.(Dict); ok {
+		refs := make(Dict, len(d))
+		for k, v := range d {
+			if !resourceSizeSkip[k] {
+				refs[k] = v
+			}
+		}
+		o = refs
+	}
+	collectRefs(o, func(r Ref) { size += f.resourceSize(r, seen) })
+	return size
+}
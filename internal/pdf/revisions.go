@@ -0,0 +1,121 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Revision is a saved state of a file: the original file or the file as
+// of one of its incremental updates.
+type Revision struct {
+	// Size is the length of the file up to the end of the revision's
+	// %%EOF line; the first Size bytes of the file are the revision.
+	Size int64
+
+	// Added, Modified and Deleted are the numbers of the objects the
+	// revision added, redefined and freed compared to the previous one.
+	// For the first revision every object counts as added.
+	Added    []int
+	Modified []int
+	Deleted  []int
+
+	// Signatures is the number of signature byte ranges that end with
+	// the revision, meaning they sign the file as of this revision.
+	Signatures int
+}
+
+// eofMarker matches the %%EOF marker with the end-of-line after it.
+var eofMarker = regexp.MustCompile(`%%EOF(?:\r\n|\r|\n)?`)
+
+// byteRange matches the /ByteRange of a signature dictionary. Signature
+// dictionaries are never compressed, as signing patches their contents
+// in place, so they can be found in the raw bytes.
+var byteRange = regexp.MustCompile(`/ByteRange\s*\[\s*(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s*\]`)
+
+// Revisions returns the revisions of a file, oldest first. Each %%EOF
+// marker ends a candidate revision, which is kept when the bytes before
+// it parse as a complete file with a cross-reference section of its own.
+// This skips the first-page section of linearized files, which points
+// to the main section after it, and %%EOF sequences inside stream data.
+func Revisions(data []byte) []Revision {
+	ends := make([]int, 0, 2)
+	for _, m := range eofMarker.FindAllIndex(data, -1) {
+		ends = append(ends, m[1])
+	}
+	if len(ends) == 0 || ends[len(ends)-1] < len(bytes.TrimRight(data, "\r\n\t \x00")) {
+		ends = append(ends, len(data)) // no %%EOF after the last update
+	}
+
+	var revs []Revision
+	var prev *File
+	lastStart := int64(-1)
+	for _, end := range ends {
+		prefix := data[:end]
+		start, err := findStartXref(prefix)
+		if err != nil || start == lastStart {
+			continue
+		}
+		f, err := ParseFile(prefix)
+		if err != nil {
+			continue
+		}
+		if p, ok := f.trailer["Prev"].(int64); ok && p >= int64(end) {
+			continue // linearized first-page section
+		}
+		rev := Revision{Size: int64(end)}
+		rev.Added, rev.Modified, rev.Deleted = diffXref(prev, f)
+		revs = append(revs, rev)
+		prev, lastStart = f, start
+	}
+
+	for _, m := range byteRange.FindAllSubmatch(data, -1) {
+		off, _ := strconv.ParseInt(string(m[3]), 10, 64)
+		n, _ := strconv.ParseInt(string(m[4]), 10, 64)
+		if i := signedRevision(revs, data, off+n); i >= 0 {
+			revs[i].Signatures++
+		}
+	}
+	return revs
+}
+
+// signedRevision returns the index of the revision a byte range ending at
+// end signs, or -1. Signers cover the file up to the %%EOF marker with or
+// without the end-of-line after it.
+func signedRevision(revs []Revision, data []byte, end int64) int {
+	for i, rev := range revs {
+		body := bytes.TrimRight(data[:rev.Size], "\r\n")
+		if end >= int64(len(body)) && end <= rev.Size {
+			return i
+		}
+	}
+	return -1
+}
+
+// diffXref compares the cross-reference entries of two revisions, prev
+// nil meaning an empty file.
+func diffXref(prev, cur *File) (added, modified, deleted []int) {
+	for num, e := range cur.xref {
+		if num == 0 {
+			continue
+		}
+		var old xrefEntry
+		if prev != nil {
+			old = prev.xref[num]
+		}
+		switch {
+		case e.kind == 0 && old.kind != 0:
+			deleted = append(deleted, num)
+		case e.kind == 0:
+		case old.kind == 0:
+			added = append(added, num)
+		case e != old:
+			modified = append(modified, num)
+		}
+	}
+	sort.Ints(added)
+	sort.Ints(modified)
+	sort.Ints(deleted)
+	return added, modified, deleted
+}
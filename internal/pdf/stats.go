@@ -0,0 +1,92 @@
+package pdf
+
+// Stats counts the objects of a document by kind.
+type Stats struct {
+	Pages   int
+	Objects int
+
+	// Streams is the number of stream objects and StreamBytes the size of
+	// their data as stored, usually compressed.
+	Streams     int
+	StreamBytes int64
+
+	// Images is the number of image XObjects and ImageBytes the size of
+	// their data as stored.
+	Images     int
+	ImageBytes int64
+
+	// Fonts is the number of font dictionaries, not counting the CIDFonts
+	// that Type0 fonts hold.
+	Fonts int
+
+	// Annotations is the number of entries in the /Annots arrays of the
+	// pages.
+	Annotations int
+
+	// ContentBytes is the size of the page content streams once decoded.
+	// Streams shared by several pages are counted once.
+	ContentBytes int64
+}
+
+// Stats counts pages, objects, streams, images, fonts and annotations in
+// one pass over the objects of the file, then decodes the content streams
+// of the pages to measure them. Objects that cannot be parsed are
+// counted but not classified, and content streams that cannot be
+// decoded add nothing to ContentBytes.
+func (f *File) Stats() (Stats, error) {
+	refs, err := f.PageRefs()
+	if err != nil {
+		return Stats{}, err
+	}
+	s := Stats{Pages: len(refs)}
+	for _, num := range f.ObjectNumbers() {
+		s.Objects++
+		obj, _ := f.Object(num)
+		switch v := obj.(type) {
+		case *Stream:
+			s.Streams++
+			s.StreamBytes += int64(len(v.Data))
+			if subtype, _ := f.Resolve(v.Dict["Subtype"]).(Name); subtype == "Image" {
+				s.Images++
+				s.ImageBytes += int64(len(v.Data))
+			}
+		case Dict:
+			typ, _ := f.Resolve(v["Type"]).(Name)
+			subtype, _ := f.Resolve(v["Subtype"]).(Name)
+			if typ == "Font" && subtype != "CIDFontType0" && subtype != "CIDFontType2" {
+				s.Fonts++
+			}
+		}
+	}
+
+	decoded := make(map[int]bool)
+	for _, ref := range refs {
+		page, ok := f.Resolve(ref).(Dict)
+		if !ok {
+			continue
+		}
+		if annots, ok := f.Resolve(page["Annots"]).(Array); ok {
+			s.Annotations += len(annots)
+		}
+		contents := []Object{page["Contents"]}
+		if arr, ok := f.Resolve(page["Contents"]).(Array); ok {
+			contents = arr
+		}
+		for _, c := range contents {
+			if r, ok := c.(Ref); ok {
+				if decoded[r.Num] {
+					continue
+				}
+				decoded[r.Num] = true
+			}
+			stream, ok := f.Resolve(c).(*Stream)
+			if !ok {
+				continue
+			}
+			if data, err := f.DecodeStream(stream); err == nil {
+				s.ContentBytes += int64(len(data))
+			}
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,133 @@
+package pdf
+
+// maxStructDepth bounds the nesting of structure elements walked.
+const maxStructDepth = 256
+
+// StructFigure is a Figure element of the document structure tree.
+type StructFigure struct {
+	// Page is the 1-based page the figure is on, or 0 when unknown.
+	Page int
+
+	// Alt is the alternate description of the figure and ActualText its
+	// replacement text, empty when not given.
+	Alt        string
+	ActualText string
+
+	// MCIDs are the marked-content sequences of the page content that
+	// belong to the figure.
+	MCIDs []int
+
+	// BBox is the bounding box of the figure given by its layout
+	// attributes, when HasBBox is set.
+	BBox    Rect
+	HasBBox bool
+}
+
+// StructFigures returns the Figure elements of the structure tree in
+// document order, following the role map for custom element types.
+// pageRefs are the page references, to number the pages of figures.
+func (f *File) StructFigures(pageRefs []Ref) []StructFigure {
+	catalog, _ := f.Resolve(f.Trailer()["Root"]).(Dict)
+	root, ok := f.Resolve(catalog["StructTreeRoot"]).(Dict)
+	if !ok {
+		return nil
+	}
+	roleMap, _ := f.Resolve(root["RoleMap"]).(Dict)
+	pageNumbers := make(map[int]int, len(pageRefs))
+	for i, ref := range pageRefs {
+		pageNumbers[ref.Num] = i + 1
+	}
+
+	var figures []StructFigure
+	visited := make(map[int]bool)
+	var walk func(o Object, page, depth int, fig *StructFigure)
+	walk = func(o Object, page, depth int, fig *StructFigure) {
+		if depth > maxStructDepth {
+			return
+		}
+		if ref, ok := o.(Ref); ok {
+			if visited[ref.Num] {
+				return
+			}
+			visited[ref.Num] = true
+		}
+		switch v := f.Resolve(o).(type) {
+		case int64:
+			if fig != nil {
+				fig.MCIDs = append(fig.MCIDs, int(v))
+			}
+		case Array:
+			for _, kid := range v {
+				walk(kid, page, depth+1, fig)
+			}
+		case Dict:
+			if pg, ok := v["Pg"].(Ref); ok {
+				page = pageNumbers[pg.Num]
+				if fig != nil && fig.Page == 0 {
+					fig.Page = page
+				}
+			}
+			switch f.Resolve(v["Type"]) {
+			case Name("MCR"):
+				if id, ok := f.Resolve(v["MCID"]).(int64); ok && fig != nil {
+					fig.MCIDs = append(fig.MCIDs, int(id))
+				}
+				return
+			case Name("OBJR"):
+				return
+			}
+			if fig == nil && f.structRole(v, roleMap) == "Figure" {
+				figures = append(figures, f.structFigure(v, page))
+				fig = &figures[len(figures)-1]
+				walk(v["K"], page, depth+1, fig)
+				return
+			}
+			walk(v["K"], page, depth+1, fig)
+		}
+	}
+	walk(root["K"], 0, 0, nil)
+	return figures
+}
+
+// structRole returns the standard type of a structure element, following
+// the role map for custom types.
+func (f *File) structRole(elem, roleMap Dict) Name {
+	role, _ := f.Resolve(elem["S"]).(Name)
+	for i := 0; i < 8; i++ {
+		mapped, ok := f.Resolve(roleMap[role]).(Name)
+		if !ok || mapped == role {
+			break
+		}
+		role = mapped
+	}
+	return role
+}
+
+// structFigure returns the text and layout attributes of a Figure
+// element.
+func (f *File) structFigure(elem Dict, page int) StructFigure {
+	fig := StructFigure{Page: page}
+	if alt, ok := f.Resolve(elem["Alt"]).(String); ok {
+		fig.Alt = DecodeTextString(alt)
+	}
+	if actual, ok := f.Resolve(elem["ActualText"]).(String); ok {
+		fig.ActualText = DecodeTextString(actual)
+	}
+
+	// The attributes are a dictionary or an array of dictionaries, each
+	// optionally followed by a revision number
+	attrs := []Object{elem["A"]}
+	if arr, ok := f.Resolve(elem["A"]).(Array); ok {
+		attrs = arr
+	}
+	for _, a := range attrs {
+		attr, ok := f.Resolve(a).(Dict)
+		if !ok || f.Resolve(attr["O"]) != Name("Layout") {
+			continue
+		}
+		if bbox, ok := rectFromObject(f.Resolve(attr["BBox"]), f.Resolve); ok {
+			fig.BBox, fig.HasBBox = bbox, true
+		}
+	}
+	return fig
+}
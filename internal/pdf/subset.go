@@ -0,0 +1,182 @@
+package pdf
+
+import "fmt"
+
+// KeepPages reduces the document to the pages at the given 0-based
+// indexes, in that order; a page listed twice is copied. The page tree is
+// replaced by a flat one and the catalog entries that describe the whole
+// document, the outline, page labels, structure tree and open action, are
+// removed, as are links to the pages left out. Other references to those
+// pages become null, so the output holds none of their content.
+func (e *Editor) KeepPages(indexes []int) error {
+	refs, err := e.PageRefs()
+	if err != nil {
+		return err
+	}
+	pages := make([]Ref, len(indexes))
+	kept := make(map[int]bool, len(indexes))
+	for i, idx := range indexes {
+		if idx < 0 || idx >= len(refs) {
+			return fmt.Errorf("page %d out of range: document has %d pages", idx+1, len(refs))
+		}
+		pages[i] = refs[idx]
+		if kept[refs[idx].Num] {
+			page, _ := e.Resolve(refs[idx]).(Dict)
+			pages[i] = e.Add(copyDict(page))
+		}
+		kept[refs[idx].Num] = true
+	}
+
+	// The old tree: every page and the nodes above them
+	drop := make(map[int]bool)
+	for _, ref := range refs {
+		drop[ref.Num] = true
+		node, _ := e.Resolve(ref).(Dict)
+		for depth := 0; node != nil && depth < 64; depth++ {
+			parent, ok := node["Parent"].(Ref)
+			if !ok || drop[parent.Num] {
+				break
+			}
+			drop[parent.Num] = true
+			node, _ = e.Resolve(parent).(Dict)
+		}
+	}
+	for _, ref := range pages {
+		delete(drop, ref.Num)
+	}
+	for _, ref := range pages {
+		e.removeLinksTo(ref, drop)
+	}
+
+	e.setFlatPageTree(pages)
+	for _, key := range []Name{"Outlines", "PageLabels", "StructTreeRoot", "OpenAction"} {
+		e.SetCatalogEntry(key, nil)
+	}
+	e.dropRefs(drop)
+	return nil
+}
+
+// removeLinksTo removes the link annotations of a page whose destination
+// is one of the pages in drop.
+func (e *Editor) removeLinksTo(ref Ref, drop map[int]bool) {
+	page, _ := e.Resolve(ref).(Dict)
+	annots, ok := e.Resolve(page["Annots"]).(Array)
+	if !ok {
+		return
+	}
+	var kept Array
+	for _, a := range annots {
+		annot, _ := e.Resolve(a).(Dict)
+		dest := annot["Dest"]
+		if action, ok := e.Resolve(annot["A"]).(Dict); ok && action["S"] == Name("GoTo") {
+			dest = action["D"]
+		}
+		if arr, ok := e.Resolve(dest).(Array); ok && len(arr) > 0 {
+			if target, ok := arr[0].(Ref); ok && drop[target.Num] {
+				continue
+			}
+		}
+		kept = append(kept, a)
+	}
+	if len(kept) < len(annots) {
+		page = copyDict(page)
+		page["Annots"] = kept
+		e.Set(ref, page)
+	}
+}
+
+// setFlatPageTree makes pages the kids of a new root page tree node,
+// copying inherited attributes to each page, and sets it as the catalog's
+// page tree. A page without a media box gets US Letter.
+func (e *Editor) setFlatPageTree(pages []Ref) {
+	root := e.Add(nil)
+	kids := make(Array, len(pages))
+	for i, ref := range pages {
+		old, _ := e.Resolve(ref).(Dict)
+		page := make(Dict, len(old)+4)
+		for k, v := range old {
+			page[k] = v
+		}
+		for _, key := range []Name{"Resources", "MediaBox", "CropBox", "Rotate"} {
+			if v := e.InheritedAttr(old, key); v != nil {
+				page[key] = v
+			}
+		}
+		if page["MediaBox"] == nil {
+			page["MediaBox"] = Array{int64(0), int64(0), int64(612), int64(792)}
+		}
+		page["Parent"] = root
+		e.Set(ref, page)
+		kids[i] = ref
+	}
+	e.Set(root, Dict{"Type": Name("Pages"), "Kids": kids, "Count": int64(len(kids))})
+	e.SetCatalogEntry("Pages", root)
+}
+
+// dropRefs replaces references to the objects in drop with null in every
+// object reachable from the trailer.
+func (e *Editor) dropRefs(drop map[int]bool) {
+	seen := make(map[int]bool)
+	var queue []Ref
+	for _, key := range []Name{"Root", "Info"} {
+		if ref, ok := e.Trailer[key].(Ref); ok {
+			queue = append(queue, ref)
+		}
+	}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if seen[ref.Num] || drop[ref.Num] {
+			continue
+		}
+		seen[ref.Num] = true
+		obj := e.Object(ref.Num)
+		if cleaned, changed := withoutRefs(obj, drop); changed {
+			obj = cleaned
+			e.Set(ref, obj)
+		}
+		collectRefs(obj, func(r Ref) { queue = append(queue, r) })
+	}
+}
+
+// withoutRefs returns o with references to the objects in drop replaced
+// by null, copying only the containers that change.
+func withoutRefs(o Object, drop map[int]bool) (Object, bool) {
+	switch v := o.(type) {
+	case Ref:
+		if drop[v.Num] {
+			return nil, true
+		}
+	case Array:
+		var out Array
+		for i, el := range v {
+			if cleaned, changed := withoutRefs(el, drop); changed {
+				if out == nil {
+					out = append(Array(nil), v...)
+				}
+				out[i] = cleaned
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case Dict:
+		var out Dict
+		for k, el := range v {
+			if cleaned, changed := withoutRefs(el, drop); changed {
+				if out == nil {
+					out = copyDict(v)
+				}
+				out[k] = cleaned
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case *Stream:
+		if d, changed := withoutRefs(v.Dict, drop); changed {
+			return &Stream{Dict: d.(Dict), Data: v.Data}, true
+		}
+	}
+	return o, false
+}
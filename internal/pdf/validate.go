@@ -0,0 +1,263 @@
+package pdf
+
+import "fmt"
+
+// maxPageTreeDepth bounds the nesting of page tree nodes Validate walks,
+// as pageRefs does.
+const maxPageTreeDepth = 64
+
+// Severity ranks a problem found by Validate.
+type Severity int
+
+const (
+	// SeverityWarning is a violation of the specification that readers
+	// commonly tolerate, such as a missing /Type entry or a page count
+	// that does not match the pages.
+	SeverityWarning Severity = iota
+
+	// SeverityError is damage that can lose content or make readers
+	// disagree, such as an object that cannot be read or a page tree
+	// node without kids.
+	SeverityError
+)
+
+// String returns "warning" or "error".
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Problem is a structural problem of a file.
+type Problem struct {
+	Severity Severity
+
+	// Code identifies the kind of problem: "xref", "object", "stream-length",
+	// "broken-ref", "trailer-size", "catalog", "page-tree", "page-count",
+	// "page-parent", "page-mediabox", "page-resources" or "page-contents".
+	Code string
+
+	// Object is the number of the object with the problem, or 0 for the
+	// trailer and direct objects.
+	Object int
+
+	Message string
+}
+
+// String formats the problem as "error: object 12: message".
+func (p Problem) String() string {
+	if p.Object == 0 {
+		return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+	}
+	return fmt.Sprintf("%s: object %d: %s", p.Severity, p.Object, p.Message)
+}
+
+// validator collects the problems of a file.
+type validator struct {
+	f        *File
+	problems []Problem
+	visited  map[int]bool // page tree nodes reached
+}
+
+// add records a problem.
+func (v *validator) add(severity Severity, code string, object int, format string, args ...any) {
+	v.problems = append(v.problems, Problem{
+		Severity: severity,
+		Code:     code,
+		Object:   object,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// exists reports whether the cross-reference table has an in-use entry
+// for the object.
+func (v *validator) exists(num int) bool {
+	e, ok := v.f.xref[num]
+	return ok && e.kind != 0
+}
+
+// Validate checks the structure of the file: that every object in the
+// cross-reference table can be read at its recorded location, that
+// streams have a /Length matching their data, that references point at
+// objects that exist, and that the catalog and page tree have their
+// required entries. Problems are listed objects first, then the catalog
+// and the page tree in document order. A file without problems returns
+// none.
+func (f *File) Validate() []Problem {
+	v := &validator{f: f, visited: make(map[int]bool)}
+	v.objects()
+	v.catalog()
+	return v.problems
+}
+
+// Validate checks the structure of the document as stored, rather than
+// that of the decrypted or repaired copy the Reader may work on, with
+// File.Validate. Encrypted documents are checked without decrypting them.
+// A file whose cross-reference structure cannot be parsed has a single
+// "xref" problem.
+func (r *Reader) Validate() []Problem {
+	var f *File
+	var err error
+	if r.original != nil {
+		f, err = ParseFileLimit(r.original, r.limit)
+	} else {
+		f, err = r.RawFile()
+	}
+	if err != nil {
+		return []Problem{{
+			Severity: SeverityError,
+			Code:     "xref",
+			Message:  fmt.Sprintf("cross-reference structure cannot be parsed: %v", err),
+		}}
+	}
+	return f.Validate()
+}
+
+// objects checks every object of the cross-reference table, its stream
+// length and its references.
+func (v *validator) objects() {
+	nums := v.f.ObjectNumbers()
+	if size, ok := v.f.trailer["Size"].(int64); !ok {
+		v.add(SeverityWarning, "trailer-size", 0, "trailer has no /Size")
+	} else if n := len(nums); n > 0 && int64(nums[n-1]) >= size {
+		v.add(SeverityWarning, "trailer-size", 0, "trailer /Size %d does not cover object %d", size, nums[n-1])
+	}
+
+	broken := func(from int) func(Ref) {
+		seen := make(map[int]bool)
+		return func(r Ref) {
+			if !v.exists(r.Num) && !seen[r.Num] {
+				seen[r.Num] = true
+				v.add(SeverityWarning, "broken-ref", from, "refers to missing object %d", r.Num)
+			}
+		}
+	}
+	collectRefs(v.f.trailer, broken(0))
+
+	for _, num := range nums {
+		obj, err := v.f.Object(num)
+		if err != nil {
+			v.add(SeverityError, "object", num, "cannot be read: %v", err)
+			continue
+		}
+		if s, ok := obj.(*Stream); ok {
+			switch length, ok := v.f.Resolve(s.Dict["Length"]).(int64); {
+			case !ok:
+				v.add(SeverityError, "stream-length", num, "stream has no /Length")
+			case length != int64(len(s.Data)):
+				v.add(SeverityError, "stream-length", num, "stream /Length is %d but its data is %d bytes", length, len(s.Data))
+			}
+		}
+		collectRefs(obj, broken(num))
+	}
+}
+
+// catalog checks the document catalog and its page tree.
+func (v *validator) catalog() {
+	rootRef, _ := v.f.trailer["Root"].(Ref)
+	root, ok := v.f.Resolve(v.f.trailer["Root"]).(Dict)
+	if !ok {
+		v.add(SeverityError, "catalog", 0, "trailer has no /Root catalog dictionary")
+		return
+	}
+	if t, _ := v.f.Resolve(root["Type"]).(Name); t != "Catalog" {
+		v.add(SeverityWarning, "catalog", rootRef.Num, "catalog /Type is %q, not /Catalog", t)
+	}
+	if _, ok := v.f.Resolve(root["Pages"]).(Dict); !ok {
+		v.add(SeverityError, "catalog", rootRef.Num, "catalog has no /Pages dictionary")
+		return
+	}
+	v.pageNode(root["Pages"], nil, 0)
+}
+
+// pageNode checks a node of the page tree and its descendants, reached
+// from parent, and returns the number of pages below it.
+func (v *validator) pageNode(o Object, parent *Ref, depth int) int {
+	ref, _ := o.(Ref)
+	if ref.Num != 0 {
+		if v.visited[ref.Num] {
+			v.add(SeverityError, "page-tree", ref.Num, "page tree node is reached more than once")
+			return 0
+		}
+		v.visited[ref.Num] = true
+	}
+	node, ok := v.f.Resolve(o).(Dict)
+	if !ok {
+		v.add(SeverityError, "page-tree", ref.Num, "page tree node is not a dictionary")
+		return 0
+	}
+	if parent != nil {
+		if p, ok := node["Parent"].(Ref); !ok || p.Num != parent.Num {
+			v.add(SeverityWarning, "page-parent", ref.Num, "/Parent does not refer to its parent node %d", parent.Num)
+		}
+	}
+
+	t, _ := v.f.Resolve(node["Type"]).(Name)
+	_, hasKids := node["Kids"]
+	switch {
+	case t == "Pages" || (t == "" && hasKids):
+		if t == "" {
+			v.add(SeverityWarning, "page-tree", ref.Num, "page tree node has no /Type")
+		}
+		if depth >= maxPageTreeDepth {
+			v.add(SeverityError, "page-tree", ref.Num, "page tree is nested more than %d levels deep", maxPageTreeDepth)
+			return 0
+		}
+		kids, ok := v.f.Resolve(node["Kids"]).(Array)
+		if !ok {
+			v.add(SeverityError, "page-tree", ref.Num, "page tree node has no /Kids array")
+			return 0
+		}
+		pages := 0
+		for _, kid := range kids {
+			if r, ok := kid.(Ref); ok && !v.exists(r.Num) {
+				v.add(SeverityError, "page-tree", ref.Num, "/Kids refers to missing object %d", r.Num)
+				continue
+			}
+			pages += v.pageNode(kid, &ref, depth+1)
+		}
+		switch count, ok := v.f.Resolve(node["Count"]).(int64); {
+		case !ok:
+			v.add(SeverityError, "page-count", ref.Num, "page tree node has no /Count")
+		case count != int64(pages):
+			v.add(SeverityWarning, "page-count", ref.Num, "/Count is %d but the node has %d pages", count, pages)
+		}
+		return pages
+	case t == "Page" || t == "":
+		if t == "" {
+			v.add(SeverityWarning, "page-tree", ref.Num, "page has no /Type")
+		}
+		v.page(node, ref.Num)
+		return 1
+	default:
+		v.add(SeverityError, "page-tree", ref.Num, "page tree node has /Type /%s", t)
+		return 0
+	}
+}
+
+// page checks the required and inheritable entries of a page.
+func (v *validator) page(page Dict, num int) {
+	if _, ok := rectFromObject(v.f.Resolve(v.f.InheritedAttr(page, "MediaBox")), v.f.Resolve); !ok {
+		v.add(SeverityError, "page-mediabox", num, "page has no valid /MediaBox")
+	}
+	if _, ok := v.f.Resolve(v.f.InheritedAttr(page, "Resources")).(Dict); !ok {
+		v.add(SeverityWarning, "page-resources", num, "page has no /Resources dictionary")
+	}
+	contents, ok := page["Contents"]
+	if !ok {
+		return
+	}
+	streams := []Object{contents}
+	if arr, ok := v.f.Resolve(contents).(Array); ok {
+		streams = arr
+	}
+	for _, s := range streams {
+		if _, ok := v.f.Resolve(s).(*Stream); !ok {
+			if r, ok := s.(Ref); !ok || v.exists(r.Num) {
+				v.add(SeverityError, "page-contents", num, "/Contents is not a stream or an array of streams")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package pdf
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Word is a run of glyphs on one row that are not separated by a space,
+// with its bounding box and dominant font.
+type Word struct {
+	S        string
+	BBox     Rect
+	Font     string
+	FontSize float64
+
+	// ID identifies the word among those of the document; see StableID.
+	ID string
+}
+
+// PageWords returns the words on a page (1-based), ordered by row from top
+// to bottom and by X position within each row. Glyphs are merged into a
+// word unless separated by whitespace or a horizontal gap wider than a
+// fraction of the font size. Word boxes span the advance widths of their
+// glyphs, from a fifth of the font size below the baseline to four fifths
+// above it.
+func (r *Reader) PageWords(pageNum int) ([]Word, error) {
+	glyphs, err := r.pageGlyphs(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Word
+	for _, line := range glyphLines(glyphs) {
+		result = append(result, groupWords(line)...)
+	}
+	for i := range result {
+		result[i].ID = StableID("w", pageNum, i, result[i].S, result[i].BBox)
+	}
+	return result, nil
+}
+
+// StableID returns the identifier of the index-th element of a kind, such
+// as "w" for words or "b" for blocks, on a page (1-based): "p3-w12-"
+// followed by eight hex digits hashing its text and its box to a tenth of
+// a point. Extracting the same document again gives the same IDs, and an
+// ID whose hash no longer matches refers to content that changed.
+func StableID(kind string, pageNum, index int, text string, box Rect) string {
+	h := fnv.New32a()
+	h.Write([]byte(text))
+	for _, v := range [4]float64{box.X0, box.Y0, box.X1, box.Y1} {
+		fmt.Fprintf(h, "|%.1f", math.Round(v*10)/10+0) // +0 folds -0 into 0
+	}
+	return fmt.Sprintf("p%d-%s%d-%08x", pageNum, kind, index, h.Sum32())
+}
+
+// groupWords merges X-sorted glyphs of a single row into words. A glyph
+// decoding to several characters, such as a ligature, has its width
+// apportioned evenly across them.
+func groupWords(items []Glyph) []Word {
+	var words []Word
+	var sb strings.Builder
+	var cur Word
+	var prevEnd float64
+	open := false
+
+	flush := func() {
+		if open {
+			cur.S = sb.String()
+			words = append(words, cur)
+		}
+		sb.Reset()
+		open = false
+	}
+
+	for _, it := range items {
+		runes := []rune(it.S)
+		if len(runes) == 0 {
+			continue
+		}
+		fontSize := it.FontSize
+		if fontSize <= 0 {
+			fontSize = 12
+		}
+		width := it.W
+		if width <= 0 {
+			width = float64(len(runes)) * fontSize * 0.5
+		}
+		charWidth := width / float64(len(runes))
+
+		for k, ch := range runes {
+			x0 := it.X + float64(k)*charWidth
+			if unicode.IsSpace(ch) {
+				flush()
+				prevEnd = x0 + charWidth
+				continue
+			}
+			if open && x0-prevEnd > fontSize*0.2 {
+				flush()
+			}
+			box := Rect{X0: x0, Y0: it.Y - fontSize*0.2, X1: x0 + charWidth, Y1: it.Y + fontSize*0.8}
+			if !open {
+				cur = Word{BBox: box, Font: it.Font, FontSize: it.FontSize}
+				open = true
+			} else {
+				cur.BBox = cur.BBox.Union(box)
+			}
+			sb.WriteRune(ch)
+			prevEnd = box.X1
+		}
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,396 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Editor is a mutable overlay on a File's object graph. Objects can be
+// replaced or added, and the result serialized as a complete new PDF.
+type Editor struct {
+	file    *File
+	objects map[int]Object // replaced and added objects
+	next    int            // next free object number
+
+	// Trailer is the trailer of the output document. Only /Root, /Info
+	// and /ID are carried over when writing.
+	Trailer Dict
+
+	// UniqueID makes the second element of the output's file identifier
+	// random instead of a hash of the output, so that every write yields a
+	// distinct file.
+	UniqueID bool
+}
+
+// NewEditor returns an editor over f. Encrypted files are rejected unless
+// they have been decrypted with File.Decrypt; their output is written
+// unencrypted.
+func NewEditor(f *File) (*Editor, error) {
+	if _, encrypted := f.trailer["Encrypt"]; encrypted && f.security == nil {
+		return nil, ErrEncrypted
+	}
+	next := 1
+	for num := range f.xref {
+		if num >= next {
+			next = num + 1
+		}
+	}
+	trailer := make(Dict, len(f.trailer))
+	for k, v := range f.trailer {
+		trailer[k] = v
+	}
+	return &Editor{
+		file:    f,
+		objects: make(map[int]Object),
+		next:    next,
+		Trailer: trailer,
+	}, nil
+}
+
+// File returns the underlying parsed file.
+func (e *Editor) File() *File {
+	return e.file
+}
+
+// Object returns the current value of an indirect object.
+func (e *Editor) Object(num int) Object {
+	if obj, ok := e.objects[num]; ok {
+		return obj
+	}
+	obj, _ := e.file.Object(num)
+	return obj
+}
+
+// Resolve follows indirect references through the editor's overlay.
+func (e *Editor) Resolve(o Object) Object {
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := o.(Ref)
+		if !ok {
+			return o
+		}
+		o = e.Object(ref.Num)
+	}
+	return nil
+}
+
+// Set replaces the object referenced by ref.
+func (e *Editor) Set(ref Ref, obj Object) {
+	e.objects[ref.Num] = obj
+}
+
+// Add stores a new indirect object and returns its reference.
+func (e *Editor) Add(obj Object) Ref {
+	ref := Ref{Num: e.next}
+	e.next++
+	e.objects[ref.Num] = obj
+	return ref
+}
+
+// Catalog returns the document catalog dictionary.
+func (e *Editor) Catalog() Dict {
+	d, _ := e.Resolve(e.Trailer["Root"]).(Dict)
+	return d
+}
+
+// SetCatalogEntry sets an entry of the document catalog, or removes it
+// when value is nil.
+func (e *Editor) SetCatalogEntry(key Name, value Object) {
+	e.updateCatalog(func(catalog Dict) {
+		if value == nil {
+			delete(catalog, key)
+		} else {
+			catalog[key] = value
+		}
+	})
+}
+
+// PageRefs returns references to the page objects in document order.
+func (e *Editor) PageRefs() ([]Ref, error) {
+	return pageRefs(e.Catalog(), e.Resolve)
+}
+
+// InheritedAttr looks up an inheritable page attribute (Resources,
+// MediaBox, CropBox, Rotate), walking up the /Parent chain.
+func (e *Editor) InheritedAttr(page Dict, key Name) Object {
+	return inheritedAttr(page, key, e.Resolve)
+}
+
+// WriteTo serializes every object reachable from the trailer as a new,
+// self-contained PDF with a classic cross-reference table. Objects are
+// renumbered in breadth-first order from the trailer, visiting dictionary
+// keys in sorted order, and the file identifier is derived from the
+// content, so identical input always yields byte-identical output unless
+// UniqueID is set.
+func (e *Editor) WriteTo(w io.Writer) (int64, error) {
+	// Assign new numbers in deterministic traversal order
+	renum := make(map[int]int)
+	var order []int
+	queue := []Object{}
+	for _, key := range []Name{"Root", "Info"} {
+		if v, ok := e.Trailer[key]; ok {
+			queue = append(queue, v)
+		}
+	}
+	enqueueRefs := func(o Object) {
+		collectRefs(o, func(r Ref) {
+			queue = append(queue, r)
+		})
+	}
+	for len(queue) > 0 {
+		o := queue[0]
+		queue = queue[1:]
+		ref, ok := o.(Ref)
+		if !ok {
+			enqueueRefs(o)
+			continue
+		}
+		if _, done := renum[ref.Num]; done {
+			continue
+		}
+		obj := e.Object(ref.Num)
+		renum[ref.Num] = len(order) + 1
+		order = append(order, ref.Num)
+		enqueueRefs(obj)
+	}
+
+	var buf bytes.Buffer
+	version := e.file.version
+	if version == "" {
+		version = "1.7"
+	}
+	buf.WriteString("%PDF-" + version + "\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(order)+1)
+	for i, num := range order {
+		offsets[i+1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		writeObject(&buf, e.Object(num), renum)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(order)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for i := 1; i <= len(order); i++ {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[i])
+	}
+
+	trailer := Dict{"Size": int64(len(order) + 1), "ID": e.fileID(buf.Bytes())}
+	for _, key := range []Name{"Root", "Info"} {
+		if v, ok := e.Trailer[key]; ok {
+			trailer[key] = v
+		}
+	}
+	buf.WriteString("trailer\n")
+	writeObject(&buf, trailer, renum)
+	fmt.Fprintf(&buf, "\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// fileID returns the /ID array for the output. The first, permanent
+// element is kept from the source file when present; the second changes
+// with the content and is the MD5 of the serialized body.
+func (e *Editor) fileID(body []byte) Array {
+	sum := md5.Sum(body)
+	permanent := String(sum[:])
+	if id, ok := e.Resolve(e.Trailer["ID"]).(Array); ok && len(id) == 2 {
+		if s, ok := e.Resolve(id[0]).(String); ok && s != "" {
+			permanent = s
+		}
+	}
+
+	changing := String(sum[:])
+	if e.UniqueID {
+		random := make([]byte, 16)
+		rand.Read(random)
+		changing = String(random)
+	}
+	return Array{permanent, changing}
+}
+
+// collectRefs calls fn for every reference directly contained in o.
+func collectRefs(o Object, fn func(Ref)) {
+	switch v := o.(type) {
+	case Ref:
+		fn(v)
+	case Array:
+		for _, el := range v {
+			collectRefs(el, fn)
+		}
+	case Dict:
+		for _, k := range sortedKeys(v) {
+			collectRefs(v[k], fn)
+		}
+	case *Stream:
+		collectRefs(v.Dict, fn)
+	}
+}
+
+// sortedKeys returns the keys of d in sorted order.
+func sortedKeys(d Dict) []Name {
+	keys := make([]Name, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// writeObject serializes o in PDF syntax, renumbering references. References
+// to objects that were not written (dangling) become null.
+func writeObject(buf *bytes.Buffer, o Object, renum map[int]int) {
+	switch v := o.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case float64:
+		buf.WriteString(formatReal(v))
+	case String:
+		writeString(buf, string(v))
+	case Name:
+		writeName(buf, v)
+	case Array:
+		buf.WriteByte('[')
+		for i, el := range v {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			writeObject(buf, el, renum)
+		}
+		buf.WriteByte(']')
+	case Dict:
+		buf.WriteString("<<")
+		for _, k := range sortedKeys(v) {
+			writeName(buf, k)
+			buf.WriteByte(' ')
+			writeObject(buf, v[k], renum)
+		}
+		buf.WriteString(">>")
+	case *Stream:
+		dict := make(Dict, len(v.Dict)+1)
+		for k, val := range v.Dict {
+			dict[k] = val
+		}
+		dict["Length"] = int64(len(v.Data))
+		writeObject(buf, dict, renum)
+		buf.WriteString("\nstream\n")
+		buf.Write(v.Data)
+		buf.WriteString("\nendstream")
+	case Ref:
+		if renum == nil {
+			fmt.Fprintf(buf, "%d %d R", v.Num, v.Gen)
+			return
+		}
+		if n, ok := renum[v.Num]; ok {
+			fmt.Fprintf(buf, "%d 0 R", n)
+		} else {
+			buf.WriteString("null")
+		}
+	default:
+		buf.WriteString("null")
+	}
+}
+
+// formatReal formats a real number without exponent notation.
+func formatReal(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// writeString writes a string as a literal when it is mostly printable
+// and as a hexadecimal string otherwise.
+func writeString(buf *bytes.Buffer, s string) {
+	binary := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < 32 || s[i] > 126 {
+			binary++
+		}
+	}
+	if binary > len(s)/4 {
+		buf.WriteByte('<')
+		const digits = "0123456789ABCDEF"
+		for i := 0; i < len(s); i++ {
+			buf.WriteByte(digits[s[i]>>4])
+			buf.WriteByte(digits[s[i]&0x0F])
+		}
+		buf.WriteByte('>')
+		return
+	}
+
+	buf.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '(', ')', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if c < 32 || c > 126 {
+				fmt.Fprintf(buf, "\\%03o", c)
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte(')')
+}
+
+// writeName writes a name with #xx escapes for delimiters and
+// non-printable bytes.
+func writeName(buf *bytes.Buffer, n Name) {
+	buf.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < 33 || c > 126 || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+}
+
+// SerializeObject returns the PDF syntax for a direct object. References
+// keep their original numbers.
+func SerializeObject(o Object) []byte {
+	var buf bytes.Buffer
+	writeObject(&buf, o, nil)
+	return buf.Bytes()
+}
+
+// NewFlateStream returns a stream holding data compressed with FlateDecode.
+// Entries in dict other than /Filter, /DecodeParms and /Length are kept.
+func NewFlateStream(dict Dict, data []byte) *Stream {
+	out := make(Dict, len(dict)+1)
+	for k, v := range dict {
+		switch k {
+		case "Filter", "DecodeParms", "Length", "DL":
+			continue
+		}
+		out[k] = v
+	}
+	out["Filter"] = Name("FlateDecode")
+	return &Stream{Dict: out, Data: deflate(data)}
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// minDictionaryWords is the number of words a text needs before its
+// dictionary coverage is trusted to flag it as garbled.
+const minDictionaryWords = 20
+
+// Dictionary reports whether words are spelled correctly, to measure how
+// much of an extracted text is real words. Words are passed lowercased.
+type Dictionary interface {
+	Contains(word string) bool
+}
+
+// WordList is a Dictionary of the words it holds.
+type WordList map[string]struct{}
+
+// NewWordList returns a word list holding the given words.
+func NewWordList(words ...string) WordList {
+	l := make(WordList, len(words))
+	for _, w := range words {
+		l.Add(w)
+	}
+	return l
+}
+
+// ReadWordList reads a word list with one word per line, such as
+// /usr/share/dict/words or a Hunspell .dic file, whose affix flags after
+// a '/' are ignored. Blank lines and lines starting with '#' are skipped.
+func ReadWordList(r io.Reader) (WordList, error) {
+	l := make(WordList)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, _, _ := strings.Cut(line, "/")
+		l.Add(word)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	return l, nil
+}
+
+// Add adds a word to the list.
+func (l WordList) Add(word string) {
+	if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+		l[word] = struct{}{}
+	}
+}
+
+// Contains reports whether the list holds word.
+func (l WordList) Contains(word string) bool {
+	_, ok := l[word]
+	return ok
+}
+
+// inDictionary reports whether a word, lowercased, is in dict. Words
+// with apostrophes or hyphens are also found when each part is.
+func inDictionary(dict Dictionary, word string) bool {
+	word = strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+	if dict.Contains(word) {
+		return true
+	}
+	if base, ok := strings.CutSuffix(word, "'s"); ok && dict.Contains(base) {
+		return true
+	}
+	parts := strings.FieldsFunc(word, func(r rune) bool { return r == '-' || r == '\'' })
+	if len(parts) < 2 {
+		return false
+	}
+	for _, p := range parts {
+		if !dict.Contains(p) {
+			return false
+		}
+	}
+	return true
+}
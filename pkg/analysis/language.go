@@ -0,0 +1,254 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// minLanguageHits is the number of common words of the best language a
+// text in Latin or Cyrillic script needs for its language to be
+// detected, and minScriptLetters the number of letters a text in another
+// script needs.
+const (
+	minLanguageHits  = 3
+	minScriptLetters = 10
+)
+
+// Language is a detected language with its confidence.
+type Language struct {
+	// Code is the BCP 47 language code, such as "en" or "ja", or "" when
+	// the language could not be detected.
+	Code string `json:"code"`
+
+	// Confidence is how clearly the text matches the language rather than
+	// the others, from 0 to 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// PageLanguage is the detected language of a page.
+type PageLanguage struct {
+	Page int `json:"page"`
+	Language
+}
+
+// LanguageReport is the result of Languages.
+type LanguageReport struct {
+	// Declared is the language declared by the document's /Lang entry,
+	// or "" when it declares none.
+	Declared string `json:"declared,omitempty"`
+
+	// Detected is the language detected over the text of all pages.
+	Detected Language `json:"detected"`
+
+	Pages []PageLanguage `json:"pages"`
+}
+
+// commonWords are frequent function words of the languages written in
+// Latin and Cyrillic script. Words shared by languages count for each;
+// what decides is which language has the most.
+var commonWords = map[string][]string{
+	"en": strings.Fields("the of and to in is that it for was on are with as be this by at from or have not but an which they were has their"),
+	"de": strings.Fields("der die und das ist nicht ein eine mit den von zu sich des auf für dem im auch es sind wird als bei oder aus nach wie werden"),
+	"fr": strings.Fields("le la les des et est une un du dans pour que qui sur pas au avec ce il sont par plus ne se aux ont cette"),
+	"es": strings.Fields("el la los las de que y en un una es por con para del se no al lo como más sus pero son está"),
+	"it": strings.Fields("il la di che è un una per non del della con sono gli le da al si come nel alla anche dei più"),
+	"pt": strings.Fields("os as de que do da em um uma para com não dos das no na por mais se são ao também"),
+	"nl": strings.Fields("de het een en van is dat op te in zijn met voor niet aan er ook als door maar bij dit wordt worden"),
+	"sv": strings.Fields("och att det som en är av för med till den på inte har de ett om var men från"),
+	"da": strings.Fields("og at det som en er af for med til den på ikke har de et om var men fra"),
+	"pl": strings.Fields("w nie na się do że jest to jak co ale od po przez dla są jego oraz tak"),
+	"cs": strings.Fields("se na je že to do jako ale by pro jsou nebo podle jeho byl také"),
+	"tr": strings.Fields("ve bir bu da de için ile olarak daha çok gibi en olan ne ama var değil her kadar sonra"),
+	"fi": strings.Fields("ja on ei se että oli ovat mutta kun joka myös tai hän kanssa sekä ole jos niin"),
+	"ru": strings.Fields("и в не на что с по это как из у за от к но для о же так все он она его"),
+	"uk": strings.Fields("і в не на що з до це як у за від та але для про його вона він також є"),
+}
+
+// commonWordLanguages maps each common word to the languages it belongs
+// to.
+var commonWordLanguages = func() map[string][]string {
+	m := make(map[string][]string)
+	for lang, words := range commonWords {
+		for _, w := range words {
+			m[w] = append(m[w], lang)
+		}
+	}
+	return m
+}()
+
+// scriptLanguages are the scripts written for essentially one language,
+// or a family told apart by letters of their own, with that language.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	code  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Arabic, "ar"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// languageCounts accumulates the evidence for the language of a text.
+type languageCounts struct {
+	letters int            // letters of any script
+	scripts map[string]int // letters by the language their script implies
+	persian int            // Arabic-script letters only Persian uses
+	words   map[string]int // common word hits by language
+}
+
+func newLanguageCounts() *languageCounts {
+	return &languageCounts{scripts: make(map[string]int), words: make(map[string]int)}
+}
+
+// add counts the letters and common words of text.
+func (c *languageCounts) add(text string) {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		for _, lang := range commonWordLanguages[word] {
+			c.words[lang]++
+		}
+		for _, r := range word {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			c.letters++
+			for _, s := range scriptLanguages {
+				if unicode.Is(s.table, r) {
+					c.scripts[s.code]++
+					break
+				}
+			}
+			if strings.ContainsRune("پچژگ", r) {
+				c.persian++
+			}
+		}
+	}
+}
+
+// merge adds the counts of o to c.
+func (c *languageCounts) merge(o *languageCounts) {
+	c.letters += o.letters
+	c.persian += o.persian
+	for k, v := range o.scripts {
+		c.scripts[k] += v
+	}
+	for k, v := range o.words {
+		c.words[k] += v
+	}
+}
+
+// best returns the most likely language among those allowed, all when
+// allowed is empty.
+func (c *languageCounts) best(allowed map[string]bool) Language {
+	ok := func(code string) bool { return len(allowed) == 0 || allowed[code] }
+
+	// Japanese mixes kana with Han characters; a text with some kana is
+	// Japanese even when Han characters are the majority
+	scripts := make(map[string]int, len(c.scripts))
+	for k, v := range c.scripts {
+		scripts[k] = v
+	}
+	if scripts["ja"] > 0 && scripts["ja"]*10 >= scripts["zh"] {
+		scripts["ja"] += scripts["zh"]
+		delete(scripts, "zh")
+	}
+	if scripts["ar"] > 0 && c.persian*50 >= scripts["ar"] {
+		scripts["fa"] = scripts["ar"]
+		delete(scripts, "ar")
+	}
+	script, n := "", 0
+	for code, v := range scripts {
+		if v > n || (v == n && code < script) {
+			script, n = code, v
+		}
+	}
+	if n >= minScriptLetters && n*2 > c.letters && ok(script) {
+		return Language{Code: script, Confidence: round2(float64(n) / float64(c.letters))}
+	}
+
+	best, second := "", 0
+	hits := 0
+	for code, n := range c.words {
+		if !ok(code) {
+			continue
+		}
+		switch {
+		case n > hits || (n == hits && code < best):
+			best, second, hits = code, hits, n
+		case n > second:
+			second = n
+		}
+	}
+	if hits < minLanguageHits {
+		return Language{}
+	}
+	// How far the best language leads the runner-up, discounted for
+	// texts too short to tell languages apart reliably
+	confidence := 1 - float64(second)/float64(hits)
+	if hits < 10 {
+		confidence *= float64(hits) / 10
+	}
+	return Language{Code: best, Confidence: round2(confidence)}
+}
+
+// round2 rounds a confidence to two decimals.
+func round2(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
+
+// DetectLanguage detects the language of a text from its script and, for
+// Latin and Cyrillic script, its most common words. It knows Arabic,
+// Chinese, Czech, Danish, Dutch, English, Finnish, French, German, Greek,
+// Hebrew, Hindi, Italian, Japanese, Korean, Persian, Polish, Portuguese,
+// Russian, Spanish, Swedish, Thai, Turkish and Ukrainian. Texts too short
+// or in another language get an empty code.
+func DetectLanguage(text string, opts ...Option) Language {
+	c := newLanguageCounts()
+	c.add(text)
+	return c.best(applyOptions(opts).Languages)
+}
+
+// DetectPageLanguage detects the language of the text of a page.
+func DetectPageLanguage(page *crazypdf.Page, opts ...Option) (Language, error) {
+	text, err := page.PlainText()
+	if err != nil {
+		return Language{}, err
+	}
+	return DetectLanguage(text, opts...), nil
+}
+
+// Languages returns the language a document declares and the language
+// detected in its text, overall and per page, for routing documents to
+// language-specific processing. The declared language is often missing
+// or left at the authoring tool's default, so the detected one is the
+// better guide for untagged documents.
+func Languages(doc *crazypdf.Document, opts ...Option) (*LanguageReport, error) {
+	cfg := applyOptions(opts)
+	declared, err := doc.Language()
+	if err != nil {
+		return nil, err
+	}
+	report := &LanguageReport{Declared: declared}
+	all := newLanguageCounts()
+	for _, page := range doc.Pages() {
+		text, err := page.PlainText()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d: %w", page.Number, err)
+		}
+		c := newLanguageCounts()
+		c.add(text)
+		all.merge(c)
+		report.Pages = append(report.Pages, PageLanguage{Page: page.Number, Language: c.best(cfg.Languages)})
+	}
+	report.Detected = all.best(cfg.Languages)
+	return report, nil
+}
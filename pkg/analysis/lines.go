@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// textLine is a horizontal run of glyphs sharing a baseline.
+type textLine struct {
+	box      crazypdf.Rect
+	text     string
+	fontSize float64
+	glyphs   []internalpdf.StyledText
+}
+
+// glyphBox estimates the bounding box of a single styled text element.
+// Ascent and descent are approximated from the font size.
+func glyphBox(st internalpdf.StyledText) crazypdf.Rect {
+	fs := st.FontSize
+	if fs <= 0 {
+		fs = 12
+	}
+	w := st.W
+	if w <= 0 {
+		w = float64(len([]rune(st.Text))) * fs * 0.5
+	}
+	return crazypdf.Rect{X0: st.X, Y0: st.Y - fs*0.2, X1: st.X + w, Y1: st.Y + fs*0.8}
+}
+
+// buildLines groups styled text into lines by baseline, splitting a
+// baseline into separate lines wherever the horizontal gap is wide
+// enough to indicate a column or sidebar boundary.
+func buildLines(texts []internalpdf.StyledText) []textLine {
+	if len(texts) == 0 {
+		return nil
+	}
+
+	sorted := make([]internalpdf.StyledText, len(texts))
+	copy(sorted, texts)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Y != sorted[j].Y {
+			return sorted[i].Y > sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	// Group by approximate baseline
+	const yTolerance = 2.0
+	var groups [][]internalpdf.StyledText
+	for _, st := range sorted {
+		n := len(groups)
+		if n == 0 || math.Abs(groups[n-1][0].Y-st.Y) > yTolerance {
+			groups = append(groups, []internalpdf.StyledText{st})
+			continue
+		}
+		groups[n-1] = append(groups[n-1], st)
+	}
+
+	var lines []textLine
+	for _, g := range groups {
+		sort.Slice(g, func(a, b int) bool { return g[a].X < g[b].X })
+
+		start := 0
+		for i := 1; i <= len(g); i++ {
+			if i < len(g) {
+				prev := glyphBox(g[i-1])
+				fs := g[i-1].FontSize
+				if fs <= 0 {
+					fs = 12
+				}
+				if g[i].X-prev.X1 <= fs*3 {
+					continue
+				}
+			}
+			lines = append(lines, newTextLine(g[start:i]))
+			start = i
+		}
+	}
+	return lines
+}
+
+// newTextLine builds a line from glyphs sorted by X position, inserting
+// spaces where the gap between glyphs indicates a word break.
+func newTextLine(glyphs []internalpdf.StyledText) textLine {
+	ln := textLine{glyphs: glyphs}
+	var sb strings.Builder
+	var sizeSum float64
+	for i, st := range glyphs {
+		box := glyphBox(st)
+		ln.box = ln.box.Union(box)
+		if i > 0 {
+			prev := glyphBox(glyphs[i-1])
+			fs := st.FontSize
+			if fs <= 0 {
+				fs = 12
+			}
+			if box.X0-prev.X1 > fs*0.25 && !strings.HasSuffix(sb.String(), " ") && !strings.HasPrefix(st.Text, " ") {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(st.Text)
+		sizeSum += st.FontSize
+	}
+	ln.text = strings.TrimSpace(sb.String())
+	ln.fontSize = sizeSum / float64(len(glyphs))
+	return ln
+}
+
+// overlapX reports whether two rectangles overlap horizontally.
+func overlapX(a, b crazypdf.Rect) bool {
+	return a.X0 < b.X1 && b.X0 < a.X1
+}
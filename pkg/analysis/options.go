@@ -0,0 +1,170 @@
+package analysis
+
+import "github.com/ayushanand18/crazypdf/pkg/crazypdf"
+
+// config holds configuration for page analysis operations.
+type config struct {
+	HeaderBand    float64 // fraction of page height treated as the header band
+	FooterBand    float64 // fraction of page height treated as the footer band
+	MinFigureArea float64 // minimum figure area as a fraction of the page area
+
+	WordsPerMinute float64 // reading speed used for reading time estimates
+
+	Dictionary            Dictionary // word list for dictionary coverage; nil to skip it
+	MinDictionaryCoverage float64    // coverage below which text is garbled
+
+	Languages map[string]bool // language codes detection chooses from; nil for all
+
+	MinOrientationConfidence float64         // confidence needed for AutoRotate to turn a page
+	OrientationHook          OrientationFunc // detects the orientation of pages without text
+	WriteOptions             []crazypdf.WriteOption
+	Metadata                 crazypdf.MetadataPolicy
+
+	Space crazypdf.CoordinateSpace // coordinate space of returned geometry
+	DPI   float64                  // pixels per inch of returned geometry; 0 for points
+}
+
+// Option is a functional option for configuring page analysis.
+type Option func(*config)
+
+// WithHeaderBand sets the fraction of the page height, measured from the
+// top edge, in which text is classified as a running header. Default is 0.08.
+func WithHeaderBand(fraction float64) Option {
+	return func(c *config) {
+		c.HeaderBand = fraction
+	}
+}
+
+// WithFooterBand sets the fraction of the page height, measured from the
+// bottom edge, in which text is classified as a running footer. Default is 0.08.
+func WithFooterBand(fraction float64) Option {
+	return func(c *config) {
+		c.FooterBand = fraction
+	}
+}
+
+// WithMinFigureArea sets the minimum area, as a fraction of the page area,
+// for an image or graphics cluster to be reported as a figure. Default is 0.01.
+func WithMinFigureArea(fraction float64) Option {
+	return func(c *config) {
+		c.MinFigureArea = fraction
+	}
+}
+
+// WithWordsPerMinute sets the reading speed used to estimate reading time.
+// Default is 238, the average silent reading speed of adults for
+// non-fiction.
+func WithWordsPerMinute(wpm float64) Option {
+	return func(c *config) {
+		c.WordsPerMinute = wpm
+	}
+}
+
+// WithDictionary sets the dictionary ReadingStats looks words up in to
+// measure how much of the text is real words, such as a WordList read
+// with ReadWordList. The dictionary should cover the document languages.
+// Default is nil, no dictionary coverage.
+func WithDictionary(dict Dictionary) Option {
+	return func(c *config) {
+		c.Dictionary = dict
+	}
+}
+
+// WithMinDictionaryCoverage sets the fraction of words found in the
+// dictionary below which text is flagged as garbled. Default is 0.5.
+func WithMinDictionaryCoverage(fraction float64) Option {
+	return func(c *config) {
+		c.MinDictionaryCoverage = fraction
+	}
+}
+
+// WithLanguages restricts language detection to the given BCP 47
+// language codes, such as "en" and "de", for collections known to be in
+// a few languages. By default every supported language is considered.
+func WithLanguages(codes ...string) Option {
+	return func(c *config) {
+		c.Languages = make(map[string]bool, len(codes))
+		for _, code := range codes {
+			c.Languages[code] = true
+		}
+	}
+}
+
+// WithOrientationHook sets a function, typically backed by an OCR engine,
+// that detects the orientation of pages with too little text to detect it
+// from, such as scans without a text layer.
+func WithOrientationHook(fn OrientationFunc) Option {
+	return func(c *config) {
+		c.OrientationHook = fn
+	}
+}
+
+// WithMinOrientationConfidence sets the confidence, from 0 to 1, a
+// detected orientation needs for AutoRotate to turn the page. Default is
+// 0.6.
+func WithMinOrientationConfidence(confidence float64) Option {
+	return func(c *config) {
+		c.MinOrientationConfidence = confidence
+	}
+}
+
+// WithWriteOptions sets how AutoRotate writes the corrected document,
+// such as computing its SHA-256 with crazypdf.WithChecksum.
+func WithWriteOptions(opts ...crazypdf.WriteOption) Option {
+	return func(c *config) {
+		c.WriteOptions = opts
+	}
+}
+
+// WithMetadata sets what happens to the document metadata in the output
+// of AutoRotate: crazypdf.MetadataPreserve (the default),
+// crazypdf.MetadataStrip or crazypdf.MetadataReplace(info).
+func WithMetadata(policy crazypdf.MetadataPolicy) Option {
+	return func(c *config) {
+		c.Metadata = policy
+	}
+}
+
+// WithCoordinateSpace sets the coordinate space of the boxes Regions and
+// PrintedPageNumbers return: crazypdf.PDFDefault, PDF user space, or
+// crazypdf.TopLeft, with the origin at the top-left corner of the media
+// box and the Y axis pointing down. Default is crazypdf.PDFDefault.
+func WithCoordinateSpace(space crazypdf.CoordinateSpace) Option {
+	return func(c *config) {
+		c.Space = space
+	}
+}
+
+// WithDPI scales the boxes Regions and PrintedPageNumbers return to
+// pixels at dpi dots per inch, to line up with a page rendered at that
+// resolution. Default is 0, coordinates in points.
+func WithDPI(dpi float64) Option {
+	return func(c *config) {
+		c.DPI = dpi
+	}
+}
+
+// defaultConfig returns the default analysis configuration.
+func defaultConfig() *config {
+	return &config{
+		HeaderBand:    0.08,
+		FooterBand:    0.08,
+		MinFigureArea: 0.01,
+
+		WordsPerMinute: 238,
+
+		MinDictionaryCoverage: 0.5,
+
+		MinOrientationConfidence: 0.6,
+		Metadata:                 crazypdf.MetadataPreserve,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// minOrientationText is the amount of text, in bytes shown, a page needs
+// for its orientation to be detected from the text direction.
+const minOrientationText = 20
+
+// Sources of a detected orientation.
+const (
+	OrientationFromText = "text"
+	OrientationFromHook = "hook"
+)
+
+// OrientationFunc detects the orientation of a page as displayed, for
+// pages with too little text, typically by running OCR on the rendered
+// page. It returns the clockwise rotation in degrees, a multiple of 90,
+// that makes the page upright and its confidence from 0 to 1.
+type OrientationFunc func(page *crazypdf.Page) (rotation int, confidence float64, err error)
+
+// PageOrientation is the detected orientation of a page.
+type PageOrientation struct {
+	Page int `json:"page"`
+
+	// Rotation is the clockwise angle in degrees, 0, 90, 180 or 270, by
+	// which the page as displayed must be turned for its text to read
+	// upright.
+	Rotation int `json:"rotation"`
+
+	// Confidence is the share of the page's text that runs in the
+	// detected direction, or the confidence reported by the orientation
+	// hook. It is 0 when the orientation could not be detected.
+	Confidence float64 `json:"confidence"`
+
+	// Source is OrientationFromText or OrientationFromHook, or empty when
+	// the orientation could not be detected.
+	Source string `json:"source,omitempty"`
+}
+
+// Orientation detects whether a page is displayed sideways or upside
+// down, as scans fed in the wrong way round are. It compares the
+// directions in which the page's text runs, including the invisible text
+// layer of OCRed scans, with the page rotation. Pages with too little
+// text are passed to the hook set with WithOrientationHook, if any, and
+// are otherwise reported with zero confidence.
+func Orientation(page *crazypdf.Page, opts ...Option) (*PageOrientation, error) {
+	return orientation(page, applyOptions(opts))
+}
+
+// orientation detects the orientation of a page for cfg.
+func orientation(page *crazypdf.Page, cfg *config) (*PageOrientation, error) {
+	out := &PageOrientation{Page: page.Number}
+	dirs, err := page.TextDirections()
+	if err != nil {
+		return nil, err
+	}
+	if total := dirs.Total(); total >= minOrientationText {
+		rotate, err := page.Rotation()
+		if err != nil {
+			return nil, err
+		}
+		best := 0
+		for i := range dirs {
+			if dirs[i] > dirs[best] {
+				best = i
+			}
+		}
+		// Text at a counterclockwise angle is upright once the page
+		// as displayed is turned clockwise by the same angle
+		out.Rotation = ((best*90-rotate)%360 + 360) % 360
+		out.Confidence = float64(dirs[best]) / float64(total)
+		out.Source = OrientationFromText
+		return out, nil
+	}
+	if cfg.OrientationHook == nil {
+		return out, nil
+	}
+	rotation, confidence, err := cfg.OrientationHook(page)
+	if err != nil {
+		return nil, fmt.Errorf("orientation hook: %w", err)
+	}
+	if rotation%90 != 0 {
+		return nil, fmt.Errorf("orientation hook: rotation %d is not a multiple of 90", rotation)
+	}
+	out.Rotation = (rotation%360 + 360) % 360
+	out.Confidence = confidence
+	out.Source = OrientationFromHook
+	return out, nil
+}
+
+// RotateReport describes the result of AutoRotate.
+type RotateReport struct {
+	// Pages lists the pages that were turned.
+	Pages []PageOrientation `json:"pages"`
+
+	// Output describes the written document.
+	Output *crazypdf.WriteResult `json:"output,omitempty"`
+}
+
+// AutoRotate detects the orientation of every page and writes a copy of
+// the document to w in which sideways and upside-down pages are displayed
+// upright, so that viewers and OCR engines get them the right way round.
+// Pages are turned by changing their /Rotate entry, leaving their content
+// untouched, when the detected orientation has at least the confidence
+// set with WithMinOrientationConfidence.
+func AutoRotate(doc *crazypdf.Document, w io.Writer, opts ...Option) (*RotateReport, error) {
+	cfg := applyOptions(opts)
+	return autoRotate(doc, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.Write(w, fn, cfg.WriteOptions...)
+	})
+}
+
+// AutoRotateFile rotates pages like AutoRotate and writes the result to
+// path atomically.
+func AutoRotateFile(doc *crazypdf.Document, path string, opts ...Option) (*RotateReport, error) {
+	cfg := applyOptions(opts)
+	return autoRotate(doc, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.WriteFile(path, fn, cfg.WriteOptions...)
+	})
+}
+
+// autoRotate turns the pages and hands the serialized document to write.
+func autoRotate(doc *crazypdf.Document, cfg *config, write func(func(io.Writer) error) (*crazypdf.WriteResult, error)) (*RotateReport, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+	refs, err := editor.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+
+	report := &RotateReport{}
+	for i, page := range doc.Pages() {
+		o, err := orientation(page, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect orientation of page %d: %w", page.Number, err)
+		}
+		if o.Rotation == 0 || o.Confidence < cfg.MinOrientationConfidence || i >= len(refs) {
+			continue
+		}
+		rotate, err := page.Rotation()
+		if err != nil {
+			return nil, err
+		}
+		editor.SetPageRotation(refs[i], rotate+o.Rotation)
+		report.Pages = append(report.Pages, *o)
+	}
+
+	crazypdf.ApplyMetadata(editor, cfg.Metadata)
+	writeCfg := crazypdf.NewWriteConfig(cfg.WriteOptions...)
+	editor.UniqueID = writeCfg.UniqueID
+	if err := crazypdf.ApplyInitialView(editor, writeCfg.InitialView); err != nil {
+		return nil, fmt.Errorf("failed to set initial view: %w", err)
+	}
+	output, err := write(func(w io.Writer) error {
+		_, err := editor.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	report.Output = output
+	return report, nil
+}
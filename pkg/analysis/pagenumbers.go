@@ -0,0 +1,233 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// PrintedNumber is the page number printed on a physical page.
+type PrintedNumber struct {
+	// Page is the 1-based physical page number and Label the page label
+	// the document assigns it, which viewers show and which is the page
+	// number when the document defines no labels.
+	Page  int    `json:"page"`
+	Label string `json:"label"`
+
+	// Printed is the number as printed, such as "12" or "iv", and Value
+	// its numeric value. They are empty and zero when no number was
+	// found.
+	Printed string `json:"printed,omitempty"`
+	Value   int    `json:"value,omitempty"`
+
+	// Roman reports a number printed in roman numerals, as front matter
+	// often is.
+	Roman bool `json:"roman,omitempty"`
+
+	// BBox is where the number is printed. It is nil when the number was
+	// inferred.
+	BBox *crazypdf.Rect `json:"bbox,omitempty"`
+
+	// Inferred reports a number that was not found on the page but lies
+	// between pages whose numbers follow the same sequence, such as a
+	// full-page figure without a footer.
+	Inferred bool `json:"inferred,omitempty"`
+}
+
+// numberWindow is the number of pages on either side whose numbers are
+// compared with a candidate on a page.
+const numberWindow = 3
+
+// numberCandidate is a word in the header or footer band that reads as a
+// page number.
+type numberCandidate struct {
+	text  string
+	value int
+	roman bool
+	bbox  crazypdf.Rect
+}
+
+// offset is the difference between the printed and the physical page
+// number that a candidate on page implies.
+func (c numberCandidate) offset(page int) int {
+	return c.value - page
+}
+
+// PrintedPageNumbers detects the page number printed on each page of the
+// document, in the header or footer band, and returns it with the
+// document's own page label for comparison. Scanned compilations and
+// excerpts often print numbers offset from the physical page, or
+// restart them per part.
+//
+// A number is accepted when a page within three pages carries a number
+// of the same style in the same sequence, which rules out years, chapter
+// numbers and totals such as the 40 of "12 of 40". Pages without a
+// number between pages of the same sequence get the inferred number.
+func PrintedPageNumbers(doc *crazypdf.Document, opts ...Option) ([]PrintedNumber, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+
+	labels, err := doc.PageLabels()
+	if err != nil {
+		return nil, err
+	}
+	pages := doc.Pages()
+	candidates := make([][]numberCandidate, len(pages))
+	for i, page := range pages {
+		if candidates[i], err = pageNumberCandidates(page, cfg); err != nil {
+			return nil, fmt.Errorf("failed to read text on page %d: %w", page.Number, err)
+		}
+	}
+
+	out := make([]PrintedNumber, len(pages))
+	chosen := make([]*numberCandidate, len(pages))
+	for i := range pages {
+		out[i] = PrintedNumber{Page: i + 1, Label: labels[i]}
+		best, bestSupport := -1, 0
+		for j, c := range candidates[i] {
+			if s := support(candidates, i, c); s > bestSupport {
+				best, bestSupport = j, s
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		c := candidates[i][best]
+		chosen[i] = &c
+		coords, err := pages[i].Coordinates(cfg.Space, cfg.DPI)
+		if err != nil {
+			return nil, err
+		}
+		bbox := coords.Rect(c.bbox)
+		out[i].Printed, out[i].Value, out[i].Roman, out[i].BBox = c.text, c.value, c.roman, &bbox
+	}
+
+	// Fill gaps inside a sequence
+	for i := 0; i < len(pages); i++ {
+		if chosen[i] == nil {
+			continue
+		}
+		j := i + 1
+		for j < len(pages) && chosen[j] == nil {
+			j++
+		}
+		if j < len(pages) && j > i+1 && chosen[j].roman == chosen[i].roman &&
+			chosen[j].offset(j) == chosen[i].offset(i) {
+			upper := chosen[i].text != strings.ToLower(chosen[i].text)
+			for k := i + 1; k < j; k++ {
+				v := chosen[i].offset(i) + k
+				out[k].Value, out[k].Roman, out[k].Inferred = v, chosen[i].roman, true
+				out[k].Printed = formatNumber(v, chosen[i].roman, upper)
+			}
+		}
+		i = j - 1
+	}
+	return out, nil
+}
+
+// support counts the pages near page i with a candidate in the same
+// sequence as c.
+func support(candidates [][]numberCandidate, i int, c numberCandidate) int {
+	n := 0
+	for j := max(0, i-numberWindow); j <= min(len(candidates)-1, i+numberWindow); j++ {
+		if j == i {
+			continue
+		}
+		for _, other := range candidates[j] {
+			if other.roman == c.roman && other.offset(j) == c.offset(i) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// pageNumberCandidates returns the words in the header and footer bands
+// of a page that read as page numbers, alone or in forms such as
+// "Page 12", "- 12 -" or "12 of 40".
+func pageNumberCandidates(page *crazypdf.Page, cfg *config) ([]numberCandidate, error) {
+	box, err := page.CropBox()
+	if err != nil {
+		return nil, err
+	}
+	words, err := page.Words()
+	if err != nil {
+		return nil, err
+	}
+	headerTop := box.Y1 - box.Height()*cfg.HeaderBand
+	footerTop := box.Y0 + box.Height()*cfg.FooterBand
+
+	var out []numberCandidate
+	for _, w := range words {
+		if w.BBox.Y0 < headerTop && w.BBox.Y1 > footerTop {
+			continue
+		}
+		text := strings.Trim(w.S, "-–—()[]|.,:")
+		if strings.HasPrefix(strings.ToLower(text), "p.") {
+			text = text[2:]
+		}
+		c := numberCandidate{text: text, bbox: w.BBox}
+		if v, err := strconv.Atoi(text); err == nil && len(text) <= 4 && v > 0 {
+			c.value = v
+		} else if v, ok := parseRoman(text); ok {
+			c.value, c.roman = v, true
+		} else {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// romanNumerals are the values of roman numerals, largest first, with
+// the subtractive pairs.
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"}, {100, "c"}, {90, "xc"},
+	{50, "l"}, {40, "xl"}, {10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"},
+}
+
+// parseRoman parses a roman numeral in one case, accepting only the
+// canonical form so that words such as "dim" and "iiii" are rejected.
+func parseRoman(s string) (int, bool) {
+	lower := strings.ToLower(s)
+	if s == "" || (s != lower && s != strings.ToUpper(s)) {
+		return 0, false
+	}
+	v, rest := 0, lower
+	for _, n := range romanNumerals {
+		for strings.HasPrefix(rest, n.symbol) {
+			v += n.value
+			rest = rest[len(n.symbol):]
+		}
+	}
+	if rest != "" || v == 0 || v >= 4000 || formatNumber(v, true, false) != lower {
+		return 0, false
+	}
+	return v, true
+}
+
+// formatNumber formats a page number in decimal or roman numerals.
+func formatNumber(v int, roman, upper bool) string {
+	if !roman || v <= 0 {
+		return strconv.Itoa(v)
+	}
+	var b strings.Builder
+	for _, n := range romanNumerals {
+		for v >= n.value {
+			b.WriteString(n.symbol)
+			v -= n.value
+		}
+	}
+	if upper {
+		return strings.ToUpper(b.String())
+	}
+	return b.String()
+}
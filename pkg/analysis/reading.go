@@ -0,0 +1,200 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// TextStats holds word counts and readability measures for a span of text.
+type TextStats struct {
+	// Page is the 1-based page number, or 0 for document totals.
+	Page int `json:"page"`
+
+	Words     int `json:"words"`
+	Sentences int `json:"sentences"`
+	Syllables int `json:"syllables"`
+
+	// ReadingTime is the estimated time to read the text at the
+	// configured reading speed.
+	ReadingTime time.Duration `json:"reading_time"`
+
+	// FleschReadingEase scores readability from about 0 (very difficult)
+	// to 100 (very easy). It is 0 when the text has no words.
+	FleschReadingEase float64 `json:"flesch_reading_ease"`
+
+	// FleschKincaidGrade is the US school grade level needed to
+	// understand the text.
+	FleschKincaidGrade float64 `json:"flesch_kincaid_grade"`
+
+	// Skew is the skew angle of the page's text in degrees, as estimated
+	// by Skew. It is 0 for document totals.
+	Skew float64 `json:"skew,omitempty"`
+
+	// DictionaryCoverage is the fraction of the words, not counting
+	// numbers, found in the dictionary set with WithDictionary, from 0 to
+	// 1. A text layer that is mostly not words is corrupt, as when fonts
+	// lack a usable Unicode mapping. It is 0 when no dictionary is set.
+	DictionaryCoverage float64 `json:"dictionary_coverage,omitempty"`
+
+	// Garbled is set when the text has at least 20 words and its
+	// DictionaryCoverage is below the minimum set with
+	// WithMinDictionaryCoverage.
+	Garbled bool `json:"garbled,omitempty"`
+
+	// found and checked count the words found in the dictionary and the
+	// words looked up, for document totals.
+	found, checked int
+}
+
+// ReadingReport is the result of ReadingStats.
+type ReadingReport struct {
+	Pages []TextStats `json:"pages"`
+	Total TextStats   `json:"total"`
+}
+
+// ReadingStats computes word counts, estimated reading time and
+// Flesch-style readability for every page of a document and for the
+// document as a whole. Readability formulas are calibrated for English.
+// With WithDictionary it also measures how much of the text is real
+// words, to flag pages and documents with a garbled text layer.
+func ReadingStats(doc *crazypdf.Document, opts ...Option) (*ReadingReport, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+
+	report := &ReadingReport{}
+	for _, page := range doc.Pages() {
+		stats, err := PageReadingStats(page, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read text on page %d: %w", page.Number, err)
+		}
+		report.Pages = append(report.Pages, stats)
+
+		report.Total.Words += stats.Words
+		report.Total.Sentences += stats.Sentences
+		report.Total.Syllables += stats.Syllables
+		report.Total.found += stats.found
+		report.Total.checked += stats.checked
+	}
+	report.Total.finish(cfg)
+	return report, nil
+}
+
+// PageReadingStats computes reading statistics for a single page.
+func PageReadingStats(page *crazypdf.Page, opts ...Option) (TextStats, error) {
+	cfg := applyOptions(opts)
+	text, err := page.PlainText()
+	if err != nil {
+		return TextStats{}, err
+	}
+	skew, err := Skew(page)
+	if err != nil {
+		return TextStats{}, err
+	}
+	stats := countText(text, cfg.Dictionary)
+	stats.Page = page.Number
+	stats.Skew = skew.Angle
+	stats.finish(cfg)
+	return stats, nil
+}
+
+// countText counts words, sentences and syllables, and the words found in
+// dict unless it is nil. A word is a run of letters and digits, allowing
+// inner apostrophes and hyphens; a sentence ends at '.', '!' or '?'
+// followed by a space or the end of the text.
+func countText(text string, dict Dictionary) TextStats {
+	var stats TextStats
+	runes := []rune(text)
+	inSentence := false
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !isWordRune(r) {
+			if (r == '.' || r == '!' || r == '?') && inSentence &&
+				(i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+				stats.Sentences++
+				inSentence = false
+			}
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && (isWordRune(runes[i]) ||
+			(runes[i] == '\'' || runes[i] == '’' || runes[i] == '-') && i+1 < len(runes) && isWordRune(runes[i+1])) {
+			i++
+		}
+		word := string(runes[start:i])
+		stats.Words++
+		stats.Syllables += syllables(word)
+		inSentence = true
+		if dict != nil && strings.IndexFunc(word, unicode.IsDigit) < 0 {
+			stats.checked++
+			if inDictionary(dict, word) {
+				stats.found++
+			}
+		}
+	}
+	if inSentence {
+		stats.Sentences++
+	}
+	return stats
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// syllables estimates the syllables of an English word by counting vowel
+// groups, ignoring a silent final 'e'. Every word has at least one.
+func syllables(word string) int {
+	w := strings.ToLower(word)
+	isVowel := func(r rune) bool { return strings.ContainsRune("aeiouy", r) }
+
+	count := 0
+	prevVowel := false
+	for _, r := range w {
+		v := isVowel(r)
+		if v && !prevVowel {
+			count++
+		}
+		prevVowel = v
+	}
+	if strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "le") && count > 1 {
+		count--
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+// finish derives reading time, readability and dictionary coverage from
+// the counts.
+func (s *TextStats) finish(cfg *config) {
+	if cfg.WordsPerMinute > 0 {
+		s.ReadingTime = time.Duration(float64(s.Words) / cfg.WordsPerMinute * float64(time.Minute)).Round(time.Second)
+	}
+	if s.checked > 0 {
+		s.DictionaryCoverage = round2(float64(s.found) / float64(s.checked))
+		s.Garbled = s.checked >= minDictionaryWords && s.DictionaryCoverage < cfg.MinDictionaryCoverage
+	}
+	if s.Words == 0 || s.Sentences == 0 {
+		return
+	}
+	wordsPerSentence := float64(s.Words) / float64(s.Sentences)
+	syllablesPerWord := float64(s.Syllables) / float64(s.Words)
+	s.FleschReadingEase = round1(206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord)
+	s.FleschKincaidGrade = round1(0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59)
+}
+
+// round1 rounds to one decimal place.
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
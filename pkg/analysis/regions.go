@@ -0,0 +1,356 @@
+// Package analysis provides layout analysis for PDF pages.
+//
+// It operates on crazypdf.Page using geometric heuristics over text
+// positions and painted graphics, and is the foundation for layout-aware
+// processing such as header/footer removal and figure detection.
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// RegionKind identifies the semantic zone of a page region.
+type RegionKind int
+
+const (
+	// RegionBody is the main text flow of the page.
+	RegionBody RegionKind = iota
+
+	// RegionHeader is running header text near the top edge.
+	RegionHeader
+
+	// RegionFooter is running footer text near the bottom edge,
+	// typically page numbers and document identifiers.
+	RegionFooter
+
+	// RegionSidebar is narrow text placed beside the main text column,
+	// such as margin notes or pull quotes.
+	RegionSidebar
+
+	// RegionFigure is an image or a cluster of vector graphics, together
+	// with any text labels drawn inside it.
+	RegionFigure
+)
+
+// String returns the lowercase name of the region kind.
+func (k RegionKind) String() string {
+	switch k {
+	case RegionBody:
+		return "body"
+	case RegionHeader:
+		return "header"
+	case RegionFooter:
+		return "footer"
+	case RegionSidebar:
+		return "sidebar"
+	case RegionFigure:
+		return "figure"
+	default:
+		return fmt.Sprintf("RegionKind(%d)", int(k))
+	}
+}
+
+// Region is a classified area of a page.
+type Region struct {
+	Kind RegionKind
+
+	// BBox is the region's bounding box in PDF points, or in the space
+	// set with WithCoordinateSpace and WithDPI.
+	BBox crazypdf.Rect
+
+	// Text is the text contained in the region, one line per row.
+	// It is empty for figures without labels.
+	Text string
+}
+
+// maxBandLines is the number of lines a header or footer band may hold
+// before the band is assumed to contain body text instead.
+const maxBandLines = 3
+
+// Regions classifies the areas of a page into header, body, footer,
+// sidebar and figure zones. Regions are returned in reading order:
+// top to bottom, then left to right.
+func Regions(page *crazypdf.Page, opts ...Option) ([]Region, error) {
+	cfg := applyOptions(opts)
+
+	box, err := page.MediaBox()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page geometry: %w", err)
+	}
+	texts, err := page.StyledTexts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page text: %w", err)
+	}
+	graphics, err := page.Graphics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page graphics: %w", err)
+	}
+
+	lines := buildLines(texts)
+	figures := detectFigures(graphics, lines, box, cfg)
+
+	// Assign lines that fall inside a figure to that figure
+	figureLines := make([][]textLine, len(figures))
+	var rest []textLine
+	for _, ln := range lines {
+		cx := (ln.box.X0 + ln.box.X1) / 2
+		cy := (ln.box.Y0 + ln.box.Y1) / 2
+		assigned := false
+		for i, fig := range figures {
+			if fig.Contains(cx, cy) {
+				figureLines[i] = append(figureLines[i], ln)
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			rest = append(rest, ln)
+		}
+	}
+
+	// Classify the remaining lines
+	headerTop := box.Y1 - box.Height()*cfg.HeaderBand
+	footerTop := box.Y0 + box.Height()*cfg.FooterBand
+	var headers, footers, body []textLine
+	for _, ln := range rest {
+		switch {
+		case ln.box.Y0 >= headerTop:
+			headers = append(headers, ln)
+		case ln.box.Y1 <= footerTop:
+			footers = append(footers, ln)
+		default:
+			body = append(body, ln)
+		}
+	}
+	if len(headers) > maxBandLines {
+		body = append(body, headers...)
+		headers = nil
+	}
+	if len(footers) > maxBandLines {
+		body = append(body, footers...)
+		footers = nil
+	}
+
+	body, sidebars := splitSidebars(body, box)
+
+	var regions []Region
+	regions = append(regions, bandRegion(RegionHeader, headers)...)
+	regions = append(regions, groupLines(RegionBody, body)...)
+	regions = append(regions, groupLines(RegionSidebar, sidebars)...)
+	regions = append(regions, bandRegion(RegionFooter, footers)...)
+	for i, fig := range figures {
+		bbox := fig
+		for _, ln := range figureLines[i] {
+			bbox = bbox.Union(ln.box)
+		}
+		regions = append(regions, Region{
+			Kind: RegionFigure,
+			BBox: bbox,
+			Text: joinLines(figureLines[i]),
+		})
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool {
+		if regions[i].BBox.Y1 != regions[j].BBox.Y1 {
+			return regions[i].BBox.Y1 > regions[j].BBox.Y1
+		}
+		return regions[i].BBox.X0 < regions[j].BBox.X0
+	})
+	coords, err := page.Coordinates(cfg.Space, cfg.DPI)
+	if err != nil {
+		return nil, err
+	}
+	for i := range regions {
+		regions[i].BBox = coords.Rect(regions[i].BBox)
+	}
+	return regions, nil
+}
+
+// detectFigures returns the bounding boxes of images and clusters of
+// vector graphics that are large enough to be figures. Full-page
+// backgrounds and graphics that mostly frame text (boxes, ruled tables)
+// are excluded.
+func detectFigures(graphics []crazypdf.Graphic, lines []textLine, page crazypdf.Rect, cfg *config) []crazypdf.Rect {
+	pageArea := page.Area()
+	minArea := pageArea * cfg.MinFigureArea
+	maxArea := pageArea * 0.9
+
+	var figures []crazypdf.Rect
+	var paths []crazypdf.Rect
+	for _, g := range graphics {
+		bbox := g.BBox.Intersect(page)
+		if bbox.IsEmpty() {
+			continue
+		}
+		if g.Kind == crazypdf.GraphicImage {
+			if bbox.Area() >= minArea && bbox.Area() < maxArea {
+				figures = append(figures, bbox)
+			}
+			continue
+		}
+		if bbox.Area() < maxArea {
+			paths = append(paths, bbox)
+		}
+	}
+
+	for _, cluster := range clusterRects(paths, 4) {
+		if cluster.Area() < minArea || cluster.Area() >= maxArea {
+			continue
+		}
+		var textArea float64
+		for _, ln := range lines {
+			textArea += ln.box.Intersect(cluster).Area()
+		}
+		if textArea > cluster.Area()*0.3 {
+			continue
+		}
+		figures = append(figures, cluster)
+	}
+
+	return clusterRects(figures, 0)
+}
+
+// clusterRects merges rectangles that overlap or lie within gap points of
+// each other, returning the bounding box of each cluster.
+func clusterRects(rects []crazypdf.Rect, gap float64) []crazypdf.Rect {
+	clusters := append([]crazypdf.Rect(nil), rects...)
+
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(clusters); i++ {
+			grown := crazypdf.Rect{
+				X0: clusters[i].X0 - gap, Y0: clusters[i].Y0 - gap,
+				X1: clusters[i].X1 + gap, Y1: clusters[i].Y1 + gap,
+			}
+			for j := i + 1; j < len(clusters); j++ {
+				if !touches(grown, clusters[j]) {
+					continue
+				}
+				clusters[i] = clusters[i].Union(clusters[j])
+				clusters = append(clusters[:j], clusters[j+1:]...)
+				merged = true
+				break
+			}
+			if merged {
+				break
+			}
+		}
+	}
+	return clusters
+}
+
+// touches reports whether two rectangles overlap or share an edge. Unlike
+// Rect.Intersects it also holds for zero-width rules.
+func touches(a, b crazypdf.Rect) bool {
+	return a.X0 <= b.X1 && b.X0 <= a.X1 && a.Y0 <= b.Y1 && b.Y0 <= a.Y1
+}
+
+// splitSidebars separates narrow lines lying entirely beside the main text
+// column from the body. The body column is estimated from lines spanning
+// a substantial fraction of the page width.
+func splitSidebars(lines []textLine, page crazypdf.Rect) (body, sidebars []textLine) {
+	colX0, colX1 := 0.0, 0.0
+	found := false
+	for _, ln := range lines {
+		if ln.box.Width() < page.Width()*0.4 {
+			continue
+		}
+		if !found || ln.box.X0 < colX0 {
+			colX0 = ln.box.X0
+		}
+		if !found || ln.box.X1 > colX1 {
+			colX1 = ln.box.X1
+		}
+		found = true
+	}
+	if !found {
+		return lines, nil
+	}
+
+	for _, ln := range lines {
+		narrow := ln.box.Width() < page.Width()*0.35
+		beside := ln.box.X1 <= colX0 || ln.box.X0 >= colX1
+		if narrow && beside {
+			sidebars = append(sidebars, ln)
+		} else {
+			body = append(body, ln)
+		}
+	}
+	return body, sidebars
+}
+
+// groupLines merges vertically adjacent, horizontally overlapping lines
+// into regions of the given kind.
+func groupLines(kind RegionKind, lines []textLine) []Region {
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].box.Y1 != lines[j].box.Y1 {
+			return lines[i].box.Y1 > lines[j].box.Y1
+		}
+		return lines[i].box.X0 < lines[j].box.X0
+	})
+
+	type group struct {
+		box   crazypdf.Rect
+		lines []textLine
+	}
+	var groups []*group
+	for _, ln := range lines {
+		var target *group
+		for i := len(groups) - 1; i >= 0; i-- {
+			g := groups[i]
+			last := g.lines[len(g.lines)-1]
+			gap := last.box.Y0 - ln.box.Y1
+			if overlapX(g.box, ln.box) && gap < last.box.Height()*1.5 {
+				target = g
+				break
+			}
+		}
+		if target == nil {
+			groups = append(groups, &group{box: ln.box, lines: []textLine{ln}})
+			continue
+		}
+		target.box = target.box.Union(ln.box)
+		target.lines = append(target.lines, ln)
+	}
+
+	regions := make([]Region, 0, len(groups))
+	for _, g := range groups {
+		regions = append(regions, Region{Kind: kind, BBox: g.box, Text: joinLines(g.lines)})
+	}
+	return regions
+}
+
+// bandRegion merges all lines of a header or footer band into one region.
+func bandRegion(kind RegionKind, lines []textLine) []Region {
+	if len(lines) == 0 {
+		return nil
+	}
+	var box crazypdf.Rect
+	for _, ln := range lines {
+		box = box.Union(ln.box)
+	}
+	return []Region{{Kind: kind, BBox: box, Text: joinLines(lines)}}
+}
+
+// joinLines joins line texts top to bottom, left to right.
+func joinLines(lines []textLine) string {
+	sorted := make([]textLine, len(lines))
+	copy(sorted, lines)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].box.Y1 != sorted[j].box.Y1 {
+			return sorted[i].box.Y1 > sorted[j].box.Y1
+		}
+		return sorted[i].box.X0 < sorted[j].box.X0
+	})
+	parts := make([]string, 0, len(sorted))
+	for _, ln := range sorted {
+		if ln.text != "" {
+			parts = append(parts, ln.text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// maxSkew is the largest angle, in degrees from the nearest page axis, at
+// which baselines count as skewed rather than set at an angle on purpose.
+const maxSkew = 15
+
+// PageSkew is the skew of the text on a page, as on a page that went
+// through the scanner at a slight angle.
+type PageSkew struct {
+	Page int `json:"page"`
+
+	// Angle is the counterclockwise angle in degrees of the text
+	// baselines from the nearest page axis, positive when lines rise to
+	// the right. Rendering and OCR steps deskew the page by rotating it
+	// by -Angle about its center.
+	Angle float64 `json:"angle"`
+
+	// Samples is the number of glyph pairs the angle was measured on. It
+	// is 0 when the page has no text to measure, such as a scan without
+	// an OCR layer.
+	Samples int `json:"samples"`
+
+	// cx and cy are the center of the crop box, which Deskew rotates
+	// about.
+	cx, cy float64
+}
+
+// Skew estimates the skew of a page from the baselines of its text,
+// including the invisible text layer of OCRed scans: the weighted median
+// of the angles between consecutive glyph origins that lie within 15
+// degrees of a page axis. Text set at a larger angle, such as a rotated
+// label, is ignored, as is the page rotation; Orientation detects pages
+// turned by quarter turns.
+func Skew(page *crazypdf.Page) (*PageSkew, error) {
+	box, err := page.CropBox()
+	if err != nil {
+		return nil, err
+	}
+	glyphs, err := page.Glyphs()
+	if err != nil {
+		return nil, err
+	}
+	out := &PageSkew{Page: page.Number, cx: (box.X0 + box.X1) / 2, cy: (box.Y0 + box.Y1) / 2}
+
+	type sample struct{ angle, weight float64 }
+	var samples []sample
+	total := 0.0
+	for i := 1; i < len(glyphs); i++ {
+		a, b := glyphs[i-1], glyphs[i]
+		size := math.Abs(a.FontSize)
+		if size == 0 {
+			size = 12
+		}
+		dx, dy := b.X-a.X, b.Y-a.Y
+		d := math.Hypot(dx, dy)
+		if d < size*0.1 || d > size*3 {
+			continue // overprinted glyphs or the next line
+		}
+		angle := math.Mod(math.Atan2(dy, dx)*180/math.Pi+45, 90)
+		if angle < 0 {
+			angle += 90
+		}
+		angle -= 45
+		if math.Abs(angle) > maxSkew {
+			continue
+		}
+		samples = append(samples, sample{angle, d})
+		total += d
+	}
+	if len(samples) == 0 {
+		return out, nil
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].angle < samples[j].angle })
+	acc := 0.0
+	for _, s := range samples {
+		acc += s.weight
+		if acc >= total/2 {
+			out.Angle = math.Round(s.angle*100) / 100
+			break
+		}
+	}
+	out.Samples = len(samples)
+	return out, nil
+}
+
+// Deskew maps a point on the page to where it lies once the page is
+// deskewed, rotated by -Angle about the center of its crop box, to
+// annotate extracted coordinates for a deskewed rendering.
+func (s *PageSkew) Deskew(x, y float64) (float64, float64) {
+	if s.Angle == 0 {
+		return x, y
+	}
+	sin, cos := math.Sincos(-s.Angle * math.Pi / 180)
+	dx, dy := x-s.cx, y-s.cy
+	return s.cx + dx*cos - dy*sin, s.cy + dx*sin + dy*cos
+}
+
+// DeskewRect returns the bounding box of r once the page is deskewed.
+func (s *PageSkew) DeskewRect(r crazypdf.Rect) crazypdf.Rect {
+	out := crazypdf.Rect{X0: math.Inf(1), Y0: math.Inf(1), X1: math.Inf(-1), Y1: math.Inf(-1)}
+	for _, p := range [4][2]float64{{r.X0, r.Y0}, {r.X1, r.Y0}, {r.X0, r.Y1}, {r.X1, r.Y1}} {
+		x, y := s.Deskew(p[0], p[1])
+		out.X0, out.Y0 = math.Min(out.X0, x), math.Min(out.Y0, y)
+		out.X1, out.Y1 = math.Max(out.X1, x), math.Max(out.Y1, y)
+	}
+	return out
+}
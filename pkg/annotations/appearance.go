@@ -0,0 +1,98 @@
+package annotations
+
+import (
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Appearance is the normal appearance of one annotation: the form that
+// viewers draw in the annotation rectangle, such as the picture of a
+// stamp or the visible part of a signature.
+type Appearance struct {
+	// Page is the 1-based page number and Index the position of the
+	// annotation in the page's annotation list.
+	Page  int `json:"page"`
+	Index int `json:"index"`
+
+	Subtype string `json:"subtype"`
+
+	// Name is the annotation name, if any.
+	Name string `json:"name,omitempty"`
+
+	// State is the appearance state the stream was selected by, such as
+	// On or Off for a check box. It is empty for annotations with a
+	// single appearance.
+	State string `json:"state,omitempty"`
+
+	// Rect is the annotation rectangle on the page.
+	Rect crazypdf.Rect `json:"rect"`
+
+	// BBox and Matrix are the bounding box and matrix of the appearance
+	// form, in form space.
+	BBox   crazypdf.Rect `json:"bbox"`
+	Matrix [6]float64    `json:"matrix"`
+
+	// Content is the decoded content stream of the appearance.
+	Content []byte `json:"-"`
+
+	// Drawing is the paths and images the appearance paints, placed in
+	// the annotation rectangle in page user space. Text is not included.
+	// Render it with render.DrawingSVG.
+	Drawing []crazypdf.DrawItem `json:"-"`
+}
+
+// Appearances returns the normal appearance of every annotation in the
+// document that has one, in page order. When subtypes are given, such as
+// "Stamp" or "Widget" (form fields and signatures), only annotations of
+// those subtypes are returned. Annotations without an appearance stream
+// are left out.
+func Appearances(doc *crazypdf.Document, subtypes ...string) ([]Appearance, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := file.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	include := make(map[string]bool, len(subtypes))
+	for _, s := range subtypes {
+		include[s] = true
+	}
+
+	var out []Appearance
+	for i, ref := range refs {
+		page, ok := file.Resolve(ref).(internalpdf.Dict)
+		if !ok {
+			continue
+		}
+		for _, app := range file.PageAppearances(page) {
+			if len(include) > 0 && !include[string(app.Subtype)] {
+				continue
+			}
+			content, err := file.DecodeStream(app.Form)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode appearance of annotation %d on page %d: %w", app.Index, i+1, err)
+			}
+			out = append(out, Appearance{
+				Page:    i + 1,
+				Index:   app.Index,
+				Subtype: string(app.Subtype),
+				Name:    app.Name,
+				State:   string(app.State),
+				Rect:    app.Rect,
+				BBox:    app.BBox,
+				Matrix:  app.Matrix,
+				Content: content,
+				Drawing: file.AppearanceDrawing(app),
+			})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,134 @@
+// Package annotations works with the annotations of PDF documents:
+// comments, highlights, stamps, drawings and other markups placed on top
+// of the page content.
+//
+// Flatten burns annotation appearances into the page content so that
+// review markups survive in viewers that ignore annotations and in
+// archives, at the cost of no longer being editable. Appearances exports
+// the appearance streams themselves, such as stamps and signatures.
+package annotations
+
+import (
+	"fmt"
+	"io"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// PageReport summarizes what was flattened on one page.
+type PageReport struct {
+	Page int `json:"page"`
+
+	// Flattened is the number of annotations drawn into the page content
+	// and removed.
+	Flattened int `json:"flattened"`
+
+	// Removed is the number of annotations removed without drawing:
+	// hidden annotations and the pop-ups of flattened annotations.
+	Removed int `json:"removed"`
+
+	// Skipped is the number of annotations kept because they have no
+	// appearance stream to draw.
+	Skipped int `json:"skipped"`
+}
+
+// Report describes the result of Flatten. Pages lists only the pages
+// that had annotations to flatten.
+type Report struct {
+	Pages []PageReport `json:"pages"`
+
+	// Output describes the written document.
+	Output *crazypdf.WriteResult `json:"output,omitempty"`
+}
+
+// Flattened returns the total number of annotations flattened.
+func (r *Report) Flattened() int {
+	n := 0
+	for _, p := range r.Pages {
+		n += p.Flattened
+	}
+	return n
+}
+
+// Flatten draws the appearance of the document's annotations into the
+// page content, removes the annotations and writes the result to w.
+// Links and form fields are kept unless selected with WithSubtypes or
+// WithForms. Annotations without an appearance stream cannot be drawn and
+// are kept; they are counted in PageReport.Skipped.
+func Flatten(doc *crazypdf.Document, w io.Writer, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	return flatten(doc, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.Write(w, fn, cfg.WriteOptions...)
+	})
+}
+
+// FlattenFile flattens annotations like Flatten and writes the result to
+// path atomically.
+func FlattenFile(doc *crazypdf.Document, path string, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	return flatten(doc, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.WriteFile(path, fn, cfg.WriteOptions...)
+	})
+}
+
+// flatten flattens annotations and hands the serialized document to
+// write.
+func flatten(doc *crazypdf.Document, cfg *config, write func(func(io.Writer) error) (*crazypdf.WriteResult, error)) (*Report, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+	refs, err := editor.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+
+	include := func(subtype internalpdf.Name) bool {
+		return cfg.includes(string(subtype))
+	}
+	report := &Report{}
+	for i, ref := range refs {
+		stats, err := editor.FlattenPage(ref, include)
+		if err != nil {
+			return nil, fmt.Errorf("failed to flatten page %d: %w", i+1, err)
+		}
+		if stats == (internalpdf.FlattenStats{}) {
+			continue
+		}
+		report.Pages = append(report.Pages, PageReport{
+			Page:      i + 1,
+			Flattened: stats.Flattened,
+			Removed:   stats.Removed,
+			Skipped:   stats.Skipped,
+		})
+	}
+	if cfg.Forms {
+		// The fields' widgets are now page content
+		editor.SetCatalogEntry("AcroForm", nil)
+	}
+
+	crazypdf.ApplyMetadata(editor, cfg.Metadata)
+	writeCfg := crazypdf.NewWriteConfig(cfg.WriteOptions...)
+	editor.UniqueID = writeCfg.UniqueID
+	if err := crazypdf.ApplyInitialView(editor, writeCfg.InitialView); err != nil {
+		return nil, fmt.Errorf("failed to set initial view: %w", err)
+	}
+	output, err := write(func(w io.Writer) error {
+		_, err := editor.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	report.Output = output
+	return report, nil
+}
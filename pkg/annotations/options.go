@@ -0,0 +1,79 @@
+package annotations
+
+import "github.com/ayushanand18/crazypdf/pkg/crazypdf"
+
+// config holds configuration for flattening.
+type config struct {
+	Subtypes     map[string]bool // subtypes to flatten; nil for all but Link and Widget
+	Forms        bool            // flatten form field widgets and drop the form
+	WriteOptions []crazypdf.WriteOption
+	Metadata     crazypdf.MetadataPolicy
+}
+
+// Option is a functional option for configuring flattening.
+type Option func(*config)
+
+// WithSubtypes restricts flattening to annotations of the given subtypes,
+// such as "Highlight", "FreeText" or "Stamp". By default every subtype
+// except Link and Widget is flattened.
+func WithSubtypes(subtypes ...string) Option {
+	return func(c *config) {
+		c.Subtypes = make(map[string]bool, len(subtypes))
+		for _, s := range subtypes {
+			c.Subtypes[s] = true
+		}
+	}
+}
+
+// WithForms also flattens form field widgets, drawing the field values
+// into the page, and removes the interactive form so the fields can no
+// longer be edited.
+func WithForms() Option {
+	return func(c *config) {
+		c.Forms = true
+	}
+}
+
+// WithWriteOptions sets how the flattened document is written, such as
+// computing its SHA-256 with crazypdf.WithChecksum.
+func WithWriteOptions(opts ...crazypdf.WriteOption) Option {
+	return func(c *config) {
+		c.WriteOptions = opts
+	}
+}
+
+// WithMetadata sets what happens to the document metadata in the
+// flattened output: crazypdf.MetadataPreserve (the default),
+// crazypdf.MetadataStrip or crazypdf.MetadataReplace(info).
+func WithMetadata(policy crazypdf.MetadataPolicy) Option {
+	return func(c *config) {
+		c.Metadata = policy
+	}
+}
+
+// includes reports whether annotations of a subtype are flattened.
+func (c *config) includes(subtype string) bool {
+	if subtype == "Widget" {
+		return c.Forms
+	}
+	if c.Subtypes != nil {
+		return c.Subtypes[subtype]
+	}
+	return subtype != "Link"
+}
+
+// defaultConfig returns the default flattening configuration.
+func defaultConfig() *config {
+	return &config{
+		Metadata: crazypdf.MetadataPreserve,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
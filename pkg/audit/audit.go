@@ -0,0 +1,144 @@
+// Package audit finds and removes active content in PDF documents.
+//
+// Active content is anything a viewer executes or that reaches outside the
+// document: JavaScript, launching applications and submitting form data.
+// StripActive writes a sanitized copy of a document without it, suitable
+// for distribution, leaving pages, links, bookmarks and forms otherwise
+// intact.
+package audit
+
+import (
+	"fmt"
+	"io"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// activeActions are the action types removed by StripActive.
+var activeActions = []internalpdf.Name{"JavaScript", "Launch", "SubmitForm"}
+
+// Removal is an action removed from the document.
+type Removal struct {
+	// Action is the action type: JavaScript, Launch or SubmitForm.
+	Action string `json:"action"`
+
+	// Location describes where the action was, such as "page 3
+	// annotation" or "document open action".
+	Location string `json:"location"`
+
+	// Trigger is the event that ran the action, such as O (page opened)
+	// or K (field keystroke) for additional actions; empty for actions
+	// run on click or on open.
+	Trigger string `json:"trigger,omitempty"`
+}
+
+// Report describes the result of StripActive.
+type Report struct {
+	Removed []Removal `json:"removed"`
+
+	// Output describes the written document.
+	Output *crazypdf.WriteResult `json:"output,omitempty"`
+}
+
+// StripActive removes JavaScript, Launch and SubmitForm actions from the
+// document and writes the sanitized copy to w. Actions are removed from
+// the document open action, document-level scripts, pages, annotations,
+// form fields and bookmarks; a link whose only action was removed stays
+// in place but does nothing.
+func StripActive(doc *crazypdf.Document, w io.Writer, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	return stripActive(doc, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.Write(w, fn, cfg.WriteOptions...)
+	})
+}
+
+// StripActiveFile removes active content like StripActive and writes the
+// sanitized copy to path atomically.
+func StripActiveFile(doc *crazypdf.Document, path string, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	return stripActive(doc, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.WriteFile(path, fn, cfg.WriteOptions...)
+	})
+}
+
+// stripActive removes active content and hands the serialized document
+// to write.
+func stripActive(doc *crazypdf.Document, cfg *config, write func(func(io.Writer) error) (*crazypdf.WriteResult, error)) (*Report, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+	locations, err := objectLocations(editor)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, s := range editor.StripActions(activeActions...) {
+		r := Removal{Action: string(s.Type), Location: locations[s.Object]}
+		if r.Location == "" {
+			r.Location = fmt.Sprintf("object %d", s.Object)
+		}
+		switch s.Key {
+		case "A":
+		case "Next":
+			r.Location += " chained action"
+		case "OpenAction":
+			r.Location += " open action"
+		case "JavaScript":
+			r.Location += " script"
+		default:
+			r.Trigger = string(s.Key)
+		}
+		report.Removed = append(report.Removed, r)
+	}
+
+	crazypdf.ApplyMetadata(editor, cfg.Metadata)
+	writeCfg := crazypdf.NewWriteConfig(cfg.WriteOptions...)
+	editor.UniqueID = writeCfg.UniqueID
+	if err := crazypdf.ApplyInitialView(editor, writeCfg.InitialView); err != nil {
+		return nil, fmt.Errorf("failed to set initial view: %w", err)
+	}
+	output, err := write(func(w io.Writer) error {
+		_, err := editor.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	report.Output = output
+	return report, nil
+}
+
+// objectLocations describes the catalog, pages and annotations of a
+// document by object number, for reporting.
+func objectLocations(e *internalpdf.Editor) (map[int]string, error) {
+	refs, err := e.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	locations := make(map[int]string)
+	if root, ok := e.Trailer["Root"].(internalpdf.Ref); ok {
+		locations[root.Num] = "document"
+	}
+	for i, ref := range refs {
+		locations[ref.Num] = fmt.Sprintf("page %d", i+1)
+		page, _ := e.Resolve(ref).(internalpdf.Dict)
+		annots, _ := e.Resolve(page["Annots"]).(internalpdf.Array)
+		for _, a := range annots {
+			if annot, ok := a.(internalpdf.Ref); ok {
+				locations[annot.Num] = fmt.Sprintf("page %d annotation", i+1)
+			}
+		}
+	}
+	return locations, nil
+}
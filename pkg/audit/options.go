@@ -0,0 +1,45 @@
+package audit
+
+import "github.com/ayushanand18/crazypdf/pkg/crazypdf"
+
+// config holds configuration for sanitizing documents.
+type config struct {
+	WriteOptions []crazypdf.WriteOption
+	Metadata     crazypdf.MetadataPolicy
+}
+
+// Option is a functional option for configuring sanitization.
+type Option func(*config)
+
+// WithWriteOptions sets how the sanitized document is written, such as
+// computing its SHA-256 with crazypdf.WithChecksum.
+func WithWriteOptions(opts ...crazypdf.WriteOption) Option {
+	return func(c *config) {
+		c.WriteOptions = opts
+	}
+}
+
+// WithMetadata sets what happens to the document metadata in the
+// sanitized output: crazypdf.MetadataPreserve (the default),
+// crazypdf.MetadataStrip or crazypdf.MetadataReplace(info).
+func WithMetadata(policy crazypdf.MetadataPolicy) Option {
+	return func(c *config) {
+		c.Metadata = policy
+	}
+}
+
+// defaultConfig returns the default sanitization configuration.
+func defaultConfig() *config {
+	return &config{
+		Metadata: crazypdf.MetadataPreserve,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,206 @@
+// Package batch runs a function over many PDF files with a worker pool.
+//
+// It handles the plumbing that otherwise gets rewritten around the
+// library: opening and closing each document, bounded concurrency,
+// collecting per-file errors without stopping the batch, retrying
+// transient failures with backoff and reporting progress.
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Result is the outcome of processing one file.
+type Result struct {
+	Path string
+
+	// Err is the error from opening the file or from the function, or
+	// nil on success.
+	Err error
+
+	// Attempts is the number of times the file was tried.
+	Attempts int
+
+	// Duration is the total time spent on the file, including retries.
+	Duration time.Duration
+}
+
+// Report holds the results of a batch in the order of the input paths.
+type Report struct {
+	Results []Result
+}
+
+// Succeeded returns the number of files processed without error.
+func (r *Report) Succeeded() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Failures returns the results of files that failed.
+func (r *Report) Failures() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Err != nil {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Err joins the errors of every failed file, each prefixed with its path,
+// or returns nil when all succeeded.
+func (r *Report) Err() error {
+	var errs []error
+	for _, res := range r.Failures() {
+		errs = append(errs, fmt.Errorf("%s: %w", res.Path, res.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// Progress describes the state of a batch after a file finishes.
+type Progress struct {
+	// Result is the outcome of the file that just finished.
+	Result Result
+
+	// Done and Failed count the files finished so far and those that
+	// failed; Total is the number of files in the batch.
+	Done   int
+	Failed int
+	Total  int
+}
+
+// Process opens each file, calls fn with the document and closes it,
+// processing files concurrently. A failing file does not stop the batch:
+// every file is attempted and the returned report holds one result per
+// path. The error is the report's Err, so it is non-nil when any file
+// failed. Transient failures are retried when WithRetries is set, and a
+// panic while processing a file is recovered and reported as that file's
+// error.
+func Process(paths []string, fn func(*crazypdf.Document) error, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	report := &Report{Results: make([]Result, len(paths))}
+
+	var mu sync.Mutex
+	done, failed := 0, 0
+	finish := func(res Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if res.Err != nil {
+			failed++
+		}
+		if cfg.Progress != nil {
+			cfg.Progress(Progress{Result: res, Done: done, Failed: failed, Total: len(paths)})
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers && w < len(paths); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := processFile(paths[i], fn, cfg)
+				report.Results[i] = res
+				finish(res)
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return report, report.Err()
+}
+
+// processFile processes one file, retrying transient failures.
+func processFile(path string, fn func(*crazypdf.Document) error, cfg *config) Result {
+	res := Result{Path: path}
+	start := time.Now()
+	delay := cfg.RetryDelay
+	for {
+		res.Attempts++
+		res.Err = attempt(path, fn, cfg)
+		if res.Err == nil || res.Attempts > cfg.Retries || !cfg.RetryIf(res.Err) {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	res.Duration = time.Since(start)
+	return res
+}
+
+// attempt opens a file once and calls fn with it.
+func attempt(path string, fn func(*crazypdf.Document) error, cfg *config) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	doc, err := crazypdf.Open(path, cfg.OpenOptions...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := doc.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to close document: %w", cerr)
+		}
+	}()
+	return fn(doc)
+}
+
+// transientError marks an error as worth retrying.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// Transient marks err as transient so that the default retry policy
+// retries it. Functions passed to Process use it for failures of their
+// own, such as a timed-out upload of the results. It returns nil when err
+// is nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientError{err: err}
+}
+
+// IsTransient reports whether err is likely to succeed on retry: errors
+// marked with Transient, timeouts, and interrupted or busy system calls.
+// Errors about the document itself, such as an invalid PDF or a wrong
+// password, are not transient.
+func IsTransient(err error) bool {
+	var marked *transientError
+	if errors.As(err, &marked) {
+		return true
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true
+	}
+	return errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.Is(err, syscall.EINTR) ||
+		errors.Is(err, syscall.EAGAIN) ||
+		errors.Is(err, syscall.EBUSY) ||
+		errors.Is(err, syscall.ETIMEDOUT)
+}
@@ -0,0 +1,93 @@
+package batch
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// config holds configuration for batch processing.
+type config struct {
+	Workers     int           // files processed concurrently
+	Retries     int           // extra attempts for transient failures
+	RetryDelay  time.Duration // delay before the first retry, doubled after each
+	RetryIf     func(error) bool
+	Progress    func(Progress)
+	OpenOptions []crazypdf.Option
+}
+
+// Option is a functional option for configuring batch processing.
+type Option func(*config)
+
+// WithWorkers sets how many files are processed concurrently. Default is
+// the number of CPUs.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.Workers = n
+	}
+}
+
+// WithRetries sets how many times a file is retried after a transient
+// failure. Default is 0.
+func WithRetries(n int) Option {
+	return func(c *config) {
+		c.Retries = n
+	}
+}
+
+// WithRetryDelay sets the delay before the first retry; each further
+// retry waits twice as long. Default is 100ms.
+func WithRetryDelay(d time.Duration) Option {
+	return func(c *config) {
+		c.RetryDelay = d
+	}
+}
+
+// WithRetryIf sets the function deciding whether an error is transient
+// and the file should be retried. Default is IsTransient.
+func WithRetryIf(fn func(error) bool) Option {
+	return func(c *config) {
+		c.RetryIf = fn
+	}
+}
+
+// WithProgress sets a function called after each file finishes. Calls are
+// serialized, so fn need not be safe for concurrent use.
+func WithProgress(fn func(Progress)) Option {
+	return func(c *config) {
+		c.Progress = fn
+	}
+}
+
+// WithOpenOptions sets the options used to open every document, such as
+// a password.
+func WithOpenOptions(opts ...crazypdf.Option) Option {
+	return func(c *config) {
+		c.OpenOptions = opts
+	}
+}
+
+// defaultConfig returns the default batch configuration.
+func defaultConfig() *config {
+	return &config{
+		Workers:    runtime.NumCPU(),
+		RetryDelay: 100 * time.Millisecond,
+		RetryIf:    IsTransient,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.RetryIf == nil {
+		cfg.RetryIf = IsTransient
+	}
+	return cfg
+}
@@ -0,0 +1,341 @@
+// Package contentstream disassembles PDF content streams into a readable
+// operator listing for debugging extraction issues, and rewrites them.
+//
+// Each operation is printed on its own line in content stream syntax,
+// indented by graphics state, text object and marked-content nesting, and
+// annotated with what it does: the text of shown strings, the font and
+// size selected, and the translation, scale and rotation of matrices.
+//
+// Parse turns a stream into Operations that a Builder filters, extends
+// and stamps over before serializing them back into a valid stream, the
+// primitive behind redaction, watermarking and flattening.
+package contentstream
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// commentColumn is the column at which comments start when the operation
+// is shorter.
+const commentColumn = 40
+
+// Disassemble returns a listing of the operations in a decoded content
+// stream, one per line. When the stream is malformed, the listing of the
+// operations before the error is returned with the error.
+func Disassemble(data []byte, opts ...Option) (string, error) {
+	cfg := applyOptions(opts)
+	ops, parseErr := internalpdf.ParseContent(data)
+
+	var b strings.Builder
+	depth := 0
+	for _, op := range ops {
+		switch op.Name {
+		case "Q", "ET", "EMC", "EX":
+			if depth > 0 {
+				depth--
+			}
+		}
+
+		line := strings.Repeat(cfg.Indent, depth) + formatOp(op)
+		if cfg.Comments {
+			if comment := describe(op); comment != "" {
+				pad := commentColumn - len(line)
+				if pad < 1 {
+					pad = 1
+				}
+				line += strings.Repeat(" ", pad) + "% " + comment
+			}
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+
+		switch op.Name {
+		case "q", "BT", "BMC", "BDC", "BX":
+			depth++
+		}
+	}
+
+	if parseErr != nil {
+		return b.String(), fmt.Errorf("malformed content after %d operations: %w", len(ops), parseErr)
+	}
+	return b.String(), nil
+}
+
+// formatOp writes an operation in content stream syntax. Inline image
+// data is replaced by its length.
+func formatOp(op internalpdf.Op) string {
+	if op.Name == "BI" && len(op.Operands) == 2 {
+		dict, _ := op.Operands[0].(internalpdf.Dict)
+		data, _ := op.Operands[1].(internalpdf.String)
+		var b strings.Builder
+		b.WriteString("BI")
+		for _, k := range sortedKeys(dict) {
+			fmt.Fprintf(&b, " %s %s", internalpdf.SerializeObject(k), internalpdf.SerializeObject(dict[k]))
+		}
+		fmt.Fprintf(&b, " ID <%d bytes> EI", len(data))
+		return b.String()
+	}
+
+	parts := make([]string, 0, len(op.Operands)+1)
+	for _, operand := range op.Operands {
+		parts = append(parts, string(internalpdf.SerializeObject(operand)))
+	}
+	parts = append(parts, op.Name)
+	return strings.Join(parts, " ")
+}
+
+// describe returns the comment for an operation: the text or matrix it
+// carries, or what the operator does.
+func describe(op internalpdf.Op) string {
+	args := op.Operands
+	switch op.Name {
+	case "Tj", "'":
+		if len(args) == 1 {
+			return "show " + quote(args[0])
+		}
+	case "\"":
+		if len(args) == 3 {
+			return "next line, show " + quote(args[2])
+		}
+	case "TJ":
+		if len(args) == 1 {
+			if arr, ok := args[0].(internalpdf.Array); ok {
+				return "show " + quote(joinTJ(arr))
+			}
+		}
+	case "Tf":
+		if len(args) == 2 {
+			return fmt.Sprintf("font %s at %s pt", internalpdf.SerializeObject(args[0]), internalpdf.SerializeObject(args[1]))
+		}
+	case "cm", "Tm":
+		if m, ok := matrix(args); ok {
+			return operators[op.Name] + ": " + decompose(m)
+		}
+	case "Do":
+		if len(args) == 1 {
+			return "paint XObject " + string(internalpdf.SerializeObject(args[0]))
+		}
+	}
+	return operators[op.Name]
+}
+
+// joinTJ concatenates the strings of a TJ array, inserting a space where
+// a position adjustment moves right by more than a quarter of the font
+// size, as word spacing does.
+func joinTJ(arr internalpdf.Array) internalpdf.String {
+	var b strings.Builder
+	for _, el := range arr {
+		switch v := el.(type) {
+		case internalpdf.String:
+			b.WriteString(string(v))
+		case int64:
+			if v < -250 {
+				b.WriteByte(' ')
+			}
+		case float64:
+			if v < -250 {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return internalpdf.String(b.String())
+}
+
+// quote formats the bytes of a string operand: quoted when they are
+// printable ASCII, as hex otherwise, since their meaning depends on the
+// font's encoding.
+func quote(o internalpdf.Object) string {
+	s, ok := o.(internalpdf.String)
+	if !ok {
+		return string(internalpdf.SerializeObject(o))
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return "<" + hex.EncodeToString([]byte(s)) + ">"
+		}
+	}
+	return fmt.Sprintf("%q", string(s))
+}
+
+// matrix reads the six numeric operands of cm or Tm.
+func matrix(args []internalpdf.Object) ([6]float64, bool) {
+	var m [6]float64
+	if len(args) != 6 {
+		return m, false
+	}
+	for i, a := range args {
+		switch v := a.(type) {
+		case int64:
+			m[i] = float64(v)
+		case float64:
+			m[i] = v
+		default:
+			return m, false
+		}
+	}
+	return m, true
+}
+
+// decompose describes a matrix as translation, scale, rotation and skew,
+// leaving out the identity parts.
+func decompose(m [6]float64) string {
+	a, b, c, d, e, f := m[0], m[1], m[2], m[3], m[4], m[5]
+	var parts []string
+	if e != 0 || f != 0 {
+		parts = append(parts, fmt.Sprintf("translate %s, %s", num(e), num(f)))
+	}
+	sx := math.Hypot(a, b)
+	det := a*d - b*c
+	sy := 0.0
+	if sx != 0 {
+		sy = det / sx
+	}
+	if sx != 1 || sy != 1 {
+		if sx == sy {
+			parts = append(parts, "scale "+num(sx))
+		} else {
+			parts = append(parts, fmt.Sprintf("scale %s, %s", num(sx), num(sy)))
+		}
+	}
+	if rot := math.Atan2(b, a) * 180 / math.Pi; rot != 0 {
+		parts = append(parts, fmt.Sprintf("rotate %s°", num(rot)))
+	}
+	if sx != 0 && det != 0 {
+		if skew := (a*c + b*d) / det; math.Abs(skew) > 1e-9 {
+			parts = append(parts, "skew "+num(skew))
+		}
+	}
+	if len(parts) == 0 {
+		return "identity"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// num formats a number with at most three decimals.
+func num(v float64) string {
+	s := fmt.Sprintf("%.3f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// sortedKeys returns the keys of d in lexical order.
+func sortedKeys(d internalpdf.Dict) []internalpdf.Name {
+	keys := make([]internalpdf.Name, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// operators describes the content stream operators (PDF 32000-1:2008,
+// annex A).
+var operators = map[string]string{
+	// General graphics state
+	"w":  "line width",
+	"J":  "line cap",
+	"j":  "line join",
+	"M":  "miter limit",
+	"d":  "dash pattern",
+	"ri": "rendering intent",
+	"i":  "flatness",
+	"gs": "apply ExtGState",
+
+	// Special graphics state
+	"q":  "save graphics state",
+	"Q":  "restore graphics state",
+	"cm": "concatenate matrix",
+
+	// Path construction
+	"m":  "move to",
+	"l":  "line to",
+	"c":  "curve to",
+	"v":  "curve to (first control point at current point)",
+	"y":  "curve to (second control point at end point)",
+	"h":  "close subpath",
+	"re": "rectangle",
+
+	// Path painting
+	"S":  "stroke",
+	"s":  "close and stroke",
+	"f":  "fill (nonzero)",
+	"F":  "fill (nonzero)",
+	"f*": "fill (even-odd)",
+	"B":  "fill and stroke (nonzero)",
+	"B*": "fill and stroke (even-odd)",
+	"b":  "close, fill and stroke (nonzero)",
+	"b*": "close, fill and stroke (even-odd)",
+	"n":  "end path without painting",
+
+	// Clipping paths
+	"W":  "clip (nonzero)",
+	"W*": "clip (even-odd)",
+
+	// Text objects
+	"BT": "begin text",
+	"ET": "end text",
+
+	// Text state
+	"Tc": "character spacing",
+	"Tw": "word spacing",
+	"Tz": "horizontal scaling",
+	"TL": "leading",
+	"Tf": "font and size",
+	"Tr": "text rendering mode",
+	"Ts": "text rise",
+
+	// Text positioning
+	"Td": "move to next line",
+	"TD": "move to next line and set leading",
+	"Tm": "text matrix",
+	"T*": "move to next line",
+
+	// Text showing
+	"Tj": "show text",
+	"TJ": "show text with positioning",
+	"'":  "next line, show text",
+	"\"": "set spacing, next line, show text",
+
+	// Type 3 fonts
+	"d0": "glyph width",
+	"d1": "glyph width and bounding box",
+
+	// Color
+	"CS":  "stroke color space",
+	"cs":  "fill color space",
+	"SC":  "stroke color",
+	"SCN": "stroke color",
+	"sc":  "fill color",
+	"scn": "fill color",
+	"G":   "stroke gray",
+	"g":   "fill gray",
+	"RG":  "stroke RGB",
+	"rg":  "fill RGB",
+	"K":   "stroke CMYK",
+	"k":   "fill CMYK",
+
+	// Shading, images and XObjects
+	"sh": "paint shading",
+	"BI": "inline image",
+	"Do": "paint XObject",
+
+	// Marked content
+	"MP":  "marked-content point",
+	"DP":  "marked-content point with properties",
+	"BMC": "begin marked content",
+	"BDC": "begin marked content with properties",
+	"EMC": "end marked content",
+
+	// Compatibility
+	"BX": "begin compatibility section",
+	"EX": "end compatibility section",
+}
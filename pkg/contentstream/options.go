@@ -0,0 +1,44 @@
+package contentstream
+
+// config holds configuration for disassembly.
+type config struct {
+	Indent   string // indentation per nesting level
+	Comments bool   // annotate operations with their meaning
+}
+
+// Option is a functional option for configuring disassembly.
+type Option func(*config)
+
+// WithIndent sets the indentation added for each level of q/Q, BT/ET and
+// marked-content nesting. Default is two spaces.
+func WithIndent(indent string) Option {
+	return func(c *config) {
+		c.Indent = indent
+	}
+}
+
+// WithComments sets whether operations are followed by a % comment
+// describing them, with the text of strings and the decomposition of
+// matrices. Default is true.
+func WithComments(enabled bool) Option {
+	return func(c *config) {
+		c.Comments = enabled
+	}
+}
+
+// defaultConfig returns the default disassembly configuration.
+func defaultConfig() *config {
+	return &config{
+		Indent:   "  ",
+		Comments: true,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
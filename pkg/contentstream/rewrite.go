@@ -0,0 +1,322 @@
+package contentstream
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Name is a PDF name operand without the leading slash, such as the F1 of
+// /F1 12 Tf.
+type Name string
+
+// String is a PDF string operand holding the bytes of a literal or
+// hexadecimal string, in the encoding of the current font.
+type String string
+
+// Array is a PDF array operand, such as the argument of TJ.
+type Array []any
+
+// Dict is a PDF dictionary operand, such as the properties of BDC.
+type Dict map[Name]any
+
+// Operation is one content stream operator with its operands. Operands are
+// nil, bool, int, int64, float64, Name, String, Array or Dict. An inline
+// image is the operator BI with two operands: its Dict of entries and its
+// data as a String.
+type Operation struct {
+	Operator string
+	Operands []any
+}
+
+// Op returns the operation of operator with the given operands, for
+// building content to insert.
+func Op(operator string, operands ...any) Operation {
+	return Operation{Operator: operator, Operands: operands}
+}
+
+// Parse parses a decoded content stream into its operations. When the
+// stream is malformed, the operations before the error are returned with
+// the error.
+func Parse(data []byte) ([]Operation, error) {
+	ops, err := internalpdf.ParseContent(data)
+	out := make([]Operation, len(ops))
+	for i, op := range ops {
+		operands := make([]any, len(op.Operands))
+		for j, o := range op.Operands {
+			operands[j] = fromObject(o)
+		}
+		out[i] = Operation{Operator: op.Name, Operands: operands}
+	}
+	if err != nil {
+		return out, fmt.Errorf("malformed content after %d operations: %w", len(ops), err)
+	}
+	return out, nil
+}
+
+// Serialize writes operations as a content stream, one operation per
+// line. It fails when an operand has an unsupported type, an operator is
+// not a valid keyword or q/Q, BT/ET or marked-content operators are not
+// properly nested, so the result is always a stream viewers accept.
+func Serialize(ops []Operation) ([]byte, error) {
+	if err := checkNesting(ops); err != nil {
+		return nil, err
+	}
+	internal := make([]internalpdf.Op, len(ops))
+	for i, op := range ops {
+		if err := checkOperator(op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		operands := make([]internalpdf.Object, len(op.Operands))
+		for j, o := range op.Operands {
+			obj, err := toObject(o)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d (%s): operand %d: %w", i, op.Operator, j, err)
+			}
+			operands[j] = obj
+		}
+		internal[i] = internalpdf.Op{Name: op.Operator, Operands: operands}
+	}
+	return internalpdf.SerializeContent(internal), nil
+}
+
+// Builder rewrites a sequence of operations: filtering out operations,
+// inserting new ones and stamping content on top, then serializing the
+// result. Methods return the builder so calls can be chained; an invalid
+// call, such as an insertion out of range, is reported by Bytes.
+type Builder struct {
+	ops []Operation
+	err error
+}
+
+// NewBuilder returns a builder over a copy of ops, typically the result
+// of Parse.
+func NewBuilder(ops []Operation) *Builder {
+	return &Builder{ops: append([]Operation(nil), ops...)}
+}
+
+// Operations returns a copy of the current operations.
+func (b *Builder) Operations() []Operation {
+	return append([]Operation(nil), b.ops...)
+}
+
+// Filter keeps the operations for which keep returns true.
+func (b *Builder) Filter(keep func(op Operation) bool) *Builder {
+	out := b.ops[:0]
+	for _, op := range b.ops {
+		if keep(op) {
+			out = append(out, op)
+		}
+	}
+	b.ops = out
+	return b
+}
+
+// Insert inserts ops before the operation at index i; i equal to the
+// number of operations appends them.
+func (b *Builder) Insert(i int, ops ...Operation) *Builder {
+	if i < 0 || i > len(b.ops) {
+		if b.err == nil {
+			b.err = fmt.Errorf("insert at %d out of range [0, %d]", i, len(b.ops))
+		}
+		return b
+	}
+	b.ops = append(b.ops[:i], append(append([]Operation(nil), ops...), b.ops[i:]...)...)
+	return b
+}
+
+// Append adds ops after the current operations.
+func (b *Builder) Append(ops ...Operation) *Builder {
+	b.ops = append(b.ops, ops...)
+	return b
+}
+
+// RemoveText removes the text-showing operations, keeping the line moves
+// of ' and " and the spacing set by ", so the positioning of any text
+// left in place is unchanged.
+func (b *Builder) RemoveText() *Builder {
+	out := b.ops[:0:0]
+	for _, op := range b.ops {
+		switch op.Operator {
+		case "Tj", "TJ":
+		case "'":
+			out = append(out, Op("T*"))
+		case "\"":
+			if len(op.Operands) == 3 {
+				out = append(out, Op("Tw", op.Operands[0]), Op("Tc", op.Operands[1]))
+			}
+			out = append(out, Op("T*"))
+		default:
+			out = append(out, op)
+		}
+	}
+	b.ops = out
+	return b
+}
+
+// Stamp paints ops over the existing content, such as a watermark or an
+// approval stamp. The existing content is wrapped in q/Q and the stamp is
+// drawn in its own q/Q, so neither leaks graphics state into the other
+// and the stamp starts from the page's default coordinate system.
+func (b *Builder) Stamp(ops ...Operation) *Builder {
+	out := make([]Operation, 0, len(b.ops)+len(ops)+4)
+	if len(b.ops) > 0 {
+		out = append(out, Op("q"))
+		out = append(out, b.ops...)
+		out = append(out, Op("Q"))
+	}
+	out = append(out, Op("q"))
+	out = append(out, ops...)
+	out = append(out, Op("Q"))
+	b.ops = out
+	return b
+}
+
+// Bytes serializes the operations as Serialize does, or returns the
+// first error of an earlier call.
+func (b *Builder) Bytes() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return Serialize(b.ops)
+}
+
+// checkNesting reports the first Q, ET or EMC without a matching q, BT
+// or BMC/BDC, a BT inside a text object, and blocks left open at the end.
+// The three kinds are counted separately, as producers commonly
+// interleave marked content with q/Q.
+func checkNesting(ops []Operation) error {
+	var saves, marks int
+	inText := false
+	for i, op := range ops {
+		switch op.Operator {
+		case "q":
+			saves++
+		case "Q":
+			if saves == 0 {
+				return fmt.Errorf("operation %d: Q without a matching q", i)
+			}
+			saves--
+		case "BT":
+			if inText {
+				return fmt.Errorf("operation %d: BT inside a text object", i)
+			}
+			inText = true
+		case "ET":
+			if !inText {
+				return fmt.Errorf("operation %d: ET without a matching BT", i)
+			}
+			inText = false
+		case "BMC", "BDC":
+			marks++
+		case "EMC":
+			if marks == 0 {
+				return fmt.Errorf("operation %d: EMC without a matching BMC or BDC", i)
+			}
+			marks--
+		}
+	}
+	switch {
+	case saves > 0:
+		return fmt.Errorf("%d unclosed q at end of content", saves)
+	case inText:
+		return errors.New("unclosed BT at end of content")
+	case marks > 0:
+		return fmt.Errorf("%d unclosed marked-content sequences at end of content", marks)
+	}
+	return nil
+}
+
+// checkOperator reports an operator that is not a keyword, which would
+// be read back as a different token, and a malformed inline image.
+func checkOperator(op Operation) error {
+	if op.Operator == "" {
+		return errors.New("empty operator")
+	}
+	for i := 0; i < len(op.Operator); i++ {
+		c := op.Operator[i]
+		if c <= ' ' || c > '~' || strings.IndexByte("()<>[]{}/%", c) >= 0 {
+			return fmt.Errorf("invalid operator %q", op.Operator)
+		}
+	}
+	if op.Operator == "BI" {
+		if len(op.Operands) != 2 {
+			return errors.New("inline image needs a Dict and a String operand")
+		}
+		if _, ok := op.Operands[0].(Dict); !ok {
+			return errors.New("inline image entries are not a Dict")
+		}
+		if _, ok := op.Operands[1].(String); !ok {
+			return errors.New("inline image data is not a String")
+		}
+	}
+	return nil
+}
+
+// fromObject converts a parsed operand to its exported type.
+func fromObject(o internalpdf.Object) any {
+	switch v := o.(type) {
+	case internalpdf.Name:
+		return Name(v)
+	case internalpdf.String:
+		return String(v)
+	case internalpdf.Array:
+		arr := make(Array, len(v))
+		for i, el := range v {
+			arr[i] = fromObject(el)
+		}
+		return arr
+	case internalpdf.Dict:
+		d := make(Dict, len(v))
+		for k, el := range v {
+			d[Name(k)] = fromObject(el)
+		}
+		return d
+	default:
+		return v
+	}
+}
+
+// toObject converts an exported operand for serialization.
+func toObject(o any) (internalpdf.Object, error) {
+	switch v := o.(type) {
+	case nil, bool, int64:
+		return v, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("number %v is not finite", v)
+		}
+		return v, nil
+	case int:
+		return int64(v), nil
+	case Name:
+		return internalpdf.Name(v), nil
+	case String:
+		return internalpdf.String(v), nil
+	case Array:
+		arr := make(internalpdf.Array, len(v))
+		for i, el := range v {
+			obj, err := toObject(el)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = obj
+		}
+		return arr, nil
+	case Dict:
+		d := make(internalpdf.Dict, len(v))
+		for k, el := range v {
+			obj, err := toObject(el)
+			if err != nil {
+				return nil, err
+			}
+			d[internalpdf.Name(k)] = obj
+		}
+		return d, nil
+	default:
+		return nil, fmt.Errorf("unsupported operand type %T", o)
+	}
+}
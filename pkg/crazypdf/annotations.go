@@ -0,0 +1,121 @@
+package crazypdf
+
+import (
+	"fmt"
+	"time"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Annotation is an annotation of a page: a comment, highlight, link,
+// stamp, form field or other object placed over the page content.
+type Annotation struct {
+	// Index is the position of the annotation in the page's annotation
+	// list.
+	Index int
+
+	// Subtype is the annotation type, such as Text (sticky note),
+	// FreeText, Highlight, Underline, StrikeOut, Ink, Stamp, Link, Popup
+	// or Widget (form field).
+	Subtype string
+
+	// Rect is the annotation rectangle on the page.
+	Rect Rect
+
+	// Contents is the text of the annotation: the comment of a note or
+	// markup annotation, or an alternate description for others.
+	Contents string
+
+	// Author is the author of markup annotations, usually the reviewer's
+	// name, and Subject their subject.
+	Author  string
+	Subject string
+
+	// Name uniquely identifies the annotation on its page, if set.
+	Name string
+
+	// Modified and Created are the annotation dates, zero when not given.
+	Modified, Created time.Time
+
+	// Color is the annotation color: 1 (gray), 3 (RGB) or 4 (CMYK)
+	// components from 0 to 1, or nil for transparent or unset.
+	Color []float64
+
+	// Quads are the regions of text marked by Highlight, Underline,
+	// StrikeOut and Squiggly annotations, one per line, and the active
+	// regions of some links.
+	Quads []Quad
+
+	// ReplyTo is the Index of the annotation this one replies to on the
+	// same page, or -1. Replies form comment threads.
+	ReplyTo int
+
+	// Hidden reports whether the annotation is hidden from view.
+	Hidden bool
+
+	// URI is the target of links that open a web address, and Dest the
+	// destination of links within the document.
+	URI  string
+	Dest *Destination
+}
+
+// Annotations returns the annotations of the page in the order of its
+// annotation list, which is usually the order they were added.
+func (p *Page) Annotations() ([]Annotation, error) {
+	file, page, refs, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+
+	var out []Annotation
+	for _, a := range file.PageAnnots(page) {
+		annot := Annotation{
+			Index:    a.Index,
+			Subtype:  string(a.Subtype),
+			Rect:     a.Rect,
+			Contents: a.Contents,
+			Author:   a.Author,
+			Subject:  a.Subject,
+			Name:     a.Name,
+			Modified: a.Modified,
+			Created:  a.Created,
+			Color:    a.Color,
+			Quads:    a.QuadPoints,
+			ReplyTo:  a.ReplyTo,
+			Hidden:   a.Flags&(annotFlagHidden|annotFlagNoView) != 0,
+			URI:      a.URI,
+		}
+		if a.Dest != nil {
+			if dest, ok := internalpdf.ResolveDest(catalog, a.Dest, refs, file.Resolve); ok {
+				annot.Dest = destination(dest)
+			}
+		}
+		out = append(out, annot)
+	}
+	return out, nil
+}
+
+// object returns the parsed file, the page dictionary and the page
+// references of the document.
+func (p *Page) object() (*internalpdf.File, internalpdf.Dict, []internalpdf.Ref, error) {
+	file, err := p.doc.RawFile()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	refs, err := file.PageRefs()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	if p.Number < 1 || p.Number > len(refs) {
+		return nil, nil, nil, fmt.Errorf("%w: page %d, page tree has %d pages", ErrPageOutOfRange, p.Number, len(refs))
+	}
+	page, _ := file.Resolve(refs[p.Number-1]).(internalpdf.Dict)
+	return file, page, refs, nil
+}
+
+// Annotation flags (PDF 32000-1:2008, table 165).
+const (
+	annotFlagHidden = 1 << 1
+	annotFlagNoView = 1 << 5
+)
@@ -0,0 +1,95 @@
+package crazypdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Attachment is a file embedded in the document, such as the XML invoice
+// of a ZUGFeRD or Factur-X PDF.
+type Attachment struct {
+	// Name is the name the file is listed under in the document.
+	Name string
+
+	// FileName is the file name, such as "factur-x.xml".
+	FileName    string
+	Description string
+
+	// MIMEType is the media type declared for the file, such as
+	// "text/xml", or "" when none is declared.
+	MIMEType string
+
+	// Relationship is how the file relates to the document in PDF/A-3
+	// and PDF 2.0 files: Source, Data, Alternative, Supplement or
+	// Unspecified. It is "" when not given.
+	Relationship string
+
+	// Size is the size of the file in bytes as declared by the document,
+	// or -1 when it is not declared.
+	Size int64
+
+	// Created and Modified are the file dates, zero when not given.
+	Created, Modified time.Time
+
+	doc    *Document
+	stream *internalpdf.Stream
+	file   *internalpdf.File
+}
+
+// Attachments returns the files embedded in the document, in the order
+// the document lists them. The file contents are read on demand with
+// ReadAll or Open.
+func (d *Document) Attachments() ([]*Attachment, error) {
+	file, err := d.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+
+	var out []*Attachment
+	for _, ef := range internalpdf.EmbeddedFiles(catalog, file.Resolve) {
+		out = append(out, &Attachment{
+			Name:         ef.Name,
+			FileName:     ef.FileName,
+			Description:  ef.Description,
+			MIMEType:     ef.Subtype,
+			Relationship: ef.Relationship,
+			Size:         ef.Size,
+			Created:      ef.Created,
+			Modified:     ef.Modified,
+			doc:          d,
+			stream:       ef.Stream,
+			file:         file,
+		})
+	}
+	return out, nil
+}
+
+// ReadAll returns the decoded contents of the file.
+func (a *Attachment) ReadAll() ([]byte, error) {
+	if a.doc.IsClosed() {
+		return nil, ErrDocumentClosed
+	}
+	if a.stream == nil {
+		return nil, errors.New("attachment has no embedded file stream")
+	}
+	data, err := a.file.DecodeStream(a.stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode attachment %q: %w", a.Name, err)
+	}
+	return data, nil
+}
+
+// Open returns a reader over the decoded contents of the file.
+func (a *Attachment) Open() (io.Reader, error) {
+	data, err := a.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
@@ -0,0 +1,147 @@
+package crazypdf
+
+import (
+	"errors"
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Backend is a way of parsing a PDF file into a document. Files that one
+// backend rejects may open with another, so the Open functions try a
+// chain of them in turn; see WithBackend.
+type Backend int
+
+const (
+	// BackendStandard reads the cross-reference table and objects with
+	// ledongthuc/pdf. Files and random-access sources are read on demand.
+	BackendStandard Backend = iota
+
+	// BackendRebuild scans the whole file for object headers with the
+	// package's own parser and rebuilds the cross-reference table,
+	// trailer and page tree where they are missing or damaged, as
+	// WithRepair does. Document.RepairReport describes what it recovered.
+	BackendRebuild
+)
+
+// String returns "standard" or "rebuild".
+func (b Backend) String() string {
+	switch b {
+	case BackendStandard:
+		return "standard"
+	case BackendRebuild:
+		return "rebuild"
+	default:
+		return fmt.Sprintf("Backend(%d)", int(b))
+	}
+}
+
+// WithBackend sets the backends the Open functions try, in order, until
+// one opens the document. A password that does not open the document
+// stops the chain, since no other backend would accept it either.
+// Backends after the first read the whole file into memory. WithRepair
+// adds BackendRebuild to the end of the chain when it is not in it.
+// Default is BackendStandard alone.
+func WithBackend(chain ...Backend) Option {
+	return func(c *Config) {
+		c.Backends = append([]Backend{}, chain...)
+	}
+}
+
+// Backend returns the backend that opened the document.
+func (d *Document) Backend() Backend {
+	if d.reader.RepairReport() != nil {
+		return BackendRebuild
+	}
+	return BackendStandard
+}
+
+// backends returns the chain of backends to try.
+func (c *Config) backends() []Backend {
+	chain := c.Backends
+	if chain == nil {
+		chain = []Backend{BackendStandard}
+	}
+	if c.Repair && !hasBackend(chain, BackendRebuild) {
+		chain = append(chain[:len(chain):len(chain)], BackendRebuild)
+	}
+	return chain
+}
+
+// streaming reports whether the chain opens a source without reading it
+// whole: BackendStandard alone.
+func (c *Config) streaming() bool {
+	chain := c.backends()
+	return len(chain) == 1 && chain[0] == BackendStandard
+}
+
+// hasBackend reports whether chain holds b.
+func hasBackend(chain []Backend, b Backend) bool {
+	for _, x := range chain {
+		if x == b {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBackends reports an empty chain and unknown or repeated
+// backends.
+func (c *Config) validateBackends() []error {
+	if c.Backends == nil {
+		return nil
+	}
+	if len(c.Backends) == 0 {
+		return []error{errors.New("no backend given to WithBackend")}
+	}
+	var errs []error
+	for i, b := range c.Backends {
+		switch {
+		case b != BackendStandard && b != BackendRebuild:
+			errs = append(errs, fmt.Errorf("unknown backend %d", int(b)))
+		case hasBackend(c.Backends[:i], b):
+			errs = append(errs, fmt.Errorf("backend %s given twice", b))
+		}
+	}
+	return errs
+}
+
+// openChain opens a document with the configured backends in turn. open
+// opens the source as is with BackendStandard, when it comes first; read
+// returns the whole file for the other attempts and is called at most
+// once. The errors of all failed backends are returned, the first as is
+// and the others naming their backend.
+func (c *Config) openChain(open func() (*internalpdf.Reader, error), read func() ([]byte, error)) (*internalpdf.Reader, error) {
+	var errs []error
+	var data []byte
+	for i, b := range c.backends() {
+		var reader *internalpdf.Reader
+		var err error
+		if i == 0 && b == BackendStandard {
+			reader, err = open()
+		} else {
+			if data == nil {
+				if data, err = read(); err != nil {
+					errs = append(errs, err)
+					break
+				}
+			}
+			if b == BackendStandard {
+				reader, err = internalpdf.OpenBytes(data, c.Password)
+			} else {
+				reader, err = internalpdf.OpenRepaired(data, c.Password)
+			}
+		}
+		if err == nil {
+			return reader, nil
+		}
+		if internalpdf.IsPasswordError(err) {
+			return nil, err
+		}
+		if i > 0 {
+			err = fmt.Errorf("%s backend failed: %w", b, err)
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
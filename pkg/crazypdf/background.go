@@ -0,0 +1,156 @@
+package crazypdf
+
+import (
+	"bytes"
+	"image"
+	"math"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// backgroundCoverage is the fraction of the crop box a fill or image must
+// cover to be part of the page background.
+const backgroundCoverage = 0.95
+
+// Background is what a page is painted with under its content, such as
+// the colored fill or full-page picture of a slide.
+type Background struct {
+	// Color is the color of the background as RGB components in [0, 1]:
+	// white paper with the fills and images of Items painted over it in
+	// turn, blended by their opacity, images by their mean color. Images
+	// whose data cannot be decoded leave it as it was.
+	Color [3]float64
+
+	// Items are the filled paths and images that cover the page, in
+	// painting order, so renders and exports can reproduce them. Empty
+	// for plain paper.
+	Items []DrawItem
+}
+
+// Image returns the topmost image of the background, or nil when it has
+// none.
+func (b *Background) Image() *DrawItem {
+	for i := len(b.Items) - 1; i >= 0; i-- {
+		if b.Items[i].Image {
+			return &b.Items[i]
+		}
+	}
+	return nil
+}
+
+// Luminance returns the relative luminance of Color as defined by WCAG,
+// from 0 for black to 1 for white.
+func (b *Background) Luminance() float64 {
+	return luminance(b.Color)
+}
+
+// Dark reports whether text must be lighter than the background to
+// stand out on it: white text contrasts with it more than black text.
+func (b *Background) Dark() bool {
+	l := b.Luminance()
+	return (1+0.05)/(l+0.05) > (l+0.05)/0.05
+}
+
+// Contrast returns the WCAG contrast ratio of text in the RGB color c on
+// the background, from 1 for none to 21 for black on white. Text below
+// 4.5 is hard to read and below about 1.5 all but invisible, such as
+// dark text left on a dark slide.
+func (b *Background) Contrast(c [3]float64) float64 {
+	l1, l2 := luminance(b.Color), luminance(c)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// Background returns the fills and images covering this page under its
+// content, as slide decks and designed documents paint, and the color
+// they leave. An item covers the page when, within its clipping path, it
+// paints at least 95% of the crop box. Items painted over the text rather
+// than under it are not told apart; documents rarely hide all their text.
+func (p *Page) Background() (*Background, error) {
+	box, err := p.CropBox()
+	if err != nil {
+		return nil, err
+	}
+	items, err := p.Drawing()
+	if err != nil {
+		return nil, err
+	}
+	bg := &Background{Color: [3]float64{1, 1, 1}}
+	for _, it := range items {
+		if !it.Image && (!it.Fill || it.FillAlpha <= 0) {
+			continue
+		}
+		area := it.BBox()
+		for c := it.Clip; c != nil; c = c.Parent {
+			area = area.Intersect(DrawItem{Segments: c.Segments}.BBox())
+		}
+		if area.Intersect(box).Area() < box.Area()*backgroundCoverage {
+			continue
+		}
+		bg.Items = append(bg.Items, it)
+		if !it.Image {
+			bg.Color = blend(bg.Color, it.FillColor, it.FillAlpha)
+		} else if c, ok := meanImageColor(it.ImageStream); ok {
+			bg.Color = c
+		}
+	}
+	return bg, nil
+}
+
+// blend paints color c with opacity alpha over under.
+func blend(under, c [3]float64, alpha float64) [3]float64 {
+	alpha = math.Min(alpha, 1)
+	var out [3]float64
+	for i := range out {
+		out[i] = under[i]*(1-alpha) + c[i]*alpha
+	}
+	return out
+}
+
+// luminance returns the WCAG relative luminance of an sRGB color.
+func luminance(c [3]float64) float64 {
+	var lin [3]float64
+	for i, v := range c {
+		if v <= 0.04045 {
+			lin[i] = v / 12.92
+		} else {
+			lin[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*lin[0] + 0.7152*lin[1] + 0.0722*lin[2]
+}
+
+// meanImageColor decodes an image XObject and returns its mean color,
+// sampled on a grid of at most 64 by 64 pixels.
+func meanImageColor(s *internalpdf.Stream) ([3]float64, bool) {
+	if s == nil || !internalpdf.ImageSupported(s) {
+		return [3]float64{}, false
+	}
+	data, _, err := internalpdf.EncodeImage(s)
+	if err != nil {
+		return [3]float64{}, false
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return [3]float64{}, false
+	}
+	r := img.Bounds()
+	stepX, stepY := max(r.Dx()/64, 1), max(r.Dy()/64, 1)
+	var sum [3]float64
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y += stepY {
+		for x := r.Min.X; x < r.Max.X; x += stepX {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			sum[0] += float64(cr) / 0xffff
+			sum[1] += float64(cg) / 0xffff
+			sum[2] += float64(cb) / 0xffff
+			n++
+		}
+	}
+	if n == 0 {
+		return [3]float64{}, false
+	}
+	return [3]float64{sum[0] / float64(n), sum[1] / float64(n), sum[2] / float64(n)}, true
+}
@@ -0,0 +1,118 @@
+package crazypdf
+
+import (
+	"fmt"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Capabilities reports which operations are expected to work on a
+// document, so callers can pick a processing path up front: text
+// extraction for born-digital files, OCR for scans, structure-based
+// extraction for tagged files.
+type Capabilities struct {
+	// TextLayer reports whether any page shows text, and TextPages lists
+	// the 1-based pages that do. Scanned pages without an OCR layer have
+	// none, so text extraction, search and redaction find nothing there.
+	TextLayer bool
+	TextPages []int
+
+	// FontsDecodable reports whether every font maps its character codes
+	// to Unicode, through a ToUnicode map, a standard encoding or a
+	// Unicode CMap. Text shown with the fonts in UndecodableFonts may
+	// extract as garbage.
+	FontsDecodable   bool
+	UndecodableFonts []string
+
+	// StructureTree reports whether the document is tagged with a logical
+	// structure tree describing reading order, headings and alt text.
+	StructureTree bool
+
+	// Images is the number of images painted on the pages and
+	// ExtractableImages those that can be exported: JPEG images and
+	// 8-bit gray, RGB, CMYK and indexed images with supported filters.
+	// ImagesExtractable reports whether there are images and all of them
+	// can be exported.
+	Images            int
+	ExtractableImages int
+	ImagesExtractable bool
+}
+
+// Capabilities inspects the document's content streams, fonts and
+// catalog without extracting text. Pages whose content cannot be decoded
+// count as having no text or images.
+func (d *Document) Capabilities() (*Capabilities, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	refs, err := file.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	fonts, err := file.Fonts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fonts: %w", err)
+	}
+
+	c := &Capabilities{FontsDecodable: true}
+	_, c.StructureTree = file.Resolve(catalog["StructTreeRoot"]).(internalpdf.Dict)
+
+	for i, ref := range refs {
+		page, ok := file.Resolve(ref).(internalpdf.Dict)
+		if !ok {
+			continue
+		}
+		usage, err := file.PageUsage(page)
+		if err != nil {
+			continue
+		}
+		if usage.ShowsText {
+			c.TextPages = append(c.TextPages, i+1)
+		}
+		for _, img := range usage.Images {
+			c.Images++
+			if internalpdf.ImageSupported(img) {
+				c.ExtractableImages++
+			}
+		}
+	}
+	c.TextLayer = len(c.TextPages) > 0
+	c.ImagesExtractable = c.Images > 0 && c.ExtractableImages == c.Images
+
+	for _, font := range fonts {
+		if !fontDecodable(font) {
+			c.FontsDecodable = false
+			c.UndecodableFonts = append(c.UndecodableFonts, font.BaseFont)
+		}
+	}
+	return c, nil
+}
+
+// fontDecodable reports whether text shown with a font can be mapped to
+// Unicode.
+func fontDecodable(font internalpdf.FontInfo) bool {
+	if font.ToUnicode {
+		return true
+	}
+	switch font.Subtype {
+	case "Type0":
+		// Predefined Unicode CMaps such as UniGB-UCS2-H; Identity-H maps
+		// to glyph IDs, which carry no text
+		return strings.HasPrefix(font.Encoding, "Uni")
+	case "Type3":
+		return false
+	}
+	// Symbolic fonts without an encoding use the font program's own
+	return font.Encoding != "" || font.Flags&fontFlagSymbolic == 0
+}
+
+// fontFlagSymbolic is the FontDescriptor flag of fonts using characters
+// outside the standard Latin set (PDF 32000-1:2008, table 123).
+const fontFlagSymbolic = 1 << 2
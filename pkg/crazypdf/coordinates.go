@@ -0,0 +1,131 @@
+package crazypdf
+
+import (
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// CoordinateSpace is the coordinate system in which geometry such as
+// bounding boxes is reported.
+type CoordinateSpace int
+
+const (
+	// PDFDefault is PDF user space, as stored in the document: the Y axis
+	// points up and the origin is usually the bottom-left corner of the
+	// page.
+	PDFDefault CoordinateSpace = iota
+
+	// TopLeft puts the origin at the top-left corner of the media box,
+	// with the Y axis pointing down, as images, screens and most layout
+	// tools expect. Page rotation is not applied.
+	TopLeft
+)
+
+// String returns "pdf" or "top-left".
+func (s CoordinateSpace) String() string {
+	if s == TopLeft {
+		return "top-left"
+	}
+	return "pdf"
+}
+
+// MarshalText encodes the coordinate space by name.
+func (s CoordinateSpace) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a coordinate space from "pdf" or "top-left".
+func (s *CoordinateSpace) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "pdf":
+		*s = PDFDefault
+	case "top-left":
+		*s = TopLeft
+	default:
+		return fmt.Errorf("unknown coordinate space %q", text)
+	}
+	return nil
+}
+
+// Coordinates converts geometry from the PDF user space of a page to a
+// coordinate space, in points or in pixels. The zero value leaves
+// geometry unchanged.
+type Coordinates struct {
+	m, inverse internalpdf.Matrix
+	convert    bool
+}
+
+// Coordinates returns the conversion of geometry on this page to space,
+// scaled to pixels at dpi dots per inch, or left in points when dpi is 0.
+func (p *Page) Coordinates(space CoordinateSpace, dpi float64) (Coordinates, error) {
+	if dpi < 0 {
+		return Coordinates{}, fmt.Errorf("invalid DPI %g", dpi)
+	}
+	if space == PDFDefault && (dpi == 0 || dpi == 72) {
+		return Coordinates{}, nil
+	}
+	scale := 1.0
+	if dpi > 0 {
+		scale = dpi / 72
+	}
+	m := internalpdf.Matrix{scale, 0, 0, scale, 0, 0}
+	if space == TopLeft {
+		box, err := p.MediaBox()
+		if err != nil {
+			return Coordinates{}, fmt.Errorf("failed to read page geometry: %w", err)
+		}
+		m = internalpdf.Matrix{scale, 0, 0, -scale, -box.X0 * scale, box.Y1 * scale}
+	}
+	inverse := internalpdf.Matrix{1 / m[0], 0, 0, 1 / m[3], -m[4] / m[0], -m[5] / m[3]}
+	return Coordinates{m: m, inverse: inverse, convert: true}, nil
+}
+
+// Point converts a point.
+func (c Coordinates) Point(x, y float64) (float64, float64) {
+	if !c.convert {
+		return x, y
+	}
+	return c.m.Apply(x, y)
+}
+
+// Rect converts a rectangle. The result is normalized, so in TopLeft Y0
+// is its top edge.
+func (c Coordinates) Rect(r Rect) Rect {
+	if !c.convert || r.IsEmpty() {
+		return r
+	}
+	return c.m.TransformRect(r)
+}
+
+// Quad converts each corner of a quadrilateral.
+func (c Coordinates) Quad(q Quad) Quad {
+	if !c.convert {
+		return q
+	}
+	for i := 0; i < len(q); i += 2 {
+		q[i], q[i+1] = c.m.Apply(q[i], q[i+1])
+	}
+	return q
+}
+
+// Words returns a copy of the words with their bounding boxes converted.
+func (c Coordinates) Words(words []Word) []Word {
+	if !c.convert || words == nil {
+		return words
+	}
+	out := make([]Word, len(words))
+	for i, w := range words {
+		w.BBox = c.Rect(w.BBox)
+		out[i] = w
+	}
+	return out
+}
+
+// ToPDF converts a rectangle back to PDF user space.
+func (c Coordinates) ToPDF(r Rect) Rect {
+	if !c.convert {
+		return r
+	}
+	return c.inverse.TransformRect(r)
+}
@@ -1,4 +1,3 @@
-
 // Package crazypdf is a generic PDF processing library for Go.
 //
 // It provides a modular architecture where the core package defines the
@@ -14,24 +13,42 @@
 //	}
 //	defer doc.Close()
 //
-//	// Use the extract package for text extraction
-//	text, err := extract.Text(doc)
+//	text, err := doc.Text()
 //	fmt.Println(text)
 //
+// Document.Text and Page.Text cover the common case. The extract package
+// offers the other layout modes and options:
+//
+//	text, err := extract.Text(doc, extract.WithLayout(extract.LayoutPhysical))
+//
 // # Architecture
 //
 // The library is organized into:
 //   - pkg/crazypdf: Core types — Document, Page, Config, errors
 //   - pkg/extract: Text extraction with multiple layout modes
+//   - pkg/analysis: Layout analysis such as page region classification
+//   - pkg/metadata: Information dictionary and XMP metadata
 //   - cmd/crazypdf: CLI tool with subcommand architecture
 //
 // Feature modules accept *Document or *Page and use their public methods
-// (PlainText, TextByRow, StyledTexts, ContentStream, PhysicalLayoutText)
-// to access page content without reaching into private fields.
+// (PlainText, TextByRow, StyledTexts, ContentStream, PhysicalLayoutText,
+// MediaBox, Graphics) to access page content without reaching into private
+// fields.
+//
+// # Concurrency
+//
+// A Document may be shared between goroutines. Document.Page and the Page
+// accessors can run concurrently, and Close waits for those already
+// running before it releases the file; accessors called after Close has
+// begun return ErrDocumentClosed. Feature modules that read a document
+// through its pages or through Document.RawFile inherit this behavior. Options and results are not
+// shared between calls, so feature module functions may also be called
+// concurrently on the same document. Parsed data that is cached and
+// shared, such as the glyphs of pages, is never modified after parsing,
+// and slices returned to callers are their own to modify.
 //
 // # Planned Features
 //
 //   - structurize: Convert PDF structure into machine-readable format
-//   - metadata: Extract document metadata (title, author, keywords, etc.)
 //   - tables: Detect and extract tabular data
 package crazypdf
@@ -1,96 +1,394 @@
 package crazypdf
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
 )
 
 // Document represents an opened PDF document.
 // It is the central type that all feature modules operate on.
+//
+// A Document is safe for concurrent use: pages may be read from several
+// goroutines at once, and Close may be called while they are being read.
+// Close waits for page operations already in progress to finish; page
+// operations started after Close begins return ErrDocumentClosed.
 type Document struct {
 	filePath string
 	reader   *internalpdf.Reader
-	pages    []*Page
 	config   *Config
-	closed   bool
+
+	// pages holds the pages created so far, by index. Pages are created
+	// on first access, so opening a large document does not allocate one
+	// for every page.
+	pagesMu sync.Mutex
+	pages   []*Page
+
+	// labels caches the page labels, read on first use.
+	labelsOnce sync.Once
+	labels     []string
+	labelsErr  error
+
+	// source is closed with the document when the document opened it
+	// itself, as OpenFS does.
+	source io.Closer
+
+	// onClose, if set, is called once the document is closed; a
+	// WorkerPool uses it to free the document's slot.
+	onClose func()
+
+	// mu orders acquire against Close, inflight counts page operations
+	// in progress and closed is set once Close begins.
+	mu       sync.Mutex
+	inflight sync.WaitGroup
+	closed   atomic.Bool
 }
 
 // Open opens a PDF file from disk and returns a Document ready for processing.
 func Open(filePath string, opts ...Option) (*Document, error) {
-	cfg := applyOptions(opts)
+	return openFile(context.Background(), filePath, opts)
+}
 
-	reader, err := internalpdf.OpenFile(filePath)
+// openFile opens a PDF file from disk, tracing the open as a child of the
+// span in ctx.
+func openFile(ctx context.Context, filePath string, opts []Option) (*Document, error) {
+	cfg, err := applyOptions(opts)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+		return nil, err
 	}
+	return cfg.traceOpen(ctx, filePath, func() (*Document, error) {
+		reader, err := cfg.openChain(
+			func() (*internalpdf.Reader, error) { return internalpdf.OpenFile(filePath, cfg.Password) },
+			func() ([]byte, error) { return os.ReadFile(filePath) },
+		)
+		if err != nil {
+			return nil, openError(err, cfg.Password)
+		}
+		return newDocument(reader, filePath, cfg), nil
+	})
+}
 
-	doc := &Document{
-		filePath: filePath,
-		reader:   reader,
-		config:   cfg,
+// OpenContext is like Open but gives up when ctx is cancelled or its
+// deadline passes, returning ctx.Err(). Parsing the file cannot be
+// interrupted, so it finishes in the background and the document is
+// closed once it does.
+func OpenContext(ctx context.Context, filePath string, opts ...Option) (*Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// Build page list
-	numPages := reader.NumPages()
-	doc.pages = make([]*Page, numPages)
-	for i := 0; i < numPages; i++ {
-		doc.pages[i] = &Page{
-			Number: i + 1, // 1-based page number
-			doc:    doc,
-		}
+	type result struct {
+		doc *Document
+		err error
 	}
+	done := make(chan result, 1)
+	go func() {
+		doc, err := openFile(ctx, filePath, opts)
+		done <- result{doc, err}
+	}()
 
-	return doc, nil
+	select {
+	case res := <-done:
+		return res.doc, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-done; res.doc != nil {
+				res.doc.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
 }
 
 // OpenBytes opens a PDF from a byte slice and returns a Document ready for processing.
 func OpenBytes(data []byte, opts ...Option) (*Document, error) {
-	cfg := applyOptions(opts)
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.traceOpen(context.Background(), "bytes", func() (*Document, error) {
+		reader, err := openBytes(data, cfg)
+		if err != nil {
+			return nil, openError(err, cfg.Password)
+		}
+		return newDocument(reader, "", cfg), nil
+	})
+}
 
-	reader, err := internalpdf.OpenBytes(data)
+// openBytes opens a PDF held in memory with the configured backends.
+func openBytes(data []byte, cfg *Config) (*internalpdf.Reader, error) {
+	return cfg.openChain(
+		func() (*internalpdf.Reader, error) { return internalpdf.OpenBytes(data, cfg.Password) },
+		func() ([]byte, error) { return data, nil },
+	)
+}
+
+// OpenReaderAt opens a PDF from random-access storage of the given size,
+// such as an *os.File, a memory-mapped file or a ranged reader over
+// object storage. Text extraction reads only the parts of the file it
+// needs. The caller keeps ownership of r and must keep it open until the
+// document is closed; Close does not close r.
+func OpenReaderAt(r io.ReaderAt, size int64, opts ...Option) (*Document, error) {
+	cfg, err := applyOptions(opts)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+		return nil, err
 	}
+	return cfg.traceOpen(context.Background(), "reader", func() (*Document, error) {
+		reader, err := cfg.openChain(
+			func() (*internalpdf.Reader, error) { return internalpdf.OpenReaderAt(r, size, cfg.Password) },
+			func() ([]byte, error) { return readAll(r, size) },
+		)
+		if err != nil {
+			return nil, openError(err, cfg.Password)
+		}
+		return newDocument(reader, "", cfg), nil
+	})
+}
 
-	doc := &Document{
-		filePath: "",
-		reader:   reader,
-		config:   cfg,
+// OpenReader opens a PDF from a sequential stream, such as a network
+// response body. Because a PDF's cross-reference table is at its end, the
+// stream is read to completion and spooled to a temporary file in the
+// scratch directory rather than held in memory; the file is removed when
+// the document is closed. With WithInMemoryOnly the stream is read into
+// memory instead. The caller remains responsible for closing r.
+func OpenReader(r io.Reader, opts ...Option) (*Document, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.traceOpen(context.Background(), "stream", func() (*Document, error) {
+		reader, err := openStream(r, cfg)
+		if err != nil {
+			return nil, openError(err, cfg.Password)
+		}
+		return newDocument(reader, "", cfg), nil
+	})
+}
+
+// openStream opens a sequential stream, spooling it to the scratch
+// directory or, with WithInMemoryOnly, WithRepair or a chain of several
+// backends, reading it into memory.
+func openStream(r io.Reader, cfg *Config) (*internalpdf.Reader, error) {
+	if !cfg.InMemoryOnly && cfg.streaming() {
+		return internalpdf.OpenReader(r, cfg.ScratchDir, cfg.Password)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return openBytes(data, cfg)
+}
+
+// readAll reads size bytes of r from the start.
+func readAll(r io.ReaderAt, size int64) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+// OpenFS opens the named PDF from a file system, such as an embed.FS, an
+// os.DirFS or a zip archive. Files that support random access are read on
+// demand as with OpenReaderAt; others are spooled to a temporary file as
+// with OpenReader. The file is closed when the document is closed.
+func OpenFS(fsys fs.FS, name string, opts ...Option) (*Document, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.traceOpen(context.Background(), name, func() (*Document, error) {
+		return openFS(fsys, name, cfg)
+	})
+}
+
+// openFS opens the named PDF from a file system.
+func openFS(fsys fs.FS, name string, cfg *Config) (*Document, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
 	}
 
-	// Build page list
-	numPages := reader.NumPages()
-	doc.pages = make([]*Page, numPages)
-	for i := 0; i < numPages; i++ {
-		doc.pages[i] = &Page{
-			Number: i + 1, // 1-based page number
-			doc:    doc,
+	var reader *internalpdf.Reader
+	if ra, ok := f.(io.ReaderAt); ok {
+		var stat fs.FileInfo
+		if stat, err = f.Stat(); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to stat %s: %w", name, err)
 		}
+		reader, err = cfg.openChain(
+			func() (*internalpdf.Reader, error) { return internalpdf.OpenReaderAt(ra, stat.Size(), cfg.Password) },
+			func() ([]byte, error) { return readAll(ra, stat.Size()) },
+		)
+	} else {
+		reader, err = openStream(f, cfg)
+	}
+	if err != nil {
+		f.Close()
+		return nil, openError(err, cfg.Password)
 	}
 
+	doc := newDocument(reader, "", cfg)
+	doc.source = f
 	return doc, nil
 }
 
+// openError classifies an error from opening a PDF: ErrPasswordRequired
+// or ErrWrongPassword for encrypted documents the password does not open,
+// ErrInvalidPDF otherwise. ErrInvalidPDF also wraps the cause, so that
+// I/O errors such as timeouts stay inspectable with errors.Is and
+// errors.As.
+func openError(err error, password string) error {
+	switch {
+	case !internalpdf.IsPasswordError(err):
+		return fmt.Errorf("%w: %w", ErrInvalidPDF, err)
+	case password == "":
+		return ErrPasswordRequired
+	default:
+		return ErrWrongPassword
+	}
+}
+
+// newDocument wraps an opened reader in a Document.
+func newDocument(reader *internalpdf.Reader, filePath string, cfg *Config) *Document {
+	reader.SetRowCacheSize(cfg.PageCacheSize)
+	reader.SetMemoryLimit(cfg.MaxMemory)
+	return &Document{
+		filePath: filePath,
+		reader:   reader,
+		config:   cfg,
+		pages:    make([]*Page, reader.NumPages()),
+	}
+}
+
+// page returns the page at a valid 0-based index, creating it on first
+// access.
+func (d *Document) page(index int) *Page {
+	d.pagesMu.Lock()
+	defer d.pagesMu.Unlock()
+	if d.pages[index] == nil {
+		d.pages[index] = &Page{
+			Number: index + 1, // 1-based page number
+			doc:    d,
+		}
+	}
+	return d.pages[index]
+}
+
 // NumPages returns the total number of pages in the document.
 func (d *Document) NumPages() int {
 	return len(d.pages)
 }
 
-// Page returns a specific page by 0-based index.
+// Page returns a specific page by 0-based index. It may be called from
+// several goroutines; every call for an index returns the same *Page.
 func (d *Document) Page(index int) (*Page, error) {
-	if d.closed {
+	if d.closed.Load() {
 		return nil, ErrDocumentClosed
 	}
 	if index < 0 || index >= len(d.pages) {
 		return nil, fmt.Errorf("%w: requested %d, document has %d pages", ErrPageOutOfRange, index, len(d.pages))
 	}
-	return d.pages[index], nil
+	return d.page(index), nil
 }
 
-// Pages returns all pages in the document.
+// Pages returns all pages in the document. The returned slice is a copy
+// and may be modified freely. It is nil after the document is closed.
 func (d *Document) Pages() []*Page {
-	return d.pages
+	if d.closed.Load() {
+		return nil
+	}
+	pages := make([]*Page, len(d.pages))
+	for i := range pages {
+		pages[i] = d.page(i)
+	}
+	return pages
+}
+
+// EachPage calls fn for every page in order, stopping at the first error
+// fn returns and returning it. It returns ErrDocumentClosed if the
+// document is closed before fn is called for a page; closing it once fn
+// has been called for the last page does not fail the iteration.
+func (d *Document) EachPage(fn func(*Page) error) error {
+	for i := range d.pages {
+		if d.closed.Load() {
+			return ErrDocumentClosed
+		}
+		if err := fn(d.page(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PageIter returns an iterator over the pages in order, for use with
+// range. It yields nothing once the document is closed, including when
+// it is closed during iteration; use EachPage to have that reported as
+// an error.
+func (d *Document) PageIter() iter.Seq[*Page] {
+	return func(yield func(*Page) bool) {
+		for i := range d.pages {
+			if d.closed.Load() || !yield(d.page(i)) {
+				return
+			}
+		}
+	}
+}
+
+// PagesSeq returns an iterator over the pages in order with their 0-based
+// indexes, for use with range. Pages are created as they are reached, so
+// ranging over part of a large document does not set up every page. Like
+// PageIter, it stops once the document is closed.
+func (d *Document) PagesSeq() iter.Seq2[int, *Page] {
+	return func(yield func(int, *Page) bool) {
+		for i := range d.pages {
+			if d.closed.Load() || !yield(i, d.page(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Text returns the text of all pages, separated by blank lines. It is the
+// same as extract.Text with default options; use the extract package for
+// other layout modes and separators.
+func (d *Document) Text() (string, error) {
+	if d.closed.Load() {
+		return "", ErrDocumentClosed
+	}
+	var sb strings.Builder
+	for i := range d.pages {
+		page := d.page(i)
+		text, err := page.Text()
+		if err != nil {
+			return "", fmt.Errorf("failed to extract text from page %d: %w", page.Number, err)
+		}
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(text)
+	}
+	return sb.String(), nil
+}
+
+// CreateTemp creates a temporary file in the document's scratch directory
+// for operations that need to spool data, following os.CreateTemp for
+// pattern. The caller removes the file when done. It returns
+// ErrScratchDisabled when the document was opened with WithInMemoryOnly.
+func (d *Document) CreateTemp(pattern string) (*os.File, error) {
+	if d.config.InMemoryOnly {
+		return nil, ErrScratchDisabled
+	}
+	return os.CreateTemp(d.config.ScratchDir, pattern)
 }
 
 // FilePath returns the file path of the opened document.
@@ -98,25 +396,76 @@ func (d *Document) FilePath() string {
 	return d.filePath
 }
 
-// Close releases all resources held by the document.
+// Close releases all resources held by the document. It waits for page
+// operations in progress in other goroutines to finish before releasing
+// the underlying file. Calling Close more than once is a no-op.
 func (d *Document) Close() error {
-	if d.closed {
+	d.mu.Lock()
+	if d.closed.Load() {
+		d.mu.Unlock()
 		return nil
 	}
-	d.closed = true
+	d.closed.Store(true)
+	d.mu.Unlock()
+
+	d.inflight.Wait()
+	var err error
 	if d.reader != nil {
-		return d.reader.Close()
+		err = d.reader.Close()
 	}
+	if d.source != nil {
+		err = errors.Join(err, d.source.Close())
+	}
+	if d.onClose != nil {
+		d.onClose()
+	}
+	return err
+}
+
+// acquire registers a page operation, returning ErrDocumentClosed once
+// Close has begun. Each successful acquire must be paired with release.
+func (d *Document) acquire() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed.Load() {
+		return ErrDocumentClosed
+	}
+	d.inflight.Add(1)
 	return nil
 }
 
+// release ends a page operation registered by acquire.
+func (d *Document) release() {
+	d.inflight.Done()
+}
+
 // Reader returns the internal PDF reader for advanced operations.
-// This is intended for use by feature modules (e.g., extract).
+// This is intended for use by feature modules (e.g., extract). Calls made
+// directly on the reader are not tracked by Close.
 func (d *Document) Reader() *internalpdf.Reader {
 	return d.reader
 }
 
-// IsClosed returns whether the document has been closed.
+// RawFile returns the document parsed by the package's own object
+// parser, for feature modules that read or rewrite the raw object graph
+// (e.g., redact). Unlike Reader().RawFile, it fails with
+// ErrDocumentClosed once Close has begun, and Close waits for the file
+// to be read. The parsed file is held in memory, so it can be used after
+// the document is closed.
+func (d *Document) RawFile() (*internalpdf.File, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	return file, nil
+}
+
+// IsClosed returns whether the document has been closed, or is being
+// closed.
 func (d *Document) IsClosed() bool {
-	return d.closed
+	return d.closed.Load()
 }
@@ -1,6 +1,10 @@
 package crazypdf
 
-import "errors"
+import (
+	"errors"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
 
 var (
 	// ErrInvalidPDF indicates the file is not a valid PDF or is corrupted.
@@ -17,4 +21,21 @@ var (
 
 	// ErrDocumentClosed indicates an operation was attempted on a closed document.
 	ErrDocumentClosed = errors.New("crazypdf: document is closed")
+
+	// ErrInvalidConfig indicates the options passed to Open are invalid or
+	// conflict with each other.
+	ErrInvalidConfig = errors.New("crazypdf: invalid configuration")
+
+	// ErrScratchDisabled indicates an operation needed a temporary file
+	// but the document was opened with WithInMemoryOnly.
+	ErrScratchDisabled = errors.New("crazypdf: temporary files are disabled")
+
+	// ErrPoolLimit indicates a document is larger than the memory limit
+	// of a WorkerPool, so it could never be opened through it.
+	ErrPoolLimit = errors.New("crazypdf: document exceeds pool memory limit")
+
+	// ErrResourceLimit indicates an operation would have used more memory
+	// than the limit set with WithMaxMemory, for example decoding a
+	// decompression bomb.
+	ErrResourceLimit = internalpdf.ErrResourceLimit
 )
@@ -0,0 +1,70 @@
+package crazypdf
+
+import "fmt"
+
+// Figure is a figure of a tagged document, with the alternate text that
+// describes it to readers who cannot see it.
+type Figure struct {
+	// Alt is the alternate description of the figure and ActualText its
+	// replacement text, for figures that show text. Either may be empty.
+	Alt        string
+	ActualText string
+
+	// BBox is the area of the figure on the page: the bounding box from
+	// its layout attributes or, failing that, of the images and paths in
+	// its marked content. It is empty when the position is unknown.
+	BBox Rect
+}
+
+// Text returns the alternate description of the figure, or its
+// replacement text when it has none.
+func (f Figure) Text() string {
+	if f.Alt != "" {
+		return f.Alt
+	}
+	return f.ActualText
+}
+
+// Figures returns the Figure elements of the structure tree on this
+// page, in structure order, with their alternate text. Untagged
+// documents have none.
+func (p *Page) Figures() ([]Figure, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	file, page, refs, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Figure
+	var items []DrawItem
+	drawn := false
+	for _, sf := range file.StructFigures(refs) {
+		if sf.Page != p.Number {
+			continue
+		}
+		fig := Figure{Alt: sf.Alt, ActualText: sf.ActualText, BBox: sf.BBox}
+		if !sf.HasBBox && len(sf.MCIDs) > 0 {
+			if !drawn {
+				items, err = file.PageDrawing(page)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read page graphics: %w", err)
+				}
+				drawn = true
+			}
+			ids := make(map[int]bool, len(sf.MCIDs))
+			for _, id := range sf.MCIDs {
+				ids[id] = true
+			}
+			for _, it := range items {
+				if ids[it.MCID] {
+					fig.BBox = fig.BBox.Union(it.BBox())
+				}
+			}
+		}
+		out = append(out, fig)
+	}
+	return out, nil
+}
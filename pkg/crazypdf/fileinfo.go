@@ -0,0 +1,164 @@
+package crazypdf
+
+import "fmt"
+
+// FileInfo describes the file structure of a PDF document, for tooling
+// that audits incoming files.
+type FileInfo struct {
+	// Version is the PDF version of the document: the header version,
+	// or the catalog version when that is later.
+	Version string
+
+	// HeaderVersion is the version in the %PDF header and CatalogVersion
+	// the catalog /Version entry, which PDF 1.4 and later files use to
+	// declare a later version without rewriting the header. It is empty
+	// when the catalog has none.
+	HeaderVersion  string
+	CatalogVersion string
+
+	// Objects is the number of indirect objects in use, of which
+	// CompressedObjects are stored in object streams (PDF 1.5).
+	Objects           int
+	CompressedObjects int
+
+	// TrailerSize is the /Size entry of the trailer: one more than the
+	// highest object number.
+	TrailerSize int
+
+	// XRefStreams reports whether the file uses cross-reference streams
+	// (PDF 1.5) instead of, or in addition to, cross-reference tables.
+	XRefStreams bool
+
+	// Revisions is the number of cross-reference sections: one for the
+	// original file plus one per incremental update.
+	Revisions int
+
+	// ID is the file identifier pair from the trailer, hex encoded. It is
+	// empty when the file has no identifier.
+	ID []string
+
+	// Size is the file size in bytes.
+	Size int64
+}
+
+// Version returns the PDF version of the document, such as "1.7": the
+// version in the file header, or the catalog /Version when that is later.
+func (d *Document) Version() (string, error) {
+	if err := d.acquire(); err != nil {
+		return "", err
+	}
+	defer d.release()
+	version, err := d.reader.Version()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	return version, nil
+}
+
+// FileInfo returns the version, object count, cross-reference format and
+// other trailer information of the document. For encrypted documents it
+// describes the file as stored, not the decrypted copy.
+func (d *Document) FileInfo() (*FileInfo, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	version, err := d.reader.Version()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	s, err := d.reader.Structure()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	return &FileInfo{
+		Version:           version,
+		HeaderVersion:     s.HeaderVersion,
+		CatalogVersion:    d.reader.CatalogVersion(),
+		Objects:           s.Objects,
+		CompressedObjects: s.CompressedObjects,
+		TrailerSize:       s.TrailerSize,
+		XRefStreams:       s.XrefStreams,
+		Revisions:         s.Sections,
+		ID:                s.ID,
+		Size:              s.FileSize,
+	}, nil
+}
+
+// Stats returns the number of pages, objects, streams, images, fonts and
+// annotations of the document, the stored size of its streams and images
+// and the decoded size of its page content, gathered in one pass. For
+// encrypted documents it describes the decrypted copy, so sizes can
+// differ slightly from those of the file as stored.
+func (d *Document) Stats() (*Stats, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	s, err := file.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	return &s, nil
+}
+
+// SHA256 returns the hex-encoded SHA-256 of the document file, for
+// recording where extracted data came from. For encrypted documents it is
+// the hash of the file as stored.
+func (d *Document) SHA256() (string, error) {
+	if err := d.acquire(); err != nil {
+		return "", err
+	}
+	defer d.release()
+	return d.reader.SHA256()
+}
+
+// ID returns the file identifier pair from the trailer, hex encoded:
+// permanent is set when the file is first created and changing is
+// updated when it is modified. Both are empty when the file has no /ID,
+// and changing is empty when it has only one entry.
+func (d *Document) ID() (permanent, changing string, err error) {
+	if err := d.acquire(); err != nil {
+		return "", "", err
+	}
+	defer d.release()
+	s, err := d.reader.Structure()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	if len(s.ID) > 0 {
+		permanent = s.ID[0]
+	}
+	if len(s.ID) > 1 {
+		changing = s.ID[1]
+	}
+	return permanent, changing, nil
+}
+
+// Fingerprint returns a hex-encoded SHA-256 of what the pages show, for
+// deduplicating files that were resubmitted after being re-saved,
+// recompressed, re-encrypted or given new metadata. Unlike SHA256 it
+// ignores the file layout, object numbering, compression, metadata and
+// /ID and hashes the page geometry and content operations, with fonts
+// identified by name and images by their decoded data. Documents with the
+// same fingerprint look the same but may differ in annotations, form
+// fields, bookmarks and attachments.
+func (d *Document) Fingerprint() (string, error) {
+	if err := d.acquire(); err != nil {
+		return "", err
+	}
+	defer d.release()
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	sum, err := file.Fingerprint()
+	if err != nil {
+		return "", fmt.Errorf("failed to read page tree: %w", err)
+	}
+	return sum, nil
+}
@@ -0,0 +1,76 @@
+package crazypdf
+
+import (
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Rect is an axis-aligned rectangle in PDF user space, measured in points
+// with the origin at the bottom-left of the page.
+type Rect = internalpdf.Rect
+
+// Quad is a quadrilateral in PDF user space, with its corners in
+// annotation QuadPoints order: upper-left, upper-right, lower-left,
+// lower-right.
+type Quad = internalpdf.Quad
+
+// RectQuad returns the quadrilateral covering r.
+func RectQuad(r Rect) Quad {
+	return internalpdf.RectQuad(r)
+}
+
+// Word is a positioned word on a page, with its bounding box, font and an
+// ID, such as "p1-w12-3f9a0c1e", that stays the same between extractions
+// and exports of the document.
+type Word = internalpdf.Word
+
+// Graphic is a painted vector path or image on a page, with its bounding box.
+type Graphic = internalpdf.Graphic
+
+// Graphic kinds reported by Page.Graphics.
+const (
+	GraphicPath  = internalpdf.GraphicPath
+	GraphicImage = internalpdf.GraphicImage
+)
+
+// DrawItem is a painted path or image with its colors, line style and
+// clipping, as returned by Page.Drawing.
+type DrawItem = internalpdf.DrawItem
+
+// Segment is one step of a DrawItem path in user space.
+type Segment = internalpdf.Segment
+
+// Clip is a clipping path applied to a DrawItem.
+type Clip = internalpdf.Clip
+
+// TextDirections is the amount of text on a page running in each baseline
+// direction, as returned by Page.TextDirections.
+type TextDirections = internalpdf.TextDirections
+
+// Glyph is a character shown on a page with the origin of its baseline,
+// as returned by Page.Glyphs.
+type Glyph = internalpdf.Glyph
+
+// Layer is an optional content group of a document, as returned by
+// Document.Layers.
+type Layer = internalpdf.Layer
+
+// Resource is a named resource of a page or of a form XObject on it, as
+// returned by Page.Resources.
+type Resource = internalpdf.Resource
+
+// Stats holds the counts and sizes of the parts of a document, as
+// returned by Document.Stats.
+type Stats = internalpdf.Stats
+
+// Problem is a structural problem of a document, as returned by
+// Document.Validate.
+type Problem = internalpdf.Problem
+
+// Severity ranks a Problem.
+type Severity = internalpdf.Severity
+
+// Problem severities.
+const (
+	SeverityWarning = internalpdf.SeverityWarning
+	SeverityError   = internalpdf.SeverityError
+)
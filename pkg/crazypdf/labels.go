@@ -0,0 +1,58 @@
+package crazypdf
+
+import (
+	"strconv"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// PageLabels returns the label of every page, in page order: the page
+// numbers printed in the document, such as "i", "ii" and "iii" for front
+// matter followed by "1", "2", or "A-1" with a prefix. Documents without
+// page labels are labeled "1", "2", "3" and so on.
+func (d *Document) PageLabels() ([]string, error) {
+	labels, err := d.pageLabels()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), labels...), nil
+}
+
+// pageLabels returns the cached page labels, reading them on first use.
+// The slice is shared and must not be modified.
+func (d *Document) pageLabels() ([]string, error) {
+	if d.IsClosed() {
+		return nil, ErrDocumentClosed
+	}
+	d.labelsOnce.Do(func() {
+		d.labels, d.labelsErr = d.readPageLabels()
+	})
+	return d.labels, d.labelsErr
+}
+
+// readPageLabels reads the page labels from the catalog.
+func (d *Document) readPageLabels() ([]string, error) {
+	file, err := d.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	labels := internalpdf.PageLabels(catalog, d.NumPages(), file.Resolve)
+	if labels == nil {
+		labels = make([]string, d.NumPages())
+		for i := range labels {
+			labels[i] = strconv.Itoa(i + 1)
+		}
+	}
+	return labels, nil
+}
+
+// Label returns the page label of this page, such as "iv" or "A-1". It is
+// the page number when the document has no page labels.
+func (p *Page) Label() (string, error) {
+	labels, err := p.doc.pageLabels()
+	if err != nil {
+		return "", err
+	}
+	return labels[p.Number-1], nil
+}
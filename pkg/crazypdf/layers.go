@@ -0,0 +1,24 @@
+package crazypdf
+
+// Layers returns the optional content groups of the document, the layers
+// viewers let users show or hide, such as translations, stamps or
+// watermarks, with their names and whether they are visible by default.
+// Documents without layers have none.
+func (d *Document) Layers() ([]Layer, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	return d.reader.Layers()
+}
+
+// GlyphsShown reports, for each glyph of Glyphs, whether it is shown
+// when the layers for which on returns true are visible and the others
+// hidden. Glyphs outside any layer are always shown.
+func (p *Page) GlyphsShown(on func(Layer) bool) ([]bool, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageGlyphsShown(p.Number, on)
+}
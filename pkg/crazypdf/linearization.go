@@ -0,0 +1,82 @@
+package crazypdf
+
+import (
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Linearization describes how a linearized ("fast web view") document is
+// organized: the objects of the first page come first, followed by hint
+// tables, so a viewer can show that page before the rest of the file has
+// downloaded.
+type Linearization struct {
+	// Valid reports whether the linearization still describes the file.
+	// An incremental update appends to a linearized file without
+	// reorganizing it, after which viewers treat it as not linearized;
+	// such files need to be relinearized to load quickly again.
+	Valid bool
+
+	// Version is the linearization version, 1 in current files.
+	Version float64
+
+	// Length is the length of the file in bytes when it was linearized.
+	Length int64
+
+	// Pages is the page count when the file was linearized and FirstPage
+	// the 1-based number of the page the file is organized to show
+	// first, normally 1.
+	Pages     int
+	FirstPage int
+
+	// FirstPageObject is the object number of the first page and
+	// FirstPageEnd the offset of the end of its objects: the bytes a
+	// viewer needs before it can show that page.
+	FirstPageObject int
+	FirstPageEnd    int64
+
+	// HintOffset and HintLength locate the primary hint stream.
+	HintOffset int64
+	HintLength int64
+}
+
+// IsLinearized reports whether the document is linearized for fast web
+// view and has not been updated since, so that viewers can show its first
+// page before downloading the rest.
+func (d *Document) IsLinearized() (bool, error) {
+	lin, err := d.Linearization()
+	if err != nil {
+		return false, err
+	}
+	return lin != nil && lin.Valid, nil
+}
+
+// Linearization returns the linearization parameters of the document, or
+// nil when it is not linearized. A file that was linearized and then
+// incrementally updated is reported with Valid unset. For encrypted and
+// repaired documents it describes the file as stored.
+func (d *Document) Linearization() (*Linearization, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	data, err := d.reader.Original()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	lin := internalpdf.ParseLinearization(data)
+	if lin == nil {
+		return nil, nil
+	}
+	return &Linearization{
+		Valid:           lin.Length == int64(len(data)) && lin.MainXrefOffset < lin.Length,
+		Version:         lin.Version,
+		Length:          lin.Length,
+		Pages:           lin.Pages,
+		FirstPage:       lin.FirstPage + 1,
+		FirstPageObject: lin.FirstPageObject,
+		FirstPageEnd:    lin.FirstPageEnd,
+		HintOffset:      lin.HintOffset,
+		HintLength:      lin.HintLength,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package crazypdf
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// LinkTo returns a URL fragment that opens the document at a page, zoomed
+// to rect, using the standard PDF open parameters:
+// "#page=3&view=FitR,72,500,300,540". rect is in PDF user space, as
+// returned by search and Page.Words; an empty rect opens the whole page
+// ("#page=3"). Append the fragment to the document URL to deep link to a
+// search hit.
+func (d *Document) LinkTo(page int, rect Rect) string {
+	link := "#page=" + strconv.Itoa(page)
+	if rect.Width() <= 0 || rect.Height() <= 0 {
+		return link
+	}
+	return fmt.Sprintf("%s&view=FitR,%s,%s,%s,%s", link,
+		linkNumber(rect.X0), linkNumber(rect.Y0), linkNumber(rect.X1), linkNumber(rect.Y1))
+}
+
+// LinkToDest returns a URL fragment that opens the document at a named
+// destination: "#nameddest=chapter2". Named destinations keep working
+// when pages are inserted or removed before them.
+func (d *Document) LinkToDest(name string) string {
+	return "#nameddest=" + url.PathEscape(name)
+}
+
+// NamedDestinations returns the document's named destinations by name,
+// for use with LinkToDest. Destinations that do not point to a page of
+// the document are left out.
+func (d *Document) NamedDestinations() (map[string]*Destination, error) {
+	file, err := d.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := file.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	dests := internalpdf.NamedDests(catalog, refs, file.Resolve)
+	out := make(map[string]*Destination, len(dests))
+	for name, dest := range dests {
+		out[name] = destination(dest)
+	}
+	return out, nil
+}
+
+// linkNumber formats a coordinate with at most two decimals.
+func linkNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// Link is a hyperlink on a page: a link annotation that opens a web
+// address or goes to a place in the document.
+type Link struct {
+	// Page is the 1-based number of the page the link is on.
+	Page int
+
+	// Rect is the clickable area and Quads, when set, its exact regions,
+	// one per line of a link that wraps.
+	Rect  Rect
+	Quads []Quad
+
+	// Text is the anchor text: the words whose centers fall inside the
+	// clickable area, separated by spaces.
+	Text string
+
+	// URI is the web address of external links, and Dest the destination
+	// of links within the document. Exactly one is set.
+	URI  string
+	Dest *Destination
+}
+
+// Links returns the links of the page that open a URI or go to a page of
+// the document, with their anchor text, in the order of the page's
+// annotation list. Links that launch files or run other actions are left
+// out.
+func (p *Page) Links() ([]Link, error) {
+	annots, err := p.Annotations()
+	if err != nil {
+		return nil, err
+	}
+	var links []Link
+	for _, a := range annots {
+		if a.Subtype != "Link" || (a.URI == "" && a.Dest == nil) {
+			continue
+		}
+		links = append(links, Link{Page: p.Number, Rect: a.Rect, Quads: a.Quads, URI: a.URI, Dest: a.Dest})
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	words, err := p.Words()
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].Text = anchorText(links[i], words)
+	}
+	return links, nil
+}
+
+// anchorText joins the words whose centers fall inside the link's quads,
+// or its rectangle when it has none.
+func anchorText(link Link, words []Word) string {
+	areas := []Rect{link.Rect}
+	if len(link.Quads) > 0 {
+		areas = areas[:0]
+		for _, q := range link.Quads {
+			areas = append(areas, q.BBox())
+		}
+	}
+	var parts []string
+	for _, w := range words {
+		x, y := (w.BBox.X0+w.BBox.X1)/2, (w.BBox.Y0+w.BBox.Y1)/2
+		for _, area := range areas {
+			if area.Contains(x, y) {
+				parts = append(parts, w.S)
+				break
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,107 @@
+package crazypdf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Info holds the fields of a document information dictionary. Empty
+// strings and zero times are omitted when written.
+type Info struct {
+	Title        string
+	Author       string
+	Subject      string
+	Keywords     string
+	Creator      string
+	Producer     string
+	CreationDate time.Time
+	ModDate      time.Time
+}
+
+// metadataMode selects how a MetadataPolicy treats document metadata.
+type metadataMode int
+
+const (
+	metadataPreserve metadataMode = iota
+	metadataStrip
+	metadataReplace
+)
+
+// MetadataPolicy controls what happens to document metadata (the
+// information dictionary and XMP stream) when a writer module produces a
+// new PDF. The zero value preserves metadata.
+type MetadataPolicy struct {
+	mode metadataMode
+	info Info
+}
+
+var (
+	// MetadataPreserve copies the source document's metadata unchanged.
+	MetadataPreserve = MetadataPolicy{mode: metadataPreserve}
+
+	// MetadataStrip removes the information dictionary and XMP metadata,
+	// dropping author, producer and creation details from the output.
+	MetadataStrip = MetadataPolicy{mode: metadataStrip}
+)
+
+// MetadataReplace replaces the source document's metadata with info.
+// XMP metadata is removed so that it cannot contradict the new values.
+func MetadataReplace(info Info) MetadataPolicy {
+	return MetadataPolicy{mode: metadataReplace, info: info}
+}
+
+// ApplyMetadata applies a metadata policy to a document being rewritten.
+// It is intended for use by writer modules (e.g., redact).
+func ApplyMetadata(e *internalpdf.Editor, policy MetadataPolicy) {
+	switch policy.mode {
+	case metadataStrip:
+		e.StripMetadata()
+	case metadataReplace:
+		e.SetInfo(policy.info.dict())
+	}
+}
+
+// dict converts the info to a PDF information dictionary.
+func (info Info) dict() internalpdf.Dict {
+	d := internalpdf.Dict{}
+	for key, val := range map[internalpdf.Name]string{
+		"Title":    info.Title,
+		"Author":   info.Author,
+		"Subject":  info.Subject,
+		"Keywords": info.Keywords,
+		"Creator":  info.Creator,
+		"Producer": info.Producer,
+	} {
+		if val != "" {
+			d[key] = internalpdf.TextString(val)
+		}
+	}
+	if !info.CreationDate.IsZero() {
+		d["CreationDate"] = internalpdf.DateString(info.CreationDate)
+	}
+	if !info.ModDate.IsZero() {
+		d["ModDate"] = internalpdf.DateString(info.ModDate)
+	}
+	return d
+}
+
+// Language returns the natural language the document declares in its
+// catalog /Lang entry, a BCP 47 tag such as "en-US", or "" when it
+// declares none. Tagged documents may override it for parts of the
+// content; analysis.Languages detects the language of the text itself.
+func (d *Document) Language() (string, error) {
+	if err := d.acquire(); err != nil {
+		return "", err
+	}
+	defer d.release()
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	lang, _ := file.Resolve(catalog["Lang"]).(internalpdf.String)
+	return strings.TrimSpace(internalpdf.DecodeTextString(lang)), nil
+}
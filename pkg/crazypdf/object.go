@@ -0,0 +1,283 @@
+package crazypdf
+
+import (
+	"fmt"
+	"sort"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Kind is the type of a PDF object.
+type Kind int
+
+// Object kinds (PDF 32000-1:2008, 7.3).
+const (
+	KindNull Kind = iota
+	KindBool
+	KindInteger
+	KindReal
+	KindString
+	KindName
+	KindArray
+	KindDict
+	KindStream
+)
+
+// String returns the lower-case name of the kind, such as "dict".
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindInteger:
+		return "integer"
+	case KindReal:
+		return "real"
+	case KindString:
+		return "string"
+	case KindName:
+		return "name"
+	case KindArray:
+		return "array"
+	case KindDict:
+		return "dict"
+	case KindStream:
+		return "stream"
+	}
+	return fmt.Sprintf("Kind(%d)", k)
+}
+
+// Object is a read-only view of a PDF object for navigating the object
+// graph, such as the catalog entries a feature of this package does not
+// cover. Indirect references are followed transparently, and missing
+// entries, out-of-range indexes and unresolvable references yield a null
+// Object, so lookups can be chained:
+//
+//	lang := catalog.Key("Lang").Text()
+//	kids := catalog.Key("Pages").Key("Kids").Len()
+//
+// The accessors return the zero value when the object has another kind.
+// Strings of encrypted documents are already decrypted. An Object stays
+// readable after its document is closed.
+type Object struct {
+	file *internalpdf.File
+	obj  internalpdf.Object
+	ref  internalpdf.Ref // reference it was reached through, if any
+}
+
+// newObject resolves o, remembering the reference when o is one.
+func newObject(file *internalpdf.File, o internalpdf.Object) Object {
+	ref, _ := o.(internalpdf.Ref)
+	return Object{file: file, obj: file.Resolve(o), ref: ref}
+}
+
+// Catalog returns the document catalog, the root of the object graph.
+func (d *Document) Catalog() (Object, error) {
+	trailer, err := d.Trailer()
+	if err != nil {
+		return Object{}, err
+	}
+	catalog := trailer.Key("Root")
+	if catalog.Kind() != KindDict {
+		return Object{}, fmt.Errorf("%w: trailer has no catalog", ErrInvalidPDF)
+	}
+	return catalog, nil
+}
+
+// Trailer returns the trailer dictionary, with the document-level
+// entries Root, Info, ID and Size. For files with cross-reference streams
+// it is the dictionary of the last stream.
+func (d *Document) Trailer() (Object, error) {
+	if err := d.acquire(); err != nil {
+		return Object{}, err
+	}
+	defer d.release()
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	return Object{file: file, obj: file.Trailer()}, nil
+}
+
+// Object returns the page dictionary.
+func (p *Page) Object() (Object, error) {
+	if err := p.doc.acquire(); err != nil {
+		return Object{}, err
+	}
+	defer p.doc.release()
+	file, _, refs, err := p.object()
+	if err != nil {
+		return Object{}, err
+	}
+	return newObject(file, refs[p.Number-1]), nil
+}
+
+// Kind returns the type of the object.
+func (o Object) Kind() Kind {
+	switch o.obj.(type) {
+	case bool:
+		return KindBool
+	case int64:
+		return KindInteger
+	case float64:
+		return KindReal
+	case internalpdf.String:
+		return KindString
+	case internalpdf.Name:
+		return KindName
+	case internalpdf.Array:
+		return KindArray
+	case internalpdf.Dict:
+		return KindDict
+	case *internalpdf.Stream:
+		return KindStream
+	}
+	return KindNull
+}
+
+// IsNull reports whether the object is null or missing.
+func (o Object) IsNull() bool {
+	return o.Kind() == KindNull
+}
+
+// Ref returns the object number and generation of the indirect
+// reference the object was reached through; ok is false for direct
+// objects.
+func (o Object) Ref() (num, gen int, ok bool) {
+	return o.ref.Num, o.ref.Gen, o.ref != (internalpdf.Ref{})
+}
+
+// Key returns the entry of a dictionary, or of a stream's dictionary.
+func (o Object) Key(name string) Object {
+	d := o.dict()
+	if d == nil {
+		return Object{}
+	}
+	return newObject(o.file, d[internalpdf.Name(name)])
+}
+
+// Keys returns the keys of a dictionary, or of a stream's dictionary, in
+// lexical order.
+func (o Object) Keys() []string {
+	d := o.dict()
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Index returns the element at index i of an array.
+func (o Object) Index(i int) Object {
+	arr, _ := o.obj.(internalpdf.Array)
+	if i < 0 || i >= len(arr) {
+		return Object{}
+	}
+	return newObject(o.file, arr[i])
+}
+
+// Len returns the number of elements of an array or entries of a
+// dictionary or stream dictionary.
+func (o Object) Len() int {
+	if arr, ok := o.obj.(internalpdf.Array); ok {
+		return len(arr)
+	}
+	return len(o.dict())
+}
+
+// Bool returns the value of a boolean.
+func (o Object) Bool() bool {
+	b, _ := o.obj.(bool)
+	return b
+}
+
+// Int returns the value of an integer, or of a real truncated toward
+// zero.
+func (o Object) Int() int64 {
+	switch v := o.obj.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// Float returns the value of a real or integer.
+func (o Object) Float() float64 {
+	switch v := o.obj.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// Name returns a name without the leading slash.
+func (o Object) Name() string {
+	n, _ := o.obj.(internalpdf.Name)
+	return string(n)
+}
+
+// RawString returns the bytes of a string as stored.
+func (o Object) RawString() string {
+	s, _ := o.obj.(internalpdf.String)
+	return string(s)
+}
+
+// Text returns a string decoded as a text string, as used for titles and
+// other human-readable values: UTF-16 or UTF-8 with a byte order mark,
+// PDFDocEncoding otherwise.
+func (o Object) Text() string {
+	s, _ := o.obj.(internalpdf.String)
+	return internalpdf.DecodeTextString(s)
+}
+
+// StreamData returns the data of a stream with its filters applied. It
+// fails for filters this package cannot decode, such as image codecs;
+// RawStreamData returns their data still encoded.
+func (o Object) StreamData() ([]byte, error) {
+	s, ok := o.obj.(*internalpdf.Stream)
+	if !ok {
+		return nil, fmt.Errorf("object is a %s, not a stream", o.Kind())
+	}
+	data, err := o.file.DecodeStream(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode stream: %w", err)
+	}
+	return data, nil
+}
+
+// RawStreamData returns a copy of the data of a stream as stored, before
+// its filters are applied.
+func (o Object) RawStreamData() []byte {
+	s, ok := o.obj.(*internalpdf.Stream)
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), s.Data...)
+}
+
+// String returns the object in PDF syntax, with indirect references
+// inside it left as references, for debugging. Streams are shown as
+// their dictionary.
+func (o Object) String() string {
+	if s, ok := o.obj.(*internalpdf.Stream); ok {
+		return string(internalpdf.SerializeObject(s.Dict)) + " stream"
+	}
+	return string(internalpdf.SerializeObject(o.obj))
+}
+
+// dict returns the dictionary of a dictionary or stream, or nil.
+func (o Object) dict() internalpdf.Dict {
+	switch v := o.obj.(type) {
+	case internalpdf.Dict:
+		return v
+	case *internalpdf.Stream:
+		return v.Dict
+	}
+	return nil
+}
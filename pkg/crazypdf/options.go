@@ -1,26 +1,155 @@
 package crazypdf
 
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
 // Config holds configuration for opening a PDF document.
 type Config struct {
 	// Password is the password for encrypted PDFs. Empty string for unencrypted.
 	Password string
+
+	// PageCacheSize is the number of pages whose parsed text is kept so
+	// that repeated extractions from a page do not parse it again. Zero
+	// disables caching.
+	PageCacheSize int
+
+	// ScratchDir is the directory for temporary files, such as the copy
+	// of a stream opened with OpenReader. Empty uses os.TempDir.
+	ScratchDir string
+
+	// InMemoryOnly forbids temporary files: data that would be spooled to
+	// disk is held in memory instead.
+	InMemoryOnly bool
+
+	// Repair recovers damaged files that fail to open instead of
+	// returning ErrInvalidPDF.
+	Repair bool
+
+	// Backends is the chain of backends tried in turn to open a
+	// document. Nil means BackendStandard alone.
+	Backends []Backend
+
+	// MaxMemory caps, in bytes, the decoded size of each stream and the
+	// text held in the page cache. Zero means no limit.
+	MaxMemory int64
+
+	// TracerProvider records spans for opening documents and extracting
+	// text. Nil disables tracing.
+	TracerProvider TracerProvider
 }
 
 // Option is a functional option for configuring PDF document opening.
+// Options are validated together after all have been applied; the Open
+// functions return an error wrapping ErrInvalidConfig, without reading
+// the file, when a value is out of range or options conflict.
 type Option func(*Config)
 
-// WithPassword sets the password for opening encrypted PDFs.
+// WithPassword sets the password for opening encrypted PDFs. Either the
+// user or the owner password opens the document.
 func WithPassword(password string) Option {
 	return func(c *Config) {
 		c.Password = password
 	}
 }
 
-// applyOptions creates a Config from the given options.
-func applyOptions(opts []Option) *Config {
-	cfg := &Config{}
-	for _, opt := range opts {
+// WithPageCacheSize sets how many pages' parsed text is cached. Calling
+// several text accessors on a page, such as PlainText and then
+// StyledTexts, parses its content once while it stays in the cache; the
+// least recently used pages are evicted first. Zero disables caching.
+// Default is 16.
+func WithPageCacheSize(pages int) Option {
+	return func(c *Config) {
+		c.PageCacheSize = pages
+	}
+}
+
+// WithScratchDir sets the directory used for temporary files, for
+// example a writable volume when the default temporary directory is
+// read-only or too small.
+func WithScratchDir(dir string) Option {
+	return func(c *Config) {
+		c.ScratchDir = dir
+	}
+}
+
+// WithInMemoryOnly forbids temporary files. Operations that would spool
+// data to disk keep it in memory instead, which suits read-only
+// containers at the cost of memory use.
+func WithInMemoryOnly() Option {
+	return func(c *Config) {
+		c.InMemoryOnly = true
+	}
+}
+
+// WithRepair recovers documents that fail to open because their
+// cross-reference table, trailer or page tree is missing or corrupt. The
+// objects are found by scanning the file for their headers and the pages
+// that can still be read are kept; Document.RepairReport describes what
+// was recovered and skipped. Files that open normally are unaffected.
+// Streams opened with OpenReader are read into memory. It adds
+// BackendRebuild to the backends tried; see WithBackend.
+func WithRepair() Option {
+	return func(c *Config) {
+		c.Repair = true
+	}
+}
+
+// WithMaxMemory caps the memory a document uses for decompressed data, to
+// protect services that open untrusted files against decompression bombs.
+// A stream that would decode to more than bytes, such as a page's content
+// or an image, fails with ErrResourceLimit as soon as it passes the limit,
+// and the page cache evicts pages once the text it holds is estimated to
+// pass it. The file itself and streams decoded while opening, such as
+// those of encrypted documents, are not counted; bound those with a
+// WorkerPool. Zero means no limit, the default.
+func WithMaxMemory(bytes int64) Option {
+	return func(c *Config) {
+		c.MaxMemory = bytes
+	}
+}
+
+// applyOptions creates a Config from the given options and validates it.
+// The error wraps ErrInvalidConfig and lists every problem found.
+func applyOptions(opts []Option) (*Config, error) {
+	cfg := &Config{PageCacheSize: internalpdf.DefaultRowCacheSize}
+	var errs []error
+	for i, opt := range opts {
+		if opt == nil {
+			errs = append(errs, fmt.Errorf("option %d is nil", i))
+			continue
+		}
 		opt(cfg)
 	}
-	return cfg
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// validate reports values that are out of range or options that conflict.
+func (c *Config) validate() []error {
+	var errs []error
+	if c.PageCacheSize < 0 {
+		errs = append(errs, fmt.Errorf("page cache size %d is negative", c.PageCacheSize))
+	}
+	if c.MaxMemory < 0 {
+		errs = append(errs, fmt.Errorf("memory limit %d is negative", c.MaxMemory))
+	}
+	if c.ScratchDir != "" {
+		if c.InMemoryOnly {
+			errs = append(errs, errors.New("WithScratchDir and WithInMemoryOnly conflict"))
+		} else if info, err := os.Stat(c.ScratchDir); err != nil {
+			errs = append(errs, fmt.Errorf("scratch directory: %w", err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("scratch directory %s is not a directory", c.ScratchDir))
+		}
+	}
+	errs = append(errs, c.validateBackends()...)
+	return errs
 }
@@ -0,0 +1,14 @@
+module github.com/ayushanand18/crazypdf/pkg/crazypdf/oteltrace
+
+go 1.24.4
+
+require (
+	github.com/ayushanand18/crazypdf v0.0.0
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+)
+
+require github.com/ledongthuc/pdf v0.0.0-20250511090121-5959a4027728 // indirect
+
+// The adapter is developed against the crazypdf module of this repository
+replace github.com/ayushanand18/crazypdf => ../../..
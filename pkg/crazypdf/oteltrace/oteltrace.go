@@ -0,0 +1,78 @@
+// Package oteltrace records the spans of crazypdf with OpenTelemetry.
+//
+// It is a module of its own, so that the crazypdf module does not depend
+// on OpenTelemetry; only programs that import this package do:
+//
+//	doc, err := crazypdf.Open("report.pdf",
+//		crazypdf.WithTracerProvider(oteltrace.New(otel.GetTracerProvider())))
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New returns a crazypdf.TracerProvider that records spans with tp.
+func New(tp trace.TracerProvider) crazypdf.TracerProvider {
+	return provider{tp}
+}
+
+// provider adapts a trace.TracerProvider.
+type provider struct{ tp trace.TracerProvider }
+
+func (p provider) Tracer(name string) crazypdf.Tracer {
+	return tracer{p.tp.Tracer(name)}
+}
+
+// tracer adapts a trace.Tracer.
+type tracer struct{ t trace.Tracer }
+
+func (t tracer) Start(ctx context.Context, spanName string, attrs ...crazypdf.Attribute) (context.Context, crazypdf.Span) {
+	ctx, s := t.t.Start(ctx, spanName, trace.WithAttributes(Attributes(attrs...)...))
+	return ctx, span{s}
+}
+
+// span adapts a trace.Span. Errors recorded also set the span's status.
+type span struct{ s trace.Span }
+
+func (s span) SetAttributes(attrs ...crazypdf.Attribute) {
+	s.s.SetAttributes(Attributes(attrs...)...)
+}
+
+func (s span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.s.End()
+}
+
+// Attributes converts crazypdf attributes to OpenTelemetry attributes.
+// Values of other types than those crazypdf.Attribute documents are
+// recorded as strings formatted with fmt.
+func Attributes(attrs ...crazypdf.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, len(attrs))
+	for i, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kvs[i] = attribute.String(a.Key, v)
+		case int:
+			kvs[i] = attribute.Int(a.Key, v)
+		case int64:
+			kvs[i] = attribute.Int64(a.Key, v)
+		case float64:
+			kvs[i] = attribute.Float64(a.Key, v)
+		case bool:
+			kvs[i] = attribute.Bool(a.Key, v)
+		default:
+			kvs[i] = attribute.String(a.Key, fmt.Sprint(v))
+		}
+	}
+	return kvs
+}
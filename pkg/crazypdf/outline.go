@@ -0,0 +1,121 @@
+package crazypdf
+
+import (
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// maxOutlineDepth bounds the nesting of outline items read, guarding
+// against malformed files.
+const maxOutlineDepth = 64
+
+// OutlineItem is an entry of the document outline (bookmarks).
+type OutlineItem struct {
+	Title string
+
+	// Level is the nesting depth: 0 for top-level items.
+	Level int
+
+	// Page is the 1-based page the item points to, or 0 when it does not
+	// point to a page of the document, for example when it opens a URI.
+	Page int
+
+	// Dest is the destination of the item, with the page and how it is
+	// fitted in the viewer window. It is nil when Page is 0.
+	Dest *Destination
+
+	// URI is the target of items that open a web link.
+	URI string
+
+	// Open reports whether the item is shown expanded when the document
+	// is opened.
+	Open bool
+
+	Children []*OutlineItem
+}
+
+// Outline returns the document outline as a tree of items in display
+// order. It returns nil for documents without bookmarks.
+func (d *Document) Outline() ([]*OutlineItem, error) {
+	file, err := d.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	root, ok := file.Resolve(catalog["Outlines"]).(internalpdf.Dict)
+	if !ok {
+		return nil, nil
+	}
+	refs, err := file.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+
+	r := &outlineReader{catalog: catalog, refs: refs, resolve: file.Resolve, seen: make(map[int]bool)}
+	return r.items(root["First"], 0), nil
+}
+
+// outlineReader converts outline item dictionaries, visiting each item
+// once so that cyclic /Next and /First links terminate.
+type outlineReader struct {
+	catalog internalpdf.Dict
+	refs    []internalpdf.Ref
+	resolve func(internalpdf.Object) internalpdf.Object
+	seen    map[int]bool
+}
+
+// items reads the sibling chain starting at first.
+func (r *outlineReader) items(first internalpdf.Object, level int) []*OutlineItem {
+	if level >= maxOutlineDepth {
+		return nil
+	}
+	var out []*OutlineItem
+	for next := first; next != nil; {
+		if ref, ok := next.(internalpdf.Ref); ok {
+			if r.seen[ref.Num] {
+				break
+			}
+			r.seen[ref.Num] = true
+		}
+		node, ok := r.resolve(next).(internalpdf.Dict)
+		if !ok {
+			break
+		}
+		out = append(out, r.item(node, level))
+		next = node["Next"]
+	}
+	return out
+}
+
+// item reads one outline item and its children.
+func (r *outlineReader) item(node internalpdf.Dict, level int) *OutlineItem {
+	item := &OutlineItem{Level: level}
+	if title, ok := r.resolve(node["Title"]).(internalpdf.String); ok {
+		item.Title = internalpdf.DecodeTextString(title)
+	}
+	if count, ok := r.resolve(node["Count"]).(int64); ok {
+		item.Open = count > 0
+	}
+
+	dest := node["Dest"]
+	if action, ok := r.resolve(node["A"]).(internalpdf.Dict); ok {
+		switch kind, _ := r.resolve(action["S"]).(internalpdf.Name); kind {
+		case "GoTo":
+			dest = action["D"]
+		case "URI":
+			if uri, ok := r.resolve(action["URI"]).(internalpdf.String); ok {
+				item.URI = string(uri)
+			}
+		}
+	}
+	if dest != nil {
+		if d, ok := internalpdf.ResolveDest(r.catalog, dest, r.refs, r.resolve); ok {
+			item.Page = d.Page
+			item.Dest = destination(d)
+		}
+	}
+
+	item.Children = r.items(node["First"], level+1)
+	return item
+}
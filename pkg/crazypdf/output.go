@@ -0,0 +1,175 @@
+package crazypdf
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteConfig holds configuration for writing output files.
+type WriteConfig struct {
+	// Checksum enables computing the SHA-256 of the written bytes.
+	Checksum bool
+
+	// Mode is the permission of files created by WriteFile.
+	Mode os.FileMode
+
+	// UniqueID gives each written PDF a fresh file identifier. By default
+	// the identifier is derived from the content, so identical input and
+	// options produce byte-identical files.
+	UniqueID bool
+
+	// InitialView, when set, replaces the page layout, page mode and open
+	// action of written PDFs.
+	InitialView *InitialView
+}
+
+// WriteOption is a functional option for configuring output writes.
+type WriteOption func(*WriteConfig)
+
+// WithChecksum computes the SHA-256 of the output, returned in
+// WriteResult.SHA256, so pipelines can verify the artifact.
+func WithChecksum() WriteOption {
+	return func(c *WriteConfig) {
+		c.Checksum = true
+	}
+}
+
+// WithFileMode sets the permission of files created by WriteFile.
+// Default is 0644.
+func WithFileMode(mode os.FileMode) WriteOption {
+	return func(c *WriteConfig) {
+		c.Mode = mode
+	}
+}
+
+// WithUniqueID gives each written PDF a random file identifier instead of
+// one derived from its content. Output is then no longer reproducible.
+func WithUniqueID() WriteOption {
+	return func(c *WriteConfig) {
+		c.UniqueID = true
+	}
+}
+
+// WithInitialView sets how written PDFs open in a viewer: the page
+// layout, the panel shown and the open action. Empty fields keep the
+// source document's settings.
+func WithInitialView(view InitialView) WriteOption {
+	return func(c *WriteConfig) {
+		c.InitialView = &view
+	}
+}
+
+// NewWriteConfig creates a WriteConfig from the given options. Writer
+// modules use it to read settings that affect serialization.
+func NewWriteConfig(opts ...WriteOption) *WriteConfig {
+	return applyWriteOptions(opts)
+}
+
+// applyWriteOptions creates a WriteConfig from the given options.
+func applyWriteOptions(opts []WriteOption) *WriteConfig {
+	cfg := &WriteConfig{Mode: 0644}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WriteResult describes a completed write.
+type WriteResult struct {
+	// Bytes is the number of bytes written.
+	Bytes int64 `json:"bytes"`
+
+	// SHA256 is the hex-encoded SHA-256 of the output, or empty when
+	// checksums were not requested.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Write calls fn to produce output on w, counting the bytes written and
+// optionally hashing them. Feature modules use it for io.Writer targets.
+func Write(w io.Writer, fn func(io.Writer) error, opts ...WriteOption) (*WriteResult, error) {
+	cfg := applyWriteOptions(opts)
+
+	cw := &countingWriter{w: w}
+	if cfg.Checksum {
+		cw.hash = sha256.New()
+	}
+	if err := fn(cw); err != nil {
+		return nil, err
+	}
+
+	result := &WriteResult{Bytes: cw.n}
+	if cw.hash != nil {
+		result.SHA256 = hex.EncodeToString(cw.hash.Sum(nil))
+	}
+	return result, nil
+}
+
+// WriteFile calls fn to produce output and stores it at path atomically:
+// the data is written to a temporary file in the same directory, synced
+// and renamed over path, so readers never observe a partially written
+// file, even if the process crashes.
+func WriteFile(path string, fn func(io.Writer) error, opts ...WriteOption) (*WriteResult, error) {
+	cfg := applyWriteOptions(opts)
+
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	bw := bufio.NewWriter(tmp)
+	result, err := Write(bw, fn, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := tmp.Chmod(cfg.Mode); err != nil {
+		return nil, fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close %s: %w", path, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return nil, fmt.Errorf("failed to rename output into place: %w", err)
+	}
+	committed = true
+	return result, nil
+}
+
+// countingWriter counts and optionally hashes the bytes written through it.
+type countingWriter struct {
+	w    io.Writer
+	n    int64
+	hash hash.Hash
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if c.hash != nil {
+		c.hash.Write(p[:n])
+	}
+	return n, err
+}
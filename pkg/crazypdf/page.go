@@ -15,41 +15,219 @@ type Page struct {
 // PlainText extracts plain text from this page with words joined by spaces
 // and rows separated by newlines.
 func (p *Page) PlainText() (string, error) {
-	if p.doc.closed {
-		return "", ErrDocumentClosed
+	if err := p.doc.acquire(); err != nil {
+		return "", err
 	}
+	defer p.doc.release()
 	return p.doc.reader.PagePlainText(p.Number)
 }
 
+// Text returns the text of this page with words joined by spaces and rows
+// separated by newlines. It is the same as extract.PageText with default
+// options; use the extract package for other layout modes.
+func (p *Page) Text() (string, error) {
+	return p.PlainText()
+}
+
 // TextByRow returns text organized by rows with position information.
 func (p *Page) TextByRow() ([]internalpdf.TextRow, error) {
-	if p.doc.closed {
-		return nil, ErrDocumentClosed
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
 	}
+	defer p.doc.release()
 	return p.doc.reader.PageTextByRow(p.Number)
 }
 
 // StyledTexts returns text elements with font and position information.
 func (p *Page) StyledTexts() ([]internalpdf.StyledText, error) {
-	if p.doc.closed {
-		return nil, ErrDocumentClosed
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
 	}
+	defer p.doc.release()
 	return p.doc.reader.PageStyledTexts(p.Number)
 }
 
 // ContentStream returns the raw PDF content stream bytes for this page.
 func (p *Page) ContentStream() ([]byte, error) {
-	if p.doc.closed {
-		return nil, ErrDocumentClosed
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
 	}
+	defer p.doc.release()
 	return p.doc.reader.PageContentStream(p.Number)
 }
 
 // PhysicalLayoutText extracts text preserving spatial positioning on the page.
 // pageWidth is the page width in PDF points (default 612 for US Letter).
 func (p *Page) PhysicalLayoutText(pageWidth float64) (string, error) {
-	if p.doc.closed {
-		return "", ErrDocumentClosed
+	if err := p.doc.acquire(); err != nil {
+		return "", err
 	}
+	defer p.doc.release()
 	return p.doc.reader.PhysicalLayoutText(p.Number, pageWidth)
 }
+
+// MediaBox returns the page's media box in PDF points, resolving values
+// inherited from the page tree.
+func (p *Page) MediaBox() (Rect, error) {
+	if err := p.doc.acquire(); err != nil {
+		return Rect{}, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageMediaBox(p.Number)
+}
+
+// CropBox returns the region of the page that viewers display, in PDF
+// points. It defaults to the media box when the page has none.
+func (p *Page) CropBox() (Rect, error) {
+	if err := p.doc.acquire(); err != nil {
+		return Rect{}, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageCropBox(p.Number)
+}
+
+// Rotation returns the clockwise angle, in degrees, by which the page is
+// rotated when displayed: 0, 90, 180 or 270.
+func (p *Page) Rotation() (int, error) {
+	if err := p.doc.acquire(); err != nil {
+		return 0, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageRotation(p.Number)
+}
+
+// PageAttributes are the inheritable attributes of a page, resolved from
+// the page or the nearest ancestor node of the page tree that defines
+// them.
+type PageAttributes struct {
+	// Resources is the resource dictionary, a null Object when the page
+	// has none.
+	Resources Object
+
+	// MediaBox and CropBox are in PDF points, as returned by
+	// Page.MediaBox and Page.CropBox.
+	MediaBox Rect
+	CropBox  Rect
+
+	// Rotation is the clockwise display rotation in degrees, as returned
+	// by Page.Rotation.
+	Rotation int
+
+	// Inherited names the attributes taken from an ancestor rather than
+	// the page dictionary itself, such as "MediaBox".
+	Inherited []string
+}
+
+// Attributes returns the inheritable attributes of the page, Resources,
+// MediaBox, CropBox and Rotate, resolved through the page tree.
+func (p *Page) Attributes() (PageAttributes, error) {
+	if err := p.doc.acquire(); err != nil {
+		return PageAttributes{}, err
+	}
+	defer p.doc.release()
+	file, attrs, err := p.doc.reader.PageAttrs(p.Number)
+	if err != nil {
+		return PageAttributes{}, err
+	}
+	result := PageAttributes{
+		MediaBox: attrs.MediaBox,
+		CropBox:  attrs.CropBox,
+		Rotation: attrs.Rotate,
+	}
+	if attrs.Resources != nil {
+		result.Resources = Object{file: file, obj: attrs.Resources}
+	}
+	for _, key := range attrs.Inherited {
+		result.Inherited = append(result.Inherited, string(key))
+	}
+	return result, nil
+}
+
+// Size returns the width and height of the page as displayed, in PDF
+// points: the crop box dimensions, swapped when the page is rotated by 90
+// or 270 degrees.
+func (p *Page) Size() (width, height float64, err error) {
+	box, err := p.CropBox()
+	if err != nil {
+		return 0, 0, err
+	}
+	rotation, err := p.Rotation()
+	if err != nil {
+		return 0, 0, err
+	}
+	if rotation == 90 || rotation == 270 {
+		return box.Height(), box.Width(), nil
+	}
+	return box.Width(), box.Height(), nil
+}
+
+// Graphics returns the vector paths and images painted on this page.
+func (p *Page) Graphics() ([]Graphic, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageGraphics(p.Number)
+}
+
+// Drawing returns the paths and images painted on this page in painting
+// order, with the graphics state each was painted with. Text is not
+// included.
+func (p *Page) Drawing() ([]DrawItem, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageDrawing(p.Number)
+}
+
+// Words returns the words on this page with their bounding boxes, ordered
+// by row from top to bottom and left to right within a row.
+func (p *Page) Words() ([]Word, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageWords(p.Number)
+}
+
+// Glyphs returns the characters shown on this page in content stream
+// order, with the origin of each on its baseline. For positioned text use
+// Words; glyphs suit measurements along the baselines, such as the angle
+// of skewed text.
+func (p *Page) Glyphs() ([]Glyph, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageGlyphs(p.Number)
+}
+
+// TextDirections returns how much text on this page runs in each
+// direction in user space, before the page rotation is applied: left to
+// right, bottom to top, right to left and top to bottom. Text of a page
+// scanned or drawn sideways runs in one of the other directions.
+func (p *Page) TextDirections() (TextDirections, error) {
+	if err := p.doc.acquire(); err != nil {
+		return TextDirections{}, err
+	}
+	defer p.doc.release()
+	return p.doc.reader.PageTextDirections(p.Number)
+}
+
+// Resources returns the fonts, XObjects, graphics states, color spaces,
+// patterns and shadings in the resources of this page and of the form
+// XObjects it holds, with the size of each in bytes, to find what makes a
+// file large. A resource shared by several pages or forms is listed for
+// each; its Object number identifies the copies.
+func (p *Page) Resources() ([]Resource, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	file, page, _, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+	return file.PageResources(page), nil
+}
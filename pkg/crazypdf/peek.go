@@ -0,0 +1,72 @@
+package crazypdf
+
+import (
+	"fmt"
+	"os"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// QuickInfo is a summary of a PDF file returned by Peek.
+type QuickInfo struct {
+	// Version is the PDF version, such as "1.7".
+	Version string
+
+	// Encrypted reports whether the file is encrypted. Files that need a
+	// password to open report only Version and Encrypted.
+	Encrypted bool
+
+	// Pages is the page count recorded in the page tree.
+	Pages int
+
+	// Title is the title from the document information dictionary.
+	Title string
+
+	// FirstPage is the media box of the first page in points.
+	FirstPage Rect
+}
+
+// Peek returns the page count, version, encryption status, title and
+// first page size of a PDF file without opening it as a Document. Only
+// the header, cross-reference table, trailer, information dictionary and
+// the page tree down to the first page are read, so it is fast enough for
+// listing large numbers of files.
+func Peek(path string) (*QuickInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	version, err := internalpdf.HeaderVersion(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	reader, err := internalpdf.OpenReaderAt(f, stat.Size(), "")
+	if internalpdf.IsPasswordError(err) {
+		return &QuickInfo{Version: version, Encrypted: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	defer reader.Close()
+
+	info := &QuickInfo{
+		Encrypted: reader.Encrypted(),
+		Pages:     reader.NumPages(),
+		Title:     reader.InfoText("Title"),
+	}
+	if info.Version, err = reader.Version(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	if info.Pages > 0 {
+		if info.FirstPage, err = reader.PageMediaBox(1); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,217 @@
+package crazypdf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// WorkerPool bounds the documents a service holds open at once and the
+// memory they use. Documents opened through the pool count against its
+// limits until they are closed; opens that would exceed a limit wait in
+// a first-in, first-out queue, so a large document is not starved by
+// small ones.
+//
+// A document is charged the size of its file, which is what it keeps in
+// memory when opened from bytes, from a stream spooled in memory or
+// encrypted (decrypted into memory). What it decodes while in use, such
+// as page content and the text held in its page cache, is not charged;
+// open it with WithMaxMemory to bound that too.
+type WorkerPool struct {
+	maxDocs int
+	maxMem  int64
+
+	mu      sync.Mutex
+	docs    int
+	mem     int64
+	waiting []*poolWaiter
+	stats   PoolStats
+}
+
+// poolWaiter is an open queued for a slot. ready is closed once the slot
+// is granted.
+type poolWaiter struct {
+	size  int64
+	ready chan struct{}
+}
+
+// PoolStats are counters of a WorkerPool.
+type PoolStats struct {
+	// Open is the number of documents open through the pool and
+	// MemBytes the memory charged to them.
+	Open     int
+	MemBytes int64
+
+	// Queued is the number of opens waiting for a slot.
+	Queued int
+
+	// Opened counts documents opened through the pool, Failed opens that
+	// failed after getting a slot, and Cancelled opens whose context
+	// ended while queued.
+	Opened    int64
+	Failed    int64
+	Cancelled int64
+
+	// Waited counts opens that had to queue, WaitTime is their total
+	// time in the queue and MaxWait the longest.
+	Waited   int64
+	WaitTime time.Duration
+	MaxWait  time.Duration
+}
+
+// NewWorkerPool returns a pool allowing at most maxDocs open documents
+// using at most maxMemBytes of memory together. A limit of 0 or less is
+// unlimited.
+func NewWorkerPool(maxDocs int, maxMemBytes int64) *WorkerPool {
+	return &WorkerPool{maxDocs: maxDocs, maxMem: maxMemBytes}
+}
+
+// Open opens a PDF file like Open once the pool has room for it, waiting
+// in the queue until then or until ctx is done. Closing the document
+// returns its slot to the pool. A file larger than the pool's memory
+// limit fails with ErrPoolLimit rather than waiting forever.
+func (p *WorkerPool) Open(ctx context.Context, filePath string, opts ...Option) (*Document, error) {
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open PDF: %w", ErrInvalidPDF, err)
+	}
+	return p.open(ctx, fi.Size(), func() (*Document, error) {
+		return openFile(ctx, filePath, opts)
+	})
+}
+
+// OpenBytes opens a PDF from data like OpenBytes once the pool has room
+// for it, as Open does.
+func (p *WorkerPool) OpenBytes(ctx context.Context, data []byte, opts ...Option) (*Document, error) {
+	return p.open(ctx, int64(len(data)), func() (*Document, error) {
+		return OpenBytes(data, opts...)
+	})
+}
+
+// Stats returns the current counters of the pool.
+func (p *WorkerPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Open = p.docs
+	stats.MemBytes = p.mem
+	stats.Queued = len(p.waiting)
+	return stats
+}
+
+// open waits for a slot of the given size, opens the document and ties
+// the slot to it.
+func (p *WorkerPool) open(ctx context.Context, size int64, open func() (*Document, error)) (*Document, error) {
+	if err := p.acquire(ctx, size); err != nil {
+		return nil, err
+	}
+	doc, err := open()
+
+	p.mu.Lock()
+	if err != nil {
+		p.stats.Failed++
+	} else {
+		p.stats.Opened++
+	}
+	p.mu.Unlock()
+
+	if err != nil {
+		p.release(size)
+		return nil, err
+	}
+	doc.onClose = func() { p.release(size) }
+	return doc, nil
+}
+
+// acquire takes a slot of the given size, queueing until one is free.
+func (p *WorkerPool) acquire(ctx context.Context, size int64) error {
+	p.mu.Lock()
+	if p.maxMem > 0 && size > p.maxMem {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: document of %d bytes, limit %d bytes", ErrPoolLimit, size, p.maxMem)
+	}
+	if len(p.waiting) == 0 && p.fits(size) {
+		p.take(size)
+		p.mu.Unlock()
+		return nil
+	}
+	w := &poolWaiter{size: size, ready: make(chan struct{})}
+	p.waiting = append(p.waiting, w)
+	p.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-w.ready:
+		p.recordWait(time.Since(start))
+		return nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-w.ready:
+		// granted as ctx ended; hand the slot on
+		p.docs--
+		p.mem -= size
+		p.grant()
+	default:
+		for i, q := range p.waiting {
+			if q == w {
+				p.waiting = append(p.waiting[:i], p.waiting[i+1:]...)
+				break
+			}
+		}
+		// the head may fit now that a larger open ahead of it left
+		p.grant()
+	}
+	p.stats.Cancelled++
+	return fmt.Errorf("waiting for a pool slot: %w", ctx.Err())
+}
+
+// release returns a slot of the given size and grants queued opens that
+// now fit.
+func (p *WorkerPool) release(size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.docs--
+	p.mem -= size
+	p.grant()
+}
+
+// grant hands slots to queued opens in order while the first one fits.
+// The caller must hold p.mu.
+func (p *WorkerPool) grant() {
+	for len(p.waiting) > 0 && p.fits(p.waiting[0].size) {
+		w := p.waiting[0]
+		p.waiting = p.waiting[1:]
+		p.take(w.size)
+		close(w.ready)
+	}
+}
+
+// fits reports whether a document of the given size is within the
+// limits. The caller must hold p.mu.
+func (p *WorkerPool) fits(size int64) bool {
+	return (p.maxDocs <= 0 || p.docs < p.maxDocs) &&
+		(p.maxMem <= 0 || p.mem+size <= p.maxMem)
+}
+
+// take charges a slot of the given size. The caller must hold p.mu.
+func (p *WorkerPool) take(size int64) {
+	p.docs++
+	p.mem += size
+}
+
+// recordWait records the time an open spent in the queue.
+func (p *WorkerPool) recordWait(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Waited++
+	p.stats.WaitTime += d
+	if d > p.stats.MaxWait {
+		p.stats.MaxWait = d
+	}
+}
@@ -0,0 +1,42 @@
+package crazypdf
+
+// RepairReport describes how a damaged document opened with WithRepair
+// or BackendRebuild was recovered.
+type RepairReport struct {
+	// Objects is the number of objects recovered and SkippedObjects the
+	// numbers of objects whose definitions were found but could not be
+	// parsed.
+	Objects        int
+	SkippedObjects []int
+
+	// Pages is the number of pages recovered and SkippedPages the number
+	// of pages left out because their page object was missing or
+	// damaged.
+	Pages        int
+	SkippedPages int
+
+	// RebuiltTrailer reports that the trailer named no usable catalog and
+	// one was found by scanning or created; RebuiltPageTree that the page tree was unreadable and the pages were
+	// collected from the page objects found, in object number order,
+	// which is usually but not always the original order.
+	RebuiltTrailer  bool
+	RebuiltPageTree bool
+}
+
+// RepairReport returns how the document was recovered when it was opened
+// by BackendRebuild, with WithRepair after it failed to open normally or
+// chosen with WithBackend, or nil otherwise.
+func (d *Document) RepairReport() *RepairReport {
+	r := d.reader.RepairReport()
+	if r == nil {
+		return nil
+	}
+	return &RepairReport{
+		Objects:         r.Objects,
+		SkippedObjects:  r.SkippedObjects,
+		Pages:           r.Pages,
+		SkippedPages:    r.SkippedPages,
+		RebuiltTrailer:  r.RebuiltTrailer,
+		RebuiltPageTree: r.RebuiltPageTree,
+	}
+}
@@ -0,0 +1,87 @@
+package crazypdf
+
+import (
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Revision is a saved state of a document. Incremental updates append
+// changes to the end of a file and leave the bytes before them intact,
+// so each earlier state can still be read: the original file and one
+// revision per update.
+type Revision struct {
+	// Number is the 1-based revision number, 1 being the original file.
+	Number int
+
+	// Size is the length of the revision in bytes: the revision is the
+	// first Size bytes of the file.
+	Size int64
+
+	// Added, Modified and Deleted are the numbers of the objects the
+	// revision added, changed and removed compared to the previous one.
+	// For the first revision every object counts as added.
+	Added    []int
+	Modified []int
+	Deleted  []int
+
+	// Signatures is the number of digital signatures that sign the file
+	// as of this revision. Revisions after the last signed one were made
+	// after signing; whether such changes are permitted depends on the
+	// signature's modification policy.
+	Signatures int
+}
+
+// Revisions returns the revisions of the document, oldest first. A file
+// that was never incrementally updated has one revision; one whose
+// cross-reference data is too damaged to parse, opened with WithRepair,
+// has none. For encrypted and repaired documents the revisions are those
+// of the file as stored.
+func (d *Document) Revisions() ([]Revision, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	data, err := d.reader.Original()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	revs := internalpdf.Revisions(data)
+	out := make([]Revision, len(revs))
+	for i, r := range revs {
+		out[i] = Revision{
+			Number:     i + 1,
+			Size:       r.Size,
+			Added:      r.Added,
+			Modified:   r.Modified,
+			Deleted:    r.Deleted,
+			Signatures: r.Signatures,
+		}
+	}
+	return out, nil
+}
+
+// OpenRevision opens the document as of an earlier revision, numbered as
+// by Revisions, for example to see what a document looked like when it
+// was signed. The revision is opened from memory with the options of d,
+// including its password, followed by opts.
+func (d *Document) OpenRevision(number int, opts ...Option) (*Document, error) {
+	revs, err := d.Revisions()
+	if err != nil {
+		return nil, err
+	}
+	if number < 1 || number > len(revs) {
+		return nil, fmt.Errorf("revision %d out of range: document has %d revisions", number, len(revs))
+	}
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	data, err := d.reader.Original()
+	d.release()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	cfg := *d.config
+	inherit := func(c *Config) { *c = cfg }
+	return OpenBytes(data[:revs[number-1].Size], append([]Option{inherit}, opts...)...)
+}
@@ -0,0 +1,175 @@
+package crazypdf
+
+import (
+	"fmt"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Permissions are the operations the author of an encrypted document
+// allows. They are recorded in the document and honored by viewers; this
+// library does not enforce them. A user who opens the document with the
+// owner password is granted every permission.
+type Permissions struct {
+	Print                   bool // print, possibly at low resolution
+	PrintHighQuality        bool // print at full resolution
+	Modify                  bool // change the contents
+	Copy                    bool // copy or extract text and graphics
+	Annotate                bool // add or change annotations and fill form fields
+	FillForms               bool // fill form fields, even when Annotate is denied
+	ExtractForAccessibility bool // extract text and graphics for accessibility tools
+	Assemble                bool // insert, rotate and delete pages, create bookmarks
+}
+
+// Denied returns the names of the denied permissions, such as "print"
+// and "copy", in the order of the Permissions fields.
+func (p Permissions) Denied() []string {
+	var denied []string
+	for _, perm := range []struct {
+		name    string
+		allowed bool
+	}{
+		{"print", p.Print},
+		{"print-high-quality", p.PrintHighQuality},
+		{"modify", p.Modify},
+		{"copy", p.Copy},
+		{"annotate", p.Annotate},
+		{"fill-forms", p.FillForms},
+		{"extract-for-accessibility", p.ExtractForAccessibility},
+		{"assemble", p.Assemble},
+	} {
+		if !perm.allowed {
+			denied = append(denied, perm.name)
+		}
+	}
+	return denied
+}
+
+// allPermissions grants every operation.
+var allPermissions = Permissions{true, true, true, true, true, true, true, true}
+
+// Encryption describes how a document is protected.
+type Encryption struct {
+	// Encrypted reports whether the document is encrypted. The other
+	// fields are zero when it is not, except Permissions, which then
+	// allows everything.
+	Encrypted bool
+
+	// Filter is the security handler, usually Standard for password
+	// protection.
+	Filter string
+
+	// Algorithm is the cipher: RC4, AES or None (for documents that only
+	// carry permissions); empty when unknown.
+	Algorithm string
+
+	// KeyLength is the key length in bits, such as 40, 128 or 256.
+	KeyLength int
+
+	// Revision is the revision of the standard security handler, from 2
+	// to 6.
+	Revision int
+
+	// Permissions are the operations allowed to users who open the
+	// document with the user password.
+	Permissions Permissions
+
+	// MetadataEncrypted reports whether the XMP metadata stream is
+	// encrypted. Indexers can read unencrypted metadata without a
+	// password.
+	MetadataEncrypted bool
+}
+
+// String returns a short description such as "AES-128, denies copy,
+// modify".
+func (e *Encryption) String() string {
+	if !e.Encrypted {
+		return "none"
+	}
+	desc := e.Algorithm
+	if desc == "" {
+		desc = "unknown algorithm"
+	}
+	if e.KeyLength > 0 && e.Algorithm != "None" {
+		desc = fmt.Sprintf("%s-%d", desc, e.KeyLength)
+	}
+	if denied := e.Permissions.Denied(); len(denied) > 0 {
+		desc += ", denies " + strings.Join(denied, ", ")
+	}
+	return desc
+}
+
+// Encryption returns whether the document is encrypted, the algorithm
+// used and the permissions granted to users, so that callers can decide
+// whether text extraction is allowed before doing it.
+func (d *Document) Encryption() (*Encryption, error) {
+	if d.IsClosed() {
+		return nil, ErrDocumentClosed
+	}
+	info, ok := d.reader.Encryption()
+	if !ok {
+		return &Encryption{Permissions: allPermissions}, nil
+	}
+	return newEncryption(info), nil
+}
+
+// newEncryption interprets an encryption dictionary.
+func newEncryption(info internalpdf.EncryptionInfo) *Encryption {
+	enc := &Encryption{
+		Encrypted:         true,
+		Filter:            info.Filter,
+		Revision:          info.R,
+		Permissions:       permissions(info.P, info.R),
+		MetadataEncrypted: info.EncryptMetadata,
+	}
+	switch info.V {
+	case 1:
+		enc.Algorithm, enc.KeyLength = "RC4", 40
+	case 2, 3:
+		enc.Algorithm, enc.KeyLength = "RC4", info.Length
+		if enc.KeyLength == 0 {
+			enc.KeyLength = 40
+		}
+	case 4:
+		switch info.StreamMethod {
+		case "V2":
+			enc.Algorithm, enc.KeyLength = "RC4", 128
+		case "AESV2":
+			enc.Algorithm, enc.KeyLength = "AES", 128
+		case "None":
+			enc.Algorithm = "None"
+		}
+	case 5:
+		enc.Algorithm, enc.KeyLength = "AES", 256
+		if info.StreamMethod == "None" {
+			enc.Algorithm, enc.KeyLength = "None", 0
+		}
+	}
+	return enc
+}
+
+// permissions decodes the /P flags. Revision 2 has no separate bits for
+// form filling, accessibility, assembly and high-quality printing; they
+// follow the annotate, copy, modify and print bits.
+func permissions(p int32, revision int) Permissions {
+	bit := func(n int) bool { return p&(1<<(n-1)) != 0 }
+	perms := Permissions{
+		Print:    bit(3),
+		Modify:   bit(4),
+		Copy:     bit(5),
+		Annotate: bit(6),
+	}
+	if revision < 3 {
+		perms.FillForms = perms.Annotate
+		perms.ExtractForAccessibility = perms.Copy
+		perms.Assemble = perms.Modify
+		perms.PrintHighQuality = perms.Print
+		return perms
+	}
+	perms.FillForms = bit(9)
+	perms.ExtractForAccessibility = bit(10)
+	perms.Assemble = bit(11)
+	perms.PrintHighQuality = perms.Print && bit(12)
+	return perms
+}
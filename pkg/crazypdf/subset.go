@@ -0,0 +1,122 @@
+package crazypdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// ExtractPages returns a new document made of the pages at the given
+// 0-based indices, in the order given; an index listed twice copies the
+// page. The document is built and opened in memory with the options of
+// d, and is independent of it: close each when done. The outline, page
+// labels, structure tree and open action describe the whole document and
+// are left out, as are links to pages left out. The new document is not
+// encrypted. Use SaveAs to store it, or split.BySections to cut a
+// document into files along its bookmarks.
+func (d *Document) ExtractPages(indices []int) (*Document, error) {
+	if len(indices) == 0 {
+		return nil, errors.New("no pages to extract")
+	}
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	data, err := d.extractPages(indices)
+	d.release()
+	if err != nil {
+		return nil, err
+	}
+	cfg := *d.config
+	inherit := func(c *Config) { *c = cfg }
+	return OpenBytes(data, inherit)
+}
+
+// extractPages serializes the document of the given pages.
+func (d *Document) extractPages(indices []int) ([]byte, error) {
+	n := d.reader.NumPages()
+	for _, idx := range indices {
+		if idx < 0 || idx >= n {
+			return nil, fmt.Errorf("%w: index %d, document has %d pages", ErrPageOutOfRange, idx, n)
+		}
+	}
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+	if err := editor.KeepPages(indices); err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := editor.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveAs stores the document at path as it was opened: the file as read
+// for opened documents, including any encryption, or the bytes built by
+// ExtractPages. With WithInitialView or WithUniqueID the document is
+// rewritten to apply them, which an encrypted document cannot be: SaveAs
+// then fails with ErrInvalidConfig rather than drop its encryption. The
+// metadata is kept as it is. The file is written atomically as by
+// WriteFile.
+func (d *Document) SaveAs(path string, opts ...WriteOption) (*WriteResult, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	writeCfg := applyWriteOptions(opts)
+	if writeCfg.InitialView == nil && !writeCfg.UniqueID {
+		data, err := d.reader.Original()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PDF: %w", err)
+		}
+		return WriteFile(path, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}, opts...)
+	}
+
+	if d.reader.Encrypted() {
+		return nil, fmt.Errorf("%w: an encrypted document cannot be saved with an initial view or unique ID", ErrInvalidConfig)
+	}
+	file, err := d.reader.RawFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+	editor.UniqueID = writeCfg.UniqueID
+	if err := ApplyInitialView(editor, writeCfg.InitialView); err != nil {
+		return nil, fmt.Errorf("failed to set initial view: %w", err)
+	}
+	return WriteFile(path, func(w io.Writer) error {
+		_, err := editor.WriteTo(w)
+		return err
+	}, opts...)
+}
+
+// WriteTo writes the document to w as SaveAs stores it, so it can be
+// handed to other PDF libraries, such as pdfcpu's api.ReadContext,
+// without reading the file again. It implements io.WriterTo.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if err := d.acquire(); err != nil {
+		return 0, err
+	}
+	defer d.release()
+	data, err := d.reader.Original()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
@@ -0,0 +1,59 @@
+package crazypdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // decode JPEG thumbnails
+	_ "image/png"  // decode PNG thumbnails
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// Thumbnail is the preview image some writers embed for a page.
+type Thumbnail struct {
+	// Width and Height are the dimensions of the image in pixels.
+	Width, Height int
+
+	// Data is the image encoded as JPEG or PNG, and MIMEType its type:
+	// "image/jpeg" or "image/png". JPEG thumbnails are passed through as
+	// stored; others are converted to PNG.
+	Data     []byte
+	MIMEType string
+}
+
+// Image decodes the thumbnail.
+func (t *Thumbnail) Image() (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(t.Data))
+	return img, err
+}
+
+// Thumbnail returns the thumbnail image embedded for this page, or nil
+// when there is none, for cheap previews without rendering the page.
+// Few writers still embed thumbnails; viewers generate their own.
+func (p *Page) Thumbnail() (*Thumbnail, error) {
+	if err := p.doc.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.doc.release()
+	file, page, _, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+	thumb := file.PageThumbnail(page)
+	if thumb == nil {
+		return nil, nil
+	}
+	data, mime, err := internalpdf.EncodeImage(thumb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode thumbnail of page %d: %w", p.Number, err)
+	}
+	out := &Thumbnail{Data: data, MIMEType: mime}
+	if w, ok := thumb.Dict["Width"].(int64); ok {
+		out.Width = int(w)
+	}
+	if h, ok := thumb.Dict["Height"].(int64); ok {
+		out.Height = int(h)
+	}
+	return out, nil
+}
@@ -0,0 +1,91 @@
+package crazypdf
+
+import "context"
+
+// tracerName is the instrumentation name passed to TracerProvider.Tracer.
+const tracerName = "github.com/ayushanand18/crazypdf"
+
+// TracerProvider creates tracers for instrumentation. Its methods mirror
+// OpenTelemetry's trace.TracerProvider, Tracer and Span, so this module
+// does not depend on OpenTelemetry itself; the oteltrace module adapts an
+// OpenTelemetry TracerProvider to it.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and
+	// returns a context holding the new span.
+	Start(ctx context.Context, spanName string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation being traced.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a span attribute. Value is a string, int, int64, float64
+// or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// WithTracerProvider records spans for opening documents and, in feature
+// modules such as extract, for extracting text, with page counts and byte
+// sizes as attributes. Without it nothing is traced.
+func WithTracerProvider(tp TracerProvider) Option {
+	return func(c *Config) {
+		c.TracerProvider = tp
+	}
+}
+
+// StartSpan starts a span with the document's tracer provider, or returns
+// a span that does nothing when the document was opened without
+// WithTracerProvider. It is intended for use by feature modules (e.g.,
+// extract).
+func (d *Document) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return d.config.startSpan(ctx, name, attrs...)
+}
+
+// StartSpan starts a span with the tracer provider of the page's document,
+// like Document.StartSpan.
+func (p *Page) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return p.doc.StartSpan(ctx, name, attrs...)
+}
+
+// startSpan starts a span with the configured tracer provider.
+func (c *Config) startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if c.TracerProvider == nil {
+		return ctx, noopSpan{}
+	}
+	return c.TracerProvider.Tracer(tracerName).Start(ctx, name, attrs...)
+}
+
+// traceOpen runs open in a "crazypdf.Open" span describing the source and
+// the opened document.
+func (c *Config) traceOpen(ctx context.Context, source string, open func() (*Document, error)) (*Document, error) {
+	_, span := c.startSpan(ctx, "crazypdf.Open", Attribute{"pdf.source", source})
+	defer span.End()
+	doc, err := open()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(
+		Attribute{"pdf.pages", doc.NumPages()},
+		Attribute{"pdf.size_bytes", doc.reader.Size()},
+		Attribute{"pdf.encrypted", doc.reader.Encrypted()},
+	)
+	return doc, nil
+}
+
+// noopSpan is the span of documents opened without a tracer provider.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
@@ -0,0 +1,18 @@
+package crazypdf
+
+// Validate checks the structure of the document: that every object of
+// the cross-reference table can be read where it is recorded, that
+// streams have a /Length matching their data, that references point at
+// objects that exist, and that the catalog and the page tree have their
+// required entries, with kids, counts and parents that agree. It returns
+// the problems found with their severity, objects in number order first
+// and then the page tree in page order, or none for a sound file. The
+// file is checked as stored: encrypted documents without decrypting them
+// and documents opened with WithRepair before their repair.
+func (d *Document) Validate() ([]Problem, error) {
+	if err := d.acquire(); err != nil {
+		return nil, err
+	}
+	defer d.release()
+	return d.reader.Validate(), nil
+}
@@ -0,0 +1,5 @@
+package crazypdf
+
+// Version is the version of the crazypdf library, recorded in the
+// provenance of exports.
+const Version = "0.1.0"
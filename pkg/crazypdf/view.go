@@ -0,0 +1,459 @@
+package crazypdf
+
+import (
+	"fmt"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+)
+
+// PageLayout is the page arrangement a viewer uses when the document is
+// opened.
+type PageLayout string
+
+const (
+	PageLayoutSinglePage     PageLayout = "SinglePage"     // one page at a time
+	PageLayoutOneColumn      PageLayout = "OneColumn"      // pages in one continuous column
+	PageLayoutTwoColumnLeft  PageLayout = "TwoColumnLeft"  // two columns, odd pages on the left
+	PageLayoutTwoColumnRight PageLayout = "TwoColumnRight" // two columns, odd pages on the right
+	PageLayoutTwoPageLeft    PageLayout = "TwoPageLeft"    // two pages at a time, odd pages on the left
+	PageLayoutTwoPageRight   PageLayout = "TwoPageRight"   // two pages at a time, odd pages on the right
+)
+
+// PageMode is the panel a viewer shows next to the pages when the
+// document is opened.
+type PageMode string
+
+const (
+	PageModeUseNone        PageMode = "UseNone"        // no panel
+	PageModeUseOutlines    PageMode = "UseOutlines"    // bookmarks panel
+	PageModeUseThumbs      PageMode = "UseThumbs"      // page thumbnails
+	PageModeFullScreen     PageMode = "FullScreen"     // full-screen, no menus or panels
+	PageModeUseOC          PageMode = "UseOC"          // optional content (layers) panel
+	PageModeUseAttachments PageMode = "UseAttachments" // attachments panel
+)
+
+// Destination is a page and how it is fitted in the viewer window.
+type Destination struct {
+	// Page is the 1-based page number.
+	Page int
+
+	// Fit is the fit type: XYZ, Fit, FitH, FitV, FitR, FitB, FitBH or
+	// FitBV. Empty means Fit.
+	Fit string
+
+	// Params are the numeric parameters of the fit type, such as left,
+	// top and zoom for XYZ. NaN keeps the viewer's current value.
+	Params []float64
+}
+
+// OpenAction is the action a viewer performs when the document is opened.
+type OpenAction struct {
+	// Type is the action type: GoTo, URI, JavaScript, Named, or another
+	// action type for actions that are only reported.
+	Type string
+
+	// Dest is the destination of GoTo actions.
+	Dest *Destination
+
+	// Target is the URI of URI actions, the script of JavaScript actions
+	// and the action name (such as NextPage or Print) of Named actions.
+	// For Launch actions it is the file or application launched and for
+	// SubmitForm actions the URL form data is sent to.
+	Target string
+
+	// Next are the actions the viewer performs after this one, in order.
+	// They are read from documents but not written.
+	Next []*OpenAction
+}
+
+// Print scaling values for ViewerPreferences.PrintScaling.
+const (
+	PrintScalingAppDefault = "AppDefault" // the viewer's default scaling, usually shrink to fit
+	PrintScalingNone       = "None"       // print at actual size
+)
+
+// Duplex values for ViewerPreferences.Duplex.
+const (
+	DuplexSimplex       = "Simplex"             // print single-sided
+	DuplexFlipShortEdge = "DuplexFlipShortEdge" // double-sided, flipped on the short edge
+	DuplexFlipLongEdge  = "DuplexFlipLongEdge"  // double-sided, flipped on the long edge
+)
+
+// ViewerPreferences holds the viewer window and print dialog settings of
+// the document. False and empty fields are unset and leave the choice to
+// the viewer.
+type ViewerPreferences struct {
+	HideToolbar     bool
+	HideMenubar     bool
+	HideWindowUI    bool
+	FitWindow       bool // resize the window to the first page
+	CenterWindow    bool
+	DisplayDocTitle bool // show the document title instead of the file name
+
+	// PrintScaling is the print dialog's default page scaling:
+	// PrintScalingAppDefault or PrintScalingNone.
+	PrintScaling string
+
+	// Duplex is the print dialog's default paper handling: DuplexSimplex,
+	// DuplexFlipShortEdge or DuplexFlipLongEdge.
+	Duplex string
+
+	// NumCopies is the print dialog's default number of copies; 0 is
+	// unset.
+	NumCopies int
+
+	// NonFullScreenPageMode is the panel shown when leaving full-screen
+	// mode, for documents that open with PageModeFullScreen:
+	// PageModeUseNone, PageModeUseOutlines, PageModeUseThumbs or
+	// PageModeUseOC.
+	NonFullScreenPageMode PageMode
+
+	// Direction is the reading order, L2R or R2L, which decides on which
+	// side viewers put the first of two pages shown side by side.
+	Direction string
+
+	// PickTrayByPDFSize makes the print dialog choose the paper tray by
+	// page size.
+	PickTrayByPDFSize bool
+
+	// PrintPageRange is the print dialog's default selection of pages, as
+	// pairs of first and last 1-based page numbers.
+	PrintPageRange []int
+}
+
+// InitialView describes how a viewer presents the document when it is
+// opened. Empty fields are unset.
+type InitialView struct {
+	Layout      PageLayout
+	Mode        PageMode
+	OpenAction  *OpenAction
+	Preferences *ViewerPreferences
+
+	// Triggers are the actions of the catalog /AA entry, which viewers run
+	// on document events, by trigger: WC (will close), WS (will save), DS
+	// (did save), WP (will print) and DP (did print). They are read from
+	// documents but not written.
+	Triggers map[string]*OpenAction
+
+	// Scripts are the names of the document-level JavaScript actions,
+	// which viewers run when the document is opened. They are read from
+	// documents but not written.
+	Scripts []string
+}
+
+// AutoExecutes reports whether viewers run actions other than going to a
+// destination without the user asking: an open action of another type,
+// such as JavaScript, Launch or URI, or chaining one, a document-level
+// script, or such an action triggered by saving, printing or closing the
+// document. Archiving and intake systems can use it to flag documents
+// for review or for audit.StripActive.
+func (v *InitialView) AutoExecutes() bool {
+	var active func(a *OpenAction) bool
+	active = func(a *OpenAction) bool {
+		if a == nil {
+			return false
+		}
+		if a.Type != "GoTo" {
+			return true
+		}
+		for _, next := range a.Next {
+			if active(next) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(v.Scripts) > 0 || active(v.OpenAction) {
+		return true
+	}
+	for _, a := range v.Triggers {
+		if active(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// InitialView returns the page layout, page mode, viewer preferences and
+// open action of the document, with the actions it runs on document
+// events and its document-level scripts.
+func (d *Document) InitialView() (*InitialView, error) {
+	file, err := d.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+
+	view := &InitialView{}
+	if layout, ok := file.Resolve(catalog["PageLayout"]).(internalpdf.Name); ok {
+		view.Layout = PageLayout(layout)
+	}
+	if mode, ok := file.Resolve(catalog["PageMode"]).(internalpdf.Name); ok {
+		view.Mode = PageMode(mode)
+	}
+	if prefs, ok := file.Resolve(catalog["ViewerPreferences"]).(internalpdf.Dict); ok {
+		view.Preferences = readViewerPreferences(prefs, file.Resolve)
+	}
+	aa, _ := file.Resolve(catalog["AA"]).(internalpdf.Dict)
+	if catalog["OpenAction"] != nil || len(aa) > 0 {
+		refs, err := file.PageRefs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page tree: %w", err)
+		}
+		r := &actionReader{catalog: catalog, refs: refs, resolve: file.Resolve, seen: make(map[int]bool)}
+		view.OpenAction = r.openAction()
+		for trigger, o := range aa {
+			r.seen = make(map[int]bool)
+			if a := r.action(o, 0); a != nil {
+				if view.Triggers == nil {
+					view.Triggers = make(map[string]*OpenAction)
+				}
+				view.Triggers[string(trigger)] = a
+			}
+		}
+	}
+	view.Scripts = internalpdf.DocumentScripts(catalog, file.Resolve)
+	return view, nil
+}
+
+// maxActionChain bounds how deep /Next chains of actions are followed.
+const maxActionChain = 16
+
+// actionReader converts the actions of a document.
+type actionReader struct {
+	catalog internalpdf.Dict
+	refs    []internalpdf.Ref
+	resolve func(internalpdf.Object) internalpdf.Object
+	seen    map[int]bool // action objects of the current chain, against cycles
+}
+
+// openAction converts the catalog /OpenAction, which is either a
+// destination array or an action dictionary.
+func (r *actionReader) openAction() *OpenAction {
+	o := r.catalog["OpenAction"]
+	if _, ok := r.resolve(o).(internalpdf.Dict); ok {
+		return r.action(o, 0)
+	}
+	dest, ok := internalpdf.ResolveDest(r.catalog, o, r.refs, r.resolve)
+	if !ok {
+		return nil
+	}
+	return &OpenAction{Type: "GoTo", Dest: destination(dest)}
+}
+
+// action converts an action dictionary and the actions chained to it
+// through /Next, or returns nil for anything else.
+func (r *actionReader) action(o internalpdf.Object, depth int) *OpenAction {
+	if ref, ok := o.(internalpdf.Ref); ok {
+		if r.seen[ref.Num] {
+			return nil
+		}
+		r.seen[ref.Num] = true
+	}
+	action, ok := r.resolve(o).(internalpdf.Dict)
+	if !ok || depth > maxActionChain {
+		return nil
+	}
+	resolve := r.resolve
+
+	kind, _ := resolve(action["S"]).(internalpdf.Name)
+	out := &OpenAction{Type: string(kind)}
+	switch kind {
+	case "GoTo":
+		if dest, ok := internalpdf.ResolveDest(r.catalog, action["D"], r.refs, resolve); ok {
+			out.Dest = destination(dest)
+		}
+	case "URI":
+		if uri, ok := resolve(action["URI"]).(internalpdf.String); ok {
+			out.Target = string(uri)
+		}
+	case "JavaScript":
+		switch js := resolve(action["JS"]).(type) {
+		case internalpdf.String:
+			out.Target = internalpdf.DecodeTextString(js)
+		case *internalpdf.Stream:
+			if data, err := internalpdf.DecodeStream(js, resolve); err == nil {
+				out.Target = string(data)
+			}
+		}
+	case "Named":
+		if name, ok := resolve(action["N"]).(internalpdf.Name); ok {
+			out.Target = string(name)
+		}
+	case "Launch":
+		out.Target = fileSpecName(action["F"], resolve)
+		if out.Target == "" {
+			win, _ := resolve(action["Win"]).(internalpdf.Dict)
+			out.Target = fileSpecName(win["F"], resolve)
+		}
+	case "SubmitForm":
+		out.Target = fileSpecName(action["F"], resolve)
+	}
+
+	next := []internalpdf.Object{action["Next"]}
+	if arr, ok := resolve(action["Next"]).(internalpdf.Array); ok {
+		next = arr
+	}
+	for _, o := range next {
+		if a := r.action(o, depth+1); a != nil {
+			out.Next = append(out.Next, a)
+		}
+	}
+	return out
+}
+
+// fileSpecName returns the file name or URL of a file specification,
+// which is a string or a dictionary.
+func fileSpecName(o internalpdf.Object, resolve func(internalpdf.Object) internalpdf.Object) string {
+	switch v := resolve(o).(type) {
+	case internalpdf.String:
+		return internalpdf.DecodeTextString(v)
+	case internalpdf.Dict:
+		for _, key := range []internalpdf.Name{"UF", "F"} {
+			if s, ok := resolve(v[key]).(internalpdf.String); ok {
+				return internalpdf.DecodeTextString(s)
+			}
+		}
+	}
+	return ""
+}
+
+// readViewerPreferences converts a /ViewerPreferences dictionary.
+func readViewerPreferences(d internalpdf.Dict, resolve func(internalpdf.Object) internalpdf.Object) *ViewerPreferences {
+	flag := func(key internalpdf.Name) bool {
+		v, _ := resolve(d[key]).(bool)
+		return v
+	}
+	name := func(key internalpdf.Name) string {
+		v, _ := resolve(d[key]).(internalpdf.Name)
+		return string(v)
+	}
+	prefs := &ViewerPreferences{
+		HideToolbar:     flag("HideToolbar"),
+		HideMenubar:     flag("HideMenubar"),
+		HideWindowUI:    flag("HideWindowUI"),
+		FitWindow:       flag("FitWindow"),
+		CenterWindow:    flag("CenterWindow"),
+		DisplayDocTitle: flag("DisplayDocTitle"),
+		PrintScaling:    name("PrintScaling"),
+		Duplex:          name("Duplex"),
+
+		NonFullScreenPageMode: PageMode(name("NonFullScreenPageMode")),
+		Direction:             name("Direction"),
+		PickTrayByPDFSize:     flag("PickTrayByPDFSize"),
+	}
+	if n, ok := resolve(d["NumCopies"]).(int64); ok {
+		prefs.NumCopies = int(n)
+	}
+	ranges, _ := resolve(d["PrintPageRange"]).(internalpdf.Array)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		first, ok1 := resolve(ranges[i]).(int64)
+		last, ok2 := resolve(ranges[i+1]).(int64)
+		if ok1 && ok2 {
+			prefs.PrintPageRange = append(prefs.PrintPageRange, int(first), int(last))
+		}
+	}
+	return prefs
+}
+
+// viewerPreferencesDict builds a /ViewerPreferences dictionary holding
+// the set fields of p.
+func viewerPreferencesDict(p *ViewerPreferences) internalpdf.Dict {
+	d := internalpdf.Dict{}
+	for key, set := range map[internalpdf.Name]bool{
+		"HideToolbar":       p.HideToolbar,
+		"HideMenubar":       p.HideMenubar,
+		"HideWindowUI":      p.HideWindowUI,
+		"FitWindow":         p.FitWindow,
+		"CenterWindow":      p.CenterWindow,
+		"DisplayDocTitle":   p.DisplayDocTitle,
+		"PickTrayByPDFSize": p.PickTrayByPDFSize,
+	} {
+		if set {
+			d[key] = true
+		}
+	}
+	if p.PrintScaling != "" {
+		d["PrintScaling"] = internalpdf.Name(p.PrintScaling)
+	}
+	if p.Duplex != "" {
+		d["Duplex"] = internalpdf.Name(p.Duplex)
+	}
+	if p.NumCopies > 0 {
+		d["NumCopies"] = p.NumCopies
+	}
+	if p.NonFullScreenPageMode != "" {
+		d["NonFullScreenPageMode"] = internalpdf.Name(p.NonFullScreenPageMode)
+	}
+	if p.Direction != "" {
+		d["Direction"] = internalpdf.Name(p.Direction)
+	}
+	if len(p.PrintPageRange) > 0 {
+		ranges := make(internalpdf.Array, len(p.PrintPageRange))
+		for i, n := range p.PrintPageRange {
+			ranges[i] = int64(n)
+		}
+		d["PrintPageRange"] = ranges
+	}
+	return d
+}
+
+// destination converts an internal destination.
+func destination(d internalpdf.Dest) *Destination {
+	return &Destination{Page: d.Page, Fit: string(d.Fit), Params: d.Params}
+}
+
+// ApplyInitialView sets the page layout, page mode, viewer preferences
+// and open action of a document being rewritten; empty fields keep the
+// source document's values. Set preferences replace the source
+// document's preferences as a whole. Only GoTo, URI, JavaScript and Named
+// open actions can be written. It is intended for use by writer modules
+// (e.g., redact).
+func ApplyInitialView(e *internalpdf.Editor, view *InitialView) error {
+	if view == nil {
+		return nil
+	}
+	if view.Layout != "" {
+		e.SetCatalogEntry("PageLayout", internalpdf.Name(view.Layout))
+	}
+	if view.Mode != "" {
+		e.SetCatalogEntry("PageMode", internalpdf.Name(view.Mode))
+	}
+	if view.Preferences != nil {
+		e.SetCatalogEntry("ViewerPreferences", viewerPreferencesDict(view.Preferences))
+	}
+	if view.OpenAction == nil {
+		return nil
+	}
+
+	a := view.OpenAction
+	var action internalpdf.Object
+	switch a.Type {
+	case "GoTo", "":
+		if a.Dest == nil {
+			return fmt.Errorf("GoTo open action has no destination")
+		}
+		refs, err := e.PageRefs()
+		if err != nil {
+			return fmt.Errorf("failed to read page tree: %w", err)
+		}
+		if a.Dest.Page < 1 || a.Dest.Page > len(refs) {
+			return fmt.Errorf("%w: open action page %d, document has %d pages", ErrPageOutOfRange, a.Dest.Page, len(refs))
+		}
+		fit := a.Dest.Fit
+		if fit == "" {
+			fit = "Fit"
+		}
+		action = internalpdf.DestArray(refs[a.Dest.Page-1], internalpdf.Name(fit), a.Dest.Params)
+	case "URI":
+		action = internalpdf.Dict{"S": internalpdf.Name("URI"), "URI": internalpdf.String(a.Target)}
+	case "JavaScript":
+		action = internalpdf.Dict{"S": internalpdf.Name("JavaScript"), "JS": internalpdf.TextString(a.Target)}
+	case "Named":
+		action = internalpdf.Dict{"S": internalpdf.Name("Named"), "N": internalpdf.Name(a.Target)}
+	default:
+		return fmt.Errorf("cannot write %s open action", a.Type)
+	}
+	e.SetCatalogEntry("OpenAction", action)
+	return nil
+}
@@ -0,0 +1,281 @@
+// Package diff compares the extracted text of PDF documents.
+//
+// It aligns pages by position, computes a line-level edit script for each
+// page pair, and summarizes the changes as unified diff hunks and word
+// statistics.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+// Hunk is a group of nearby line edits with surrounding context, in the
+// style of a unified diff.
+type Hunk struct {
+	// OldStart and NewStart are 1-based line numbers of the hunk start.
+	OldStart int `json:"oldStart"`
+	OldLines int `json:"oldLines"`
+	NewStart int `json:"newStart"`
+	NewLines int `json:"newLines"`
+
+	Edits []Edit `json:"edits"`
+}
+
+// PageDiff describes the differences on one page.
+type PageDiff struct {
+	// Page is the 1-based page number.
+	Page int `json:"page"`
+
+	// Status is "unchanged", "changed", "added" or "removed". Pages are
+	// added or removed when the documents have different page counts.
+	Status string `json:"status"`
+
+	Hunks        []Hunk `json:"hunks,omitempty"`
+	WordsAdded   int    `json:"wordsAdded"`
+	WordsRemoved int    `json:"wordsRemoved"`
+}
+
+// Changed reports whether the page differs between the documents.
+func (p PageDiff) Changed() bool {
+	return p.Status != StatusUnchanged
+}
+
+// Page statuses reported in PageDiff.Status.
+const (
+	StatusUnchanged = "unchanged"
+	StatusChanged   = "changed"
+	StatusAdded     = "added"
+	StatusRemoved   = "removed"
+)
+
+// Stats summarizes a document comparison.
+type Stats struct {
+	PagesCompared int `json:"pagesCompared"`
+	PagesChanged  int `json:"pagesChanged"`
+	PagesAdded    int `json:"pagesAdded"`
+	PagesRemoved  int `json:"pagesRemoved"`
+	WordsAdded    int `json:"wordsAdded"`
+	WordsRemoved  int `json:"wordsRemoved"`
+}
+
+// Result is the outcome of comparing two documents.
+type Result struct {
+	Pages []PageDiff `json:"pages"`
+	Stats Stats      `json:"stats"`
+}
+
+// Documents compares the text of two documents page by page.
+func Documents(a, b *crazypdf.Document, opts ...Option) (*Result, error) {
+	cfg := applyOptions(opts)
+	extractOpts := cfg.ExtractOptions
+	if cfg.Normalize {
+		extractOpts = append(extractOpts[:len(extractOpts):len(extractOpts)], extract.WithLayout(extract.LayoutNormalized))
+	}
+
+	textsA, err := extract.AllPages(a, extractOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract old document: %w", err)
+	}
+	textsB, err := extract.AllPages(b, extractOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract new document: %w", err)
+	}
+	return PageTexts(textsA, textsB, opts...), nil
+}
+
+// PageTexts compares two documents given as per-page text. Pages are
+// aligned by index; surplus pages are reported as added or removed.
+func PageTexts(a, b []string, opts ...Option) *Result {
+	cfg := applyOptions(opts)
+
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	result := &Result{Pages: make([]PageDiff, 0, n)}
+	for i := 0; i < n; i++ {
+		var oldText, newText string
+		status := StatusChanged
+		switch {
+		case i >= len(a):
+			newText = b[i]
+			status = StatusAdded
+			result.Stats.PagesAdded++
+		case i >= len(b):
+			oldText = a[i]
+			status = StatusRemoved
+			result.Stats.PagesRemoved++
+		default:
+			oldText, newText = a[i], b[i]
+			result.Stats.PagesCompared++
+		}
+
+		if cfg.Normalize {
+			oldText, newText = extract.NormalizeText(oldText), extract.NormalizeText(newText)
+		}
+		pd := Text(oldText, newText, cfg.Context)
+		pd.Page = i + 1
+		if status != StatusChanged {
+			pd.Status = status
+		} else if pd.Changed() {
+			result.Stats.PagesChanged++
+		}
+
+		result.Stats.WordsAdded += pd.WordsAdded
+		result.Stats.WordsRemoved += pd.WordsRemoved
+		result.Pages = append(result.Pages, pd)
+	}
+	return result
+}
+
+// Text compares two texts line by line, grouping edits into hunks with
+// the given number of context lines.
+func Text(oldText, newText string, context int) PageDiff {
+	edits := Strings(splitLines(oldText), splitLines(newText))
+
+	pd := PageDiff{Status: StatusUnchanged}
+	var removed, added []string
+	for _, e := range edits {
+		switch e.Kind {
+		case OpDelete:
+			removed = append(removed, e.Text)
+		case OpInsert:
+			added = append(added, e.Text)
+		}
+	}
+	if len(removed) == 0 && len(added) == 0 {
+		return pd
+	}
+
+	pd.Status = StatusChanged
+	pd.Hunks = hunks(edits, context)
+
+	// Count words by diffing only the changed lines, so that moved or
+	// re-wrapped words are not double counted.
+	for _, e := range Strings(strings.Fields(strings.Join(removed, " ")), strings.Fields(strings.Join(added, " "))) {
+		switch e.Kind {
+		case OpDelete:
+			pd.WordsRemoved++
+		case OpInsert:
+			pd.WordsAdded++
+		}
+	}
+	return pd
+}
+
+// hunks groups an edit script into hunks with context lines around each
+// run of changes.
+func hunks(edits []Edit, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var result []Hunk
+	for i := 0; i < len(edits); {
+		if edits[i].Kind == OpEqual {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		for start < i && edits[start].Kind != OpEqual {
+			start++
+		}
+
+		// Extend the hunk while the next change lies within 2*context
+		// equal lines of the previous one.
+		end := i
+		for end < len(edits) {
+			if edits[end].Kind != OpEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Kind == OpEqual {
+				run++
+			}
+			if run < len(edits) && run-end <= 2*context {
+				end = run
+				continue
+			}
+			end += min(context, run-end)
+			break
+		}
+
+		result = append(result, newHunk(edits[start:end]))
+		i = end
+	}
+	return result
+}
+
+// newHunk computes line ranges for a slice of edits.
+func newHunk(edits []Edit) Hunk {
+	h := Hunk{Edits: edits}
+	for _, e := range edits {
+		if e.Kind != OpInsert {
+			if h.OldLines == 0 {
+				h.OldStart = e.OldIndex + 1
+			}
+			h.OldLines++
+		}
+		if e.Kind != OpDelete {
+			if h.NewLines == 0 {
+				h.NewStart = e.NewIndex + 1
+			}
+			h.NewLines++
+		}
+	}
+	return h
+}
+
+// Unified renders the page differences as a unified diff with the given
+// file labels.
+func (p PageDiff) Unified(oldName, newName string) string {
+	if len(p.Hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s (page %d)\n", oldName, p.Page)
+	fmt.Fprintf(&sb, "+++ %s (page %d)\n", newName, p.Page)
+	for _, h := range p.Hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+		for _, e := range h.Edits {
+			switch e.Kind {
+			case OpEqual:
+				sb.WriteString(" ")
+			case OpInsert:
+				sb.WriteString("+")
+			case OpDelete:
+				sb.WriteString("-")
+			}
+			sb.WriteString(e.Text)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange formats a unified diff line range.
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// splitLines splits text into lines, ignoring a trailing newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
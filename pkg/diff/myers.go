@@ -0,0 +1,123 @@
+package diff
+
+// OpKind identifies the kind of an edit operation.
+type OpKind int
+
+const (
+	// OpEqual marks an element present in both inputs.
+	OpEqual OpKind = iota
+
+	// OpInsert marks an element present only in the new input.
+	OpInsert
+
+	// OpDelete marks an element present only in the old input.
+	OpDelete
+)
+
+// String returns the lowercase name of the operation.
+func (k OpKind) String() string {
+	switch k {
+	case OpEqual:
+		return "equal"
+	case OpInsert:
+		return "insert"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the operation by name, so JSON output is readable.
+func (k OpKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Edit is a single element of an edit script.
+type Edit struct {
+	Kind OpKind `json:"kind"`
+	Text string `json:"text"`
+
+	// OldIndex and NewIndex are the 0-based positions of the element in
+	// the old and new inputs, or -1 when absent from that side.
+	OldIndex int `json:"oldIndex"`
+	NewIndex int `json:"newIndex"`
+}
+
+// Strings computes a minimal edit script turning a into b using Myers'
+// O(ND) algorithm.
+func Strings(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // move down (insertion)
+			} else {
+				x = v[offset+k-1] + 1 // move right (deletion)
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// Backtrack through the recorded frontiers
+	var edits []Edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		vd := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && vd[offset+k-1] < vd[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := vd[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, Edit{Kind: OpEqual, Text: a[x], OldIndex: x, NewIndex: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, Edit{Kind: OpInsert, Text: b[y], OldIndex: -1, NewIndex: y})
+		} else {
+			x--
+			edits = append(edits, Edit{Kind: OpDelete, Text: a[x], OldIndex: x, NewIndex: -1})
+		}
+	}
+
+	// Reverse into forward order
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
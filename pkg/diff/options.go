@@ -0,0 +1,56 @@
+package diff
+
+import "github.com/ayushanand18/crazypdf/pkg/extract"
+
+// config holds configuration for document comparison.
+type config struct {
+	Context        int // unchanged lines of context around each hunk
+	ExtractOptions []extract.Option
+	Normalize      bool // compare text in extract.LayoutNormalized
+}
+
+// Option is a functional option for configuring comparisons.
+type Option func(*config)
+
+// WithContext sets the number of unchanged lines shown around each hunk.
+// Default is 3.
+func WithContext(lines int) Option {
+	return func(c *config) {
+		c.Context = lines
+	}
+}
+
+// WithExtractOptions sets the text extraction options used for both
+// documents, such as the layout mode.
+func WithExtractOptions(opts ...extract.Option) Option {
+	return func(c *config) {
+		c.ExtractOptions = opts
+	}
+}
+
+// WithNormalization compares text normalized for diffing, as extracted
+// with extract.LayoutNormalized, so that reflowed whitespace, ligatures,
+// typographic quotes and reordered content streams are not reported as
+// changes. It overrides the layout set with WithExtractOptions. PageTexts
+// normalizes its inputs with extract.NormalizeText. Default is false.
+func WithNormalization(enabled bool) Option {
+	return func(c *config) {
+		c.Normalize = enabled
+	}
+}
+
+// defaultConfig returns the default comparison configuration.
+func defaultConfig() *config {
+	return &config{
+		Context: 3,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
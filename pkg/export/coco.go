@@ -0,0 +1,252 @@
+// Package export provides structured exports of PDF page content for
+// downstream tooling, such as COCO-style datasets for training layout
+// models.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+// COCODataset is a COCO object-detection dataset describing page images,
+// block and word boxes, and their category labels.
+type COCODataset struct {
+	// SchemaVersion is the export schema version; see SchemaVersion.
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+
+	Info        COCOInfo         `json:"info"`
+	Images      []COCOImage      `json:"images"`
+	Annotations []COCOAnnotation `json:"annotations"`
+	Categories  []COCOCategory   `json:"categories"`
+}
+
+// COCOInfo describes the dataset.
+type COCOInfo struct {
+	Description string `json:"description"`
+
+	// Provenance has one entry per document added, in the order they were
+	// added.
+	Provenance []*Provenance `json:"provenance,omitempty"`
+}
+
+// COCOImage describes one page image. The image itself is not produced by
+// the exporter; FileName names the file a renderer should write at the
+// configured DPI.
+type COCOImage struct {
+	ID       int    `json:"id"`
+	FileName string `json:"file_name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+
+	// Source and Page identify the PDF page the image was rendered from.
+	Source string `json:"source,omitempty"`
+	Page   int    `json:"page"`
+}
+
+// COCOAnnotation is a labelled box on a page image. BBox is
+// [x, y, width, height] in pixels with a top-left origin.
+type COCOAnnotation struct {
+	ID         int        `json:"id"`
+	ImageID    int        `json:"image_id"`
+	CategoryID int        `json:"category_id"`
+	BBox       [4]float64 `json:"bbox"`
+	Area       float64    `json:"area"`
+	IsCrowd    int        `json:"iscrowd"`
+	Text       string     `json:"text,omitempty"`
+
+	// StableID is the stable ID of the block or word annotated, as in
+	// extract.Block and crazypdf.Word, linking it to the same content in
+	// other exports. It is set from schema version 1.2.
+	StableID string `json:"stable_id,omitempty"`
+}
+
+// COCOCategory is a label class.
+type COCOCategory struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Supercategory string `json:"supercategory"`
+}
+
+// wordCategoryID is the category assigned to word annotations. Block
+// categories use extract.BlockType values offset by one.
+const wordCategoryID = int(extract.BlockFooter) + 2
+
+// COCOBuilder accumulates pages from one or more documents into a single
+// COCO dataset with unique image and annotation IDs.
+type COCOBuilder struct {
+	cfg     *config
+	dataset COCODataset
+}
+
+// NewCOCOBuilder returns an empty dataset builder.
+func NewCOCOBuilder(opts ...Option) *COCOBuilder {
+	b := &COCOBuilder{cfg: applyOptions(opts)}
+	b.dataset.SchemaVersion = b.cfg.schemaField()
+	b.dataset.Info.Description = "crazypdf layout dataset"
+	b.dataset.Images = []COCOImage{}
+	b.dataset.Annotations = []COCOAnnotation{}
+	for t := extract.BlockText; t <= extract.BlockFooter; t++ {
+		b.dataset.Categories = append(b.dataset.Categories, COCOCategory{
+			ID:            int(t) + 1,
+			Name:          t.String(),
+			Supercategory: "block",
+		})
+	}
+	b.dataset.Categories = append(b.dataset.Categories, COCOCategory{
+		ID:            wordCategoryID,
+		Name:          "word",
+		Supercategory: "text",
+	})
+	return b
+}
+
+// AddDocument adds every page of doc to the dataset.
+func (b *COCOBuilder) AddDocument(doc *crazypdf.Document) error {
+	if doc.IsClosed() {
+		return crazypdf.ErrDocumentClosed
+	}
+	if err := b.cfg.checkSchema(); err != nil {
+		return err
+	}
+
+	stem := "document"
+	if path := doc.FilePath(); path != "" {
+		stem = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	var prov *Provenance
+	if b.cfg.hasProvenance() {
+		var err error
+		if prov, err = newProvenance(doc, b.cfg); err != nil {
+			return err
+		}
+	}
+	for _, page := range doc.Pages() {
+		if err := b.addPage(page, stem, doc.FilePath()); err != nil {
+			return fmt.Errorf("failed to export page %d: %w", page.Number, err)
+		}
+	}
+	if prov != nil {
+		b.dataset.Info.Provenance = append(b.dataset.Info.Provenance, prov)
+	}
+	return nil
+}
+
+// addPage appends one page image and its annotations.
+func (b *COCOBuilder) addPage(page *crazypdf.Page, stem, source string) error {
+	box, err := page.MediaBox()
+	if err != nil {
+		return err
+	}
+
+	var extractOpts []extract.Option
+	if b.cfg.Analyzer != nil {
+		extractOpts = append(extractOpts, extract.WithLayoutAnalyzer(b.cfg.Analyzer))
+	}
+	blocks, err := extract.Blocks(page, extractOpts...)
+	if err != nil {
+		return err
+	}
+
+	scale := b.cfg.DPI / 72
+	image := COCOImage{
+		ID:       len(b.dataset.Images) + 1,
+		FileName: fmt.Sprintf(b.cfg.ImagePattern, stem, page.Number),
+		Width:    int(math.Round(box.Width() * scale)),
+		Height:   int(math.Round(box.Height() * scale)),
+		Source:   source,
+		Page:     page.Number,
+	}
+	b.dataset.Images = append(b.dataset.Images, image)
+
+	toPixels := func(r crazypdf.Rect) [4]float64 {
+		return [4]float64{
+			round2((r.X0 - box.X0) * scale),
+			round2((box.Y1 - r.Y1) * scale),
+			round2(r.Width() * scale),
+			round2(r.Height() * scale),
+		}
+	}
+
+	for _, block := range blocks {
+		b.annotate(image.ID, int(block.Type)+1, toPixels(block.BBox), block.Text, block.ID)
+		if !b.cfg.IncludeWords {
+			continue
+		}
+		for _, w := range block.Words {
+			b.annotate(image.ID, wordCategoryID, toPixels(w.BBox), w.S, w.ID)
+		}
+	}
+	return nil
+}
+
+// annotate appends an annotation with the next free ID, and the stable ID
+// of the content annotated when the schema version carries them.
+func (b *COCOBuilder) annotate(imageID, categoryID int, bbox [4]float64, text, stableID string) {
+	if !b.cfg.hasIDs() {
+		stableID = ""
+	}
+	b.dataset.Annotations = append(b.dataset.Annotations, COCOAnnotation{
+		ID:         len(b.dataset.Annotations) + 1,
+		ImageID:    imageID,
+		CategoryID: categoryID,
+		BBox:       bbox,
+		Area:       round2(bbox[2] * bbox[3]),
+		Text:       text,
+		StableID:   stableID,
+	})
+}
+
+// Dataset returns the accumulated dataset.
+func (b *COCOBuilder) Dataset() *COCODataset {
+	return &b.dataset
+}
+
+// Write encodes the accumulated dataset as indented JSON.
+func (b *COCOBuilder) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&b.dataset); err != nil {
+		return fmt.Errorf("failed to encode COCO dataset: %w", err)
+	}
+	return nil
+}
+
+// WriteFile encodes the accumulated dataset as indented JSON and stores it
+// at path atomically.
+func (b *COCOBuilder) WriteFile(path string) (*crazypdf.WriteResult, error) {
+	return crazypdf.WriteFile(path, b.Write, b.cfg.WriteOptions...)
+}
+
+// COCO writes a COCO-style dataset for a single document to w. Page images
+// are referenced by file name and must be rendered separately at the
+// configured DPI.
+func COCO(doc *crazypdf.Document, w io.Writer, opts ...Option) error {
+	b := NewCOCOBuilder(opts...)
+	if err := b.AddDocument(doc); err != nil {
+		return err
+	}
+	return b.Write(w)
+}
+
+// COCOFile writes a COCO-style dataset for a single document to path
+// atomically.
+func COCOFile(doc *crazypdf.Document, path string, opts ...Option) (*crazypdf.WriteResult, error) {
+	b := NewCOCOBuilder(opts...)
+	if err := b.AddDocument(doc); err != nil {
+		return nil, err
+	}
+	return b.WriteFile(path)
+}
+
+// round2 rounds to two decimal places to keep output compact.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
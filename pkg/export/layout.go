@@ -0,0 +1,190 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+// blockColors are the outline colors of block types in layout overlays.
+var blockColors = map[extract.BlockType]string{
+	extract.BlockText:    "#1f77b4",
+	extract.BlockHeading: "#d62728",
+	extract.BlockList:    "#9467bd",
+	extract.BlockFigure:  "#2ca02c",
+	extract.BlockTable:   "#ff7f0e",
+	extract.BlockHeader:  "#8c564b",
+	extract.BlockFooter:  "#8c564b",
+}
+
+// LayoutSVG writes an SVG overlay of the layout analysis of a page: word
+// boxes, lines, typed blocks, detected columns and tables drawn over the
+// page text and graphics. Each layer is a separate group with an id
+// ("graphics", "words", "lines", "blocks", "columns", "tables") so it can
+// be hidden when inspecting the output in a browser or editor.
+//
+// The built-in analyzer does not detect tables; the tables layer shows
+// BlockTable blocks from an analyzer set with WithLayoutAnalyzer.
+func LayoutSVG(page *crazypdf.Page, w io.Writer, opts ...Option) error {
+	cfg := applyOptions(opts)
+
+	box, err := page.MediaBox()
+	if err != nil {
+		return fmt.Errorf("failed to read page geometry: %w", err)
+	}
+	graphics, err := page.Graphics()
+	if err != nil {
+		return fmt.Errorf("failed to read page graphics: %w", err)
+	}
+	lines, err := extract.Lines(page)
+	if err != nil {
+		return fmt.Errorf("failed to read page text: %w", err)
+	}
+	var extractOpts []extract.Option
+	if cfg.Analyzer != nil {
+		extractOpts = append(extractOpts, extract.WithLayoutAnalyzer(cfg.Analyzer))
+	}
+	blocks, err := extract.Blocks(page, extractOpts...)
+	if err != nil {
+		return err
+	}
+
+	scale := cfg.DPI / 72
+	s := &svgCanvas{page: box}
+	fmt.Fprintf(&s.sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.2f %.2f">`+"\n",
+		box.Width()*scale, box.Height()*scale, box.Width(), box.Height())
+	s.sb.WriteString(`<rect width="100%" height="100%" fill="white"/>` + "\n")
+
+	s.group("graphics", `fill="#eee" stroke="#bbb" stroke-width="0.5"`)
+	for _, g := range graphics {
+		if g.Kind == crazypdf.GraphicImage {
+			s.rect(g.BBox, "", "image")
+		} else {
+			s.rect(g.BBox, `fill="none"`, "")
+		}
+	}
+	s.end()
+
+	s.group("words", `fill="none" stroke="#999" stroke-width="0.3"`)
+	for _, ln := range lines {
+		for _, word := range ln.Words {
+			size := word.FontSize
+			if size <= 0 {
+				size = word.BBox.Height()
+			}
+			fmt.Fprintf(&s.sb, `<text x="%.2f" y="%.2f" font-family="sans-serif" font-size="%.2f" textLength="%.2f" fill="#444" stroke="none">%s</text>`+"\n",
+				word.BBox.X0-box.X0, s.y(word.BBox.Y0+size*0.2), size, word.BBox.Width(), html.EscapeString(word.S))
+			s.rect(word.BBox, "", "")
+		}
+	}
+	s.end()
+
+	s.group("lines", `fill="none" stroke="#17becf" stroke-width="0.5"`)
+	for i, ln := range lines {
+		s.rect(ln.BBox, "", fmt.Sprintf("line %d: %s", i+1, ln.Text))
+	}
+	s.end()
+
+	s.group("blocks", `fill="none" stroke-width="1.2"`)
+	for i, b := range blocks {
+		color := blockColors[b.Type]
+		if color == "" {
+			color = "#000"
+		}
+		s.rect(b.BBox, fmt.Sprintf(`stroke="%s"`, color), fmt.Sprintf("block %d (%s)", i+1, b.Type))
+		fmt.Fprintf(&s.sb, `<text x="%.2f" y="%.2f" font-family="monospace" font-size="6" fill="%s">%d %s</text>`+"\n",
+			b.BBox.X0-box.X0, s.y(b.BBox.Y1)-1, color, i+1, b.Type)
+	}
+	s.end()
+
+	s.group("columns", `fill="none" stroke="#e377c2" stroke-width="1" stroke-dasharray="6 3"`)
+	for i, col := range Columns(blocks, box) {
+		s.rect(col, "", fmt.Sprintf("column %d", i+1))
+	}
+	s.end()
+
+	s.group("tables", `fill="#ff7f0e" fill-opacity="0.15" stroke="#ff7f0e" stroke-width="1.5"`)
+	for _, b := range blocks {
+		if b.Type == extract.BlockTable {
+			s.rect(b.BBox, "", "table")
+		}
+	}
+	s.end()
+
+	s.sb.WriteString("</svg>\n")
+	_, err = io.WriteString(w, s.sb.String())
+	return err
+}
+
+// Columns estimates the text columns of a page from its blocks. Body
+// blocks (text, headings and lists) whose horizontal extents overlap are
+// merged into one column; blocks spanning most of the page width, such
+// as titles above a two-column body, are left out unless every block is
+// that wide. Columns are returned left to right.
+func Columns(blocks []extract.Block, page crazypdf.Rect) []crazypdf.Rect {
+	var body, narrow []crazypdf.Rect
+	for _, b := range blocks {
+		switch b.Type {
+		case extract.BlockText, extract.BlockHeading, extract.BlockList:
+		default:
+			continue
+		}
+		body = append(body, b.BBox)
+		if b.BBox.Width() < page.Width()*0.6 {
+			narrow = append(narrow, b.BBox)
+		}
+	}
+	if len(narrow) > 0 {
+		body = narrow
+	}
+	sort.Slice(body, func(i, j int) bool { return body[i].X0 < body[j].X0 })
+
+	var columns []crazypdf.Rect
+	for _, r := range body {
+		n := len(columns)
+		if n > 0 && r.X0 < columns[n-1].X1 {
+			columns[n-1] = columns[n-1].Union(r)
+			continue
+		}
+		columns = append(columns, r)
+	}
+	return columns
+}
+
+// svgCanvas accumulates SVG elements for a page, converting PDF
+// coordinates to the SVG top-left origin.
+type svgCanvas struct {
+	sb   strings.Builder
+	page crazypdf.Rect
+}
+
+func (s *svgCanvas) y(y float64) float64 {
+	return s.page.Y1 - y
+}
+
+func (s *svgCanvas) group(id, attrs string) {
+	fmt.Fprintf(&s.sb, `<g id="%s" %s>`+"\n", id, attrs)
+}
+
+func (s *svgCanvas) end() {
+	s.sb.WriteString("</g>\n")
+}
+
+// rect draws r with extra attributes and an optional hover title.
+func (s *svgCanvas) rect(r crazypdf.Rect, attrs, title string) {
+	fmt.Fprintf(&s.sb, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f"`,
+		r.X0-s.page.X0, s.y(r.Y1), r.Width(), r.Height())
+	if attrs != "" {
+		s.sb.WriteString(" " + attrs)
+	}
+	if title == "" {
+		s.sb.WriteString("/>\n")
+		return
+	}
+	fmt.Fprintf(&s.sb, "><title>%s</title></rect>\n", html.EscapeString(title))
+}
@@ -0,0 +1,148 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+// config holds configuration for structured exports.
+type config struct {
+	DPI          float64 // resolution used to convert points to pixels
+	ImagePattern string  // fmt pattern for page image file names
+	Analyzer     extract.LayoutAnalyzer
+	IncludeWords bool // emit word-level annotations
+	ImageData    bool // embed image bytes in PyMuPDF image blocks
+	WriteOptions []crazypdf.WriteOption
+	Schema       string // schema version of JSON exports
+}
+
+// Option is a functional option for configuring exports.
+type Option func(*config)
+
+// WithDPI sets the resolution at which page images are (or will be)
+// rendered. Coordinates are scaled from PDF points to pixels at this
+// resolution. Default is 72, where one pixel equals one point.
+func WithDPI(dpi float64) Option {
+	return func(c *config) {
+		c.DPI = dpi
+	}
+}
+
+// WithImagePattern sets the file name pattern for page images. The pattern
+// receives the document stem and the 1-based page number, for example
+// "%s-page-%04d.png" (the default).
+func WithImagePattern(pattern string) Option {
+	return func(c *config) {
+		c.ImagePattern = pattern
+	}
+}
+
+// WithLayoutAnalyzer sets the analyzer used to produce block labels.
+// Default is extract.DefaultLayoutAnalyzer.
+func WithLayoutAnalyzer(a extract.LayoutAnalyzer) Option {
+	return func(c *config) {
+		c.Analyzer = a
+	}
+}
+
+// WithWords controls whether word-level annotations are emitted in
+// addition to block annotations. Default is true.
+func WithWords(include bool) Option {
+	return func(c *config) {
+		c.IncludeWords = include
+	}
+}
+
+// WithImageData controls whether PyMuPDFDict embeds the encoded image
+// bytes in image blocks. Default is true, as in PyMuPDF.
+func WithImageData(include bool) Option {
+	return func(c *config) {
+		c.ImageData = include
+	}
+}
+
+// WithWriteOptions sets how exports are written to files, such as
+// computing their SHA-256 with crazypdf.WithChecksum.
+func WithWriteOptions(opts ...crazypdf.WriteOption) Option {
+	return func(c *config) {
+		c.WriteOptions = opts
+	}
+}
+
+// WithSchemaVersion sets the schema version of JSON exports, such as
+// SchemaVersion10 for consumers that predate provenance. See
+// NegotiateSchema. Default is SchemaVersion.
+func WithSchemaVersion(version string) Option {
+	return func(c *config) {
+		c.Schema = version
+	}
+}
+
+// checkSchema returns an error when the configured schema version is not
+// supported.
+func (c *config) checkSchema() error {
+	if schemaIndex(c.Schema) < 0 {
+		return fmt.Errorf("unsupported schema version %q (supported: %s)", c.Schema, strings.Join(schemaVersions, ", "))
+	}
+	return nil
+}
+
+// hasProvenance reports whether the configured schema version carries
+// provenance.
+func (c *config) hasProvenance() bool {
+	return schemaIndex(c.Schema) >= schemaIndex(SchemaVersion11)
+}
+
+// hasIDs reports whether the configured schema version carries the
+// stable IDs of blocks and words.
+func (c *config) hasIDs() bool {
+	return schemaIndex(c.Schema) >= schemaIndex(SchemaVersion12)
+}
+
+// schemaField returns the schemaVersion field value: empty for 1.0, which
+// has no such field.
+func (c *config) schemaField() string {
+	if c.Schema == SchemaVersion10 {
+		return ""
+	}
+	return c.Schema
+}
+
+// options returns the settings recorded in Provenance.Options.
+func (c *config) options() map[string]any {
+	analyzer := "default"
+	if c.Analyzer != nil {
+		analyzer = fmt.Sprintf("%T", c.Analyzer)
+	}
+	return map[string]any{
+		"dpi":          c.DPI,
+		"imagePattern": c.ImagePattern,
+		"words":        c.IncludeWords,
+		"imageData":    c.ImageData,
+		"analyzer":     analyzer,
+		"schema":       c.Schema,
+	}
+}
+
+// defaultConfig returns the default export configuration.
+func defaultConfig() *config {
+	return &config{
+		DPI:          72,
+		ImagePattern: "%s-page-%04d.png",
+		IncludeWords: true,
+		ImageData:    true,
+		Schema:       SchemaVersion,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
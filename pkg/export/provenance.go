@@ -0,0 +1,62 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Provenance records where exported data came from and how it was
+// produced, so downstream data lineage can be audited.
+type Provenance struct {
+	// Source is the path of the PDF file as opened, or empty for
+	// documents opened from a reader, and SHA256 the hex-encoded hash of
+	// its bytes.
+	Source string `json:"source,omitempty"`
+	SHA256 string `json:"sha256"`
+
+	// SourceModified is the modification time of the source file, or nil
+	// when the document was not opened from a file.
+	SourceModified *time.Time `json:"sourceModified,omitempty"`
+
+	// Generator names the library and version that produced the export,
+	// such as "crazypdf 0.1.0".
+	Generator string `json:"generator"`
+
+	// Options are the export options in effect.
+	Options map[string]any `json:"options"`
+
+	// Created is when the export was produced, in UTC.
+	Created time.Time `json:"created"`
+}
+
+// NewProvenance returns the provenance of an export of doc made with
+// opts. Exporters that write whole documents, such as COCO and PyMuPDF,
+// embed it themselves; use it to attach provenance to per-page results.
+func NewProvenance(doc *crazypdf.Document, opts ...Option) (*Provenance, error) {
+	return newProvenance(doc, applyOptions(opts))
+}
+
+// newProvenance returns the provenance of an export of doc with cfg.
+func newProvenance(doc *crazypdf.Document, cfg *config) (*Provenance, error) {
+	sum, err := doc.SHA256()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash document: %w", err)
+	}
+	p := &Provenance{
+		Source:    doc.FilePath(),
+		SHA256:    sum,
+		Generator: "crazypdf " + crazypdf.Version,
+		Options:   cfg.options(),
+		Created:   time.Now().UTC().Truncate(time.Second),
+	}
+	if p.Source != "" {
+		if fi, err := os.Stat(p.Source); err == nil {
+			mod := fi.ModTime().UTC()
+			p.SourceModified = &mod
+		}
+	}
+	return p, nil
+}
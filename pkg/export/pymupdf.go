@@ -0,0 +1,376 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/extract"
+)
+
+// PyMuPDFPage mirrors the structure returned by PyMuPDF's
+// page.get_text("dict"): text blocks made of lines and spans, and image
+// blocks. Coordinates are in points with a top-left origin, as in
+// PyMuPDF.
+type PyMuPDFPage struct {
+	// SchemaVersion is the export schema version; see SchemaVersion.
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+
+	Width  float64        `json:"width"`
+	Height float64        `json:"height"`
+	Blocks []PyMuPDFBlock `json:"blocks"`
+
+	// Provenance describes the source document and export. It is set by
+	// PyMuPDF, not by PyMuPDFDict; see NewProvenance.
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// PyMuPDFBlock is a text block (Type 0) or an image block (Type 1).
+type PyMuPDFBlock struct {
+	Number int        `json:"number"`
+	Type   int        `json:"type"`
+	BBox   [4]float64 `json:"bbox"`
+
+	// ID is the stable ID of a text block: that of the extract.Block it
+	// comes from, or one of its own for a line outside every block. It is
+	// set from schema version 1.2.
+	ID string `json:"id,omitempty"`
+
+	// Lines is set for text blocks.
+	Lines []PyMuPDFLine `json:"lines,omitempty"`
+
+	// The remaining fields are set for image blocks. Width and Height are
+	// in pixels, ColorSpace is the number of color components and
+	// Transform maps the unit square onto BBox.
+	Width      int       `json:"width,omitempty"`
+	Height     int       `json:"height,omitempty"`
+	Ext        string    `json:"ext,omitempty"`
+	ColorSpace int       `json:"colorspace,omitempty"`
+	XRes       int       `json:"xres,omitempty"`
+	YRes       int       `json:"yres,omitempty"`
+	BPC        int       `json:"bpc,omitempty"`
+	Transform  []float64 `json:"transform,omitempty"`
+	Size       int       `json:"size,omitempty"`
+	Image      []byte    `json:"image,omitempty"`
+}
+
+// PyMuPDFLine is a line of a text block. Only horizontal text is
+// produced, so WMode is 0 and Dir is (1, 0).
+type PyMuPDFLine struct {
+	Spans []PyMuPDFSpan `json:"spans"`
+	WMode int           `json:"wmode"`
+	Dir   [2]float64    `json:"dir"`
+	BBox  [4]float64    `json:"bbox"`
+}
+
+// PyMuPDFSpan is a run of text in one font and size.
+type PyMuPDFSpan struct {
+	Size  float64 `json:"size"`
+	Flags int     `json:"flags"`
+	Font  string  `json:"font"`
+
+	// Color is the sRGB text color as an integer. Text color is not
+	// tracked, so it is always 0 (black).
+	Color     int        `json:"color"`
+	Ascender  float64    `json:"ascender"`
+	Descender float64    `json:"descender"`
+	Text      string     `json:"text"`
+	Origin    [2]float64 `json:"origin"`
+	BBox      [4]float64 `json:"bbox"`
+
+	// WordIDs are the stable IDs of the words of the span, as in
+	// crazypdf.Word. They are set from schema version 1.2.
+	WordIDs []string `json:"word_ids,omitempty"`
+}
+
+// Span flags, with the bit values PyMuPDF uses.
+const (
+	pyMuPDFItalic    = 1 << 1
+	pyMuPDFSerif     = 1 << 2
+	pyMuPDFMonospace = 1 << 3
+	pyMuPDFBold      = 1 << 4
+)
+
+// PyMuPDFDict returns the text and images of a page in the structure of
+// PyMuPDF's page.get_text("dict"), so code written against PyMuPDF's
+// output can consume it unchanged. Blocks come from the configured
+// layout analyzer. Font flags are inferred from font names, ascender and
+// descender use typical Latin proportions, and images carry their data
+// unless WithImageData(false) is given.
+func PyMuPDFDict(page *crazypdf.Page, opts ...Option) (*PyMuPDFPage, error) {
+	cfg := applyOptions(opts)
+	if err := cfg.checkSchema(); err != nil {
+		return nil, err
+	}
+
+	box, err := page.MediaBox()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page geometry: %w", err)
+	}
+	var extractOpts []extract.Option
+	if cfg.Analyzer != nil {
+		extractOpts = append(extractOpts, extract.WithLayoutAnalyzer(cfg.Analyzer))
+	}
+	blocks, err := extract.Blocks(page, extractOpts...)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := extract.Lines(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page text: %w", err)
+	}
+	items, err := page.Drawing()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page images: %w", err)
+	}
+
+	toTopLeft := func(r crazypdf.Rect) [4]float64 {
+		return [4]float64{
+			round2(r.X0 - box.X0), round2(box.Y1 - r.Y1),
+			round2(r.X1 - box.X0), round2(box.Y1 - r.Y0),
+		}
+	}
+
+	// Assign each line to the first text block containing its center;
+	// lines outside every block form blocks of their own.
+	var textBlocks []crazypdf.Rect
+	var blockIDs []string
+	for _, b := range blocks {
+		if b.Type != extract.BlockFigure {
+			textBlocks = append(textBlocks, b.BBox)
+			blockIDs = append(blockIDs, b.ID)
+		}
+	}
+	analyzed := len(textBlocks)
+	blockLines := make([][]extract.Line, len(textBlocks))
+	for _, ln := range lines {
+		cx, cy := (ln.BBox.X0+ln.BBox.X1)/2, (ln.BBox.Y0+ln.BBox.Y1)/2
+		assigned := false
+		for i, bbox := range textBlocks {
+			if bbox.Contains(cx, cy) {
+				blockLines[i] = append(blockLines[i], ln)
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			// Numbered after the analyzer's blocks, so IDs do not collide
+			id := internalpdf.StableID("b", page.Number, len(blocks)+len(textBlocks)-analyzed, ln.Text, ln.BBox)
+			textBlocks = append(textBlocks, ln.BBox)
+			blockIDs = append(blockIDs, id)
+			blockLines = append(blockLines, []extract.Line{ln})
+		}
+	}
+
+	out := &PyMuPDFPage{SchemaVersion: cfg.schemaField(), Width: round2(box.Width()), Height: round2(box.Height())}
+	for i := range textBlocks {
+		if len(blockLines[i]) == 0 {
+			continue
+		}
+		block := PyMuPDFBlock{Type: 0}
+		if cfg.hasIDs() {
+			block.ID = blockIDs[i]
+		}
+		var union crazypdf.Rect
+		for _, ln := range blockLines[i] {
+			block.Lines = append(block.Lines, pyMuPDFLine(ln, toTopLeft, box, cfg.hasIDs()))
+			union = union.Union(ln.BBox)
+		}
+		block.BBox = toTopLeft(union)
+		out.Blocks = append(out.Blocks, block)
+	}
+
+	flip := internalpdf.Matrix{1, 0, 0, -1, -box.X0, box.Y1}
+	for _, it := range items {
+		if !it.Image {
+			continue
+		}
+		out.Blocks = append(out.Blocks, pyMuPDFImage(it, flip, toTopLeft, cfg.ImageData))
+	}
+
+	sort.SliceStable(out.Blocks, func(i, j int) bool {
+		return out.Blocks[i].BBox[1] < out.Blocks[j].BBox[1]
+	})
+	for i := range out.Blocks {
+		out.Blocks[i].Number = i
+	}
+	return out, nil
+}
+
+// pyMuPDFLine converts a line into spans of consecutive words sharing a
+// font and size, with the IDs of their words when withIDs is set.
+func pyMuPDFLine(ln extract.Line, toTopLeft func(crazypdf.Rect) [4]float64, box crazypdf.Rect, withIDs bool) PyMuPDFLine {
+	line := PyMuPDFLine{Dir: [2]float64{1, 0}, BBox: toTopLeft(ln.BBox)}
+
+	var span PyMuPDFSpan
+	var spanBox crazypdf.Rect
+	var words []string
+	flush := func() {
+		if len(words) == 0 {
+			return
+		}
+		span.Text = strings.Join(words, " ")
+		span.BBox = toTopLeft(spanBox)
+		line.Spans = append(line.Spans, span)
+		words = nil
+	}
+
+	for _, w := range ln.Words {
+		size := w.FontSize
+		if size <= 0 {
+			size = 12
+		}
+		if len(words) > 0 && (w.Font != span.Font || round2(size) != span.Size) {
+			flush()
+		}
+		if len(words) == 0 {
+			baseline := w.BBox.Y0 + size*0.2
+			span = PyMuPDFSpan{
+				Size:      round2(size),
+				Flags:     fontFlags(w.Font),
+				Font:      w.Font,
+				Ascender:  0.8,
+				Descender: -0.2,
+				Origin:    [2]float64{round2(w.BBox.X0 - box.X0), round2(box.Y1 - baseline)},
+			}
+			spanBox = w.BBox
+		}
+		words = append(words, w.S)
+		if withIDs && w.ID != "" {
+			span.WordIDs = append(span.WordIDs, w.ID)
+		}
+		spanBox = spanBox.Union(w.BBox)
+	}
+	flush()
+	return line
+}
+
+// pyMuPDFImage converts a painted image into an image block.
+func pyMuPDFImage(it crazypdf.DrawItem, flip internalpdf.Matrix, toTopLeft func(crazypdf.Rect) [4]float64, withData bool) PyMuPDFBlock {
+	dict := it.ImageStream.Dict
+	intValue := func(key internalpdf.Name) int {
+		switch v := dict[key].(type) {
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+		return 0
+	}
+
+	m := it.Matrix.Multiply(flip)
+	block := PyMuPDFBlock{
+		Type:       1,
+		BBox:       toTopLeft(it.Matrix.TransformRect(crazypdf.Rect{X0: 0, Y0: 0, X1: 1, Y1: 1})),
+		Width:      intValue("Width"),
+		Height:     intValue("Height"),
+		ColorSpace: colorComponents(dict["ColorSpace"]),
+		XRes:       96,
+		YRes:       96,
+		BPC:        intValue("BitsPerComponent"),
+		Transform:  []float64{round2(m[0]), round2(m[1]), round2(m[2]), round2(m[3]), round2(m[4]), round2(m[5])},
+	}
+	if mask, _ := dict["ImageMask"].(bool); mask || block.BPC == 0 {
+		block.BPC = 1
+	}
+
+	data, mime, err := internalpdf.EncodeImage(it.ImageStream)
+	if err == nil {
+		block.Ext = strings.TrimPrefix(mime, "image/")
+		block.Size = len(data)
+		if withData {
+			block.Image = data
+		}
+	}
+	return block
+}
+
+// colorComponents returns the number of components of an image color
+// space, or 0 when unknown.
+func colorComponents(cs internalpdf.Object) int {
+	switch v := cs.(type) {
+	case internalpdf.Name:
+		switch v {
+		case "DeviceGray", "CalGray", "Indexed":
+			return 1
+		case "DeviceRGB", "CalRGB", "Lab":
+			return 3
+		case "DeviceCMYK":
+			return 4
+		}
+	case internalpdf.Array:
+		if len(v) == 0 {
+			return 0
+		}
+		switch v[0] {
+		case internalpdf.Name("ICCBased"):
+			if len(v) > 1 {
+				if s, ok := v[1].(*internalpdf.Stream); ok {
+					if n, ok := s.Dict["N"].(int64); ok {
+						return int(n)
+					}
+				}
+			}
+		case internalpdf.Name("Indexed"):
+			return 1
+		default:
+			return colorComponents(v[0])
+		}
+	}
+	return 0
+}
+
+// fontFlags infers PyMuPDF span flags from a font name.
+func fontFlags(font string) int {
+	lower := strings.ToLower(font)
+	flags := 0
+	if strings.Contains(lower, "italic") || strings.Contains(lower, "oblique") {
+		flags |= pyMuPDFItalic
+	}
+	if strings.Contains(lower, "bold") || strings.Contains(lower, "black") || strings.Contains(lower, "heavy") {
+		flags |= pyMuPDFBold
+	}
+	switch {
+	case strings.Contains(lower, "courier") || strings.Contains(lower, "mono"):
+		flags |= pyMuPDFMonospace | pyMuPDFSerif
+	case strings.Contains(lower, "times") || strings.Contains(lower, "roman") ||
+		strings.Contains(lower, "georgia") || strings.Contains(lower, "garamond") ||
+		strings.Contains(lower, "serif") && !strings.Contains(lower, "sans"):
+		flags |= pyMuPDFSerif
+	}
+	return flags
+}
+
+// PyMuPDF writes every page of a document as a JSON array of
+// PyMuPDFDict results, each carrying the provenance of the export.
+func PyMuPDF(doc *crazypdf.Document, w io.Writer, opts ...Option) error {
+	if doc.IsClosed() {
+		return crazypdf.ErrDocumentClosed
+	}
+	var prov *Provenance
+	if cfg := applyOptions(opts); cfg.hasProvenance() {
+		var err error
+		if prov, err = newProvenance(doc, cfg); err != nil {
+			return err
+		}
+	}
+	pages := make([]*PyMuPDFPage, 0, doc.NumPages())
+	for _, page := range doc.Pages() {
+		p, err := PyMuPDFDict(page, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to export page %d: %w", page.Number, err)
+		}
+		p.Provenance = prov
+		pages = append(pages, p)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pages); err != nil {
+		return fmt.Errorf("failed to encode pages: %w", err)
+	}
+	return nil
+}
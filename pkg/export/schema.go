@@ -0,0 +1,195 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Schema versions of the JSON exports (COCO and PyMuPDF). Versions follow
+// semantic versioning: a minor version only adds fields, so a reader of
+// 1.0 can read 1.1; a new major version changes or removes fields.
+const (
+	// SchemaVersion10 is the initial format. It carries no schemaVersion
+	// field.
+	SchemaVersion10 = "1.0"
+
+	// SchemaVersion11 adds schemaVersion and provenance.
+	SchemaVersion11 = "1.1"
+
+	// SchemaVersion12 adds the stable IDs of blocks and words: id on
+	// PyMuPDF text blocks, word_ids on PyMuPDF spans and stable_id on
+	// COCO annotations.
+	SchemaVersion12 = "1.2"
+
+	// SchemaVersion is the version written by default.
+	SchemaVersion = SchemaVersion12
+)
+
+// schemaVersions lists the supported versions, oldest first.
+var schemaVersions = []string{SchemaVersion10, SchemaVersion11, SchemaVersion12}
+
+// NegotiateSchema returns the newest supported schema version that a
+// consumer accepting the given versions can read: one with the same major
+// version and a minor version no later than an accepted one. Pass the
+// result to WithSchemaVersion. It returns an error when no supported
+// version is readable by the consumer.
+func NegotiateSchema(accepted ...string) (string, error) {
+	best := ""
+	for _, a := range accepted {
+		amajor, aminor, err := parseSchemaVersion(a)
+		if err != nil {
+			return "", err
+		}
+		for _, v := range schemaVersions {
+			major, minor, _ := parseSchemaVersion(v)
+			if major == amajor && minor <= aminor && schemaIndex(v) > schemaIndex(best) {
+				best = v
+			}
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no supported schema version is compatible with %s (supported: %s)",
+			strings.Join(accepted, ", "), strings.Join(schemaVersions, ", "))
+	}
+	return best, nil
+}
+
+// ConvertCOCO converts a COCO dataset written by COCO or COCOBuilder to
+// another schema version. Converting to an older version drops the
+// fields it lacks; converting to a newer one adds its fields empty, as
+// provenance cannot be recovered, or leaves them out, as stable IDs
+// cannot. Fields the converter does not know are kept.
+func ConvertCOCO(data []byte, version string) ([]byte, error) {
+	var dataset map[string]any
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("failed to decode COCO dataset: %w", err)
+	}
+	info, _ := dataset["info"].(map[string]any)
+	if info == nil {
+		info = map[string]any{}
+		dataset["info"] = info
+	}
+	dropIDs := func() {
+		annotations, _ := dataset["annotations"].([]any)
+		for _, a := range annotations {
+			if a, ok := a.(map[string]any); ok {
+				delete(a, "stable_id")
+			}
+		}
+	}
+	if err := convertSchema(dataset, info, []any{}, dropIDs, version); err != nil {
+		return nil, err
+	}
+	return marshalIndent(dataset)
+}
+
+// ConvertPyMuPDF converts the page array written by PyMuPDF to another
+// schema version, like ConvertCOCO.
+func ConvertPyMuPDF(data []byte, version string) ([]byte, error) {
+	var pages []map[string]any
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil, fmt.Errorf("failed to decode PyMuPDF pages: %w", err)
+	}
+	for _, page := range pages {
+		dropIDs := func() {
+			blocks, _ := page["blocks"].([]any)
+			for _, b := range blocks {
+				b, _ := b.(map[string]any)
+				delete(b, "id")
+				lines, _ := b["lines"].([]any)
+				for _, ln := range lines {
+					ln, _ := ln.(map[string]any)
+					spans, _ := ln["spans"].([]any)
+					for _, sp := range spans {
+						if sp, ok := sp.(map[string]any); ok {
+							delete(sp, "word_ids")
+						}
+					}
+				}
+			}
+		}
+		if err := convertSchema(page, page, nil, dropIDs, version); err != nil {
+			return nil, err
+		}
+	}
+	return marshalIndent(pages)
+}
+
+// convertSchema converts an export from its version, given by the
+// schemaVersion field of root, to version. holder is the object holding
+// the provenance field, which is set to emptyProvenance, if not nil, when
+// upgrading to 1.1. dropIDs removes the stable IDs when downgrading from
+// 1.2.
+func convertSchema(root, holder map[string]any, emptyProvenance any, dropIDs func(), version string) error {
+	from := SchemaVersion10
+	if v, ok := root["schemaVersion"].(string); ok {
+		from = v
+	}
+	i := schemaIndex(from)
+	if i < 0 {
+		return fmt.Errorf("unsupported schema version %q", from)
+	}
+	to := schemaIndex(version)
+	if to < 0 {
+		return fmt.Errorf("unsupported schema version %q", version)
+	}
+
+	// Upgrade or downgrade one version at a time
+	for ; i < to; i++ {
+		if schemaVersions[i+1] == SchemaVersion11 && emptyProvenance != nil {
+			holder["provenance"] = emptyProvenance
+		}
+	}
+	for ; i > to; i-- {
+		switch schemaVersions[i] {
+		case SchemaVersion11:
+			delete(holder, "provenance")
+		case SchemaVersion12:
+			dropIDs()
+		}
+	}
+
+	if version == SchemaVersion10 {
+		delete(root, "schemaVersion")
+	} else {
+		root["schemaVersion"] = version
+	}
+	return nil
+}
+
+// schemaIndex returns the position of v in schemaVersions, or -1.
+func schemaIndex(v string) int {
+	for i, s := range schemaVersions {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseSchemaVersion splits a "major.minor" version.
+func parseSchemaVersion(v string) (major, minor int, err error) {
+	ma, mi, ok := strings.Cut(v, ".")
+	if ok {
+		major, err = strconv.Atoi(ma)
+		if err == nil {
+			minor, err = strconv.Atoi(mi)
+		}
+	}
+	if !ok || err != nil || major < 0 || minor < 0 {
+		return 0, 0, fmt.Errorf("invalid schema version %q", v)
+	}
+	return major, minor, nil
+}
+
+// marshalIndent encodes v as indented JSON with a trailing newline, as
+// the exporters write it.
+func marshalIndent(v any) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
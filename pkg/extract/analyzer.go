@@ -0,0 +1,486 @@
+package extract
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// BlockType classifies a block of page content.
+type BlockType int
+
+const (
+	// BlockText is a paragraph or other run of body text.
+	BlockText BlockType = iota
+
+	// BlockHeading is a title or section heading.
+	BlockHeading
+
+	// BlockList is a bulleted or enumerated list.
+	BlockList
+
+	// BlockFigure is an image or graphic, with any labels inside it.
+	BlockFigure
+
+	// BlockTable is tabular content. The built-in analyzer does not emit
+	// tables; the type exists for pluggable analyzers that do.
+	BlockTable
+
+	// BlockHeader is running header text at the top of the page.
+	BlockHeader
+
+	// BlockFooter is running footer text at the bottom of the page.
+	BlockFooter
+)
+
+// String returns the lowercase name of the block type.
+func (t BlockType) String() string {
+	switch t {
+	case BlockText:
+		return "text"
+	case BlockHeading:
+		return "heading"
+	case BlockList:
+		return "list"
+	case BlockFigure:
+		return "figure"
+	case BlockTable:
+		return "table"
+	case BlockHeader:
+		return "header"
+	case BlockFooter:
+		return "footer"
+	default:
+		return fmt.Sprintf("BlockType(%d)", int(t))
+	}
+}
+
+// Block is a typed group of page content produced by a LayoutAnalyzer.
+type Block struct {
+	Type BlockType
+
+	// BBox is the block's bounding box in PDF points.
+	BBox crazypdf.Rect
+
+	// Text is the block text with lines separated by newlines.
+	Text string
+
+	// Words are the words making up the block, in reading order.
+	Words []crazypdf.Word
+
+	// ID identifies the block among those of the document, such as
+	// "p1-b0-9c2e41d7", from its page, its index and a hash of its text
+	// and box, as word IDs are. Blocks sets it when the analyzer leaves it
+	// empty.
+	ID string
+}
+
+// LayoutInput is the page content handed to a LayoutAnalyzer.
+type LayoutInput struct {
+	// PageNumber is the 1-based page number.
+	PageNumber int
+
+	// PageBox is the page media box in PDF points.
+	PageBox crazypdf.Rect
+
+	// Words are the positioned words on the page.
+	Words []crazypdf.Word
+
+	// Graphics are the painted paths and images on the page.
+	Graphics []crazypdf.Graphic
+}
+
+// LayoutAnalyzer segments a page into typed blocks. Implementations may
+// wrap external models (for example an ONNX layout detector) as long as
+// they map their output onto Blocks.
+type LayoutAnalyzer interface {
+	Analyze(in *LayoutInput) ([]Block, error)
+}
+
+// LayoutAnalyzerFunc adapts an ordinary function to the LayoutAnalyzer
+// interface.
+type LayoutAnalyzerFunc func(in *LayoutInput) ([]Block, error)
+
+// Analyze calls f(in).
+func (f LayoutAnalyzerFunc) Analyze(in *LayoutInput) ([]Block, error) {
+	return f(in)
+}
+
+// DefaultLayoutAnalyzer returns the built-in geometric heuristic analyzer
+// with DefaultHeuristics. It is used when no analyzer is configured with
+// WithLayoutAnalyzer; the heuristics of a Profile then apply.
+func DefaultLayoutAnalyzer() LayoutAnalyzer {
+	return heuristicAnalyzer{h: DefaultHeuristics()}
+}
+
+// Blocks segments a page into typed blocks using the configured
+// LayoutAnalyzer.
+func Blocks(page *crazypdf.Page, opts ...Option) ([]Block, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := NewLayoutInput(page)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer := cfg.Analyzer
+	if analyzer == nil {
+		analyzer = heuristicAnalyzer{h: cfg.Heuristics, trace: cfg.tracer(page.Number)}
+	}
+	blocks, err := analyzer.Analyze(in)
+	if err != nil {
+		return nil, fmt.Errorf("layout analysis failed on page %d: %w", page.Number, err)
+	}
+	coords, err := cfg.coordinates(page)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blocks {
+		if blocks[i].ID == "" {
+			blocks[i].ID = internalpdf.StableID("b", page.Number, i, blocks[i].Text, blocks[i].BBox)
+		}
+		blocks[i].BBox = coords.Rect(blocks[i].BBox)
+		blocks[i].Words = coords.Words(blocks[i].Words)
+	}
+	return blocks, nil
+}
+
+// Line is a horizontal run of words sharing a baseline, as grouped by
+// the built-in analyzer before lines are merged into blocks.
+type Line struct {
+	// BBox is the line's bounding box in PDF points.
+	BBox crazypdf.Rect
+
+	// Text is the words of the line separated by single spaces.
+	Text string
+
+	// FontSize is the mean font size of the words.
+	FontSize float64
+
+	Words []crazypdf.Word
+}
+
+// Lines groups the words of a page into lines, top to bottom. A baseline
+// is split into separate lines where a wide gap suggests a column
+// boundary. Geometry is in the space set with WithCoordinateSpace and
+// WithDPI, and the grouping follows the heuristics of WithProfile; other
+// options are ignored.
+func Lines(page *crazypdf.Page, opts ...Option) ([]Line, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	coords, err := cfg.coordinates(page)
+	if err != nil {
+		return nil, err
+	}
+	words, err := page.Words()
+	if err != nil {
+		return nil, err
+	}
+	var lines []Line
+	for _, ln := range layoutLines(words, cfg.Heuristics, cfg.tracer(page.Number)) {
+		lines = append(lines, Line{BBox: coords.Rect(ln.box), Text: ln.text(), FontSize: ln.fontSize, Words: coords.Words(ln.words)})
+	}
+	return lines, nil
+}
+
+// NewLayoutInput collects the words, graphics and geometry of a page.
+func NewLayoutInput(page *crazypdf.Page) (*LayoutInput, error) {
+	box, err := page.MediaBox()
+	if err != nil {
+		return nil, err
+	}
+	words, err := page.Words()
+	if err != nil {
+		return nil, err
+	}
+	graphics, err := page.Graphics()
+	if err != nil {
+		return nil, err
+	}
+	return &LayoutInput{
+		PageNumber: page.Number,
+		PageBox:    box,
+		Words:      words,
+		Graphics:   graphics,
+	}, nil
+}
+
+// heuristicAnalyzer is the built-in LayoutAnalyzer. It groups words into
+// lines and lines into blocks by proximity, then types each block from
+// its position, font size and leading markers.
+type heuristicAnalyzer struct {
+	h     Heuristics
+	trace internalpdf.Tracer
+}
+
+// layoutLine is a horizontal run of words.
+type layoutLine struct {
+	box      crazypdf.Rect
+	words    []crazypdf.Word
+	fontSize float64
+}
+
+func (l layoutLine) text() string {
+	parts := make([]string, len(l.words))
+	for i, w := range l.words {
+		parts[i] = w.S
+	}
+	return strings.Join(parts, " ")
+}
+
+// listMarker matches bullets and enumerators that open a list item.
+var listMarker = regexp.MustCompile(`^([•·▪◦‣∙*–-]|\(?(\d{1,3}|[a-zA-Z]|[ivxlcIVXLC]{1,6})[.)])$`)
+
+// Analyze implements LayoutAnalyzer.
+func (a heuristicAnalyzer) Analyze(in *LayoutInput) ([]Block, error) {
+	h, trace := a.h, a.trace
+	page := in.PageBox
+	lines := layoutLines(in.Words, h, trace)
+
+	// Figures: sufficiently large images that are not page backgrounds
+	var figures []Block
+	for _, g := range in.Graphics {
+		bbox := g.BBox.Intersect(page)
+		if g.Kind != crazypdf.GraphicImage || bbox.IsEmpty() {
+			continue
+		}
+		if bbox.Area() < page.Area()*h.FigureMinArea || bbox.Area() >= page.Area()*h.FigureMaxArea {
+			trace.Printf("image at (%.2f, %.2f)-(%.2f, %.2f) is not a figure: %.1f%% of the page area, outside %.1f%% to %.1f%%", bbox.X0, bbox.Y0, bbox.X1, bbox.Y1, bbox.Area()/page.Area()*100, h.FigureMinArea*100, h.FigureMaxArea*100)
+			continue
+		}
+		figures = append(figures, Block{Type: BlockFigure, BBox: bbox})
+	}
+
+	var rest []layoutLine
+	for _, ln := range lines {
+		cx := (ln.box.X0 + ln.box.X1) / 2
+		cy := (ln.box.Y0 + ln.box.Y1) / 2
+		inFigure := false
+		for i := range figures {
+			if figures[i].BBox.Contains(cx, cy) {
+				figures[i].Words = append(figures[i].Words, ln.words...)
+				figures[i].Text = joinText(figures[i].Text, ln.text())
+				inFigure = true
+				break
+			}
+		}
+		if !inFigure {
+			rest = append(rest, ln)
+		}
+	}
+
+	// Running headers and footers
+	headerTop := page.Y1 - page.Height()*h.MarginBand
+	footerTop := page.Y0 + page.Height()*h.MarginBand
+	var header, footer, body []layoutLine
+	for _, ln := range rest {
+		switch {
+		case ln.box.Y0 >= headerTop:
+			header = append(header, ln)
+		case ln.box.Y1 <= footerTop:
+			footer = append(footer, ln)
+		default:
+			body = append(body, ln)
+		}
+	}
+	if len(header) > h.MaxMarginLines {
+		trace.Printf("%d lines above y=%.2f are body text, not a header: more than max_margin_lines %d", len(header), headerTop, h.MaxMarginLines)
+		body, header = append(body, header...), nil
+	}
+	if len(footer) > h.MaxMarginLines {
+		trace.Printf("%d lines below y=%.2f are body text, not a footer: more than max_margin_lines %d", len(footer), footerTop, h.MaxMarginLines)
+		body, footer = append(body, footer...), nil
+	}
+
+	bodySize := medianFontSize(body)
+
+	var blocks []Block
+	if len(header) > 0 {
+		blocks = append(blocks, linesBlock(BlockHeader, header))
+	}
+	for _, group := range groupLayoutLines(body, h, trace) {
+		blocks = append(blocks, linesBlock(classifyLines(group, bodySize, h), group))
+	}
+	if len(footer) > 0 {
+		blocks = append(blocks, linesBlock(BlockFooter, footer))
+	}
+	blocks = append(blocks, figures...)
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].BBox.Y1 != blocks[j].BBox.Y1 {
+			return blocks[i].BBox.Y1 > blocks[j].BBox.Y1
+		}
+		return blocks[i].BBox.X0 < blocks[j].BBox.X0
+	})
+	return blocks, nil
+}
+
+// layoutLines groups words sharing a baseline into lines, splitting a
+// baseline wherever a gap wider than h.ColumnGap font sizes suggests a
+// column boundary.
+func layoutLines(words []crazypdf.Word, h Heuristics, trace internalpdf.Tracer) []layoutLine {
+	sorted := make([]crazypdf.Word, len(words))
+	copy(sorted, words)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].BBox.Y0 != sorted[j].BBox.Y0 {
+			return sorted[i].BBox.Y0 > sorted[j].BBox.Y0
+		}
+		return sorted[i].BBox.X0 < sorted[j].BBox.X0
+	})
+
+	var rows [][]crazypdf.Word
+	for _, w := range sorted {
+		n := len(rows)
+		if n > 0 {
+			ref := rows[n-1][0].BBox
+			tolerance := math.Max(ref.Height(), w.BBox.Height()) * h.LineTolerance
+			if math.Abs(ref.Y0-w.BBox.Y0) <= tolerance {
+				trace.Printf("word %q at (%.2f, %.2f) joins the line of %q: baselines %.2f apart <= %.2f (line_tolerance × height)", w.S, w.BBox.X0, w.BBox.Y0, rows[n-1][0].S, math.Abs(ref.Y0-w.BBox.Y0), tolerance)
+				rows[n-1] = append(rows[n-1], w)
+				continue
+			}
+			trace.Printf("word %q at (%.2f, %.2f) starts a line: baselines %.2f apart > %.2f (line_tolerance × height)", w.S, w.BBox.X0, w.BBox.Y0, math.Abs(ref.Y0-w.BBox.Y0), tolerance)
+		}
+		rows = append(rows, []crazypdf.Word{w})
+	}
+
+	var lines []layoutLine
+	for _, row := range rows {
+		sort.SliceStable(row, func(a, b int) bool { return row[a].BBox.X0 < row[b].BBox.X0 })
+		start := 0
+		for i := 1; i <= len(row); i++ {
+			if i < len(row) {
+				size := row[i-1].FontSize
+				if size <= 0 {
+					size = 12
+				}
+				gap := row[i].BBox.X0 - row[i-1].BBox.X1
+				if gap <= size*h.ColumnGap {
+					continue
+				}
+				trace.Printf("line at y=%.2f splits between %q and %q at x=%.2f: gap %.2f > %.2f (column_gap × font size)", row[i].BBox.Y0, row[i-1].S, row[i].S, row[i].BBox.X0, gap, size*h.ColumnGap)
+			}
+			lines = append(lines, newLayoutLine(row[start:i]))
+			start = i
+		}
+	}
+	return lines
+}
+
+func newLayoutLine(words []crazypdf.Word) layoutLine {
+	ln := layoutLine{words: words}
+	var sum float64
+	for _, w := range words {
+		ln.box = ln.box.Union(w.BBox)
+		sum += w.FontSize
+	}
+	ln.fontSize = sum / float64(len(words))
+	return ln
+}
+
+// groupLayoutLines merges vertically adjacent lines that overlap
+// horizontally and share a similar font size.
+func groupLayoutLines(lines []layoutLine, h Heuristics, trace internalpdf.Tracer) [][]layoutLine {
+	var groups [][]layoutLine
+	var boxes []crazypdf.Rect
+	for _, ln := range lines {
+		target := -1
+		for i := len(groups) - 1; i >= 0; i-- {
+			last := groups[i][len(groups[i])-1]
+			gap := last.box.Y0 - ln.box.Y1
+			sameSize := math.Abs(last.fontSize-ln.fontSize) <= h.BlockFontSizeTolerance
+			overlaps := boxes[i].X0 < ln.box.X1 && ln.box.X0 < boxes[i].X1
+			if overlaps && sameSize && gap < last.box.Height()*h.BlockGap {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			trace.Printf("line %q at y=%.2f starts block %d: no block above within %.2f line heights, %.2f points of font size and overlapping it", ln.text(), ln.box.Y0, len(groups), h.BlockGap, h.BlockFontSizeTolerance)
+			groups = append(groups, []layoutLine{ln})
+			boxes = append(boxes, ln.box)
+			continue
+		}
+		last := groups[target][len(groups[target])-1]
+		trace.Printf("line %q at y=%.2f joins block %d: gap %.2f < %.2f (block_gap × line height)", ln.text(), ln.box.Y0, target, last.box.Y0-ln.box.Y1, last.box.Height()*h.BlockGap)
+		groups[target] = append(groups[target], ln)
+		boxes[target] = boxes[target].Union(ln.box)
+	}
+	return groups
+}
+
+// classifyLines types a group of body lines as heading, list or text.
+func classifyLines(lines []layoutLine, bodySize float64, h Heuristics) BlockType {
+	var size float64
+	var words int
+	bold := true
+	markers := 0
+	for _, ln := range lines {
+		size += ln.fontSize
+		words += len(ln.words)
+		for _, w := range ln.words {
+			if !strings.Contains(strings.ToLower(w.Font), "bold") {
+				bold = false
+			}
+		}
+		if len(ln.words) > 1 && listMarker.MatchString(ln.words[0].S) {
+			markers++
+		}
+	}
+	size /= float64(len(lines))
+
+	switch {
+	case len(lines) <= 3 && bodySize > 0 && size >= bodySize*h.HeadingScale:
+		return BlockHeading
+	case len(lines) <= 2 && bold && words <= 12:
+		return BlockHeading
+	case markers > 0 && markers*2 >= len(lines):
+		return BlockList
+	}
+	return BlockText
+}
+
+// medianFontSize returns the median font size of lines, weighted by the
+// number of words on each line.
+func medianFontSize(lines []layoutLine) float64 {
+	var sizes []float64
+	for _, ln := range lines {
+		for range ln.words {
+			sizes = append(sizes, ln.fontSize)
+		}
+	}
+	if len(sizes) == 0 {
+		return 0
+	}
+	sort.Float64s(sizes)
+	return sizes[len(sizes)/2]
+}
+
+// linesBlock builds a block of the given type from lines.
+func linesBlock(typ BlockType, lines []layoutLine) Block {
+	b := Block{Type: typ}
+	for _, ln := range lines {
+		b.BBox = b.BBox.Union(ln.box)
+		b.Words = append(b.Words, ln.words...)
+		b.Text = joinText(b.Text, ln.text())
+	}
+	return b
+}
+
+// joinText appends a line to text, separated by a newline.
+func joinText(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + "\n" + line
+}
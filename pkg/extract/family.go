@@ -0,0 +1,207 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Family is a kind of document whose text extracts best with its own
+// profile.
+type Family string
+
+const (
+	// FamilyGeneric is any document not recognized as another family. Its
+	// profile is DefaultProfile.
+	FamilyGeneric Family = "generic"
+
+	// FamilyLaTeXPaper is a document typeset with TeX, such as a paper in
+	// two narrow columns set in Computer Modern with tight word spacing.
+	FamilyLaTeXPaper Family = "latex-paper"
+
+	// FamilyOfficeExport is a document exported from an office suite,
+	// such as Word, PowerPoint or LibreOffice, with running headers and
+	// footers in wide page margins.
+	FamilyOfficeExport Family = "office-export"
+
+	// FamilyScanned is a scan: page images, with or without an OCR text
+	// layer, whose baselines are uneven.
+	FamilyScanned Family = "scanned"
+)
+
+// Families lists the families Classify picks from.
+var Families = []Family{FamilyGeneric, FamilyLaTeXPaper, FamilyOfficeExport, FamilyScanned}
+
+// FamilyProfile returns the built-in profile tuned for a family, named
+// after it, or nil for an unknown family. Each profile picks the layout
+// whose line, column and block grouping its heuristics tune; Blocks and
+// Paragraphs use them whatever the layout.
+func FamilyProfile(f Family) *Profile {
+	p := DefaultProfile()
+	p.Name = string(f)
+	h := &p.Heuristics
+	switch f {
+	case FamilyGeneric:
+	case FamilyLaTeXPaper:
+		// Gutters between columns are narrow, glyph runs are positioned
+		// without space characters, and section headings are set only
+		// slightly larger than the body; columns are read one after the
+		// other
+		p.Layout = LayoutColumns
+		h.ColumnGap = 1.5
+		h.RawWordGap = 0.2
+		h.HeadingScale = 1.1
+		h.MarginBand = 0.06
+	case FamilyOfficeExport:
+		// Headers and footers sit in margins of an inch or more and
+		// paragraphs are spaced apart, so text is read by paragraph
+		p.Layout = LayoutParagraphs
+		h.MarginBand = 0.1
+		h.BlockGap = 1.4
+	case FamilyScanned:
+		// OCR baselines waver and words are spaced unevenly, and pages
+		// without an OCR layer have no text at all; words are grouped
+		// into lines and paragraphs by these looser thresholds
+		p.Layout = LayoutParagraphs
+		h.LineTolerance = 0.7
+		h.ColumnGap = 3
+		h.BlockGap = 1.5
+		h.BlockFontSizeTolerance = 3
+		p.SkipEmptyPages = true
+	default:
+		return nil
+	}
+	return p
+}
+
+// Classification is the family Classify picked for a document.
+type Classification struct {
+	Family Family
+
+	// Reasons are the signals that led to the family, such as
+	// "producer pdfTeX-1.40.25" or "font CMR10".
+	Reasons []string
+}
+
+// producerSignals are substrings of the Producer or Creator of documents
+// of each family, matched without case.
+var producerSignals = map[Family][]string{
+	FamilyLaTeXPaper:   {"pdftex", "luatex", "xetex", "dvipdf", "latex", "tex output"},
+	FamilyOfficeExport: {"microsoft", "word", "powerpoint", "excel", "libreoffice", "openoffice", "google docs", "keynote", "wps office"},
+	FamilyScanned:      {"scan", "abbyy", "tesseract", "ocrmypdf", "omnipage", "paper capture", "naps2"},
+}
+
+// fontSignals are prefixes of the names of fonts typical of each family,
+// matched without case and subset tag.
+var fontSignals = map[Family][]string{
+	FamilyLaTeXPaper:   {"cmr", "cmmi", "cmsy", "cmex", "cmbx", "cmti", "cmtt", "lmroman", "lmsans", "lmmono", "sfrm", "nimbusromno9l", "txsy", "msbm"},
+	FamilyOfficeExport: {"calibri", "cambria", "aptos", "arialmt", "arial-", "timesnewromanps", "segoeui", "verdana", "tahoma", "consolas", "georgia"},
+}
+
+// Classify inspects the producer, creator and fonts of doc and how much
+// of it is page images without text, and picks the family of its
+// document. A document that is mostly images without text, or produced
+// by OCR software, is FamilyScanned; otherwise the family with the most
+// signals wins, the producer counting twice, and FamilyGeneric when there
+// are none or a tie. Signals that cannot be read, such as the fonts of a
+// file whose page tree is damaged, are left out.
+func Classify(doc *crazypdf.Document) (*Classification, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	// The page tree of a damaged file may not be readable here even
+	// though its text is; the page and font signals are then left out
+	caps, capsErr := doc.Capabilities()
+	fonts, _ := file.Fonts()
+
+	scores := make(map[Family]int)
+	reasons := make(map[Family][]string)
+	info, _ := file.Resolve(file.Trailer()["Info"]).(internalpdf.Dict)
+	for _, key := range []internalpdf.Name{"Producer", "Creator"} {
+		s, ok := file.Resolve(info[key]).(internalpdf.String)
+		if !ok {
+			continue
+		}
+		value := internalpdf.DecodeTextString(s)
+		for _, f := range Families {
+			if containsAny(strings.ToLower(value), producerSignals[f]) {
+				scores[f] += 2
+				reasons[f] = append(reasons[f], strings.ToLower(string(key))+" "+value)
+			}
+		}
+	}
+
+	if capsErr == nil {
+		pages := doc.NumPages()
+		imagePages := pages - len(caps.TextPages)
+		if pages > 0 && caps.Images >= imagePages && imagePages*2 > pages {
+			reasons[FamilyScanned] = append(reasons[FamilyScanned], fmt.Sprintf("%d of %d pages without text, %d images", imagePages, pages, caps.Images))
+			return &Classification{Family: FamilyScanned, Reasons: reasons[FamilyScanned]}, nil
+		}
+		if scores[FamilyScanned] > 0 && caps.Images > 0 {
+			return &Classification{Family: FamilyScanned, Reasons: reasons[FamilyScanned]}, nil
+		}
+	}
+
+	for _, font := range fonts {
+		name := font.BaseFont
+		if _, after, ok := strings.Cut(name, "+"); ok {
+			name = after
+		}
+		for _, f := range []Family{FamilyLaTeXPaper, FamilyOfficeExport} {
+			if hasPrefixAny(strings.ToLower(name), fontSignals[f]) {
+				scores[f]++
+				reasons[f] = append(reasons[f], "font "+name)
+			}
+		}
+	}
+
+	latex, office := scores[FamilyLaTeXPaper], scores[FamilyOfficeExport]
+	switch {
+	case latex > office:
+		return &Classification{Family: FamilyLaTeXPaper, Reasons: reasons[FamilyLaTeXPaper]}, nil
+	case office > latex:
+		return &Classification{Family: FamilyOfficeExport, Reasons: reasons[FamilyOfficeExport]}, nil
+	}
+	return &Classification{Family: FamilyGeneric}, nil
+}
+
+// AutoProfile classifies doc and returns the profile of its family: the
+// one given for the family in profiles, to override the built-in tuning,
+// or FamilyProfile's. profiles may be nil.
+func AutoProfile(doc *crazypdf.Document, profiles map[Family]*Profile) (*Profile, *Classification, error) {
+	c, err := Classify(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p, ok := profiles[c.Family]; ok && p != nil {
+		return p, c, nil
+	}
+	return FamilyProfile(c.Family), c, nil
+}
+
+// containsAny reports whether s contains any of substrs.
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrefixAny reports whether s starts with any of prefixes.
+func hasPrefixAny(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
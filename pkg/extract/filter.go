@@ -0,0 +1,253 @@
+package extract
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// filtering reports whether text is filtered by font, font size, region
+// or layer.
+func (c *textConfig) filtering() bool {
+	return c.MinFontSize > 0 || c.MaxFontSize > 0 || len(c.ExcludeRegions) > 0 ||
+		len(c.IncludeFonts) > 0 || len(c.ExcludeFonts) > 0 || c.Layers != nil
+}
+
+// fontSelected reports whether text in the named font passes the include
+// and exclude font patterns. Patterns are matched without case against
+// the name without its subset tag. Text whose font is unknown passes.
+func (c *textConfig) fontSelected(name string) bool {
+	if name == "" {
+		return true
+	}
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		name = name[i+1:]
+	}
+	name = strings.ToLower(name)
+	matches := func(patterns []string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(strings.ToLower(p), name); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if len(c.IncludeFonts) > 0 && !matches(c.IncludeFonts) {
+		return false
+	}
+	return !matches(c.ExcludeFonts)
+}
+
+// textFilter drops the text of a page in fonts not selected, outside the
+// configured font size range, inside the excluded regions or on layers
+// not kept. Text rows carry no fonts, font sizes or layers, so those of a
+// run of text are the ones of the glyph shown at or just before its start
+// on the same baseline.
+type textFilter struct {
+	cfg     *textConfig
+	glyphs  map[int64][]filterGlyph // by rounded baseline, sorted by X
+	regions []crazypdf.Rect         // excluded regions in PDF user space
+}
+
+// filterGlyph is a glyph of the page and whether it is on a layer not
+// kept.
+type filterGlyph struct {
+	crazypdf.Glyph
+	hidden bool
+}
+
+// newTextFilter returns the filter of a page.
+func newTextFilter(page *crazypdf.Page, cfg *textConfig) (*textFilter, error) {
+	glyphs, err := page.Glyphs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page glyphs: %w", err)
+	}
+	var shown []bool
+	if cfg.Layers != nil {
+		if shown, err = page.GlyphsShown(cfg.Layers); err != nil {
+			return nil, fmt.Errorf("failed to read page layers: %w", err)
+		}
+	}
+	f := &textFilter{cfg: cfg, glyphs: make(map[int64][]filterGlyph)}
+	if len(cfg.ExcludeRegions) > 0 {
+		coords, err := cfg.coordinates(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range cfg.ExcludeRegions {
+			f.regions = append(f.regions, coords.ToPDF(r))
+		}
+	}
+	for i, g := range glyphs {
+		y := int64(math.Round(g.Y))
+		f.glyphs[y] = append(f.glyphs[y], filterGlyph{Glyph: g, hidden: i < len(shown) && !shown[i]})
+	}
+	for _, line := range f.glyphs {
+		sort.SliceStable(line, func(i, j int) bool { return line[i].X < line[j].X })
+	}
+	return f, nil
+}
+
+// glyphAt returns the glyph that starts the text s shown at or just before
+// x on the baseline y, or nil when there is none. Of glyphs at the same
+// position, as when a font without widths leaves them unadvanced, the one
+// from which the following glyphs spell s is preferred.
+func (f *textFilter) glyphAt(x, y float64, s string) *filterGlyph {
+	var best *filterGlyph
+	base := int64(math.Round(y))
+	for dy := int64(-1); dy <= 1; dy++ {
+		line := f.glyphs[base+dy]
+		i := sort.Search(len(line), func(i int) bool { return line[i].X > x+0.5 })
+		if i == 0 || (best != nil && line[i-1].X <= best.X) {
+			continue
+		}
+		best = &line[i-1]
+		for j := i - 1; j >= 0 && line[j].X == line[i-1].X; j-- {
+			if spells(line[j:], s) {
+				best = &line[j]
+			}
+		}
+	}
+	return best
+}
+
+// spells reports whether the glyphs start with the text s.
+func spells(glyphs []filterGlyph, s string) bool {
+	for _, g := range glyphs {
+		if s == "" {
+			break
+		}
+		if !strings.HasPrefix(s, g.S) || g.S == "" {
+			return false
+		}
+		s = s[len(g.S):]
+	}
+	return s == ""
+}
+
+// keep reports whether the text s in the given font, starting at x on the
+// baseline y, passes the filters. A width of 0 is estimated and an empty
+// font or font size of 0 is looked up; text whose font or font size stays
+// unknown passes the font or font size filter. The text is in an
+// excluded region when its center is, and on a layer not kept when the
+// glyph starting it is.
+func (f *textFilter) keep(s string, x, y, w, size float64, font string) bool {
+	size = math.Abs(size)
+	if size == 0 || font == "" || f.cfg.Layers != nil {
+		if g := f.glyphAt(x, y, s); g != nil {
+			if g.hidden {
+				return false
+			}
+			if size == 0 {
+				size = math.Abs(g.FontSize)
+			}
+			if font == "" {
+				font = g.Font
+			}
+		}
+	}
+	if !f.cfg.fontSelected(font) {
+		return false
+	}
+	if size > 0 && (size < f.cfg.MinFontSize || (f.cfg.MaxFontSize > 0 && size > f.cfg.MaxFontSize)) {
+		return false
+	}
+	if len(f.regions) == 0 {
+		return true
+	}
+	if size == 0 {
+		size = 12
+	}
+	if w <= 0 {
+		w = float64(utf8.RuneCountInString(s)) * size * 0.5
+	}
+	cx, cy := x+w/2, y+size*0.3
+	for _, r := range f.regions {
+		if r.Contains(cx, cy) {
+			return false
+		}
+	}
+	return true
+}
+
+// pageRows returns the text rows of a page without the text the filters
+// drop. Rows the filters leave empty are dropped.
+func pageRows(page *crazypdf.Page, cfg *textConfig) ([]internalpdf.TextRow, error) {
+	rows, err := page.TextByRow()
+	if err != nil || !cfg.filtering() {
+		return rows, err
+	}
+	f, err := newTextFilter(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := rows[:0]
+	for _, row := range rows {
+		if len(row.Words) == 0 {
+			out = append(out, row)
+			continue
+		}
+		var words []internalpdf.TextWord
+		for _, w := range row.Words {
+			if f.keep(w.S, w.X, w.Y, w.W, w.FontSize, w.Font) {
+				words = append(words, w)
+			}
+		}
+		if len(words) > 0 {
+			out = append(out, internalpdf.TextRow{Position: row.Position, Words: words})
+		}
+	}
+	return out, nil
+}
+
+// pageStyledTexts returns the styled texts of a page without the text the
+// filters drop.
+func pageStyledTexts(page *crazypdf.Page, cfg *textConfig) ([]internalpdf.StyledText, error) {
+	texts, err := page.StyledTexts()
+	if err != nil || !cfg.filtering() {
+		return texts, err
+	}
+	f, err := newTextFilter(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := texts[:0]
+	for _, st := range texts {
+		if f.keep(st.Text, st.X, st.Y, st.W, st.FontSize, st.Font) {
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+
+// pageWords returns the words of a page without the words the filters
+// drop.
+func pageWords(page *crazypdf.Page, cfg *textConfig) ([]crazypdf.Word, error) {
+	words, err := page.Words()
+	if err != nil || !cfg.filtering() {
+		return words, err
+	}
+	f, err := newTextFilter(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := words[:0]
+	for _, w := range words {
+		// Word boxes reach a fifth of the font size below the baseline,
+		// taking 12 points when the size is unknown
+		size := math.Abs(w.FontSize)
+		if size == 0 {
+			size = 12
+		}
+		if f.keep(w.S, w.BBox.X0, w.BBox.Y0+size*0.2, w.BBox.Width(), w.FontSize, w.Font) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
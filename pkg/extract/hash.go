@@ -0,0 +1,94 @@
+package extract
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Options is a list of extraction options, as passed to Text and the
+// other extraction functions.
+type Options []Option
+
+// Hash returns a stable key of the settings the options result in, for
+// caches of extraction results: options that configure extraction alike,
+// in whatever order and through whichever options, have the same hash,
+// and options differing in a setting that changes the text or geometry
+// returned have different hashes. Font patterns and layer names count as
+// sets. WithSkippedPages and WithTrace only observe extraction and are
+// left out, and an analyzer set with WithLayoutAnalyzer counts by its
+// type alone, so a cache using analyzers that hold state must key them
+// itself. The hash covers settings, not the extraction code: key cached
+// results with the version of this package too. It is the hex SHA-256 of
+// a canonical encoding of the settings.
+func (o Options) Hash() string {
+	cfg, _ := applyOptions(o)
+	return cfg.hash()
+}
+
+// Hash returns the hash of the settings of p, as Options.Hash does for
+// WithProfile(p) alone. The profile's Name does not count.
+func (p *Profile) Hash() string {
+	return Options{WithProfile(p)}.Hash()
+}
+
+// hash returns the hash of the settings, as described at Options.Hash.
+func (c *textConfig) hash() string {
+	p := c.profile()
+	key := struct {
+		*Profile
+		// Layers is not omitted when empty: WithLayers() keeps only the
+		// text outside layers, where no WithLayers keeps all text
+		Layers   []string `json:"layers"`
+		Analyzer string   `json:"analyzer,omitempty"`
+	}{Profile: p, Layers: p.Layers}
+	if c.Analyzer != nil {
+		key.Analyzer = fmt.Sprintf("%T", c.Analyzer)
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		// Settings JSON cannot hold, such as a NaN font size
+		data = []byte(fmt.Sprintf("%v %v %q", *p, p.Layers, key.Analyzer))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// profile returns the settings of c as a profile, with font patterns and
+// layer names sorted.
+func (c *textConfig) profile() *Profile {
+	p := &Profile{
+		Version:           ProfileVersion,
+		Layout:            c.Layout,
+		PageSeparator:     c.PageSeparator,
+		PageWidth:         c.PageWidth,
+		SkipEmptyPages:    c.SkipEmpty,
+		ImagePlaceholder:  c.ImagePlaceholder,
+		FigureAltText:     c.AltText,
+		MinFontSize:       c.MinFontSize,
+		MaxFontSize:       c.MaxFontSize,
+		IncludeFonts:      sortedStrings(c.IncludeFonts),
+		ExcludeFonts:      sortedStrings(c.ExcludeFonts),
+		Layers:            sortedStrings(c.LayerNames),
+		VisibleLayersOnly: c.VisibleLayers,
+		CoordinateSpace:   c.Space,
+		DPI:               c.DPI,
+		Heuristics:        c.Heuristics,
+	}
+	for _, r := range c.ExcludeRegions {
+		p.ExcludeRegions = append(p.ExcludeRegions, [4]float64{r.X0, r.Y0, r.X1, r.Y1})
+	}
+	return p
+}
+
+// sortedStrings returns a sorted copy of s, nil when s is nil.
+func sortedStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	out := append([]string{}, s...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,40 @@
+package extract
+
+import (
+	"fmt"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Links returns the links of every page with their anchor text, in page
+// order. See crazypdf.Page.Links. Link areas are in the space set with
+// WithCoordinateSpace and WithDPI; destinations stay in the PDF user
+// space of their target page, and other options are ignored.
+func Links(doc *crazypdf.Document, opts ...Option) ([]crazypdf.Link, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var links []crazypdf.Link
+	for _, page := range doc.Pages() {
+		pageLinks, err := page.Links()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read links of page %d: %w", page.Number, err)
+		}
+		coords, err := cfg.coordinates(page)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pageLinks {
+			pageLinks[i].Rect = coords.Rect(pageLinks[i].Rect)
+			for j, q := range pageLinks[i].Quads {
+				pageLinks[i].Quads[j] = coords.Quad(q)
+			}
+		}
+		links = append(links, pageLinks...)
+	}
+	return links, nil
+}
@@ -0,0 +1,111 @@
+package extract
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizeText returns text in the form produced by LayoutNormalized,
+// for comparing texts extracted by other means:
+//
+//   - letters followed by a combining accent are composed, so "e\u0301"
+//     becomes "é"
+//   - ligatures such as "ﬁ" are split into their letters, and typographic
+//     quotes, dashes and spaces are replaced by their ASCII forms
+//   - soft hyphens and zero-width characters are removed
+//   - runs of spaces and tabs become a single space, lines are trimmed and
+//     blank lines are dropped
+func NormalizeText(text string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(normalizeRunes(line)), " ")
+		if line == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// normalizeRunes applies the character mappings of NormalizeText.
+func normalizeRunes(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if next, nsize := utf8.DecodeRuneInString(s[i:]); unicode.Is(unicode.Mn, next) {
+			if c, ok := compositions[[2]rune{r, next}]; ok {
+				r = c
+				i += nsize
+			}
+		}
+		switch {
+		case normalizedRunes[r] != "":
+			sb.WriteString(normalizedRunes[r])
+		case r == '\u00ad' || unicode.Is(unicode.Cf, r):
+			// soft hyphen, zero-width and bidi controls
+		case unicode.IsSpace(r) || unicode.Is(unicode.Zs, r):
+			sb.WriteByte(' ')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// normalizedRunes maps characters to their plain replacements.
+var normalizedRunes = map[rune]string{
+	'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st", 'ﬆ': "st",
+	'\u2018': "'", '\u2019': "'", '\u201a': "'", '\u201b': "'", '\u2032': "'",
+	'\u201c': `"`, '\u201d': `"`, '\u201e': `"`, '\u201f': `"`, '\u2033': `"`,
+	'\u00ab': `"`, '\u00bb': `"`,
+	'\u2010': "-", '\u2011': "-", '\u2012': "-", '\u2013': "-", '\u2014': "-",
+	'\u2015': "-", '\u2212': "-",
+	'\u2026': "...", '\u2022': "*",
+}
+
+// compositions maps a letter and a combining accent to the precomposed
+// character.
+var compositions = func() map[[2]rune]rune {
+	const table = "" +
+		"A\u0300ÀA\u0301ÁA\u0302ÂA\u0303ÃA\u0304ĀA\u0306ĂA\u0307ȦA\u0308Ä" +
+		"A\u030aÅA\u030cǍA\u0328Ąa\u0300àa\u0301áa\u0302âa\u0303ãa\u0304ā" +
+		"a\u0306ăa\u0307ȧa\u0308äa\u030aåa\u030cǎa\u0328ąC\u0301ĆC\u0302Ĉ" +
+		"C\u0307ĊC\u030cČC\u0327Çc\u0301ćc\u0302ĉc\u0307ċc\u030cčc\u0327ç" +
+		"D\u0307ḊD\u030cĎD\u0327Ḑd\u0307ḋd\u030cďd\u0327ḑE\u0300ÈE\u0301É" +
+		"E\u0302ÊE\u0303ẼE\u0304ĒE\u0306ĔE\u0307ĖE\u0308ËE\u030cĚE\u0327Ȩ" +
+		"E\u0328Ęe\u0300èe\u0301ée\u0302êe\u0303ẽe\u0304ēe\u0306ĕe\u0307ė" +
+		"e\u0308ëe\u030cěe\u0327ȩe\u0328ęG\u0301ǴG\u0302ĜG\u0304ḠG\u0306Ğ" +
+		"G\u0307ĠG\u030cǦG\u0327Ģg\u0301ǵg\u0302ĝg\u0304ḡg\u0306ğg\u0307ġ" +
+		"g\u030cǧg\u0327ģH\u0302ĤH\u0307ḢH\u0308ḦH\u030cȞH\u0327Ḩh\u0302ĥ" +
+		"h\u0307ḣh\u0308ḧh\u030cȟh\u0327ḩI\u0300ÌI\u0301ÍI\u0302ÎI\u0303Ĩ" +
+		"I\u0304ĪI\u0306ĬI\u0307İI\u0308ÏI\u030cǏI\u0328Įi\u0300ìi\u0301í" +
+		"i\u0302îi\u0303ĩi\u0304īi\u0306ĭi\u0308ïi\u030cǐi\u0328įJ\u0302Ĵ" +
+		"j\u0302ĵj\u030cǰK\u0301ḰK\u030cǨK\u0327Ķk\u0301ḱk\u030cǩk\u0327ķ" +
+		"L\u0301ĹL\u030cĽL\u0327Ļl\u0301ĺl\u030cľl\u0327ļN\u0300ǸN\u0301Ń" +
+		"N\u0303ÑN\u0307ṄN\u030cŇN\u0327Ņn\u0300ǹn\u0301ńn\u0303ñn\u0307ṅ" +
+		"n\u030cňn\u0327ņO\u0300ÒO\u0301ÓO\u0302ÔO\u0303ÕO\u0304ŌO\u0306Ŏ" +
+		"O\u0307ȮO\u0308ÖO\u030bŐO\u030cǑO\u0328Ǫo\u0300òo\u0301óo\u0302ô" +
+		"o\u0303õo\u0304ōo\u0306ŏo\u0307ȯo\u0308öo\u030bőo\u030cǒo\u0328ǫ" +
+		"R\u0301ŔR\u0307ṘR\u030cŘR\u0327Ŗr\u0301ŕr\u0307ṙr\u030cřr\u0327ŗ" +
+		"S\u0301ŚS\u0302ŜS\u0307ṠS\u030cŠS\u0327Şs\u0301śs\u0302ŝs\u0307ṡ" +
+		"s\u030cšs\u0327şT\u0307ṪT\u030cŤT\u0327Ţt\u0307ṫt\u0308ẗt\u030cť" +
+		"t\u0327ţU\u0300ÙU\u0301ÚU\u0302ÛU\u0303ŨU\u0304ŪU\u0306ŬU\u0308Ü" +
+		"U\u030aŮU\u030bŰU\u030cǓU\u0328Ųu\u0300ùu\u0301úu\u0302ûu\u0303ũ" +
+		"u\u0304ūu\u0306ŭu\u0308üu\u030aůu\u030bűu\u030cǔu\u0328ųW\u0300Ẁ" +
+		"W\u0301ẂW\u0302ŴW\u0307ẆW\u0308Ẅw\u0300ẁw\u0301ẃw\u0302ŵw\u0307ẇ" +
+		"w\u0308ẅw\u030aẘY\u0300ỲY\u0301ÝY\u0302ŶY\u0303ỸY\u0304ȲY\u0307Ẏ" +
+		"Y\u0308Ÿy\u0300ỳy\u0301ýy\u0302ŷy\u0303ỹy\u0304ȳy\u0307ẏy\u0308ÿ" +
+		"y\u030aẙZ\u0301ŹZ\u0302ẐZ\u0307ŻZ\u030cŽz\u0301źz\u0302ẑz\u0307ż" +
+		"z\u030cž"
+	m := make(map[[2]rune]rune)
+	runes := []rune(table)
+	for i := 0; i+2 < len(runes); i += 3 {
+		m[[2]rune{runes[i], runes[i+1]}] = runes[i+2]
+	}
+	return m
+}()
@@ -1,5 +1,13 @@
 package extract
 
+import (
+	"fmt"
+	"io"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
 // LayoutMode controls how text is extracted from PDF pages.
 type LayoutMode int
 
@@ -15,13 +23,103 @@ const (
 	// LayoutPhysical attempts to preserve the physical/spatial layout
 	// of text on the page, using x,y coordinates to position text.
 	LayoutPhysical
+
+	// LayoutNormalized produces stable text for comparing document
+	// versions: lines ordered by position, top to bottom and left to
+	// right, with whitespace collapsed and Unicode normalized as by
+	// NormalizeText. Text that only moved within its line, was re-encoded
+	// or was set with different ligatures or spacing compares equal.
+	LayoutNormalized
+
+	// LayoutParagraphs writes each paragraph as one line, with blank lines
+	// between paragraphs, as grouped by Paragraphs.
+	LayoutParagraphs
+
+	// LayoutColumns writes lines in reading order column by column, with
+	// columns found by recursive XY-cut, so the lines of a two-column
+	// paper are not interleaved.
+	LayoutColumns
+)
+
+// String returns the lowercase name of the layout mode, as accepted by
+// UnmarshalText.
+func (m LayoutMode) String() string {
+	switch m {
+	case LayoutSimple:
+		return "simple"
+	case LayoutRaw:
+		return "raw"
+	case LayoutPhysical:
+		return "physical"
+	case LayoutNormalized:
+		return "normalized"
+	case LayoutParagraphs:
+		return "paragraphs"
+	case LayoutColumns:
+		return "columns"
+	default:
+		return fmt.Sprintf("LayoutMode(%d)", int(m))
+	}
+}
+
+// MarshalText encodes the layout mode by name, so JSON profiles are
+// readable.
+func (m LayoutMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a layout mode from its name.
+func (m *LayoutMode) UnmarshalText(text []byte) error {
+	for _, mode := range []LayoutMode{LayoutSimple, LayoutRaw, LayoutPhysical, LayoutNormalized, LayoutParagraphs, LayoutColumns} {
+		if string(text) == mode.String() {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown layout mode %q", text)
+}
+
+// CoordinateSpace is the coordinate system of the geometry returned by
+// Blocks, Lines and Links and of the regions given to
+// WithExcludeRegions; see WithCoordinateSpace.
+type CoordinateSpace = crazypdf.CoordinateSpace
+
+// Coordinate spaces.
+const (
+	PDFDefault = crazypdf.PDFDefault
+	TopLeft    = crazypdf.TopLeft
 )
 
 // textConfig holds configuration for text extraction operations.
 type textConfig struct {
 	Layout        LayoutMode
 	PageSeparator string
-	PageWidth     float64 // page width in points for physical layout
+	PageWidth     float64 // page width in points for physical layout; 0 uses the crop box
+	Analyzer      LayoutAnalyzer
+	SkipEmpty     bool           // leave pages without text out of Text
+	OnSkip        func(page int) // called for each page left out
+
+	ImagePlaceholder string // marker written where images occur; empty for none
+	AltText          string // format of figure alternate text written where figures occur; empty for none
+
+	MinFontSize    float64         // smallest font size kept; 0 for no minimum
+	MaxFontSize    float64         // largest font size kept; 0 for no maximum
+	ExcludeRegions []crazypdf.Rect // regions whose text is dropped
+	IncludeFonts   []string        // font name patterns whose text is kept; nil for all
+	ExcludeFonts   []string        // font name patterns whose text is dropped
+
+	Layers        func(crazypdf.Layer) bool // layers whose text is kept, besides text outside layers; nil for all
+	LayerNames    []string                  // layers given to WithLayers; nil when not used
+	VisibleLayers bool                      // set by WithVisibleLayersOnly
+
+	Space crazypdf.CoordinateSpace // coordinate space of returned and given geometry
+	DPI   float64                  // pixels per inch of returned and given geometry; 0 for points
+
+	Heuristics Heuristics // thresholds of line grouping, the built-in analyzer and LayoutRaw
+
+	Trace io.Writer // receives the layout decisions taken; nil for none
+
+	err error // the first option that could not be applied
 }
 
 // Option is a functional option for configuring text extraction.
@@ -42,28 +140,219 @@ func WithPageSeparator(sep string) Option {
 	}
 }
 
+// WithSkipEmptyPages leaves pages whose text is empty or only whitespace
+// out of Text and TextContext, together with their page separators, so
+// blank or image-only pages do not produce runs of empty chunks.
+// AllPages still returns one entry per page. Default is false.
+func WithSkipEmptyPages(enabled bool) Option {
+	return func(c *textConfig) {
+		c.SkipEmpty = enabled
+	}
+}
+
+// WithSkippedPages sets a function called with the 1-based number of each
+// page that WithSkipEmptyPages leaves out, in page order.
+func WithSkippedPages(fn func(page int)) Option {
+	return func(c *textConfig) {
+		c.OnSkip = fn
+	}
+}
+
 // WithPageWidth sets the page width in PDF points for physical layout mode.
-// Default is 612 (US Letter width). This affects column spacing in LayoutPhysical.
+// By default each page's own crop box width is used. This affects column
+// spacing in LayoutPhysical.
 func WithPageWidth(width float64) Option {
 	return func(c *textConfig) {
 		c.PageWidth = width
 	}
 }
 
+// WithLayoutAnalyzer sets the analyzer used by Blocks to segment pages.
+// Default is DefaultLayoutAnalyzer.
+func WithLayoutAnalyzer(a LayoutAnalyzer) Option {
+	return func(c *textConfig) {
+		c.Analyzer = a
+	}
+}
+
+// WithImagePlaceholder writes a marker on a line of its own where each
+// image occurs in the text of a page, so consumers such as LLM ingestion
+// pipelines know a figure was there. The marker is a fmt format given
+// the image width and height in pixels, such as "[IMAGE: %dx%d]"; one
+// without verbs, such as "[IMAGE]", is written as is. Images are placed
+// by their top edge among the lines of text. Default is "", no markers.
+func WithImagePlaceholder(format string) Option {
+	return func(c *textConfig) {
+		c.ImagePlaceholder = format
+	}
+}
+
+// WithFigureAltText writes the alternate text of each figure of a tagged
+// document on a line of its own at the figure's position in the text of
+// a page, so accessibility-aware pipelines see what images show. The
+// format is a fmt format given the text, such as "[FIGURE: %s]". Figures
+// without alternate text are left out, and figures whose position is
+// unknown are written after the page text. Default is "", no alternate
+// text.
+func WithFigureAltText(format string) Option {
+	return func(c *textConfig) {
+		c.AltText = format
+	}
+}
+
+// WithFontSizeRange keeps only text set in font sizes from min to max
+// points, dropping fine print such as legal boilerplate below min or
+// display type above max. A max of 0 sets no upper limit. Text whose font
+// size is unknown is kept. Default is no range.
+func WithFontSizeRange(min, max float64) Option {
+	return func(c *textConfig) {
+		c.MinFontSize = min
+		c.MaxFontSize = max
+	}
+}
+
+// WithExcludeRegions drops text whose center lies in any of the regions
+// from every page, such as a letterhead or a footer fixed in place. The
+// regions are in the coordinate space set with WithCoordinateSpace and
+// WithDPI, PDF user space by default. Default is no regions.
+func WithExcludeRegions(regions []crazypdf.Rect) Option {
+	return func(c *textConfig) {
+		c.ExcludeRegions = regions
+	}
+}
+
+// WithFontFilter selects text by the name of its font, such as dropping a
+// watermark or barcode font, or keeping only the font of the body text.
+// Text is kept when its font matches one of include, or include is
+// empty, and matches none of exclude. Patterns are path.Match globs, such
+// as "Arial*" or "*Barcode*", matched without case against the font's
+// base name without a subset tag. Default is no filter.
+func WithFontFilter(include, exclude []string) Option {
+	return func(c *textConfig) {
+		c.IncludeFonts = include
+		c.ExcludeFonts = exclude
+	}
+}
+
 // defaultConfig returns the default text extraction configuration.
 func defaultConfig() *textConfig {
 	return &textConfig{
 		Layout:        LayoutSimple,
 		PageSeparator: "\n\n",
-		PageWidth:     612,
+		Heuristics:    DefaultHeuristics(),
 	}
 }
 
-// applyOptions creates a textConfig from the given options.
-func applyOptions(opts []Option) *textConfig {
+// applyOptions creates a textConfig from the given options. It fails if
+// any of them could not be applied.
+func applyOptions(opts []Option) (*textConfig, error) {
 	cfg := defaultConfig()
 	for _, opt := range opts {
 		opt(cfg)
 	}
-	return cfg
+	return cfg, cfg.err
+}
+
+// fail records err as the reason an option could not be applied, unless
+// an earlier option failed.
+func (c *textConfig) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// layoutThresholds returns the thresholds of LayoutSimple and
+// LayoutPhysical from the heuristics.
+func (c *textConfig) layoutThresholds() internalpdf.LayoutThresholds {
+	return internalpdf.LayoutThresholds{
+		WordGap:         c.Heuristics.WordGap,
+		WordGapFontSize: c.Heuristics.WordGapFontSize,
+		LineTolerance:   c.Heuristics.PhysicalLineTolerance,
+		Columns:         c.Heuristics.PhysicalColumns,
+	}
+}
+
+// WithVisibleLayersOnly drops the text of layers (optional content
+// groups) hidden by default, such as alternate translations or stamps a
+// viewer does not show, keeping the text a reader sees on screen. Text
+// outside layers is kept. Default is the text of all layers.
+func WithVisibleLayersOnly() Option {
+	return func(c *textConfig) {
+		c.Layers = func(l crazypdf.Layer) bool { return l.Visible }
+		c.LayerNames = nil
+		c.VisibleLayers = true
+	}
+}
+
+// WithLayers keeps the text of the named layers (optional content
+// groups), whether visible by default or not, and drops that of the
+// others, to select one translation or include a hidden stamp
+// deliberately. Text outside layers is kept. It replaces
+// WithVisibleLayersOnly. Default is the text of all layers.
+func WithLayers(names ...string) Option {
+	return func(c *textConfig) {
+		keep := make(map[string]bool, len(names))
+		for _, name := range names {
+			keep[name] = true
+		}
+		c.Layers = func(l crazypdf.Layer) bool { return keep[l.Name] }
+		c.LayerNames = append([]string{}, names...)
+		c.VisibleLayers = false
+	}
+}
+
+// WithCoordinateSpace sets the coordinate space of the geometry Blocks,
+// Lines and Links return and of the regions given to
+// WithExcludeRegions: PDFDefault, PDF user space with the Y axis pointing
+// up, or TopLeft, with the origin at the top-left corner of the media box
+// and the Y axis pointing down. Default is PDFDefault.
+func WithCoordinateSpace(space CoordinateSpace) Option {
+	return func(c *textConfig) {
+		c.Space = space
+	}
+}
+
+// WithDPI scales the geometry Blocks, Lines and Links return, and the
+// regions given to WithExcludeRegions, to pixels at dpi dots per inch,
+// to line up with a page rendered at that resolution. Font sizes stay in
+// points. Default is 0, coordinates in points.
+func WithDPI(dpi float64) Option {
+	return func(c *textConfig) {
+		c.DPI = dpi
+	}
+}
+
+// WithTrace writes each layout decision to w, one line per decision
+// prefixed with the page number, with the coordinates and the threshold
+// that decided it: where a space is inserted or glyph groups are joined
+// in LayoutSimple and LayoutRaw, where lines start and texts are placed
+// in LayoutPhysical, and which words form a line, where lines split into
+// columns and which lines form a block in LayoutNormalized, Lines and
+// Blocks with the built-in analyzer. Paragraphs and LayoutParagraphs
+// also trace paragraph breaks, and LayoutColumns the cuts between
+// columns and bands. It explains text such as words glued together; the
+// output is meant for people and its format may change.
+// Write errors are ignored. Default is nil, no trace.
+func WithTrace(w io.Writer) Option {
+	return func(c *textConfig) {
+		c.Trace = w
+	}
+}
+
+// tracer returns the Tracer writing the layout decisions on page to the
+// configured trace writer, or nil when there is none.
+func (c *textConfig) tracer(page int) internalpdf.Tracer {
+	if c.Trace == nil {
+		return nil
+	}
+	w := c.Trace
+	return func(format string, args ...any) {
+		fmt.Fprintf(w, "page %d: %s\n", page, fmt.Sprintf(format, args...))
+	}
+}
+
+// coordinates returns the conversion of geometry on page to the
+// configured coordinate space.
+func (c *textConfig) coordinates(page *crazypdf.Page) (crazypdf.Coordinates, error) {
+	return page.Coordinates(c.Space, c.DPI)
 }
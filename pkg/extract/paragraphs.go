@@ -0,0 +1,173 @@
+package extract
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Paragraph is a run of lines of a block that reads as one paragraph.
+type Paragraph struct {
+	// BBox is the paragraph's bounding box.
+	BBox crazypdf.Rect
+
+	// Text is the lines of the paragraph joined by single spaces, or
+	// without one after a line ending in a hyphen.
+	Text string
+
+	// FontSize is the mean font size of the lines.
+	FontSize float64
+
+	Lines []Line
+}
+
+// Paragraphs groups the lines of a page into paragraphs, top to bottom.
+// Lines are first grouped into blocks as Blocks does; a block is then
+// split before a line set further below the previous one than
+// h.ParagraphGap times the usual spacing of its lines, before a line
+// indented by more than h.ParagraphIndent font sizes below one that is
+// not, and where the font or font size changes. Words the filters drop
+// are left out, geometry is in the space set with WithCoordinateSpace and
+// WithDPI, and the thresholds are those of WithProfile.
+func Paragraphs(page *crazypdf.Page, opts ...Option) ([]Paragraph, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	coords, err := cfg.coordinates(page)
+	if err != nil {
+		return nil, err
+	}
+	words, err := pageWords(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var paragraphs []Paragraph
+	for _, lines := range layoutParagraphs(words, cfg.Heuristics, cfg.tracer(page.Number)) {
+		p := Paragraph{Text: paragraphText(lines)}
+		for _, ln := range lines {
+			p.BBox = p.BBox.Union(ln.box)
+			p.FontSize += ln.fontSize
+			p.Lines = append(p.Lines, Line{BBox: coords.Rect(ln.box), Text: ln.text(), FontSize: ln.fontSize, Words: coords.Words(ln.words)})
+		}
+		p.BBox = coords.Rect(p.BBox)
+		p.FontSize /= float64(len(lines))
+		paragraphs = append(paragraphs, p)
+	}
+	return paragraphs, nil
+}
+
+// layoutParagraphs groups words into lines, lines into blocks and splits
+// each block into paragraphs.
+func layoutParagraphs(words []crazypdf.Word, h Heuristics, trace internalpdf.Tracer) [][]layoutLine {
+	var paragraphs [][]layoutLine
+	for _, group := range groupLayoutLines(layoutLines(words, h, trace), h, trace) {
+		paragraphs = append(paragraphs, splitParagraphs(group, h, trace)...)
+	}
+	return paragraphs
+}
+
+// splitParagraphs splits a block of lines, top to bottom, into
+// paragraphs at wide line spacing, first-line indents and font changes.
+func splitParagraphs(lines []layoutLine, h Heuristics, trace internalpdf.Tracer) [][]layoutLine {
+	left := math.Inf(1)
+	var spacings []float64
+	for i, ln := range lines {
+		left = math.Min(left, ln.box.X0)
+		if i > 0 {
+			if d := lines[i-1].box.Y0 - ln.box.Y0; d > 0 {
+				spacings = append(spacings, d)
+			}
+		}
+	}
+	// The usual spacing is the median, so that the gaps between the
+	// paragraphs of a block do not count
+	var leading float64
+	if len(spacings) > 0 {
+		sort.Float64s(spacings)
+		leading = spacings[len(spacings)/2]
+	}
+
+	var paragraphs [][]layoutLine
+	start := 0
+	for i := 1; i < len(lines); i++ {
+		prev, ln := lines[i-1], lines[i]
+		size := ln.fontSize
+		if size <= 0 {
+			size = 12
+		}
+		spacing := prev.box.Y0 - ln.box.Y0
+		indent := size * h.ParagraphIndent
+		prevFont, font := dominantFont(prev.words), dominantFont(ln.words)
+		switch {
+		case leading > 0 && spacing > leading*h.ParagraphGap:
+			trace.Printf("line %q at y=%.2f starts a paragraph: spacing %.2f > %.2f (paragraph_gap × usual spacing %.2f)", ln.text(), ln.box.Y0, spacing, leading*h.ParagraphGap, leading)
+		case ln.box.X0-left > indent && prev.box.X0-left <= indent:
+			trace.Printf("line %q at y=%.2f starts a paragraph: indented %.2f > %.2f (paragraph_indent × font size) below an unindented line", ln.text(), ln.box.Y0, ln.box.X0-left, indent)
+		case font != prevFont:
+			trace.Printf("line %q at y=%.2f starts a paragraph: font %s after %s", ln.text(), ln.box.Y0, font, prevFont)
+		case math.Abs(ln.fontSize-prev.fontSize) > 0.5:
+			trace.Printf("line %q at y=%.2f starts a paragraph: font size %.2f after %.2f", ln.text(), ln.box.Y0, ln.fontSize, prev.fontSize)
+		default:
+			continue
+		}
+		paragraphs = append(paragraphs, lines[start:i])
+		start = i
+	}
+	return append(paragraphs, lines[start:])
+}
+
+// dominantFont returns the font most characters of words are set in.
+func dominantFont(words []crazypdf.Word) string {
+	counts := make(map[string]int)
+	var best string
+	for _, w := range words {
+		counts[w.Font] += len(w.S)
+		if counts[w.Font] > counts[best] || (counts[w.Font] == counts[best] && w.Font < best) {
+			best = w.Font
+		}
+	}
+	return best
+}
+
+// paragraphText joins the lines of a paragraph with single spaces, or
+// without one after a line ending in a hyphen, so that a word broken
+// across lines stays whole.
+func paragraphText(lines []layoutLine) string {
+	var b strings.Builder
+	for i, ln := range lines {
+		text := ln.text()
+		if i > 0 && !strings.HasSuffix(b.String(), "-") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// paragraphLayoutText writes each paragraph of a page as one line, with
+// blank lines between paragraphs.
+func paragraphLayoutText(page *crazypdf.Page, cfg *textConfig) (string, error) {
+	words, err := pageWords(page, cfg)
+	if err != nil {
+		return "", err
+	}
+	paragraphs := layoutParagraphs(words, cfg.Heuristics, cfg.tracer(page.Number))
+	texts := make([]string, len(paragraphs))
+	baselines := make([]float64, len(paragraphs))
+	for i, lines := range paragraphs {
+		texts[i] = paragraphText(lines)
+		baselines[i] = lines[0].box.Y0
+	}
+	if cfg.ImagePlaceholder != "" || cfg.AltText != "" {
+		markers, err := pageMarkers(page, cfg)
+		if err != nil {
+			return "", err
+		}
+		texts = insertMarkers(texts, baselines, markers)
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
@@ -0,0 +1,166 @@
+package extract
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// marker is the placeholder of an image or the alternate text of a
+// figure, placed at its top edge.
+type marker struct {
+	top, left float64
+	text      string
+}
+
+// pageMarkers returns the image placeholders and figure alternate texts
+// configured for a page, from top to bottom and left to right. Figures
+// whose position is unknown come last.
+func pageMarkers(page *crazypdf.Page, cfg *textConfig) ([]marker, error) {
+	var markers []marker
+	if cfg.ImagePlaceholder != "" {
+		items, err := page.Drawing()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page images: %w", err)
+		}
+		for _, it := range items {
+			if !it.Image || it.ImageStream == nil {
+				continue
+			}
+			box := it.BBox()
+			text := cfg.ImagePlaceholder
+			if strings.Contains(text, "%") {
+				text = fmt.Sprintf(text, dimension(it.ImageStream.Dict["Width"]), dimension(it.ImageStream.Dict["Height"]))
+			}
+			markers = append(markers, marker{top: box.Y1, left: box.X0, text: text})
+		}
+	}
+	if cfg.AltText != "" {
+		figures, err := page.Figures()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page figures: %w", err)
+		}
+		for _, fig := range figures {
+			text := strings.Join(strings.Fields(fig.Text()), " ")
+			if text == "" {
+				continue
+			}
+			m := marker{top: math.Inf(-1), text: fmt.Sprintf(cfg.AltText, text)}
+			if !fig.BBox.IsEmpty() {
+				m.top, m.left = fig.BBox.Y1, fig.BBox.X0
+			}
+			markers = append(markers, m)
+		}
+	}
+	sort.SliceStable(markers, func(i, j int) bool {
+		if markers[i].top != markers[j].top {
+			return markers[i].top > markers[j].top
+		}
+		return markers[i].left < markers[j].left
+	})
+	return markers, nil
+}
+
+// dimension returns an image width or height in pixels.
+func dimension(o internalpdf.Object) int {
+	switch v := o.(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// insertMarkers inserts the markers as lines of their own into lines,
+// each before the first line whose baseline lies below its top. baselines holds the baseline of each line, from top to bottom.
+func insertMarkers(lines []string, baselines []float64, markers []marker) []string {
+	out := make([]string, 0, len(lines)+len(markers))
+	m := 0
+	for i, line := range lines {
+		for m < len(markers) && baselines[i] < markers[m].top {
+			out = append(out, markers[m].text)
+			m++
+		}
+		out = append(out, line)
+	}
+	for ; m < len(markers); m++ {
+		out = append(out, markers[m].text)
+	}
+	return out
+}
+
+// withMarkers inserts image placeholders and figure alternate texts into
+// text extracted in LayoutSimple, LayoutRaw or LayoutPhysical, whose
+// lines are the rows of text on the page from top to bottom.
+func withMarkers(page *crazypdf.Page, text string, cfg *textConfig) (string, error) {
+	markers, err := pageMarkers(page, cfg)
+	if err != nil || len(markers) == 0 {
+		return text, err
+	}
+
+	var baselines []float64
+	if cfg.Layout == LayoutPhysical {
+		baselines, err = physicalBaselines(page, cfg)
+	} else {
+		baselines, err = rowBaselines(page, cfg)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	if text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	if len(lines) != len(baselines) {
+		// The text does not line up with the rows; keep it whole and
+		// list the markers after it
+		baselines = make([]float64, len(lines))
+		for i := range baselines {
+			baselines[i] = math.Inf(1)
+		}
+	}
+	return strings.Join(insertMarkers(lines, baselines, markers), "\n"), nil
+}
+
+// rowBaselines returns the baselines of the text rows of a page, one per
+// line of its LayoutSimple and LayoutRaw text.
+func rowBaselines(page *crazypdf.Page, cfg *textConfig) ([]float64, error) {
+	rows, err := pageRows(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	baselines := make([]float64, len(rows))
+	for i, row := range rows {
+		baselines[i] = float64(row.Position)
+	}
+	return baselines, nil
+}
+
+// physicalBaselines returns the baselines of the lines of the
+// LayoutPhysical text of a page, grouping text as PhysicalLayoutText does.
+func physicalBaselines(page *crazypdf.Page, cfg *textConfig) ([]float64, error) {
+	texts, err := pageStyledTexts(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ys := make([]float64, len(texts))
+	for i, st := range texts {
+		ys[i] = st.Y
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(ys)))
+
+	const yTolerance = 2.0
+	var baselines []float64
+	for _, y := range ys {
+		if n := len(baselines); n == 0 || math.Abs(baselines[n-1]-y) > yTolerance {
+			baselines = append(baselines, y)
+		}
+	}
+	return baselines, nil
+}
@@ -0,0 +1,280 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// ErrInvalidProfile is returned by the extraction functions when given
+// WithProfile with a nil profile or one whose heuristics are invalid.
+var ErrInvalidProfile = errors.New("extract: invalid profile")
+
+// ProfileVersion is the version of the profile format written by this
+// package. ParseProfile accepts profiles of this version and earlier.
+const ProfileVersion = 1
+
+// Heuristics are the thresholds of the geometric heuristics that group
+// words into lines, blocks and paragraphs, type blocks, space LayoutSimple
+// and LayoutRaw text and place LayoutPhysical text.
+// Distances are relative to the font size or height of the text, or to
+// the page size, so one set suits documents of any scale; only
+// PhysicalLineTolerance is in points.
+type Heuristics struct {
+	// LineTolerance is how far apart the baselines of two words may be,
+	// as a fraction of the taller word's height, for them to share a
+	// line. Default 0.5.
+	LineTolerance float64 `json:"line_tolerance"`
+
+	// ColumnGap is the gap between words, in font sizes, beyond which a
+	// line is split at a column boundary. Default 2.
+	ColumnGap float64 `json:"column_gap"`
+
+	// BlockGap is the vertical gap between lines, in line heights, below
+	// which they join one block. Default 1.2.
+	BlockGap float64 `json:"block_gap"`
+
+	// BlockFontSizeTolerance is the difference in font size, in points,
+	// up to which lines join one block. Default 1.5.
+	BlockFontSizeTolerance float64 `json:"block_font_size_tolerance"`
+
+	// ParagraphGap is how many times the usual spacing of the lines of a
+	// block the space above a line must be for it to start a paragraph.
+	// Default 1.3.
+	ParagraphGap float64 `json:"paragraph_gap"`
+
+	// ParagraphIndent is the indentation, in font sizes, beyond which a
+	// line below an unindented one starts a paragraph. Default 1.
+	ParagraphIndent float64 `json:"paragraph_indent"`
+
+	// HeadingScale is how many times the body font size a block of up to
+	// three lines must be set in to be a heading. Default 1.2.
+	HeadingScale float64 `json:"heading_scale"`
+
+	// MarginBand is the height of the bands at the top and bottom of the
+	// page holding running headers and footers, as a fraction of the page
+	// height. Default 0.08.
+	MarginBand float64 `json:"margin_band"`
+
+	// MaxMarginLines is the most lines a band may hold to be a header or
+	// footer rather than body text. Default 3.
+	MaxMarginLines int `json:"max_margin_lines"`
+
+	// FigureMinArea and FigureMaxArea bound the area of images typed as
+	// figures, as fractions of the page area; larger images are page
+	// backgrounds. Defaults 0.01 and 0.9.
+	FigureMinArea float64 `json:"figure_min_area"`
+	FigureMaxArea float64 `json:"figure_max_area"`
+
+	// RawWordGap is the gap between glyph groups, in average character
+	// widths, beyond which LayoutRaw inserts a space. Default 0.3.
+	RawWordGap float64 `json:"raw_word_gap"`
+
+	// WordGap is the gap between glyph groups, in estimated character
+	// widths, beyond which LayoutSimple inserts a space. Default 0.5.
+	WordGap float64 `json:"word_gap"`
+
+	// WordGapFontSize is the gap, in font sizes, LayoutSimple uses
+	// instead of WordGap after a glyph group whose width is known.
+	// Default 0.2.
+	WordGapFontSize float64 `json:"word_gap_font_size"`
+
+	// PhysicalLineTolerance is how far apart, in points, the baselines of
+	// texts may be for LayoutPhysical to place them on one line.
+	// Default 2.
+	PhysicalLineTolerance float64 `json:"physical_line_tolerance"`
+
+	// PhysicalColumns is the number of character columns LayoutPhysical
+	// spreads the page width over. Default 80.
+	PhysicalColumns int `json:"physical_columns"`
+}
+
+// DefaultHeuristics returns the thresholds used when no profile is set.
+func DefaultHeuristics() Heuristics {
+	return Heuristics{
+		LineTolerance:          0.5,
+		ColumnGap:              2,
+		BlockGap:               1.2,
+		BlockFontSizeTolerance: 1.5,
+		ParagraphGap:           1.3,
+		ParagraphIndent:        1,
+		HeadingScale:           1.2,
+		MarginBand:             0.08,
+		MaxMarginLines:         3,
+		FigureMinArea:          0.01,
+		FigureMaxArea:          0.9,
+		RawWordGap:             0.3,
+		WordGap:                internalpdf.DefaultLayoutThresholds.WordGap,
+		WordGapFontSize:        internalpdf.DefaultLayoutThresholds.WordGapFontSize,
+		PhysicalLineTolerance:  internalpdf.DefaultLayoutThresholds.LineTolerance,
+		PhysicalColumns:        internalpdf.DefaultLayoutThresholds.Columns,
+	}
+}
+
+// Validate reports thresholds that are negative or out of range.
+func (h Heuristics) Validate() error {
+	var errs []error
+	positive := func(name string, v float64) {
+		if v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %g", name, v))
+		}
+	}
+	positive("line_tolerance", h.LineTolerance)
+	positive("column_gap", h.ColumnGap)
+	positive("block_gap", h.BlockGap)
+	positive("heading_scale", h.HeadingScale)
+	positive("paragraph_gap", h.ParagraphGap)
+	positive("paragraph_indent", h.ParagraphIndent)
+	positive("raw_word_gap", h.RawWordGap)
+	positive("word_gap", h.WordGap)
+	positive("word_gap_font_size", h.WordGapFontSize)
+	positive("physical_line_tolerance", h.PhysicalLineTolerance)
+	if h.PhysicalColumns < 1 {
+		errs = append(errs, fmt.Errorf("physical_columns must be positive, got %d", h.PhysicalColumns))
+	}
+	if h.BlockFontSizeTolerance < 0 {
+		errs = append(errs, fmt.Errorf("block_font_size_tolerance must not be negative, got %g", h.BlockFontSizeTolerance))
+	}
+	if h.MarginBand < 0 || h.MarginBand >= 0.5 {
+		errs = append(errs, fmt.Errorf("margin_band must be in [0, 0.5), got %g", h.MarginBand))
+	}
+	if h.MaxMarginLines < 0 {
+		errs = append(errs, fmt.Errorf("max_margin_lines must not be negative, got %d", h.MaxMarginLines))
+	}
+	if h.FigureMinArea < 0 || h.FigureMinArea > h.FigureMaxArea || h.FigureMaxArea > 1 {
+		errs = append(errs, fmt.Errorf("figure areas must satisfy 0 <= figure_min_area <= figure_max_area <= 1, got %g and %g", h.FigureMinArea, h.FigureMaxArea))
+	}
+	return errors.Join(errs...)
+}
+
+// Profile bundles the extraction settings and heuristics tuned for a
+// family of documents, so they can be stored as JSON, versioned alongside
+// the documents and pinned: extraction with the same profile and version
+// of this package gives the same text. Start from DefaultProfile, or
+// load one with LoadProfile, and apply it with WithProfile.
+type Profile struct {
+	// Version is the profile format version, ProfileVersion when written
+	// by this package.
+	Version int `json:"version"`
+
+	// Name identifies the profile, such as the document family it was
+	// tuned for. It does not affect extraction.
+	Name string `json:"name,omitempty"`
+
+	Layout           LayoutMode `json:"layout"`
+	PageSeparator    string     `json:"page_separator"`
+	PageWidth        float64    `json:"page_width,omitempty"`
+	SkipEmptyPages   bool       `json:"skip_empty_pages,omitempty"`
+	ImagePlaceholder string     `json:"image_placeholder,omitempty"`
+	FigureAltText    string     `json:"figure_alt_text,omitempty"`
+
+	MinFontSize float64 `json:"min_font_size,omitempty"`
+	MaxFontSize float64 `json:"max_font_size,omitempty"`
+
+	// ExcludeRegions are given as x0, y0, x1, y1 in CoordinateSpace.
+	ExcludeRegions [][4]float64 `json:"exclude_regions,omitempty"`
+	IncludeFonts   []string     `json:"include_fonts,omitempty"`
+	ExcludeFonts   []string     `json:"exclude_fonts,omitempty"`
+
+	// Layers, when set, selects layers as WithLayers does; otherwise
+	// VisibleLayersOnly selects them as WithVisibleLayersOnly does.
+	Layers            []string `json:"layers,omitempty"`
+	VisibleLayersOnly bool     `json:"visible_layers_only,omitempty"`
+
+	CoordinateSpace CoordinateSpace `json:"coordinate_space"`
+	DPI             float64         `json:"dpi,omitempty"`
+
+	Heuristics Heuristics `json:"heuristics"`
+}
+
+// DefaultProfile returns the profile of the default options.
+func DefaultProfile() *Profile {
+	cfg := defaultConfig()
+	return &Profile{
+		Version:         ProfileVersion,
+		Layout:          cfg.Layout,
+		PageSeparator:   cfg.PageSeparator,
+		CoordinateSpace: cfg.Space,
+		Heuristics:      cfg.Heuristics,
+	}
+}
+
+// ParseProfile decodes a JSON profile. Settings the profile leaves out
+// keep their defaults, so a profile may hold only what it changes, but it
+// must state its version. Unknown settings, versions newer than
+// ProfileVersion and invalid heuristics are errors, so that a profile is
+// never applied only in part.
+func ParseProfile(data []byte) (*Profile, error) {
+	p := DefaultProfile()
+	p.Version = 0
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	if p.Version < 1 || p.Version > ProfileVersion {
+		return nil, fmt.Errorf("unsupported profile version %d (supported up to %d)", p.Version, ProfileVersion)
+	}
+	if err := p.Heuristics.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile heuristics: %w", err)
+	}
+	return p, nil
+}
+
+// LoadProfile reads and decodes the JSON profile at path as ParseProfile
+// does.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	return ParseProfile(data)
+}
+
+// WithProfile applies every setting of p, replacing the options given
+// before it; options given after it override single settings. The layout
+// analyzer is not part of a profile: with the default analyzer, Blocks
+// uses the heuristics of p. A nil profile or one with invalid heuristics
+// is not applied, and the extraction functions given it return an error
+// wrapping ErrInvalidProfile. Default is DefaultProfile.
+func WithProfile(p *Profile) Option {
+	return func(c *textConfig) {
+		if p == nil {
+			c.fail(fmt.Errorf("%w: nil profile", ErrInvalidProfile))
+			return
+		}
+		if err := p.Heuristics.Validate(); err != nil {
+			c.fail(fmt.Errorf("%w: heuristics: %w", ErrInvalidProfile, err))
+			return
+		}
+		c.Layout = p.Layout
+		c.PageSeparator = p.PageSeparator
+		c.PageWidth = p.PageWidth
+		c.SkipEmpty = p.SkipEmptyPages
+		c.ImagePlaceholder = p.ImagePlaceholder
+		c.AltText = p.FigureAltText
+		c.MinFontSize = p.MinFontSize
+		c.MaxFontSize = p.MaxFontSize
+		c.ExcludeRegions = nil
+		for _, r := range p.ExcludeRegions {
+			c.ExcludeRegions = append(c.ExcludeRegions, crazypdf.Rect{X0: r[0], Y0: r[1], X1: r[2], Y1: r[3]})
+		}
+		c.IncludeFonts = p.IncludeFonts
+		c.ExcludeFonts = p.ExcludeFonts
+		c.Layers, c.LayerNames, c.VisibleLayers = nil, nil, false
+		switch {
+		case p.Layers != nil:
+			WithLayers(p.Layers...)(c)
+		case p.VisibleLayersOnly:
+			WithVisibleLayersOnly()(c)
+		}
+		c.Space = p.CoordinateSpace
+		c.DPI = p.DPI
+		c.Heuristics = p.Heuristics
+	}
+}
@@ -5,7 +5,9 @@
 package extract
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"sort"
 	"strings"
 
@@ -16,64 +18,246 @@ import (
 // Text extracts text from the entire document, joining pages with the
 // configured page separator. Default layout is LayoutSimple.
 func Text(doc *crazypdf.Document, opts ...Option) (string, error) {
+	return TextContext(context.Background(), doc, opts...)
+}
+
+// TextContext is like Text but stops when ctx is cancelled or its
+// deadline passes. See AllPagesContext.
+func TextContext(ctx context.Context, doc *crazypdf.Document, opts ...Option) (string, error) {
 	if doc.IsClosed() {
 		return "", crazypdf.ErrDocumentClosed
 	}
 
-	pages, err := AllPages(doc, opts...)
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	pages, err := AllPagesContext(ctx, doc, opts...)
 	if err != nil {
 		return "", err
 	}
 
-	cfg := applyOptions(opts)
+	if cfg.SkipEmpty {
+		pages = skipEmptyPages(pages, cfg.OnSkip)
+	}
 	return strings.Join(pages, cfg.PageSeparator), nil
 }
 
+// skipEmptyPages returns the pages that have text other than whitespace,
+// calling onSkip, if set, with the number of each page left out.
+func skipEmptyPages(pages []string, onSkip func(page int)) []string {
+	kept := pages[:0:0]
+	for i, text := range pages {
+		if strings.TrimSpace(text) == "" {
+			if onSkip != nil {
+				onSkip(i + 1)
+			}
+			continue
+		}
+		kept = append(kept, text)
+	}
+	return kept
+}
+
 // PageText extracts text from a single page.
 func PageText(page *crazypdf.Page, opts ...Option) (string, error) {
-	cfg := applyOptions(opts)
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	return pageText(context.Background(), page, cfg)
+}
+
+// pageText extracts text from a page in an "extract.PageText" span, a
+// child of the span in ctx.
+func pageText(ctx context.Context, page *crazypdf.Page, cfg *textConfig) (string, error) {
+	_, span := page.StartSpan(ctx, "extract.PageText",
+		crazypdf.Attribute{Key: "pdf.page", Value: page.Number},
+		crazypdf.Attribute{Key: "extract.layout", Value: int(cfg.Layout)},
+	)
+	defer span.End()
+
+	text, err := layoutText(page, cfg)
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+	span.SetAttributes(crazypdf.Attribute{Key: "text.bytes", Value: len(text)})
+	return text, nil
+}
 
+// layoutText extracts text from a page in the configured layout.
+func layoutText(page *crazypdf.Page, cfg *textConfig) (string, error) {
+	var text string
+	var err error
 	switch cfg.Layout {
-	case LayoutSimple:
-		return page.PlainText()
 	case LayoutRaw:
-		return extractRawText(page)
+		text, err = extractRawText(page, cfg)
 	case LayoutPhysical:
-		return page.PhysicalLayoutText(cfg.PageWidth)
+		width := cfg.PageWidth
+		if width <= 0 {
+			box, err := page.CropBox()
+			if err != nil {
+				return "", fmt.Errorf("failed to read page geometry: %w", err)
+			}
+			width = box.Width()
+		}
+		var texts []internalpdf.StyledText
+		if texts, err = pageStyledTexts(page, cfg); err == nil {
+			text = internalpdf.TracePhysicalLayout(texts, width, cfg.layoutThresholds(), cfg.tracer(page.Number))
+		}
+	case LayoutNormalized:
+		return normalizedText(page, cfg)
+	case LayoutParagraphs:
+		return paragraphLayoutText(page, cfg)
+	case LayoutColumns:
+		return columnText(page, cfg)
 	default:
-		return page.PlainText()
+		var rows []internalpdf.TextRow
+		if rows, err = pageRows(page, cfg); err == nil {
+			text = internalpdf.TracePlainTextOfRows(rows, cfg.layoutThresholds(), cfg.tracer(page.Number))
+		}
+	}
+	if err != nil || (cfg.ImagePlaceholder == "" && cfg.AltText == "") {
+		return text, err
 	}
+	return withMarkers(page, text, cfg)
 }
 
 // AllPages extracts text from all pages, returning a slice with one entry per page.
 func AllPages(doc *crazypdf.Document, opts ...Option) ([]string, error) {
+	return AllPagesContext(context.Background(), doc, opts...)
+}
+
+// AllPagesContext is like AllPages but stops when ctx is cancelled or its
+// deadline passes. The context is checked before each page, so the page
+// being extracted when it is cancelled is finished first. The error wraps
+// ctx.Err() and names the page at which extraction stopped.
+func AllPagesContext(ctx context.Context, doc *crazypdf.Document, opts ...Option) ([]string, error) {
 	if doc.IsClosed() {
 		return nil, crazypdf.ErrDocumentClosed
 	}
 
 	pages := doc.Pages()
+	ctx, span := doc.StartSpan(ctx, "extract.AllPages",
+		crazypdf.Attribute{Key: "pdf.pages", Value: len(pages)},
+	)
+	defer span.End()
+
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
 	result := make([]string, 0, len(pages))
+	size := 0
 
 	for _, page := range pages {
-		text, err := PageText(page, opts...)
+		if err := ctx.Err(); err != nil {
+			err = fmt.Errorf("extraction stopped at page %d of %d: %w", page.Number, len(pages), err)
+			span.RecordError(err)
+			return nil, err
+		}
+		text, err := pageText(ctx, page, cfg)
 		if err != nil {
-			return nil, fmt.Errorf("failed to extract text from page %d: %w", page.Number, err)
+			err = fmt.Errorf("failed to extract text from page %d: %w", page.Number, err)
+			span.RecordError(err)
+			return nil, err
 		}
 		result = append(result, text)
+		size += len(text)
 	}
 
+	span.SetAttributes(crazypdf.Attribute{Key: "text.bytes", Value: size})
 	return result, nil
 }
 
+// TextSeq returns an iterator over the text of each page, extracted as
+// the loop reaches the page, so large documents can be processed without
+// holding all of their text in memory. It yields the text of each page
+// with a nil error; on an error it yields the error and stops. Pages
+// that WithSkipEmptyPages leaves out are not yielded.
+func TextSeq(doc *crazypdf.Document, opts ...Option) iter.Seq2[string, error] {
+	return TextSeqContext(context.Background(), doc, opts...)
+}
+
+// TextSeqContext is like TextSeq but stops when ctx is cancelled or its
+// deadline passes, yielding an error that wraps ctx.Err() and names the
+// page at which extraction stopped.
+func TextSeqContext(ctx context.Context, doc *crazypdf.Document, opts ...Option) iter.Seq2[string, error] {
+	cfg, err := applyOptions(opts)
+	return func(yield func(string, error) bool) {
+		if err != nil {
+			yield("", err)
+			return
+		}
+		n := doc.NumPages()
+		for i := 0; i < n; i++ {
+			if doc.IsClosed() {
+				yield("", crazypdf.ErrDocumentClosed)
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield("", fmt.Errorf("extraction stopped at page %d of %d: %w", i+1, n, err))
+				return
+			}
+			page, err := doc.Page(i)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			text, err := pageText(ctx, page, cfg)
+			if err != nil {
+				yield("", fmt.Errorf("failed to extract text from page %d: %w", page.Number, err))
+				return
+			}
+			if cfg.SkipEmpty && strings.TrimSpace(text) == "" {
+				if cfg.OnSkip != nil {
+					cfg.OnSkip(page.Number)
+				}
+				continue
+			}
+			if !yield(text, nil) {
+				return
+			}
+		}
+	}
+}
+
+// normalizedText extracts the text of a page in LayoutNormalized, with
+// the configured image placeholders and figure alternate texts.
+func normalizedText(page *crazypdf.Page, cfg *textConfig) (string, error) {
+	words, err := pageWords(page, cfg)
+	if err != nil {
+		return "", err
+	}
+	lines := layoutLines(words, cfg.Heuristics, cfg.tracer(page.Number))
+	texts := make([]string, len(lines))
+	baselines := make([]float64, len(lines))
+	for i, ln := range lines {
+		texts[i] = ln.text()
+		baselines[i] = ln.box.Y0
+	}
+	if cfg.ImagePlaceholder != "" || cfg.AltText != "" {
+		markers, err := pageMarkers(page, cfg)
+		if err != nil {
+			return "", err
+		}
+		texts = insertMarkers(texts, baselines, markers)
+	}
+	return NormalizeText(strings.Join(texts, "\n")), nil
+}
+
 // extractRawText extracts text in content stream order.
 // This uses the row-based extraction from the reader which preserves
 // the order text appears in the content stream. It uses X-position
 // and font size data to intelligently merge adjacent glyph groups.
-func extractRawText(page *crazypdf.Page) (string, error) {
-	rows, err := page.TextByRow()
+func extractRawText(page *crazypdf.Page, cfg *textConfig) (string, error) {
+	rows, err := pageRows(page, cfg)
 	if err != nil {
 		return "", err
 	}
+	trace := cfg.tracer(page.Number)
 
 	var buf strings.Builder
 	for i, row := range rows {
@@ -109,8 +293,12 @@ func extractRawText(page *crazypdf.Page) (string, error) {
 
 			gap := curr.X - prevEndX
 
-			if gap > avgCharWidth*0.3 {
+			threshold := avgCharWidth * cfg.Heuristics.RawWordGap
+			if gap > threshold {
+				trace.Printf("row y=%d: space between %q and %q at x=%.2f: gap %.2f > %.2f (raw_word_gap × character width %.2f)", row.Position, prev.S, curr.S, curr.X, gap, threshold, avgCharWidth)
 				buf.WriteString(" ")
+			} else {
+				trace.Printf("row y=%d: joined %q and %q at x=%.2f: gap %.2f <= %.2f (raw_word_gap × character width %.2f)", row.Position, prev.S, curr.S, curr.X, gap, threshold, avgCharWidth)
 			}
 			buf.WriteString(curr.S)
 		}
@@ -0,0 +1,137 @@
+package extract
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// columnText writes the lines of a page in reading order, column by
+// column, as ordered by xyCut.
+func columnText(page *crazypdf.Page, cfg *textConfig) (string, error) {
+	words, err := pageWords(page, cfg)
+	if err != nil {
+		return "", err
+	}
+	trace := cfg.tracer(page.Number)
+	lines := xyCut(layoutLines(words, cfg.Heuristics, trace), cfg.Heuristics, trace)
+	texts := make([]string, len(lines))
+	baselines := make([]float64, len(lines))
+	for i, ln := range lines {
+		texts[i] = ln.text()
+		baselines[i] = ln.box.Y0
+	}
+	if cfg.ImagePlaceholder != "" || cfg.AltText != "" {
+		markers, err := pageMarkers(page, cfg)
+		if err != nil {
+			return "", err
+		}
+		texts = insertMarkers(texts, baselines, markers)
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// xyCut orders lines for reading by recursive XY-cut. A region is cut
+// into columns, read left to right, at vertical gaps wider than
+// h.ColumnGap font sizes that no line crosses; a region without such a
+// gap is cut at horizontal gaps no line crosses into bands whose rows
+// share their columns, read top to bottom, and each column and band is cut
+// in turn. Cutting columns first keeps paragraph breaks that line up
+// across columns from interleaving them, while a title or figure
+// spanning the columns still separates the columns above it from those
+// below. Lines of a region that cannot be cut are read top to bottom and
+// left to right.
+func xyCut(lines []layoutLine, h Heuristics, trace internalpdf.Tracer) []layoutLine {
+	if len(lines) < 2 {
+		return lines
+	}
+	if parts := cutColumns(lines, h, trace); len(parts) > 1 {
+		var out []layoutLine
+		for _, part := range parts {
+			out = append(out, xyCut(part, h, trace)...)
+		}
+		return out
+	}
+	if parts := cutBands(lines, h, trace); len(parts) > 1 {
+		var out []layoutLine
+		for _, part := range parts {
+			out = append(out, xyCut(part, h, trace)...)
+		}
+		return out
+	}
+	sorted := append([]layoutLine{}, lines...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].box.Y0 != sorted[j].box.Y0 {
+			return sorted[i].box.Y0 > sorted[j].box.Y0
+		}
+		return sorted[i].box.X0 < sorted[j].box.X0
+	})
+	return sorted
+}
+
+// cutColumns splits lines at the vertical gaps no line crosses that are
+// wider than h.ColumnGap times the median font size, left to right.
+func cutColumns(lines []layoutLine, h Heuristics, trace internalpdf.Tracer) [][]layoutLine {
+	size := medianFontSize(lines)
+	if size <= 0 {
+		size = 12
+	}
+	minGap := size * h.ColumnGap
+
+	sorted := append([]layoutLine{}, lines...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].box.X0 < sorted[j].box.X0 })
+	var parts [][]layoutLine
+	start := 0
+	right := sorted[0].box.X1
+	for i := 1; i < len(sorted); i++ {
+		if gap := sorted[i].box.X0 - right; gap > minGap {
+			trace.Printf("column cut at x=%.2f to %.2f: gap %.2f > %.2f (column_gap × median font size)", right, sorted[i].box.X0, gap, minGap)
+			parts = append(parts, sorted[start:i])
+			start = i
+		}
+		right = math.Max(right, sorted[i].box.X1)
+	}
+	return append(parts, sorted[start:])
+}
+
+// cutBands splits lines at the horizontal gaps no line crosses, top to
+// bottom, into bands whose rows share their columns: the rows of a
+// two-column body, including those where one column has ended, stay
+// together below a full-width title, and are cut from it, so that they
+// are cut into columns next.
+func cutBands(lines []layoutLine, h Heuristics, trace internalpdf.Tracer) [][]layoutLine {
+	sorted := append([]layoutLine{}, lines...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].box.Y1 > sorted[j].box.Y1 })
+	var rows [][]layoutLine
+	start := 0
+	bottom := sorted[0].box.Y0
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].box.Y1 < bottom {
+			rows = append(rows, sorted[start:i])
+			start = i
+		}
+		bottom = math.Min(bottom, sorted[i].box.Y0)
+	}
+	rows = append(rows, sorted[start:])
+
+	columns := func(lines []layoutLine) int { return len(cutColumns(lines, h, nil)) }
+	bands := [][]layoutLine{rows[0]}
+	n := columns(rows[0])
+	for _, row := range rows[1:] {
+		last := len(bands) - 1
+		merged := append(bands[last][:len(bands[last]):len(bands[last])], row...)
+		c := columns(row)
+		if columns(merged) != max(n, c) {
+			trace.Printf("band cut above y=%.2f: the %d columns above and %d below do not line up", row[0].box.Y1, n, c)
+			bands = append(bands, row)
+			n = c
+			continue
+		}
+		bands[last] = merged
+		n = max(n, c)
+	}
+	return bands
+}
@@ -0,0 +1,195 @@
+// Package images reports on the images of PDF documents: their pixel
+// dimensions, effective resolution on the page, color space and
+// compression.
+package images
+
+import (
+	"fmt"
+	"math"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Image is an image painted on a page. An image placed more than once,
+// such as a logo on every page, is reported at each placement.
+type Image struct {
+	Page int `json:"page"`
+
+	// BBox is the area the image covers on the page, in user space or
+	// in the space set with WithCoordinateSpace and WithDPI.
+	BBox crazypdf.Rect `json:"bbox"`
+
+	// Width and Height are the dimensions of the image in pixels.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// XDPI and YDPI are the effective resolution of the image as placed
+	// on the page, in pixels per inch along the image's own axes.
+	XDPI float64 `json:"xdpi"`
+	YDPI float64 `json:"ydpi"`
+
+	// ColorSpace is the color space family, such as "DeviceRGB",
+	// "ICCBased" or "Indexed", and Components its number of color
+	// components. ColorSpace is empty for JPEG 2000 images that carry
+	// their color space in the image data.
+	ColorSpace       string `json:"color_space,omitempty"`
+	Components       int    `json:"components,omitempty"`
+	BitsPerComponent int    `json:"bits_per_component"`
+
+	// Filters are the compression filters of the image data in the order
+	// they are applied to decode it, such as ["DCTDecode"]; empty for
+	// uncompressed data.
+	Filters []string `json:"filters,omitempty"`
+
+	// Size is the size of the compressed image data in bytes, and
+	// CompressionRatio the size of the uncompressed pixels divided by it,
+	// or 0 when either is unknown.
+	Size             int     `json:"size"`
+	CompressionRatio float64 `json:"compression_ratio,omitempty"`
+
+	// LowResolution is set when the image is placed at fewer dots per
+	// inch than the minimum along either axis, and Oversized when at more
+	// than the maximum along either axis.
+	LowResolution bool `json:"low_resolution,omitempty"`
+	Oversized     bool `json:"oversized,omitempty"`
+}
+
+// Report lists the images painted on every page of a document, including
+// those inside form XObjects and inline images, to find low-quality scans
+// and images stored at far more pixels than the page can show before
+// archiving. The effective resolution is the pixel count over the placed
+// size; an image stretched across a page has a lower one than the same
+// image placed as a thumbnail. Stencil masks, which paint a color through
+// a 1-bit shape, are drawn as filled shapes and are not listed.
+func Report(doc *crazypdf.Document, opts ...Option) ([]Image, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+	var out []Image
+	for _, page := range doc.Pages() {
+		items, err := page.Drawing()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page %d images: %w", page.Number, err)
+		}
+		coords, err := page.Coordinates(cfg.Space, cfg.DPI)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			if it.Image && it.ImageStream != nil {
+				img := describe(page.Number, it, cfg)
+				img.BBox = coords.Rect(img.BBox)
+				out = append(out, img)
+			}
+		}
+	}
+	return out, nil
+}
+
+// describe reports on a painted image.
+func describe(page int, it crazypdf.DrawItem, cfg *config) Image {
+	dict := it.ImageStream.Dict
+	intValue := func(key internalpdf.Name) int {
+		switch v := dict[key].(type) {
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+		return 0
+	}
+
+	m := it.Matrix
+	img := Image{
+		Page:             page,
+		BBox:             m.TransformRect(crazypdf.Rect{X0: 0, Y0: 0, X1: 1, Y1: 1}),
+		Width:            intValue("Width"),
+		Height:           intValue("Height"),
+		BitsPerComponent: intValue("BitsPerComponent"),
+		Size:             len(it.ImageStream.Data),
+	}
+	img.ColorSpace, img.Components = colorSpace(dict["ColorSpace"])
+
+	switch f := dict["Filter"].(type) {
+	case internalpdf.Name:
+		img.Filters = []string{string(f)}
+	case internalpdf.Array:
+		for _, v := range f {
+			if name, ok := v.(internalpdf.Name); ok {
+				img.Filters = append(img.Filters, string(name))
+			}
+		}
+	}
+
+	// The matrix maps the unit square onto the placement, so the lengths
+	// of its column vectors are the placed width and height in points
+	if w := math.Hypot(m[0], m[1]); w > 0 {
+		img.XDPI = round1(float64(img.Width) * 72 / w)
+	}
+	if h := math.Hypot(m[2], m[3]); h > 0 {
+		img.YDPI = round1(float64(img.Height) * 72 / h)
+	}
+
+	if img.Components > 0 && img.BitsPerComponent > 0 && img.Size > 0 {
+		raw := (img.Width*img.Components*img.BitsPerComponent + 7) / 8 * img.Height
+		img.CompressionRatio = round1(float64(raw) / float64(img.Size))
+	}
+
+	if img.XDPI > 0 && img.YDPI > 0 {
+		img.LowResolution = cfg.MinDPI > 0 && math.Min(img.XDPI, img.YDPI) < cfg.MinDPI
+		img.Oversized = cfg.MaxDPI > 0 && math.Max(img.XDPI, img.YDPI) > cfg.MaxDPI
+	}
+	return img
+}
+
+// colorSpace returns the family and number of components of an image
+// color space; the components are 0 when unknown.
+func colorSpace(cs internalpdf.Object) (string, int) {
+	switch v := cs.(type) {
+	case internalpdf.Name:
+		switch v {
+		case "DeviceGray", "CalGray":
+			return string(v), 1
+		case "DeviceRGB", "CalRGB", "Lab":
+			return string(v), 3
+		case "DeviceCMYK":
+			return string(v), 4
+		}
+		return string(v), 0
+	case internalpdf.Array:
+		if len(v) == 0 {
+			return "", 0
+		}
+		family, _ := v[0].(internalpdf.Name)
+		switch family {
+		case "ICCBased":
+			if len(v) > 1 {
+				if s, ok := v[1].(*internalpdf.Stream); ok {
+					if n, ok := s.Dict["N"].(int64); ok {
+						return string(family), int(n)
+					}
+				}
+			}
+		case "Indexed", "Separation":
+			return string(family), 1
+		case "DeviceN":
+			if len(v) > 1 {
+				if names, ok := v[1].(internalpdf.Array); ok {
+					return string(family), len(names)
+				}
+			}
+		default:
+			_, n := colorSpace(family)
+			return string(family), n
+		}
+		return string(family), 0
+	}
+	return "", 0
+}
+
+// round1 rounds to one decimal.
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
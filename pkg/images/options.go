@@ -0,0 +1,69 @@
+package images
+
+import "github.com/ayushanand18/crazypdf/pkg/crazypdf"
+
+// config holds configuration for image reports.
+type config struct {
+	MinDPI float64 // effective resolution below which an image is low resolution
+	MaxDPI float64 // effective resolution above which an image is oversized
+
+	Space crazypdf.CoordinateSpace // coordinate space of Image.BBox
+	DPI   float64                  // pixels per inch of Image.BBox; 0 for points
+}
+
+// Option is a functional option for configuring image reports.
+type Option func(*config)
+
+// WithMinDPI sets the effective resolution, in dots per inch, below which
+// an image is flagged as low resolution. 0 disables the flag. Default is
+// 150.
+func WithMinDPI(dpi float64) Option {
+	return func(c *config) {
+		c.MinDPI = dpi
+	}
+}
+
+// WithMaxDPI sets the effective resolution, in dots per inch, above which
+// an image is flagged as oversized. 0 disables the flag. Default is 600.
+func WithMaxDPI(dpi float64) Option {
+	return func(c *config) {
+		c.MaxDPI = dpi
+	}
+}
+
+// WithCoordinateSpace sets the coordinate space of Image.BBox:
+// crazypdf.PDFDefault, PDF user space, or crazypdf.TopLeft, with the
+// origin at the top-left corner of the media box and the Y axis pointing
+// down. Default is crazypdf.PDFDefault.
+func WithCoordinateSpace(space crazypdf.CoordinateSpace) Option {
+	return func(c *config) {
+		c.Space = space
+	}
+}
+
+// WithDPI scales Image.BBox to pixels at dpi dots per inch, to line up
+// with a page rendered at that resolution. It does not change the
+// effective resolution reported in XDPI and YDPI. Default is 0,
+// coordinates in points.
+func WithDPI(dpi float64) Option {
+	return func(c *config) {
+		c.DPI = dpi
+	}
+}
+
+// defaultConfig returns the default image report configuration.
+func defaultConfig() *config {
+	return &config{
+		MinDPI: 150,
+		MaxDPI: 600,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,135 @@
+// Package metadata reads the descriptive metadata of PDF documents: the
+// document information dictionary and the XMP metadata stream.
+//
+// Most writers record the same facts in both places, but some only fill
+// one of them. Read merges the two, preferring the information dictionary
+// and falling back to the matching XMP properties, and keeps the full set
+// of XMP properties for callers that need more than the standard fields.
+package metadata
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Metadata holds the descriptive metadata of a document.
+type Metadata struct {
+	// Info holds the standard fields, taken from the information
+	// dictionary or, when a field is missing there, from the XMP stream.
+	crazypdf.Info
+
+	// Custom holds information dictionary entries other than the
+	// standard ones, such as Trapped or application-specific keys.
+	Custom map[string]string
+
+	// XMP holds the properties of the XMP metadata stream keyed by
+	// qualified name, such as "dc:title" or "xmp:CreateDate". Array
+	// properties have one value per item; fields of structured
+	// properties are keyed as "parent/field". It is nil when the
+	// document has no XMP stream.
+	XMP map[string][]string
+}
+
+// infoKeys lists the standard information dictionary keys.
+var infoKeys = map[string]bool{
+	"Title": true, "Author": true, "Subject": true, "Keywords": true,
+	"Creator": true, "Producer": true, "CreationDate": true, "ModDate": true,
+}
+
+// Read returns the metadata of a document. Malformed dates are left zero
+// and a malformed XMP packet yields the properties parsed before the
+// error, so damaged metadata never prevents reading the rest.
+func Read(doc *crazypdf.Document) (*Metadata, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+
+	md := &Metadata{}
+	info, _ := file.Resolve(file.Trailer()["Info"]).(internalpdf.Dict)
+	for key, value := range info {
+		var s string
+		switch v := file.Resolve(value).(type) {
+		case internalpdf.String:
+			s = internalpdf.DecodeTextString(v)
+		case internalpdf.Name:
+			s = string(v)
+		default:
+			continue
+		}
+		if !infoKeys[string(key)] {
+			if md.Custom == nil {
+				md.Custom = make(map[string]string)
+			}
+			md.Custom[string(key)] = s
+			continue
+		}
+		switch key {
+		case "Title":
+			md.Title = s
+		case "Author":
+			md.Author = s
+		case "Subject":
+			md.Subject = s
+		case "Keywords":
+			md.Keywords = s
+		case "Creator":
+			md.Creator = s
+		case "Producer":
+			md.Producer = s
+		case "CreationDate":
+			md.CreationDate, _ = internalpdf.ParseDate(s)
+		case "ModDate":
+			md.ModDate, _ = internalpdf.ParseDate(s)
+		}
+	}
+
+	catalog, _ := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	if stream, ok := file.Resolve(catalog["Metadata"]).(*internalpdf.Stream); ok {
+		data, err := file.DecodeStream(stream)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode XMP stream: %w", err)
+		}
+		md.XMP, _ = parseXMP(data)
+		md.fillFromXMP()
+	}
+	return md, nil
+}
+
+// fillFromXMP sets standard fields missing from the information
+// dictionary from their XMP equivalents.
+func (md *Metadata) fillFromXMP() {
+	first := func(key string) string {
+		if values := md.XMP[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	setString := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	setDate := func(field *time.Time, value string) {
+		if field.IsZero() && value != "" {
+			*field, _ = parseXMPDate(value)
+		}
+	}
+
+	setString(&md.Title, first("dc:title"))
+	setString(&md.Author, strings.Join(md.XMP["dc:creator"], "; "))
+	setString(&md.Subject, first("dc:description"))
+	setString(&md.Keywords, first("pdf:Keywords"))
+	setString(&md.Keywords, strings.Join(md.XMP["dc:subject"], ", "))
+	setString(&md.Creator, first("xmp:CreatorTool"))
+	setString(&md.Producer, first("pdf:Producer"))
+	setDate(&md.CreationDate, first("xmp:CreateDate"))
+	setDate(&md.ModDate, first("xmp:ModifyDate"))
+}
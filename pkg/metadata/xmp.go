@@ -0,0 +1,217 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+const rdfNS = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// wellKnownPrefixes maps common XMP namespaces to their conventional
+// prefixes, so keys are stable whatever prefixes a writer declared.
+var wellKnownPrefixes = map[string]string{
+	rdfNS:                                            "rdf",
+	"http://purl.org/dc/elements/1.1/":               "dc",
+	"http://ns.adobe.com/xap/1.0/":                   "xmp",
+	"http://ns.adobe.com/xap/1.0/mm/":                "xmpMM",
+	"http://ns.adobe.com/xap/1.0/rights/":            "xmpRights",
+	"http://ns.adobe.com/pdf/1.3/":                   "pdf",
+	"http://ns.adobe.com/pdfx/1.3/":                  "pdfx",
+	"http://ns.adobe.com/photoshop/1.0/":             "photoshop",
+	"http://www.aiim.org/pdfa/ns/id/":                "pdfaid",
+	"http://www.aiim.org/pdfua/ns/id/":               "pdfuaid",
+	"http://www.npes.org/pdfx/ns/id/":                "pdfxid",
+	"http://ns.adobe.com/xap/1.0/sType/ResourceRef#": "stRef",
+}
+
+// xmpParser collects the properties of an RDF/XML XMP packet.
+type xmpParser struct {
+	dec      *xml.Decoder
+	prefixes map[string]string
+	props    map[string][]string
+}
+
+// parseXMP returns the properties of an XMP packet. On error it returns
+// the properties parsed so far.
+func parseXMP(data []byte) (map[string][]string, error) {
+	p := &xmpParser{
+		dec:      xml.NewDecoder(bytes.NewReader(data)),
+		prefixes: make(map[string]string),
+		props:    make(map[string][]string),
+	}
+	p.dec.Strict = false
+	for {
+		tok, err := p.next()
+		if err == io.EOF {
+			return p.props, nil
+		}
+		if err != nil {
+			return p.props, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name == (xml.Name{Space: rdfNS, Local: "Description"}) {
+			if err := p.description(start, ""); err != nil {
+				return p.props, err
+			}
+		}
+	}
+}
+
+// next returns the next token, recording namespace declarations.
+func (p *xmpParser) next() (xml.Token, error) {
+	tok, err := p.dec.Token()
+	if start, ok := tok.(xml.StartElement); ok {
+		for _, attr := range start.Attr {
+			if attr.Name.Space == "xmlns" {
+				if _, known := p.prefixes[attr.Value]; !known {
+					p.prefixes[attr.Value] = attr.Name.Local
+				}
+			}
+		}
+	}
+	return tok, err
+}
+
+// qualified returns the prefixed name of an element or attribute.
+func (p *xmpParser) qualified(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	if prefix, ok := wellKnownPrefixes[n.Space]; ok {
+		return prefix + ":" + n.Local
+	}
+	if prefix, ok := p.prefixes[n.Space]; ok {
+		return prefix + ":" + n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// description reads an rdf:Description, whose properties are given as
+// attributes or child elements, keying them under prefix.
+func (p *xmpParser) description(start xml.StartElement, prefix string) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Space == rdfNS || attr.Name.Space == "" ||
+			attr.Name.Space == "http://www.w3.org/XML/1998/namespace" {
+			continue
+		}
+		key := prefix + p.qualified(attr.Name)
+		p.props[key] = append(p.props[key], attr.Value)
+	}
+	return p.fields(prefix)
+}
+
+// fields reads property elements until the enclosing element ends.
+func (p *xmpParser) fields(prefix string) error {
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := p.property(t, prefix+p.qualified(t.Name)); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// property reads one property element: a simple value, a resource
+// reference, an rdf:Alt, rdf:Seq or rdf:Bag array, or a structure.
+func (p *xmpParser) property(start xml.StartElement, key string) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Space != rdfNS {
+			continue
+		}
+		switch {
+		case attr.Name.Local == "resource":
+			p.props[key] = append(p.props[key], attr.Value)
+		case attr.Name.Local == "parseType" && attr.Value == "Resource":
+			return p.fields(key + "/")
+		}
+	}
+
+	var text strings.Builder
+	structured := false
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			structured = true
+			switch {
+			case t.Name.Space != rdfNS:
+				err = p.dec.Skip()
+			case t.Name.Local == "Alt" || t.Name.Local == "Seq" || t.Name.Local == "Bag":
+				err = p.items(key)
+			case t.Name.Local == "Description":
+				err = p.description(t, key+"/")
+			default:
+				err = p.dec.Skip()
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if s := strings.TrimSpace(text.String()); s != "" && !structured {
+				p.props[key] = append(p.props[key], s)
+			}
+			return nil
+		}
+	}
+}
+
+// items reads the rdf:li items of an array until the array ends.
+func (p *xmpParser) items(key string) error {
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name != (xml.Name{Space: rdfNS, Local: "li"}) {
+				if err := p.dec.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := p.property(t, key); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// parseXMPDate parses an XMP date, which is ISO 8601 with optional
+// precision: a year, year and month, a date, or a date and time with or
+// without seconds and time zone.
+func parseXMPDate(s string) (time.Time, error) {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04",
+		"2006-01-02",
+		"2006-01",
+		"2006",
+	}
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
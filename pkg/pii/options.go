@@ -0,0 +1,79 @@
+package pii
+
+import (
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/redact"
+)
+
+// config holds configuration for PII detection.
+type config struct {
+	Kinds         []Kind // kinds to detect; empty means all
+	RedactOptions []redact.Option
+
+	Space crazypdf.CoordinateSpace // coordinate space of match geometry
+	DPI   float64                  // pixels per inch of match geometry; 0 for points
+}
+
+// Option is a functional option for configuring PII detection.
+type Option func(*config)
+
+// WithKinds restricts detection to the given kinds. By default every kind
+// is detected.
+func WithKinds(kinds ...Kind) Option {
+	return func(c *config) {
+		c.Kinds = kinds
+	}
+}
+
+// WithRedactOptions sets the options used by Redact, such as the padding
+// and fill color.
+func WithRedactOptions(opts ...redact.Option) Option {
+	return func(c *config) {
+		c.RedactOptions = opts
+	}
+}
+
+// WithCoordinateSpace sets the coordinate space of the geometry of
+// matches, as search.WithCoordinateSpace does. Redact always redacts the
+// right areas; pass Areas only matches in crazypdf.PDFDefault at 72 DPI.
+// Default is crazypdf.PDFDefault.
+func WithCoordinateSpace(space crazypdf.CoordinateSpace) Option {
+	return func(c *config) {
+		c.Space = space
+	}
+}
+
+// WithDPI scales the geometry of matches to pixels at dpi dots per inch.
+// Default is 0, coordinates in points.
+func WithDPI(dpi float64) Option {
+	return func(c *config) {
+		c.DPI = dpi
+	}
+}
+
+// enabled reports whether a kind should be detected.
+func (c *config) enabled(kind Kind) bool {
+	if len(c.Kinds) == 0 {
+		return true
+	}
+	for _, k := range c.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultConfig returns the default detection configuration.
+func defaultConfig() *config {
+	return &config{}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
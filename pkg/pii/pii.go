@@ -0,0 +1,318 @@
+// Package pii detects personally identifiable information in PDF text,
+// such as email addresses, phone numbers and payment card numbers.
+//
+// Candidates are found with regular expressions over the page text and
+// then validated where a checksum exists (Luhn for card numbers, mod-97
+// for IBANs), so every match carries its page and bounding box and can be
+// passed straight to the redact package.
+package pii
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/redact"
+	"github.com/ayushanand18/crazypdf/pkg/search"
+)
+
+// Kind identifies a category of personal information.
+type Kind int
+
+const (
+	// KindEmail is an email address.
+	KindEmail Kind = iota
+
+	// KindPhone is a telephone number with 10 to 15 digits.
+	KindPhone
+
+	// KindSSN is a US Social Security number in the form 123-45-6789.
+	KindSSN
+
+	// KindIBAN is an International Bank Account Number.
+	KindIBAN
+
+	// KindCreditCard is a payment card number that passes the Luhn check.
+	KindCreditCard
+)
+
+// AllKinds lists every supported kind, from most to least specific.
+var AllKinds = []Kind{KindCreditCard, KindIBAN, KindSSN, KindEmail, KindPhone}
+
+// String returns the lowercase name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindEmail:
+		return "email"
+	case KindPhone:
+		return "phone"
+	case KindSSN:
+		return "ssn"
+	case KindIBAN:
+		return "iban"
+	case KindCreditCard:
+		return "credit_card"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the kind by name, so JSON output is readable.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Match is a detected piece of personal information.
+type Match struct {
+	Kind Kind
+	search.Match
+}
+
+// detector finds candidates of one kind and validates them.
+type detector struct {
+	pattern *regexp.Regexp
+	valid   func(string) bool
+}
+
+var detectors = map[Kind]detector{
+	KindEmail: {
+		pattern: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,}\b`),
+	},
+	KindPhone: {
+		pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{2,4}\)[ .-]?|\b\d{2,4}[ .-])\d{3,4}[ .-]\d{3,4}\b`),
+		valid:   validPhone,
+	},
+	KindSSN: {
+		pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		valid:   validSSN,
+	},
+	KindIBAN: {
+		pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+		valid:   validIBAN,
+	},
+	KindCreditCard: {
+		pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid:   validCard,
+	},
+}
+
+// Scan detects personal information on every page of a document.
+func Scan(doc *crazypdf.Document, opts ...Option) ([]Match, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	return scan(doc, applyOptions(opts), true)
+}
+
+// scan detects personal information on every page of a document, with
+// the geometry of matches converted to the configured coordinate space
+// when convert is set and in PDF user space otherwise.
+func scan(doc *crazypdf.Document, cfg *config, convert bool) ([]Match, error) {
+	var matches []Match
+	for _, page := range doc.Pages() {
+		pageMatches, err := scanPage(page, cfg)
+		if err == nil && convert {
+			err = cfg.convert(page, pageMatches)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan page %d: %w", page.Number, err)
+		}
+		matches = append(matches, pageMatches...)
+	}
+	return matches, nil
+}
+
+// ScanPage detects personal information on a single page. When matches of
+// different kinds overlap, the most specific kind, in AllKinds order, wins.
+func ScanPage(page *crazypdf.Page, opts ...Option) ([]Match, error) {
+	cfg := applyOptions(opts)
+	matches, err := scanPage(page, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.convert(page, matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// scanPage detects personal information on a page, in PDF user space.
+func scanPage(page *crazypdf.Page, cfg *config) ([]Match, error) {
+	var matches []Match
+	claimed := make(map[crazypdf.Rect]bool) // word boxes already matched
+	for _, kind := range AllKinds {
+		if !cfg.enabled(kind) {
+			continue
+		}
+		d := detectors[kind]
+		found, err := search.FindPage(page, d.pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range found {
+			if d.valid != nil && !d.valid(m.Text) {
+				continue
+			}
+			if overlaps(m, claimed) {
+				continue
+			}
+			for _, w := range m.Words {
+				claimed[w.BBox] = true
+			}
+			matches = append(matches, Match{Kind: kind, Match: m})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].BBox, matches[j].BBox
+		if a.Y1 != b.Y1 {
+			return a.Y1 > b.Y1
+		}
+		return a.X0 < b.X0
+	})
+	return matches, nil
+}
+
+// convert converts the geometry of the matches on a page, in place, to
+// the configured coordinate space.
+func (c *config) convert(page *crazypdf.Page, matches []Match) error {
+	coords, err := page.Coordinates(c.Space, c.DPI)
+	if err != nil {
+		return err
+	}
+	for i := range matches {
+		m := &matches[i]
+		m.BBox = coords.Rect(m.BBox)
+		for j, q := range m.Quads {
+			m.Quads[j] = coords.Quad(q)
+		}
+		m.Words = coords.Words(m.Words)
+	}
+	return nil
+}
+
+// overlaps reports whether a match covers a word claimed by another match.
+func overlaps(m search.Match, claimed map[crazypdf.Rect]bool) bool {
+	for _, w := range m.Words {
+		if claimed[w.BBox] {
+			return true
+		}
+	}
+	return false
+}
+
+// Areas converts matches into redaction areas. The matches must be in
+// PDF user space, as Scan returns them by default.
+func Areas(matches []Match) []redact.Area {
+	found := make([]search.Match, len(matches))
+	for i, m := range matches {
+		found[i] = m.Match
+	}
+	return redact.MatchAreas(found)
+}
+
+// Redact detects personal information in a document, redacts it and
+// writes the resulting document to w.
+func Redact(doc *crazypdf.Document, w io.Writer, opts ...Option) ([]Match, *redact.Report, error) {
+	if doc.IsClosed() {
+		return nil, nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+	matches, err := scan(doc, cfg, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err := redact.Apply(doc, Areas(matches), w, cfg.RedactOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Matches are in page order; convert them a page at a time
+	for start := 0; start < len(matches); {
+		end := start + 1
+		for end < len(matches) && matches[end].Page == matches[start].Page {
+			end++
+		}
+		page, err := doc.Page(matches[start].Page - 1)
+		if err == nil {
+			err = cfg.convert(page, matches[start:end])
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		start = end
+	}
+	return matches, report, nil
+}
+
+// digits returns the decimal digits of s.
+func digits(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// validPhone accepts numbers with 10 to 15 digits, the range of national
+// and international (E.164) numbers.
+func validPhone(s string) bool {
+	n := len(digits(s))
+	return n >= 10 && n <= 15
+}
+
+// validSSN rejects numbers the Social Security Administration never
+// issues: area 000, 666 or 900-999, group 00 and serial 0000.
+func validSSN(s string) bool {
+	d := digits(s)
+	if len(d) != 9 {
+		return false
+	}
+	area, group, serial := d[:3], d[3:5], d[5:]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validIBAN checks the length and ISO 7064 mod-97 checksum of an IBAN.
+func validIBAN(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	rem := 0
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+// validCard checks the length and Luhn checksum of a card number.
+func validCard(s string) bool {
+	d := digits(s)
+	if len(d) < 13 || len(d) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(d); i++ {
+		n := int(d[len(d)-1-i] - '0')
+		if i%2 == 1 {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
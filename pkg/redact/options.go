@@ -0,0 +1,73 @@
+package redact
+
+import "github.com/ayushanand18/crazypdf/pkg/crazypdf"
+
+// config holds configuration for redaction.
+type config struct {
+	Padding      float64     // points added around each area
+	Fill         *[3]float64 // RGB color of the box painted over areas; nil for none
+	WriteOptions []crazypdf.WriteOption
+	Metadata     crazypdf.MetadataPolicy
+}
+
+// Option is a functional option for configuring redaction.
+type Option func(*config)
+
+// WithPadding sets the margin in points added on every side of each
+// redaction area. Default is 1.
+func WithPadding(points float64) Option {
+	return func(c *config) {
+		c.Padding = points
+	}
+}
+
+// WithFillColor sets the RGB color, with components from 0 to 1, of the
+// opaque box painted over each redacted area. Default is black.
+func WithFillColor(r, g, b float64) Option {
+	return func(c *config) {
+		c.Fill = &[3]float64{r, g, b}
+	}
+}
+
+// WithoutFill removes content without painting a box over it, leaving
+// blank space where the redacted content was.
+func WithoutFill() Option {
+	return func(c *config) {
+		c.Fill = nil
+	}
+}
+
+// WithWriteOptions sets how the redacted document is written, such as
+// computing its SHA-256 with crazypdf.WithChecksum.
+func WithWriteOptions(opts ...crazypdf.WriteOption) Option {
+	return func(c *config) {
+		c.WriteOptions = opts
+	}
+}
+
+// WithMetadata sets what happens to the document metadata in the
+// redacted output: crazypdf.MetadataPreserve (the default),
+// crazypdf.MetadataStrip or crazypdf.MetadataReplace(info).
+func WithMetadata(policy crazypdf.MetadataPolicy) Option {
+	return func(c *config) {
+		c.Metadata = policy
+	}
+}
+
+// defaultConfig returns the default redaction configuration.
+func defaultConfig() *config {
+	return &config{
+		Padding:  1,
+		Fill:     &[3]float64{0, 0, 0},
+		Metadata: crazypdf.MetadataPreserve,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,275 @@
+// Package redact permanently removes content from PDF documents.
+//
+// Redaction is destructive: glyphs inside a redaction area are deleted from
+// the page content streams rather than merely covered, fully covered images
+// and overlapping annotations are dropped, and the document is rewritten so
+// that the removed content is not kept in unreferenced objects. An opaque
+// box is painted over every area by default.
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/search"
+)
+
+// ErrTextNotRemoved is returned when an area derived from text, such as a
+// search match, removes no glyphs from the page: the text it was meant to
+// redact would be left in the output, so nothing is written.
+var ErrTextNotRemoved = errors.New("redact: text under an area was not removed")
+
+// Area is a region of a page to redact.
+type Area struct {
+	// Page is the 1-based page number.
+	Page int `json:"page"`
+
+	// Rect is the region in PDF user space.
+	Rect crazypdf.Rect `json:"rect"`
+
+	// Text is the text the area was derived from, if any. It is used for
+	// reporting, and an area with text that removes no glyphs fails the
+	// redaction with ErrTextNotRemoved.
+	Text string `json:"text,omitempty"`
+}
+
+// PageReport summarizes what was removed from one page.
+type PageReport struct {
+	Page  int    `json:"page"`
+	Areas []Area `json:"areas"`
+
+	// Glyphs is the number of glyphs deleted from the page text.
+	Glyphs int `json:"glyphs"`
+
+	// Images is the number of fully covered images removed.
+	Images int `json:"images"`
+
+	// PartialImages is the number of images that overlap an area but were
+	// kept because they are only partly covered. Their pixels are not
+	// modified.
+	PartialImages int `json:"partial_images"`
+
+	// Annotations is the number of overlapping annotations removed.
+	Annotations int `json:"annotations"`
+}
+
+// Report describes the result of a redaction.
+type Report struct {
+	Pages []PageReport `json:"pages"`
+
+	// Warnings lists content that may still be recoverable, such as
+	// partly covered images.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Output describes the written document.
+	Output *crazypdf.WriteResult `json:"output,omitempty"`
+}
+
+// Glyphs returns the total number of glyphs removed.
+func (r *Report) Glyphs() int {
+	n := 0
+	for _, p := range r.Pages {
+		n += p.Glyphs
+	}
+	return n
+}
+
+// Apply redacts the given areas and writes the resulting document to w.
+// Areas on the same page are applied together. When an area with Text
+// removes no glyphs, nothing is written and the error wraps
+// ErrTextNotRemoved.
+func Apply(doc *crazypdf.Document, areas []Area, w io.Writer, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	return apply(doc, areas, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.Write(w, fn, cfg.WriteOptions...)
+	})
+}
+
+// ApplyFile redacts the given areas and writes the resulting document to
+// path atomically.
+func ApplyFile(doc *crazypdf.Document, areas []Area, path string, opts ...Option) (*Report, error) {
+	cfg := applyOptions(opts)
+	return apply(doc, areas, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.WriteFile(path, fn, cfg.WriteOptions...)
+	})
+}
+
+// apply redacts areas and hands the serialized document to write.
+func apply(doc *crazypdf.Document, areas []Area, cfg *config, write func(func(io.Writer) error) (*crazypdf.WriteResult, error)) (*Report, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+	refs, err := editor.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+
+	byPage := make(map[int][]Area)
+	for _, a := range areas {
+		if a.Page < 1 || a.Page > len(refs) {
+			return nil, fmt.Errorf("%w: area on page %d, document has %d pages", crazypdf.ErrPageOutOfRange, a.Page, len(refs))
+		}
+		byPage[a.Page] = append(byPage[a.Page], a)
+	}
+	pages := make([]int, 0, len(byPage))
+	for p := range byPage {
+		pages = append(pages, p)
+	}
+	sort.Ints(pages)
+
+	report := &Report{}
+	var missed []string
+	for _, p := range pages {
+		pageAreas := byPage[p]
+		rects := make([]internalpdf.Rect, len(pageAreas))
+		for i, a := range pageAreas {
+			rects[i] = internalpdf.Rect{
+				X0: a.Rect.X0 - cfg.Padding,
+				Y0: a.Rect.Y0 - cfg.Padding,
+				X1: a.Rect.X1 + cfg.Padding,
+				Y1: a.Rect.Y1 + cfg.Padding,
+			}
+		}
+
+		stats, err := editor.RedactPage(refs[p-1], rects, cfg.Fill)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact page %d: %w", p, err)
+		}
+		report.Pages = append(report.Pages, PageReport{
+			Page:          p,
+			Areas:         pageAreas,
+			Glyphs:        stats.Glyphs,
+			Images:        stats.Images,
+			PartialImages: stats.PartialImages,
+			Annotations:   stats.Annotations,
+		})
+		for i, a := range pageAreas {
+			if a.Text != "" && stats.AreaGlyphs[i] == 0 {
+				missed = append(missed, fmt.Sprintf("page %d %q", p, a.Text))
+			}
+		}
+		if stats.PartialImages > 0 {
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"page %d: %d partly covered image(s) kept with original pixels", p, stats.PartialImages))
+		}
+	}
+
+	if len(missed) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTextNotRemoved, strings.Join(missed, ", "))
+	}
+
+	crazypdf.ApplyMetadata(editor, cfg.Metadata)
+	writeCfg := crazypdf.NewWriteConfig(cfg.WriteOptions...)
+	editor.UniqueID = writeCfg.UniqueID
+	if err := crazypdf.ApplyInitialView(editor, writeCfg.InitialView); err != nil {
+		return nil, fmt.Errorf("failed to set initial view: %w", err)
+	}
+	output, err := write(func(w io.Writer) error {
+		_, err := editor.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	report.Output = output
+	return report, nil
+}
+
+// Pattern redacts every match of a regular expression and writes the
+// resulting document to w.
+func Pattern(doc *crazypdf.Document, pattern *regexp.Regexp, w io.Writer, opts ...Option) (*Report, error) {
+	matches, err := search.Find(doc, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(doc, MatchAreas(matches), w, opts...)
+}
+
+// MatchAreas converts search matches into redaction areas. A match that
+// covers only part of a word yields an area over the matched characters
+// alone, estimated from the word's box, so surrounding text is kept.
+// Matches without offsets, as built by hand, are located by searching the
+// words for their text.
+func MatchAreas(matches []search.Match) []Area {
+	var areas []Area
+	for _, m := range matches {
+		located := m.End > 0
+		for i, w := range m.Words {
+			start, end := 0, len(w.S)
+			if i == 0 {
+				if located {
+					start = m.Start
+				} else if len(m.Words) > 1 {
+					start = suffixStart(w.S, m.Text)
+				} else if idx := strings.Index(w.S, m.Text); idx >= 0 {
+					start, end = idx, idx+len(m.Text)
+				}
+			}
+			if i == len(m.Words)-1 {
+				if located {
+					end = m.End
+				} else if len(m.Words) > 1 {
+					end = prefixEnd(w.S, m.Text)
+				}
+			}
+			areas = append(areas, Area{
+				Page: m.Page,
+				Rect: subRect(w, start, end),
+				Text: w.S[start:end],
+			})
+		}
+	}
+	return areas
+}
+
+// suffixStart returns the offset of the longest suffix of word that is a
+// prefix of text.
+func suffixStart(word, text string) int {
+	for i := 0; i < len(word); i++ {
+		if strings.HasPrefix(text, word[i:]) {
+			return i
+		}
+	}
+	return 0
+}
+
+// prefixEnd returns the end of the longest prefix of word that is a suffix
+// of text.
+func prefixEnd(word, text string) int {
+	for i := len(word); i > 0; i-- {
+		if strings.HasSuffix(text, word[:i]) {
+			return i
+		}
+	}
+	return len(word)
+}
+
+// subRect estimates the box of the characters word.S[start:end], assuming
+// every character has the same width.
+func subRect(w crazypdf.Word, start, end int) crazypdf.Rect {
+	total := utf8.RuneCountInString(w.S)
+	if total == 0 {
+		return w.BBox
+	}
+	charWidth := w.BBox.Width() / float64(total)
+	r := w.BBox
+	r.X0 = w.BBox.X0 + charWidth*float64(utf8.RuneCountInString(w.S[:start]))
+	r.X1 = w.BBox.X0 + charWidth*float64(utf8.RuneCountInString(w.S[:end]))
+	return r
+}
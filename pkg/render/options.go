@@ -0,0 +1,54 @@
+package render
+
+// config holds configuration for rendering.
+type config struct {
+	Scale  float64 // output size relative to the page size in points
+	Text   bool    // draw text
+	Images bool    // embed image data instead of placeholders
+}
+
+// Option is a functional option for configuring rendering.
+type Option func(*config)
+
+// WithScale sets the output width and height relative to the page size in
+// points. The drawing itself stays vector, so scaling only changes the
+// default display size. Default is 1.
+func WithScale(scale float64) Option {
+	return func(c *config) {
+		c.Scale = scale
+	}
+}
+
+// WithText controls whether page text is drawn. Default is true.
+func WithText(enabled bool) Option {
+	return func(c *config) {
+		c.Text = enabled
+	}
+}
+
+// WithImages controls whether image data is embedded. When disabled, or
+// when an image cannot be converted, a gray placeholder is drawn in its
+// place. Default is true.
+func WithImages(enabled bool) Option {
+	return func(c *config) {
+		c.Images = enabled
+	}
+}
+
+// defaultConfig returns the default rendering configuration.
+func defaultConfig() *config {
+	return &config{
+		Scale:  1,
+		Text:   true,
+		Images: true,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
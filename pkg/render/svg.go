@@ -0,0 +1,317 @@
+// Package render converts PDF pages into other visual formats.
+//
+// Rendering works from the page's content streams: paths are converted
+// with their colors, line styles and clipping, images are embedded, and
+// text is drawn from the positioned text runs using generic font
+// families. The output is a faithful preview, not a pixel-exact
+// reproduction; shadings and patterns are approximated.
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// minLineWidth is the stroke width used for zero-width (hairline) lines,
+// in points.
+const minLineWidth = 0.3
+
+// SVG converts the vector graphics, images and text of a page into an SVG
+// document sized to the page's media box.
+func SVG(page *crazypdf.Page, opts ...Option) ([]byte, error) {
+	cfg := applyOptions(opts)
+
+	box, err := page.MediaBox()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page geometry: %w", err)
+	}
+	items, err := page.Drawing()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page graphics: %w", err)
+	}
+
+	var texts []internalpdf.StyledText
+	if cfg.Text {
+		texts, err = page.StyledTexts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page text: %w", err)
+		}
+	}
+	return svgDocument(items, texts, box, cfg), nil
+}
+
+// DrawingSVG converts painted paths and images, such as the Drawing of an
+// annotations.Appearance, into an SVG document showing the region box of
+// user space.
+func DrawingSVG(items []crazypdf.DrawItem, box crazypdf.Rect, opts ...Option) ([]byte, error) {
+	cfg := applyOptions(opts)
+	if box.Width() <= 0 || box.Height() <= 0 {
+		return nil, fmt.Errorf("empty drawing area %v", box)
+	}
+	return svgDocument(items, nil, box, cfg), nil
+}
+
+// svgDocument writes an SVG document of the region box with the given
+// drawing and text on a white background.
+func svgDocument(items []crazypdf.DrawItem, texts []internalpdf.StyledText, box crazypdf.Rect, cfg *config) []byte {
+	r := &svgRenderer{cfg: cfg, clips: make(map[*crazypdf.Clip]string)}
+	fmt.Fprintf(&r.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="white"/>`+"\n",
+		num(box.X0), num(box.Y0), num(box.Width()), num(box.Height()))
+	for i := range items {
+		r.item(&items[i])
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		num(box.Width()*cfg.Scale), num(box.Height()*cfg.Scale), num(box.Width()), num(box.Height()))
+	// PDF user space has its origin at the bottom-left with y up
+	fmt.Fprintf(&out, `<g transform="matrix(1 0 0 -1 %s %s)">`+"\n", num(-box.X0), num(box.Y1))
+	if r.defs.Len() > 0 {
+		out.WriteString("<defs>\n")
+		out.Write(r.defs.Bytes())
+		out.WriteString("</defs>\n")
+	}
+	out.Write(r.body.Bytes())
+	out.WriteString("</g>\n")
+	if len(texts) > 0 {
+		writeText(&out, texts, box)
+	}
+	out.WriteString("</svg>\n")
+	return out.Bytes()
+}
+
+// svgRenderer accumulates the definitions and drawing of a page.
+type svgRenderer struct {
+	cfg   *config
+	defs  bytes.Buffer
+	body  bytes.Buffer
+	clips map[*crazypdf.Clip]string
+}
+
+// item writes one painted path or image.
+func (r *svgRenderer) item(it *crazypdf.DrawItem) {
+	clip := ""
+	if id := r.clipID(it.Clip); id != "" {
+		clip = fmt.Sprintf(` clip-path="url(#%s)"`, id)
+	}
+
+	if it.Image {
+		m := it.Matrix
+		// Image rows run top to bottom; the unit square has y up.
+		transform := fmt.Sprintf(`matrix(%s %s %s %s %s %s) matrix(1 0 0 -1 0 1)`,
+			num(m[0]), num(m[1]), num(m[2]), num(m[3]), num(m[4]), num(m[5]))
+		opacity := ""
+		if it.FillAlpha < 1 {
+			opacity = fmt.Sprintf(` opacity="%s"`, num(it.FillAlpha))
+		}
+		fmt.Fprintf(&r.body, "<g%s%s>", clip, opacity)
+		if href := r.imageHref(it.ImageStream); href != "" {
+			fmt.Fprintf(&r.body, `<image transform="%s" width="1" height="1" preserveAspectRatio="none" href="%s"/>`, transform, href)
+		} else {
+			fmt.Fprintf(&r.body, `<rect transform="%s" width="1" height="1" fill="#ccc"/>`, transform)
+		}
+		r.body.WriteString("</g>\n")
+		return
+	}
+
+	var attrs strings.Builder
+	if it.Fill {
+		fmt.Fprintf(&attrs, ` fill="%s"`, hexColor(it.FillColor))
+		if it.FillAlpha < 1 {
+			fmt.Fprintf(&attrs, ` fill-opacity="%s"`, num(it.FillAlpha))
+		}
+		if it.EvenOdd {
+			attrs.WriteString(` fill-rule="evenodd"`)
+		}
+	} else {
+		attrs.WriteString(` fill="none"`)
+	}
+	if it.Stroke {
+		width := it.LineWidth
+		if width < minLineWidth {
+			width = minLineWidth
+		}
+		fmt.Fprintf(&attrs, ` stroke="%s" stroke-width="%s"`, hexColor(it.StrokeColor), num(width))
+		if it.StrokeAlpha < 1 {
+			fmt.Fprintf(&attrs, ` stroke-opacity="%s"`, num(it.StrokeAlpha))
+		}
+		if caps := [...]string{"", "round", "square"}; it.LineCap > 0 && it.LineCap < len(caps) {
+			fmt.Fprintf(&attrs, ` stroke-linecap="%s"`, caps[it.LineCap])
+		}
+		if joins := [...]string{"", "round", "bevel"}; it.LineJoin > 0 && it.LineJoin < len(joins) {
+			fmt.Fprintf(&attrs, ` stroke-linejoin="%s"`, joins[it.LineJoin])
+		}
+		if dash := dashArray(it.Dash); dash != "" {
+			fmt.Fprintf(&attrs, ` stroke-dasharray="%s"`, dash)
+		}
+	}
+	fmt.Fprintf(&r.body, `<path d="%s"%s%s/>`+"\n", pathData(it.Segments), attrs.String(), clip)
+}
+
+// clipID returns the id of the clipPath element for c, defining it and
+// its parents on first use.
+func (r *svgRenderer) clipID(c *crazypdf.Clip) string {
+	if c == nil {
+		return ""
+	}
+	if id, ok := r.clips[c]; ok {
+		return id
+	}
+	parent := r.clipID(c.Parent)
+	id := fmt.Sprintf("clip%d", len(r.clips)+1)
+	r.clips[c] = id
+
+	fmt.Fprintf(&r.defs, `<clipPath id="%s" clipPathUnits="userSpaceOnUse"`, id)
+	if parent != "" {
+		fmt.Fprintf(&r.defs, ` clip-path="url(#%s)"`, parent)
+	}
+	rule := ""
+	if c.EvenOdd {
+		rule = ` clip-rule="evenodd"`
+	}
+	fmt.Fprintf(&r.defs, `><path d="%s"%s/></clipPath>`+"\n", pathData(c.Segments), rule)
+	return id
+}
+
+// imageHref returns a data URI for an image, or "" when images are
+// disabled or the image cannot be converted.
+func (r *svgRenderer) imageHref(s *internalpdf.Stream) string {
+	if !r.cfg.Images || s == nil {
+		return ""
+	}
+	data, mime, err := internalpdf.EncodeImage(s)
+	if err != nil {
+		return ""
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// writeText draws text runs at their baselines, merging adjacent runs of
+// the same font so each line becomes a few elements instead of one per
+// glyph.
+func writeText(out *bytes.Buffer, texts []internalpdf.StyledText, box crazypdf.Rect) {
+	flush := func(run internalpdf.StyledText) {
+		if strings.TrimSpace(run.Text) == "" || run.FontSize <= 0 {
+			return
+		}
+		family, weight, style := fontStyle(run.Font)
+		fmt.Fprintf(out, `<text x="%s" y="%s" font-family="%s" font-size="%s"`,
+			num(run.X-box.X0), num(box.Y1-run.Y), html.EscapeString(family), num(run.FontSize))
+		if weight != "" {
+			fmt.Fprintf(out, ` font-weight="%s"`, weight)
+		}
+		if style != "" {
+			fmt.Fprintf(out, ` font-style="%s"`, style)
+		}
+		if run.W > 0 {
+			fmt.Fprintf(out, ` textLength="%s" lengthAdjust="spacingAndGlyphs"`, num(run.W))
+		}
+		fmt.Fprintf(out, ` xml:space="preserve">%s</text>`+"\n", html.EscapeString(run.Text))
+	}
+
+	var run internalpdf.StyledText
+	for i, t := range texts {
+		adjacent := i > 0 && t.Font == run.Font && t.FontSize == run.FontSize &&
+			math.Abs(t.Y-run.Y) < 0.01 && math.Abs(t.X-(run.X+run.W)) <= run.FontSize*0.1
+		if adjacent {
+			run.Text += t.Text
+			run.W = t.X + t.W - run.X
+			continue
+		}
+		if i > 0 {
+			flush(run)
+		}
+		run = t
+	}
+	if len(texts) > 0 {
+		flush(run)
+	}
+}
+
+// fontStyle maps a PDF font name to a CSS font-family list, weight and
+// style.
+func fontStyle(font string) (family, weight, style string) {
+	name := font
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		name = name[i+1:] // subset prefix
+	}
+	lower := strings.ToLower(name)
+
+	generic := "sans-serif"
+	switch {
+	case strings.Contains(lower, "courier") || strings.Contains(lower, "mono"):
+		generic = "monospace"
+	case strings.Contains(lower, "times") || strings.Contains(lower, "roman") ||
+		strings.Contains(lower, "georgia") || strings.Contains(lower, "garamond") ||
+		strings.Contains(lower, "serif") && !strings.Contains(lower, "sans"):
+		generic = "serif"
+	}
+	if strings.Contains(lower, "bold") || strings.Contains(lower, "black") || strings.Contains(lower, "heavy") {
+		weight = "bold"
+	}
+	if strings.Contains(lower, "italic") || strings.Contains(lower, "oblique") {
+		style = "italic"
+	}
+
+	base := name
+	if i := strings.IndexAny(base, ",-"); i > 0 {
+		base = base[:i]
+	}
+	if base == "" {
+		return generic, weight, style
+	}
+	return fmt.Sprintf("'%s', %s", strings.ReplaceAll(base, "'", ""), generic), weight, style
+}
+
+// pathData converts path segments to SVG path syntax.
+func pathData(segs []crazypdf.Segment) string {
+	var sb strings.Builder
+	for _, s := range segs {
+		switch s.Op {
+		case 'M', 'L':
+			fmt.Fprintf(&sb, "%c%s %s", s.Op, num(s.Pts[0]), num(s.Pts[1]))
+		case 'C':
+			fmt.Fprintf(&sb, "C%s %s %s %s %s %s", num(s.Pts[0]), num(s.Pts[1]),
+				num(s.Pts[2]), num(s.Pts[3]), num(s.Pts[4]), num(s.Pts[5]))
+		case 'Z':
+			sb.WriteByte('Z')
+		}
+	}
+	return sb.String()
+}
+
+// dashArray formats a dash pattern, or returns "" for solid lines.
+func dashArray(dash []float64) string {
+	total := 0.0
+	parts := make([]string, len(dash))
+	for i, v := range dash {
+		total += v
+		parts[i] = num(v)
+	}
+	if total <= 0 {
+		return ""
+	}
+	return strings.Join(parts, " ")
+}
+
+// hexColor formats RGB components in [0, 1] as #rrggbb.
+func hexColor(c [3]float64) string {
+	var b [3]byte
+	for i, v := range c {
+		b[i] = byte(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", b[0], b[1], b[2])
+}
+
+// num formats a coordinate with at most two decimals.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
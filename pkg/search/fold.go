@@ -0,0 +1,182 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// foldGroups maps letters with diacritics, ligatures and other letter
+// variants to the plain letters they are searched as. It lists every
+// precomposed letter of the Latin (Latin-1 Supplement, Extended-A and -B
+// and Extended Additional, which holds the Vietnamese letters), Greek
+// (including polytonic Greek Extended) and Cyrillic blocks whose
+// canonical decomposition is a letter followed by combining marks, by
+// that letter, as well as letters without a decomposition such as "ø" or
+// "ł". Letters not listed fold to themselves once combining marks are
+// removed.
+var foldGroups = map[string]string{
+	// Latin
+	"ÀÁÂÃÄÅĀĂĄǍǞǠǺȀȂȦḀẠẢẤẦẨẪẬẮẰẲẴẶàáâãäåāăąǎǟǡǻȁȃȧḁạảấầẩẫậắằẳẵặ": "a",
+	"ÆǢǼæǣǽ":           "ae",
+	"ƁḂḄḆɓḃḅḇ":         "b",
+	"ÇĆĈĊČḈçćĉċčḉ":     "c",
+	"ÐĎĐḊḌḎḐḒðďđḋḍḏḑḓ": "d",
+	"ÈÉÊËĒĔĖĘĚȄȆȨḔḖḘḚḜẸẺẼẾỀỂỄỆèéêëēĕėęěȅȇȩḕḗḙḛḝẹẻẽếềểễệ": "e",
+	"ƑḞƒḟ":           "f",
+	"ﬀ":              "ff",
+	"ﬃ":              "ffi",
+	"ﬄ":              "ffl",
+	"ﬁ":              "fi",
+	"ﬂ":              "fl",
+	"ĜĞĠĢǦǴḠĝğġģǧǵḡ": "g",
+	"ĤĦȞḢḤḦḨḪĥħȟḣḥḧḩḫẖ":                "h",
+	"ÌÍÎÏĨĪĬĮİǏȈȊḬḮỈỊìíîïĩīĭįıǐȉȋḭḯỉị": "i",
+	"Ĳĳ":          "ij",
+	"Ĵĵǰ":         "j",
+	"ĶǨḰḲḴķĸǩḱḳḵ": "k",
+	"ĹĻĽĿŁḶḸḺḼĺļľŀłḷḹḻḽ":  "l",
+	"ḾṀṂḿṁṃ":              "m",
+	"ÑŃŅŇǸṄṆṈṊñńņňŉǹṅṇṉṋ": "n",
+	"ÒÓÔÕÖØŌŎŐƠǑǪǬǾȌȎȪȬȮȰṌṎṐṒỌỎỐỒỔỖỘỚỜỞỠỢòóôõöøōŏőơǒǫǭǿȍȏȫȭȯȱṍṏṑṓọỏốồổỗộớờởỡợ": "o",
+	"Œœ":   "oe",
+	"ṔṖṕṗ": "p",
+	"ŔŖŘȐȒṘṚṜṞŕŗřȑȓṙṛṝṟ":     "r",
+	"ŚŜŞŠȘṠṢṤṦṨśŝşšſșṡṣṥṧṩẛ": "s",
+	"ß":  "ss",
+	"ﬅﬆ": "st",
+	"ŢŤŦȚṪṬṮṰţťŧțṫṭṯṱẗ": "t",
+	"Þþ": "th",
+	"ÙÚÛÜŨŪŬŮŰŲƯǓǕǗǙǛȔȖṲṴṶṸṺỤỦỨỪỬỮỰùúûüũūŭůűųưǔǖǘǚǜȕȗṳṵṷṹṻụủứừửữự": "u",
+	"ṼṾṽṿ":          "v",
+	"ŴẀẂẄẆẈŵẁẃẅẇẉẘ": "w",
+	"ẊẌẋẍ":          "x",
+	"ÝŶŸȲẎỲỴỶỸýÿŷȳẏẙỳỵỷỹ": "y",
+	"ŹŻŽẐẒẔźżžẑẓẕ":        "z",
+	"Ǯǯ":                  "ʒ",
+	// Greek
+	"ΆἈἉἊἋἌἍἎἏᾈᾉᾊᾋᾌᾍᾎᾏᾸᾹᾺΆᾼάἀἁἂἃἄἅἆἇὰάᾀᾁᾂᾃᾄᾅᾆᾇᾰᾱᾲᾳᾴᾶᾷ": "α",
+	"ΈἘἙἚἛἜἝῈΈέἐἑἒἓἔἕὲέ":                           "ε",
+	"ΉἨἩἪἫἬἭἮἯᾘᾙᾚᾛᾜᾝᾞᾟῊΉῌήἠἡἢἣἤἥἦἧὴήᾐᾑᾒᾓᾔᾕᾖᾗῂῃῄῆῇ": "η",
+	"ΊΪἸἹἺἻἼἽἾἿῘῙῚΊΐίϊἰἱἲἳἴἵἶἷὶίιῐῑῒΐῖῗ":           "ι",
+	"ΌὈὉὊὋὌὍῸΌόὀὁὂὃὄὅὸό":                           "ο",
+	"Ῥῤῥ": "ρ",
+	"ΎΫὙὛὝὟῨῩῪΎΰϋύὐὑὒὓὔὕὖὗὺύῠῡῢΰῦῧ":                "υ",
+	"ΏὨὩὪὫὬὭὮὯᾨᾩᾪᾫᾬᾭᾮᾯῺΏῼώὠὡὢὣὤὥὦὧὼώᾠᾡᾢᾣᾤᾥᾦᾧῲῳῴῶῷ": "ω",
+	// Cyrillic
+	"ӐӒӑӓ":     "а",
+	"Ѓѓ":       "г",
+	"ЀЁӖѐёӗ":   "е",
+	"ӁӜӂӝ":     "ж",
+	"Ӟӟ":       "з",
+	"ЍЙӢӤйѝӣӥ": "и",
+	"Ќќ":       "к",
+	"Ӧӧ":       "о",
+	"ЎӮӰӲўӯӱӳ": "у",
+	"Ӵӵ":       "ч",
+	"Ӹӹ":       "ы",
+	"Ӭӭ":       "э",
+	"Її":       "і",
+	"Ѷѷ":       "ѵ",
+	"Ӛӛ":       "ә",
+	"Ӫӫ":       "ө",
+}
+
+// foldTable is foldGroups indexed by letter.
+var foldTable = func() map[rune]string {
+	table := make(map[rune]string)
+	for letters, plain := range foldGroups {
+		for _, r := range letters {
+			table[r] = plain
+		}
+	}
+	return table
+}()
+
+// foldRune returns the plain form of r: "" for combining marks, which are
+// dropped, and r itself when it has no other form.
+func foldRune(r rune) string {
+	if plain, ok := foldTable[r]; ok {
+		return plain
+	}
+	if unicode.Is(unicode.Mn, r) {
+		return ""
+	}
+	return string(r)
+}
+
+// foldedText is page text with diacritics removed and ligatures expanded,
+// with the span of the original text each byte comes from, so matches on
+// the folded text can be mapped back.
+type foldedText struct {
+	text  string
+	spans [][2]int // [start, end) in the original text of each folded byte
+}
+
+// foldText folds s for diacritic-insensitive matching. Combining marks
+// extend the span of the character they follow.
+func foldText(s string) *foldedText {
+	f := &foldedText{spans: make([][2]int, 0, len(s))}
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i, r := range s {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		end := i + size
+		plain := foldRune(r)
+		if plain == "" {
+			// Attach the mark to the preceding character
+			for j := len(f.spans) - 1; j >= 0 && f.spans[j][1] == i; j-- {
+				f.spans[j][1] = end
+			}
+			continue
+		}
+		sb.WriteString(plain)
+		for range len(plain) {
+			f.spans = append(f.spans, [2]int{i, end})
+		}
+	}
+	f.text = sb.String()
+	return f
+}
+
+// original maps a match [start, end) in the folded text to the original
+// text.
+func (f *foldedText) original(start, end int) (int, int) {
+	return f.spans[start][0], f.spans[end-1][1]
+}
+
+// foldPattern returns a case-insensitive version of pattern whose literal
+// characters are folded like the page text, so that "resume" and
+// "résumé" both match "Résumé".
+func foldPattern(pattern *regexp.Regexp) (*regexp.Regexp, error) {
+	re, err := syntax.Parse(pattern.String(), syntax.Perl|syntax.FoldCase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fold pattern: %w", err)
+	}
+	foldLiterals(re)
+	folded, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fold pattern: %w", err)
+	}
+	return folded, nil
+}
+
+// foldLiterals folds the literal runes of a parsed expression in place.
+func foldLiterals(re *syntax.Regexp) {
+	if re.Op == syntax.OpLiteral {
+		var runes []rune
+		for _, r := range re.Rune {
+			runes = append(runes, []rune(foldRune(r))...)
+		}
+		re.Rune = runes
+		if len(runes) == 0 {
+			re.Op = syntax.OpEmptyMatch
+		}
+	}
+	for _, sub := range re.Sub {
+		foldLiterals(sub)
+	}
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"regexp"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// config holds configuration for search operations.
+type config struct {
+	ContextChars int  // characters of surrounding text in Match.Context
+	Folding      bool // match regardless of case and diacritics
+	Synonyms     map[string][]string
+
+	Space crazypdf.CoordinateSpace // coordinate space of match geometry
+	DPI   float64                  // pixels per inch of match geometry; 0 for points
+}
+
+// Option is a functional option for configuring search.
+type Option func(*config)
+
+// WithContextChars sets how many characters of surrounding text are
+// included on each side of a match in Match.Context. Default is 40.
+func WithContextChars(n int) Option {
+	return func(c *config) {
+		c.ContextChars = n
+	}
+}
+
+// WithFolding makes matching insensitive to case and diacritics, so that
+// "resume" matches "Résumé" and "RÉSUMÉ" and "καλημερα" matches
+// "Καλημέρα". Diacritics are removed from combining marks and from the
+// precomposed letters of the Latin, Greek and Cyrillic blocks, including
+// Vietnamese and polytonic Greek; precomposed letters of other scripts are
+// matched as they are. Ligatures such as "ﬁ" match their letters, and "ß"
+// matches "ss". Literal characters in the pattern are folded the same
+// way; character classes are only made case-insensitive. Match.Text is
+// the original page text. Default is false.
+func WithFolding(enabled bool) Option {
+	return func(c *config) {
+		c.Folding = enabled
+	}
+}
+
+// WithSynonyms expands query terms with alternatives, so that a term
+// also matches any of the words or phrases listed under it:
+//
+//	search.WithSynonyms(map[string][]string{
+//		"acetaminophen": {"paracetamol", "APAP"},
+//		"PN-4471":       {"4471-B"},
+//	})
+//
+// Keys and alternatives are compared like query terms, by their words
+// regardless of case and punctuation, and folded with WithFolding.
+// Expansion is one-way: list both directions to make terms
+// interchangeable. Synonyms apply to FindQuery and FindQueryPage; regular
+// expressions are matched as given.
+func WithSynonyms(synonyms map[string][]string) Option {
+	return func(c *config) {
+		c.Synonyms = synonyms
+	}
+}
+
+// WithCoordinateSpace sets the coordinate space of Match.BBox, Quads and
+// the boxes of Words: crazypdf.PDFDefault, PDF user space, or
+// crazypdf.TopLeft, with the origin at the top-left corner of the media
+// box and the Y axis pointing down. Redaction areas are in PDF user
+// space, so pass redact.MatchAreas only matches in PDFDefault at 72 DPI.
+// Default is crazypdf.PDFDefault.
+func WithCoordinateSpace(space crazypdf.CoordinateSpace) Option {
+	return func(c *config) {
+		c.Space = space
+	}
+}
+
+// WithDPI scales match geometry to pixels at dpi dots per inch, to line
+// up with a page rendered at that resolution. Default is 0, coordinates
+// in points.
+func WithDPI(dpi float64) Option {
+	return func(c *config) {
+		c.DPI = dpi
+	}
+}
+
+// pattern returns the expression to run over page text: pattern itself,
+// or its folded form with WithFolding.
+func (c *config) pattern(pattern *regexp.Regexp) (*regexp.Regexp, error) {
+	if !c.Folding {
+		return pattern, nil
+	}
+	return foldPattern(pattern)
+}
+
+// defaultConfig returns the default search configuration.
+func defaultConfig() *config {
+	return &config{
+		ContextChars: 40,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,286 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Query is a parsed phrase and proximity query. Terms are single words
+// or quoted phrases, joined by NEAR/k operators:
+//
+//	indemnify NEAR/10 liability
+//	"limitation of liability" NEAR/5 "consequential damages"
+//
+// Terms match whole words regardless of case and punctuation. a NEAR/k b
+// matches a and b, in either order, with at most k words between them.
+// Chained operators apply from left to right, each to the span matched
+// so far.
+type Query struct {
+	src   string
+	terms [][]string // words of each term
+	near  []int      // maximum distance between term i and i+1
+}
+
+// ParseQuery parses a phrase and proximity query.
+func ParseQuery(s string) (*Query, error) {
+	q := &Query{src: s}
+	expectTerm := true
+	for rest := strings.TrimSpace(s); rest != ""; rest = strings.TrimSpace(rest) {
+		var tok string
+		quoted := rest[0] == '"'
+		if quoted {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid query: unterminated phrase %s", rest)
+			}
+			tok, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			tok, rest = rest[:end], rest[end:]
+		}
+
+		if k, ok := nearOperator(tok); ok && !quoted {
+			if expectTerm {
+				return nil, fmt.Errorf("invalid query: %s must follow a term", tok)
+			}
+			q.near = append(q.near, k)
+			expectTerm = true
+			continue
+		}
+		if !expectTerm {
+			return nil, fmt.Errorf("invalid query: expected NEAR/k before %q", tok)
+		}
+		words := queryWords(tok)
+		if len(words) == 0 {
+			return nil, fmt.Errorf("invalid query: %q contains no words", tok)
+		}
+		q.terms = append(q.terms, words)
+		expectTerm = false
+	}
+	if len(q.terms) == 0 {
+		return nil, fmt.Errorf("invalid query: empty query")
+	}
+	if expectTerm {
+		return nil, fmt.Errorf("invalid query: expected a term after NEAR/%d", q.near[len(q.near)-1])
+	}
+	return q, nil
+}
+
+// String returns the query as given to ParseQuery.
+func (q *Query) String() string {
+	return q.src
+}
+
+// FindQuery searches every page of a document for the query. A match
+// covers the words from the first to the last matched term.
+func FindQuery(doc *crazypdf.Document, q *Query, opts ...Option) ([]Match, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+	terms := q.prepare(cfg)
+
+	var matches []Match
+	for _, page := range doc.Pages() {
+		pageMatches, err := searchPage(page, cfg, func(text string) [][]int {
+			return q.locate(terms, text)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search page %d: %w", page.Number, err)
+		}
+		matches = append(matches, pageMatches...)
+	}
+	return matches, nil
+}
+
+// FindQueryPage searches a single page for the query.
+func FindQueryPage(page *crazypdf.Page, q *Query, opts ...Option) ([]Match, error) {
+	cfg := applyOptions(opts)
+	terms := q.prepare(cfg)
+	return searchPage(page, cfg, func(text string) [][]int {
+		return q.locate(terms, text)
+	})
+}
+
+// prepare returns the alternative word sequences of each term: the term
+// itself followed by its synonyms, folded like the page text.
+func (q *Query) prepare(cfg *config) [][][]string {
+	normalize := func(words []string) []string {
+		if !cfg.Folding {
+			return words
+		}
+		folded := make([]string, len(words))
+		for i, w := range words {
+			folded[i] = strings.ToLower(foldText(w).text)
+		}
+		return folded
+	}
+
+	synonyms := make(map[string][][]string)
+	for key, alts := range cfg.Synonyms {
+		k := strings.Join(normalize(queryWords(key)), " ")
+		for _, alt := range alts {
+			if words := normalize(queryWords(alt)); len(words) > 0 {
+				synonyms[k] = append(synonyms[k], words)
+			}
+		}
+	}
+
+	terms := make([][][]string, len(q.terms))
+	for i, words := range q.terms {
+		words = normalize(words)
+		terms[i] = append([][]string{words}, synonyms[strings.Join(words, " ")]...)
+	}
+	return terms
+}
+
+// wordToken is a word of the page text with its byte range.
+type wordToken struct {
+	word       string // lowercased
+	start, end int
+}
+
+// wordSpan is a range of word tokens [start, end).
+type wordSpan struct {
+	start, end int
+}
+
+// locate returns the byte ranges of text matching the query.
+func (q *Query) locate(terms [][][]string, text string) [][]int {
+	tokens := tokenize(text)
+	spans := occurrences(tokens, terms[0])
+	for i, k := range q.near {
+		spans = near(spans, occurrences(tokens, terms[i+1]), k)
+	}
+
+	locs := make([][]int, len(spans))
+	for i, sp := range spans {
+		locs[i] = []int{tokens[sp.start].start, tokens[sp.end-1].end}
+	}
+	return locs
+}
+
+// occurrences returns the spans where the words of one of a term's
+// alternatives appear in a row. Like regular expression matches, spans do
+// not overlap: the leftmost, then longest, alternative wins.
+func occurrences(tokens []wordToken, alternatives [][]string) []wordSpan {
+	var spans []wordSpan
+	for i := 0; i < len(tokens); {
+		best := 0
+		for _, words := range alternatives {
+			if len(words) > best && matchWords(tokens[i:], words) {
+				best = len(words)
+			}
+		}
+		if best == 0 {
+			i++
+			continue
+		}
+		spans = append(spans, wordSpan{i, i + best})
+		i += best
+	}
+	return spans
+}
+
+// matchWords reports whether tokens start with words.
+func matchWords(tokens []wordToken, words []string) bool {
+	if len(words) > len(tokens) {
+		return false
+	}
+	for j, w := range words {
+		if tokens[j].word != w {
+			return false
+		}
+	}
+	return true
+}
+
+// near pairs each left span with the closest right span that has at most
+// k words between them, returning the combined spans in text order.
+func near(left, right []wordSpan, k int) []wordSpan {
+	seen := make(map[wordSpan]bool)
+	var out []wordSpan
+	for _, l := range left {
+		best, bestGap := wordSpan{}, -1
+		for _, r := range right {
+			var gap int
+			switch {
+			case r.start >= l.end:
+				gap = r.start - l.end
+			case l.start >= r.end:
+				gap = l.start - r.end
+			default:
+				continue // overlapping
+			}
+			if gap <= k && (bestGap < 0 || gap < bestGap) {
+				best, bestGap = r, gap
+			}
+		}
+		if bestGap < 0 {
+			continue
+		}
+		sp := wordSpan{min(l.start, best.start), max(l.end, best.end)}
+		if !seen[sp] {
+			seen[sp] = true
+			out = append(out, sp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].start != out[j].start {
+			return out[i].start < out[j].start
+		}
+		return out[i].end < out[j].end
+	})
+	return out
+}
+
+// tokenize splits text into words: runs of letters and digits.
+func tokenize(text string) []wordToken {
+	var tokens []wordToken
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, wordToken{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, wordToken{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// queryWords returns the lowercased words of a query term.
+func queryWords(term string) []string {
+	tokens := tokenize(term)
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word
+	}
+	return words
+}
+
+// nearOperator parses a NEAR/k operator.
+func nearOperator(tok string) (int, bool) {
+	digits, ok := strings.CutPrefix(tok, "NEAR/")
+	if !ok {
+		return 0, false
+	}
+	k, err := strconv.Atoi(digits)
+	if err != nil || k < 0 {
+		return 0, false
+	}
+	return k, true
+}
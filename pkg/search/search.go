@@ -0,0 +1,222 @@
+// Package search finds text in PDF documents and locates matches on the
+// page.
+//
+// Matching runs over a per-page text reconstructed from positioned words,
+// so every match can be mapped back to the words it covers and their
+// bounding boxes.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Match is a single search hit.
+type Match struct {
+	// Page is the 1-based page number and Label the page label printed
+	// in the document, such as "iv" (see crazypdf.Page.Label).
+	Page  int
+	Label string
+
+	// Text is the matched text.
+	Text string
+
+	// Context is the matched text with surrounding characters from the
+	// same page, for display.
+	Context string
+
+	// BBox is the union of the bounding boxes of the matched words.
+	BBox crazypdf.Rect
+
+	// Quads has one quadrilateral per text line the match covers, around
+	// the matched words of that line, in reading order. Use them instead
+	// of BBox to highlight matches that span line breaks.
+	Quads []crazypdf.Quad
+
+	// Words are the words overlapped by the match.
+	Words []crazypdf.Word
+
+	// Start is the byte offset in the first of Words where the match
+	// begins, and End the offset in the last of Words where it ends.
+	Start, End int
+}
+
+// Find searches every page of a document for the pattern.
+func Find(doc *crazypdf.Document, pattern *regexp.Regexp, opts ...Option) ([]Match, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+	pattern, err := cfg.pattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, page := range doc.Pages() {
+		pageMatches, err := findPage(page, pattern, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search page %d: %w", page.Number, err)
+		}
+		matches = append(matches, pageMatches...)
+	}
+	return matches, nil
+}
+
+// FindPage searches a single page for the pattern.
+func FindPage(page *crazypdf.Page, pattern *regexp.Regexp, opts ...Option) ([]Match, error) {
+	cfg := applyOptions(opts)
+	pattern, err := cfg.pattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return findPage(page, pattern, cfg)
+}
+
+// findPage searches a page for a pattern already prepared by
+// config.pattern.
+func findPage(page *crazypdf.Page, pattern *regexp.Regexp, cfg *config) ([]Match, error) {
+	return searchPage(page, cfg, func(text string) [][]int {
+		return pattern.FindAllStringIndex(text, -1)
+	})
+}
+
+// searchPage runs locate over the text of a page, folded with
+// WithFolding, and converts the byte ranges it returns into matches.
+func searchPage(page *crazypdf.Page, cfg *config, locate func(text string) [][]int) ([]Match, error) {
+	words, err := page.Words()
+	if err != nil {
+		return nil, err
+	}
+	label, err := page.Label()
+	if err != nil {
+		return nil, err
+	}
+	coords, err := page.Coordinates(cfg.Space, cfg.DPI)
+	if err != nil {
+		return nil, err
+	}
+	idx := newPageIndex(words)
+
+	text := idx.text
+	var folded *foldedText
+	if cfg.Folding {
+		folded = foldText(idx.text)
+		text = folded.text
+	}
+
+	var matches []Match
+	for _, loc := range locate(text) {
+		start, end := loc[0], loc[1]
+		if start == end {
+			continue
+		}
+		if folded != nil {
+			start, end = folded.original(start, end)
+		}
+		m := Match{
+			Page:    page.Number,
+			Label:   label,
+			Text:    idx.text[start:end],
+			Context: idx.context(start, end, cfg.ContextChars),
+		}
+		var line crazypdf.Rect
+		in := idx.wordsIn(start, end)
+		if len(in) > 0 {
+			first, last := idx.spans[in[0]], idx.spans[in[len(in)-1]]
+			m.Start = max(start, first[0]) - first[0]
+			m.End = min(end, last[1]) - last[0]
+		}
+		for i, wi := range in {
+			if i > 0 && !sameRow(words[wi-1], words[wi]) {
+				m.Quads = append(m.Quads, crazypdf.RectQuad(line))
+				line = crazypdf.Rect{}
+			}
+			m.Words = append(m.Words, words[wi])
+			m.BBox = m.BBox.Union(words[wi].BBox)
+			line = line.Union(words[wi].BBox)
+		}
+		if len(m.Words) > 0 {
+			m.Quads = append(m.Quads, crazypdf.RectQuad(line))
+		}
+		m.BBox = coords.Rect(m.BBox)
+		for i, q := range m.Quads {
+			m.Quads[i] = coords.Quad(q)
+		}
+		m.Words = coords.Words(m.Words)
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// pageIndex is the searchable text of a page with the byte span of each
+// word, so match offsets can be mapped back to words.
+type pageIndex struct {
+	text  string
+	spans [][2]int // [start, end) byte offsets of each word in text
+}
+
+// newPageIndex joins words into page text: words on the same row are
+// separated by spaces and rows by newlines.
+func newPageIndex(words []crazypdf.Word) *pageIndex {
+	idx := &pageIndex{spans: make([][2]int, len(words))}
+	var sb strings.Builder
+	for i, w := range words {
+		if i > 0 {
+			if sameRow(words[i-1], w) {
+				sb.WriteByte(' ')
+			} else {
+				sb.WriteByte('\n')
+			}
+		}
+		idx.spans[i][0] = sb.Len()
+		sb.WriteString(w.S)
+		idx.spans[i][1] = sb.Len()
+	}
+	idx.text = sb.String()
+	return idx
+}
+
+// sameRow reports whether two consecutive words share a text row.
+func sameRow(a, b crazypdf.Word) bool {
+	return b.BBox.Y0 < a.BBox.Y1 && a.BBox.Y0 < b.BBox.Y1 && b.BBox.X0 >= a.BBox.X0
+}
+
+// wordsIn returns the indices of words overlapping [start, end).
+func (idx *pageIndex) wordsIn(start, end int) []int {
+	var result []int
+	for i, span := range idx.spans {
+		if span[0] < end && start < span[1] {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// context returns the match with up to n bytes of surrounding text on each
+// side, with newlines flattened to spaces.
+func (idx *pageIndex) context(start, end, n int) string {
+	from := start - n
+	if from < 0 {
+		from = 0
+	}
+	to := end + n
+	if to > len(idx.text) {
+		to = len(idx.text)
+	}
+	// Avoid splitting multi-byte characters at the edges
+	for from > 0 && !isRuneStart(idx.text[from]) {
+		from--
+	}
+	for to < len(idx.text) && !isRuneStart(idx.text[to]) {
+		to++
+	}
+	return strings.ReplaceAll(idx.text[from:to], "\n", " ")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,67 @@
+package split
+
+import "github.com/ayushanand18/crazypdf/pkg/crazypdf"
+
+// config holds configuration for splitting.
+type config struct {
+	Depth        int    // outline levels that start a section
+	FrontMatter  string // title of the section before the first bookmark
+	WriteOptions []crazypdf.WriteOption
+	Metadata     crazypdf.MetadataPolicy
+}
+
+// Option is a functional option for configuring splitting.
+type Option func(*config)
+
+// WithDepth sets how many outline levels start a new section: 1 cuts at
+// top-level bookmarks only, 2 also at their children, and so on. Default
+// is 1.
+func WithDepth(levels int) Option {
+	return func(c *config) {
+		c.Depth = levels
+	}
+}
+
+// WithFrontMatter sets the title of the section holding the pages before
+// the first bookmark, such as a cover and table of contents. Empty leaves
+// those pages out. Default is "Front Matter".
+func WithFrontMatter(title string) Option {
+	return func(c *config) {
+		c.FrontMatter = title
+	}
+}
+
+// WithWriteOptions sets how each section file is written, such as
+// computing its SHA-256 with crazypdf.WithChecksum.
+func WithWriteOptions(opts ...crazypdf.WriteOption) Option {
+	return func(c *config) {
+		c.WriteOptions = opts
+	}
+}
+
+// WithMetadata sets what happens to the document metadata in the section
+// files: crazypdf.MetadataPreserve (the default), crazypdf.MetadataStrip
+// or crazypdf.MetadataReplace(info).
+func WithMetadata(policy crazypdf.MetadataPolicy) Option {
+	return func(c *config) {
+		c.Metadata = policy
+	}
+}
+
+// defaultConfig returns the default split configuration.
+func defaultConfig() *config {
+	return &config{
+		Depth:       1,
+		FrontMatter: "Front Matter",
+		Metadata:    crazypdf.MetadataPreserve,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
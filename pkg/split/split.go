@@ -0,0 +1,228 @@
+// Package split cuts documents into smaller ones, such as one file per
+// chapter following the outline (bookmarks).
+package split
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// ErrNoOutline is returned when a document has no bookmarks pointing to
+// its pages to split it by.
+var ErrNoOutline = errors.New("split: document has no outline to split by")
+
+// maxNameLength is the maximum number of characters of a bookmark title
+// used in a file name.
+const maxNameLength = 80
+
+// Section is a run of pages that starts at a bookmark.
+type Section struct {
+	// Title is the bookmark title and Level its outline depth, 0 for
+	// top-level bookmarks.
+	Title string `json:"title"`
+	Level int    `json:"level"`
+
+	// FirstPage and LastPage are the 1-based page range of the section,
+	// inclusive.
+	FirstPage int `json:"first_page"`
+	LastPage  int `json:"last_page"`
+
+	// Path is the file the section was written to by BySections.
+	Path string `json:"path,omitempty"`
+
+	// Output describes the written file.
+	Output *crazypdf.WriteResult `json:"output,omitempty"`
+}
+
+// Sections returns the sections the document's outline divides it into,
+// without writing anything. Each bookmark down to the configured depth
+// starts a section, which runs until the next one; bookmarks that do not
+// point to a page, or that point to the page where the previous section
+// starts, are skipped. Pages before the first bookmark form a front
+// matter section.
+func Sections(doc *crazypdf.Document, opts ...Option) ([]Section, error) {
+	return sections(doc, applyOptions(opts))
+}
+
+// sections computes the sections for cfg.
+func sections(doc *crazypdf.Document, cfg *config) ([]Section, error) {
+	if cfg.Depth < 1 {
+		return nil, fmt.Errorf("split: depth %d is less than 1", cfg.Depth)
+	}
+	outline, err := doc.Outline()
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []Section
+	var walk func(items []*crazypdf.OutlineItem)
+	walk = func(items []*crazypdf.OutlineItem) {
+		for _, item := range items {
+			if item.Level >= cfg.Depth {
+				continue
+			}
+			if item.Page > 0 {
+				starts = append(starts, Section{Title: item.Title, Level: item.Level, FirstPage: item.Page})
+			}
+			walk(item.Children)
+		}
+	}
+	walk(outline)
+	if len(starts) == 0 {
+		return nil, ErrNoOutline
+	}
+
+	// Outlines are normally in page order; keep the first bookmark of
+	// each start page, which is the outermost one
+	sort.SliceStable(starts, func(i, j int) bool { return starts[i].FirstPage < starts[j].FirstPage })
+	var out []Section
+	if starts[0].FirstPage > 1 && cfg.FrontMatter != "" {
+		out = append(out, Section{Title: cfg.FrontMatter, FirstPage: 1})
+	}
+	for _, s := range starts {
+		if len(out) > 0 && out[len(out)-1].FirstPage == s.FirstPage {
+			continue
+		}
+		out = append(out, s)
+	}
+	for i := range out {
+		if i+1 < len(out) {
+			out[i].LastPage = out[i+1].FirstPage - 1
+		} else {
+			out[i].LastPage = doc.NumPages()
+		}
+	}
+	return out, nil
+}
+
+// BySections writes each section of the document to its own PDF in
+// outDir, which is created if needed. Files are named after the bookmark
+// titles, prefixed with the section number to keep them in order and
+// distinct, such as "01 Introduction.pdf". The outline, page labels and
+// structure tree of the document are not carried over, and links to
+// pages of other sections are removed.
+func BySections(doc *crazypdf.Document, outDir string, opts ...Option) ([]Section, error) {
+	cfg := applyOptions(opts)
+	secs, err := sections(doc, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	width := max(2, len(strconv.Itoa(len(secs))))
+	for i := range secs {
+		s := &secs[i]
+		s.Path = filepath.Join(outDir, fmt.Sprintf("%0*d %s.pdf", width, i+1, fileName(s.Title)))
+		pages := make([]int, 0, s.LastPage-s.FirstPage+1)
+		for p := s.FirstPage; p <= s.LastPage; p++ {
+			pages = append(pages, p)
+		}
+		s.Output, err = writePages(doc, pages, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+			return crazypdf.WriteFile(s.Path, fn, cfg.WriteOptions...)
+		})
+		if err != nil {
+			return secs[:i], fmt.Errorf("section %q: %w", s.Title, err)
+		}
+	}
+	return secs, nil
+}
+
+// Pages writes a document made of the given 1-based pages, in the order
+// given, to w. Pages may repeat.
+func Pages(doc *crazypdf.Document, pages []int, w io.Writer, opts ...Option) (*crazypdf.WriteResult, error) {
+	cfg := applyOptions(opts)
+	return writePages(doc, pages, cfg, func(fn func(io.Writer) error) (*crazypdf.WriteResult, error) {
+		return crazypdf.Write(w, fn, cfg.WriteOptions...)
+	})
+}
+
+// writePages builds the document of the given pages and hands the
+// serialized document to write.
+func writePages(doc *crazypdf.Document, pages []int, cfg *config, write func(func(io.Writer) error) (*crazypdf.WriteResult, error)) (*crazypdf.WriteResult, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	if len(pages) == 0 {
+		return nil, errors.New("split: no pages to write")
+	}
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PDF: %w", err)
+	}
+
+	indexes := make([]int, len(pages))
+	for i, p := range pages {
+		if p < 1 || p > doc.NumPages() {
+			return nil, fmt.Errorf("%w: page %d, document has %d pages", crazypdf.ErrPageOutOfRange, p, doc.NumPages())
+		}
+		indexes[i] = p - 1
+	}
+	if err := editor.KeepPages(indexes); err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+
+	crazypdf.ApplyMetadata(editor, cfg.Metadata)
+	writeCfg := crazypdf.NewWriteConfig(cfg.WriteOptions...)
+	editor.UniqueID = writeCfg.UniqueID
+	if err := crazypdf.ApplyInitialView(editor, writeCfg.InitialView); err != nil {
+		return nil, fmt.Errorf("failed to set initial view: %w", err)
+	}
+	output, err := write(func(w io.Writer) error {
+		_, err := editor.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return output, nil
+}
+
+// fileName makes a bookmark title safe to use as a file name on common
+// file systems: path separators, reserved and control characters become
+// underscores, runs of spaces are collapsed and the result is shortened
+// to maxNameLength characters.
+func fileName(title string) string {
+	var b strings.Builder
+	space := false
+	n := 0
+	for _, r := range title {
+		if n >= maxNameLength {
+			break
+		}
+		switch {
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		case unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r):
+			r = '_'
+		}
+		if space {
+			b.WriteByte(' ')
+			n++
+			space = false
+		}
+		b.WriteRune(r)
+		n++
+	}
+	name := strings.TrimRight(b.String(), ". ")
+	if name == "" {
+		return "Section"
+	}
+	return name
+}
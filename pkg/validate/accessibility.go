@@ -0,0 +1,274 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Check is the outcome of a single validation rule.
+type Check struct {
+	// ID is a stable identifier of the rule, such as "language".
+	ID string `json:"id"`
+
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+
+	// Pages lists the 1-based pages the failure applies to, when it is
+	// specific to some pages.
+	Pages []int `json:"pages,omitempty"`
+}
+
+// AccessibilityReport is the result of Accessibility.
+type AccessibilityReport struct {
+	Checks []Check `json:"checks"`
+}
+
+// Passed reports whether every check passed.
+func (r *AccessibilityReport) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the checks that did not pass.
+func (r *AccessibilityReport) Failures() []Check {
+	var out []Check
+	for _, c := range r.Checks {
+		if !c.Passed {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Accessibility audits a document for the basic requirements of
+// accessible PDFs, loosely following PDF/UA: a title that viewers
+// display, a document language, a tagged logical structure, alternate
+// text on figures and a tab order that follows the structure. It is a
+// lightweight screen, not a conformance validator.
+func Accessibility(doc *crazypdf.Document) (*AccessibilityReport, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	catalog, ok := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing document catalog", crazypdf.ErrInvalidPDF)
+	}
+	refs, err := file.PageRefs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page tree: %w", err)
+	}
+
+	a := &accessibilityAudit{file: file, catalog: catalog, pageRefs: refs}
+	return &AccessibilityReport{Checks: []Check{
+		a.checkTitle(),
+		a.checkDisplayTitle(),
+		a.checkLanguage(),
+		a.checkTagged(),
+		a.checkFigures(),
+		a.checkTabOrder(),
+	}}, nil
+}
+
+// accessibilityAudit holds the objects shared by the accessibility checks.
+type accessibilityAudit struct {
+	file     *internalpdf.File
+	catalog  internalpdf.Dict
+	pageRefs []internalpdf.Ref
+}
+
+func (a *accessibilityAudit) checkTitle() Check {
+	c := Check{ID: "title"}
+	info, _ := a.file.Resolve(a.file.Trailer()["Info"]).(internalpdf.Dict)
+	title, _ := a.file.Resolve(info["Title"]).(internalpdf.String)
+	switch {
+	case strings.TrimSpace(string(title)) != "":
+		c.Passed, c.Message = true, "document has a title"
+	case a.xmpHasTitle():
+		c.Passed, c.Message = true, "document has a title in its XMP metadata"
+	default:
+		c.Message = "document has no title in its metadata"
+	}
+	return c
+}
+
+// xmpHasTitle reports whether the XMP metadata stream sets dc:title.
+func (a *accessibilityAudit) xmpHasTitle() bool {
+	stream, ok := a.file.Resolve(a.catalog["Metadata"]).(*internalpdf.Stream)
+	if !ok {
+		return false
+	}
+	data, err := a.file.DecodeStream(stream)
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(data, []byte("<dc:title"))
+}
+
+func (a *accessibilityAudit) checkDisplayTitle() Check {
+	c := Check{ID: "display-doc-title"}
+	prefs, _ := a.file.Resolve(a.catalog["ViewerPreferences"]).(internalpdf.Dict)
+	if display, _ := a.file.Resolve(prefs["DisplayDocTitle"]).(bool); display {
+		c.Passed, c.Message = true, "viewers show the document title instead of the file name"
+	} else {
+		c.Message = "ViewerPreferences /DisplayDocTitle is not true; viewers show the file name"
+	}
+	return c
+}
+
+func (a *accessibilityAudit) checkLanguage() Check {
+	c := Check{ID: "language"}
+	lang, _ := a.file.Resolve(a.catalog["Lang"]).(internalpdf.String)
+	if strings.TrimSpace(string(lang)) != "" {
+		c.Passed, c.Message = true, fmt.Sprintf("document language is %q", string(lang))
+	} else {
+		c.Message = "document language (/Lang) is not set"
+	}
+	return c
+}
+
+func (a *accessibilityAudit) checkTagged() Check {
+	c := Check{ID: "tagged"}
+	markInfo, _ := a.file.Resolve(a.catalog["MarkInfo"]).(internalpdf.Dict)
+	marked, _ := a.file.Resolve(markInfo["Marked"]).(bool)
+	_, hasTree := a.file.Resolve(a.catalog["StructTreeRoot"]).(internalpdf.Dict)
+	switch {
+	case marked && hasTree:
+		c.Passed, c.Message = true, "document is tagged"
+	case hasTree:
+		c.Message = "document has a structure tree but /MarkInfo /Marked is not true"
+	default:
+		c.Message = "document is not tagged (no structure tree)"
+	}
+	return c
+}
+
+// checkFigures walks the structure tree and verifies that every Figure
+// element has alternate text (/Alt or /ActualText).
+func (a *accessibilityAudit) checkFigures() Check {
+	c := Check{ID: "figure-alt"}
+	root, ok := a.file.Resolve(a.catalog["StructTreeRoot"]).(internalpdf.Dict)
+	if !ok {
+		c.Passed, c.Message = true, "no structure tree; figures cannot be checked"
+		return c
+	}
+	roleMap, _ := a.file.Resolve(root["RoleMap"]).(internalpdf.Dict)
+
+	pageNumbers := make(map[int]int, len(a.pageRefs))
+	for i, ref := range a.pageRefs {
+		pageNumbers[ref.Num] = i + 1
+	}
+
+	figures, missing := 0, 0
+	pages := make(map[int]bool)
+	visited := make(map[int]bool)
+	var walk func(o internalpdf.Object, page, depth int)
+	walk = func(o internalpdf.Object, page, depth int) {
+		if depth > 256 {
+			return
+		}
+		if ref, ok := o.(internalpdf.Ref); ok {
+			if visited[ref.Num] {
+				return
+			}
+			visited[ref.Num] = true
+		}
+		switch v := a.file.Resolve(o).(type) {
+		case internalpdf.Array:
+			for _, kid := range v {
+				walk(kid, page, depth+1)
+			}
+		case internalpdf.Dict:
+			if pg, ok := v["Pg"].(internalpdf.Ref); ok {
+				page = pageNumbers[pg.Num]
+			}
+			if structureRole(v, roleMap, a.file.Resolve) == "Figure" {
+				figures++
+				alt, _ := a.file.Resolve(v["Alt"]).(internalpdf.String)
+				actual, _ := a.file.Resolve(v["ActualText"]).(internalpdf.String)
+				if strings.TrimSpace(string(alt)) == "" && strings.TrimSpace(string(actual)) == "" {
+					missing++
+					if page > 0 {
+						pages[page] = true
+					}
+				}
+			}
+			walk(v["K"], page, depth+1)
+		}
+	}
+	walk(root["K"], 0, 0)
+
+	switch {
+	case figures == 0:
+		c.Passed, c.Message = true, "no tagged figures"
+	case missing == 0:
+		c.Passed, c.Message = true, fmt.Sprintf("all %d figures have alternate text", figures)
+	default:
+		c.Message = fmt.Sprintf("%d of %d figures have no alternate text", missing, figures)
+		c.Pages = sortedPages(pages)
+	}
+	return c
+}
+
+// structureRole returns the standard structure type of an element,
+// following the role map for custom types.
+func structureRole(elem, roleMap internalpdf.Dict, resolve func(internalpdf.Object) internalpdf.Object) string {
+	role, _ := resolve(elem["S"]).(internalpdf.Name)
+	for i := 0; i < 8; i++ {
+		mapped, ok := resolve(roleMap[role]).(internalpdf.Name)
+		if !ok || mapped == role {
+			break
+		}
+		role = mapped
+	}
+	return string(role)
+}
+
+// checkTabOrder verifies that pages with annotations navigate them in
+// structure order (/Tabs /S).
+func (a *accessibilityAudit) checkTabOrder() Check {
+	c := Check{ID: "tab-order"}
+	pages := make(map[int]bool)
+	withAnnots := 0
+	for i, ref := range a.pageRefs {
+		page, ok := a.file.Resolve(ref).(internalpdf.Dict)
+		if !ok {
+			continue
+		}
+		if annots, ok := a.file.Resolve(page["Annots"]).(internalpdf.Array); !ok || len(annots) == 0 {
+			continue
+		}
+		withAnnots++
+		if tabs, _ := a.file.Resolve(page["Tabs"]).(internalpdf.Name); tabs != "S" {
+			pages[i+1] = true
+		}
+	}
+
+	switch {
+	case withAnnots == 0:
+		c.Passed, c.Message = true, "no pages with annotations"
+	case len(pages) == 0:
+		c.Passed, c.Message = true, "all pages with annotations use structure tab order"
+	default:
+		c.Message = fmt.Sprintf("%d of %d pages with annotations do not set /Tabs /S", len(pages), withAnnots)
+		c.Pages = sortedPages(pages)
+	}
+	return c
+}
+
+// sortedPages returns the keys of a page set in ascending order.
+func sortedPages(set map[int]bool) []int {
+	out := make([]int, 0, len(set))
+	for p := range set {
+		out = append(out, p)
+	}
+	sort.Ints(out)
+	return out
+}
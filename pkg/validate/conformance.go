@@ -0,0 +1,306 @@
+package validate
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+	"github.com/ayushanand18/crazypdf/pkg/metadata"
+)
+
+// OutputIntent describes the intended output device of a document, which
+// PDF/A and PDF/X files declare with an ICC profile.
+type OutputIntent struct {
+	// Subtype is the standard the intent is for, such as "GTS_PDFA1" for
+	// PDF/A or "GTS_PDFX" for PDF/X.
+	Subtype string `json:"subtype"`
+
+	// Condition is the output condition identifier, such as "sRGB" or
+	// "FOGRA39".
+	Condition string `json:"condition,omitempty"`
+
+	// Profile reports whether a destination ICC profile is embedded, and
+	// Components is its number of color components: 1, 3 or 4.
+	Profile    bool `json:"profile"`
+	Components int  `json:"components,omitempty"`
+}
+
+// ConformanceReport is the result of Conformance.
+type ConformanceReport struct {
+	// PDFA is the claimed PDF/A conformance, the part and level as in
+	// "1B", "2U" or "3A", and PDFUA the claimed PDF/UA part, such as "1".
+	// They are empty when the document makes no claim.
+	PDFA  string `json:"pdfa,omitempty"`
+	PDFUA string `json:"pdfua,omitempty"`
+
+	OutputIntents []OutputIntent `json:"output_intents,omitempty"`
+
+	// Checks are the structural checks for the claimed standards. A
+	// document that claims none has no checks.
+	Checks []Check `json:"checks,omitempty"`
+}
+
+// Passed reports whether every check passed.
+func (r *ConformanceReport) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the checks that did not pass.
+func (r *ConformanceReport) Failures() []Check {
+	var out []Check
+	for _, c := range r.Checks {
+		if !c.Passed {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Conformance reports the PDF/A and PDF/UA conformance a document claims
+// in its XMP identification schemas, and its output intents. For each
+// claim it runs a subset of the standard's requirements that can be
+// checked from the file structure, to flag documents whose claim is
+// obviously false: for PDF/A an output intent, no encryption, embedded
+// fonts, no JavaScript or launch actions, the allowed PDF version and
+// embedded files, and for levels A and U fonts mapped to Unicode and for
+// level A a tagged structure; for PDF/UA the Accessibility checks. It is
+// not a conformance validator: passing documents may still violate
+// requirements on content that are not checked.
+func Conformance(doc *crazypdf.Document) (*ConformanceReport, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	md, err := metadata.Read(doc)
+	if err != nil {
+		return nil, err
+	}
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	catalog, ok := file.Resolve(file.Trailer()["Root"]).(internalpdf.Dict)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing document catalog", crazypdf.ErrInvalidPDF)
+	}
+
+	report := &ConformanceReport{
+		PDFA:          firstValue(md.XMP, "pdfaid:part") + strings.ToUpper(firstValue(md.XMP, "pdfaid:conformance")),
+		PDFUA:         firstValue(md.XMP, "pdfuaid:part"),
+		OutputIntents: outputIntents(file, catalog),
+	}
+	if report.PDFA != "" {
+		checks, err := pdfaChecks(doc, file, report)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, checks...)
+	}
+	if report.PDFUA != "" {
+		access, err := Accessibility(doc)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, access.Checks...)
+	}
+	return report, nil
+}
+
+// firstValue returns the first value of an XMP property, trimmed.
+func firstValue(xmp map[string][]string, key string) string {
+	if values := xmp[key]; len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// outputIntents returns the output intents of the catalog.
+func outputIntents(file *internalpdf.File, catalog internalpdf.Dict) []OutputIntent {
+	intents, _ := file.Resolve(catalog["OutputIntents"]).(internalpdf.Array)
+	var out []OutputIntent
+	for _, o := range intents {
+		intent, ok := file.Resolve(o).(internalpdf.Dict)
+		if !ok {
+			continue
+		}
+		subtype, _ := file.Resolve(intent["S"]).(internalpdf.Name)
+		condition, _ := file.Resolve(intent["OutputConditionIdentifier"]).(internalpdf.String)
+		oi := OutputIntent{Subtype: string(subtype), Condition: string(condition)}
+		if profile, ok := file.Resolve(intent["DestOutputProfile"]).(*internalpdf.Stream); ok {
+			n, _ := file.Resolve(profile.Dict["N"]).(int64)
+			oi.Profile, oi.Components = true, int(n)
+		}
+		out = append(out, oi)
+	}
+	return out
+}
+
+// pdfaChecks runs the PDF/A checks for the conformance claimed in report.
+func pdfaChecks(doc *crazypdf.Document, file *internalpdf.File, report *ConformanceReport) ([]Check, error) {
+	level := strings.TrimLeft(report.PDFA, "0123456789")
+	part, _ := strconv.Atoi(strings.TrimSuffix(report.PDFA, level))
+
+	checks := []Check{checkPDFAOutputIntent(report.OutputIntents), checkPDFAVersion(doc, part)}
+
+	c := Check{ID: "pdfa-encryption"}
+	enc, err := doc.Encryption()
+	if err != nil {
+		return nil, err
+	}
+	if enc.Encrypted {
+		c.Message = "PDF/A documents must not be encrypted"
+	} else {
+		c.Passed, c.Message = true, "document is not encrypted"
+	}
+	checks = append(checks, c)
+
+	fonts, err := file.Fonts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fonts: %w", err)
+	}
+	checks = append(checks, checkPDFAFonts(fonts))
+
+	caps, err := doc.Capabilities()
+	if err != nil {
+		return nil, err
+	}
+	if level == "A" || level == "U" {
+		c := Check{ID: "pdfa-unicode"}
+		if caps.FontsDecodable {
+			c.Passed, c.Message = true, "all fonts map their characters to Unicode"
+		} else {
+			c.Message = fmt.Sprintf("fonts without a Unicode mapping: %s", strings.Join(caps.UndecodableFonts, ", "))
+		}
+		checks = append(checks, c)
+	}
+	if level == "A" {
+		c := Check{ID: "pdfa-tagged"}
+		if caps.StructureTree {
+			c.Passed, c.Message = true, "document is tagged"
+		} else {
+			c.Message = "level A requires a tagged structure tree"
+		}
+		checks = append(checks, c)
+	}
+
+	checks = append(checks, checkPDFAActions(file))
+	attachments, err := doc.Attachments()
+	if err != nil {
+		return nil, err
+	}
+	return append(checks, checkPDFAAttachments(attachments, part)), nil
+}
+
+func checkPDFAOutputIntent(intents []OutputIntent) Check {
+	c := Check{ID: "pdfa-output-intent"}
+	for _, oi := range intents {
+		if oi.Subtype == "GTS_PDFA1" && oi.Profile {
+			c.Passed, c.Message = true, fmt.Sprintf("PDF/A output intent with an embedded ICC profile (%s)", oi.Condition)
+			return c
+		}
+	}
+	c.Message = "no GTS_PDFA1 output intent with an embedded ICC profile"
+	return c
+}
+
+// checkPDFAVersion checks the PDF version allowed by a PDF/A part: PDF 1.4
+// for part 1, up to PDF 1.7 for parts 2 and 3 and PDF 2.0 for part 4.
+func checkPDFAVersion(doc *crazypdf.Document, part int) Check {
+	c := Check{ID: "pdfa-version"}
+	version, err := doc.Version()
+	if err != nil {
+		c.Message = err.Error()
+		return c
+	}
+	limit := "1.7"
+	switch {
+	case part == 1:
+		limit = "1.4"
+	case part >= 4:
+		limit = "2.0"
+	}
+	if version <= limit {
+		c.Passed, c.Message = true, fmt.Sprintf("PDF %s is allowed in PDF/A-%d", version, part)
+	} else {
+		c.Message = fmt.Sprintf("PDF %s is later than PDF %s, the latest allowed in PDF/A-%d", version, limit, part)
+	}
+	return c
+}
+
+// checkPDFAFonts checks that every font is embedded. Type 3 fonts are
+// defined in the file and always count as embedded.
+func checkPDFAFonts(fonts []internalpdf.FontInfo) Check {
+	c := Check{ID: "pdfa-fonts-embedded"}
+	var missing []string
+	pages := make(map[int]bool)
+	for _, f := range fonts {
+		if f.Embedded || f.Subtype == "Type3" {
+			continue
+		}
+		missing = append(missing, f.BaseFont)
+		for _, p := range f.Pages {
+			pages[p] = true
+		}
+	}
+	if len(missing) == 0 {
+		c.Passed, c.Message = true, fmt.Sprintf("all %d fonts are embedded", len(fonts))
+	} else {
+		c.Message = fmt.Sprintf("fonts not embedded: %s", strings.Join(missing, ", "))
+		c.Pages = sortedPages(pages)
+	}
+	return c
+}
+
+// checkPDFAActions checks for the JavaScript and Launch actions PDF/A
+// forbids. The actions are found by stripping them from a throwaway copy.
+func checkPDFAActions(file *internalpdf.File) Check {
+	c := Check{ID: "pdfa-actions"}
+	editor, err := internalpdf.NewEditor(file)
+	if err != nil {
+		c.Message = err.Error()
+		return c
+	}
+	counts := make(map[internalpdf.Name]int)
+	for _, s := range editor.StripActions("JavaScript", "Launch") {
+		counts[s.Type]++
+	}
+	if len(counts) == 0 {
+		c.Passed, c.Message = true, "no JavaScript or launch actions"
+	} else {
+		c.Message = fmt.Sprintf("%d JavaScript and %d launch actions are not allowed", counts["JavaScript"], counts["Launch"])
+	}
+	return c
+}
+
+// checkPDFAAttachments checks embedded files against a PDF/A part: none
+// in part 1, only PDF files in part 2, and files with their relationship
+// to the document in part 3.
+func checkPDFAAttachments(attachments []*crazypdf.Attachment, part int) Check {
+	c := Check{ID: "pdfa-attachments"}
+	var bad []string
+	for _, a := range attachments {
+		switch {
+		case part == 1:
+		case part == 2 && (a.MIMEType == "application/pdf" || strings.EqualFold(path.Ext(a.FileName), ".pdf")):
+			continue
+		case part >= 3 && a.Relationship != "":
+			continue
+		}
+		bad = append(bad, a.FileName)
+	}
+	switch {
+	case len(attachments) == 0:
+		c.Passed, c.Message = true, "no embedded files"
+	case len(bad) == 0:
+		c.Passed, c.Message = true, fmt.Sprintf("%d embedded files are allowed in PDF/A-%d", len(attachments), part)
+	case part == 1:
+		c.Message = "PDF/A-1 does not allow embedded files"
+	case part == 2:
+		c.Message = fmt.Sprintf("PDF/A-2 allows only embedded PDF files: %s", strings.Join(bad, ", "))
+	default:
+		c.Message = fmt.Sprintf("embedded files without /AFRelationship: %s", strings.Join(bad, ", "))
+	}
+	return c
+}
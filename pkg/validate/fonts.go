@@ -0,0 +1,377 @@
+// Package validate checks PDF documents for production and compliance
+// problems, such as fonts that are not embedded and would be substituted
+// when the file is printed or viewed elsewhere.
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	internalpdf "github.com/ayushanand18/crazypdf/internal/pdf"
+	"github.com/ayushanand18/crazypdf/pkg/crazypdf"
+)
+
+// Font describes a font used by a document and how it would be rendered.
+type Font struct {
+	// Name is the font name without any subset prefix.
+	Name string `json:"name"`
+
+	// BaseFont is the PostScript name as written in the file.
+	BaseFont string `json:"base_font"`
+
+	// Type is the font type: Type1, TrueType, Type0, Type3, ...
+	Type string `json:"type"`
+
+	// Encoding is the font encoding name, if any.
+	Encoding string `json:"encoding,omitempty"`
+
+	// Embedded reports whether the font program is included in the file.
+	Embedded bool `json:"embedded"`
+
+	// Subset reports whether only the used glyphs are embedded.
+	Subset bool `json:"subset"`
+
+	// Pages lists the 1-based pages that use the font.
+	Pages []int `json:"pages"`
+
+	// Substitute is the installed font a renderer would most likely use in
+	// place of a non-embedded font, such as "Liberation Sans Bold". It is
+	// empty for embedded fonts.
+	Substitute string `json:"substitute,omitempty"`
+
+	// SubstituteFile is the path of the substitute font on this system, or
+	// empty when no matching font is installed.
+	SubstituteFile string `json:"substitute_file,omitempty"`
+
+	// MissingGlyphs lists characters shown with a non-embedded font that
+	// the substitute cannot render.
+	MissingGlyphs []rune `json:"missing_glyphs,omitempty"`
+}
+
+// FontAudit is the result of FontReport.
+type FontAudit struct {
+	Fonts []Font `json:"fonts"`
+}
+
+// NonEmbedded returns the fonts whose programs are not in the file.
+func (a *FontAudit) NonEmbedded() []Font {
+	var out []Font
+	for _, f := range a.Fonts {
+		if !f.Embedded {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// OK reports whether every font is embedded, so the document renders the
+// same everywhere.
+func (a *FontAudit) OK() bool {
+	return len(a.NonEmbedded()) == 0
+}
+
+// FontReport lists the fonts of a document, and for each non-embedded
+// font the system font that would be substituted at render time together
+// with the characters that font cannot render.
+func FontReport(doc *crazypdf.Document, opts ...Option) (*FontAudit, error) {
+	if doc.IsClosed() {
+		return nil, crazypdf.ErrDocumentClosed
+	}
+	cfg := applyOptions(opts)
+
+	file, err := doc.RawFile()
+	if err != nil {
+		return nil, err
+	}
+	infos, err := file.Fonts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fonts: %w", err)
+	}
+
+	var used map[string]map[rune]bool
+	if cfg.CheckGlyphs {
+		used, err = usedCharacters(doc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dirs := cfg.FontDirs
+	if dirs == nil {
+		dirs = systemFontDirs()
+	}
+	installed := indexFontFiles(dirs)
+	coverages := make(map[string]coverage)
+
+	audit := &FontAudit{}
+	for _, info := range infos {
+		font := newFont(info)
+		if !font.Embedded {
+			font.Substitute, font.SubstituteFile = substitute(font.Name, info.Flags, installed)
+			if cfg.CheckGlyphs {
+				cov := builtinCoverage(font.Name, info.Flags)
+				if font.SubstituteFile != "" {
+					if c, ok := coverages[font.SubstituteFile]; ok {
+						cov = c
+					} else if c, err := loadCoverage(font.SubstituteFile); err == nil {
+						coverages[font.SubstituteFile] = c
+						cov = c
+					}
+				}
+				font.MissingGlyphs = missing(used[info.BaseFont], cov)
+			}
+		}
+		audit.Fonts = append(audit.Fonts, font)
+	}
+	return audit, nil
+}
+
+// newFont converts raw font information into a report entry.
+func newFont(info internalpdf.FontInfo) Font {
+	name := info.BaseFont
+	subset := false
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		name = name[i+1:]
+		subset = true
+	}
+	if name == "" {
+		name = "(" + info.Subtype + ")"
+	}
+	return Font{
+		Name:     name,
+		BaseFont: info.BaseFont,
+		Type:     info.Subtype,
+		Encoding: info.Encoding,
+		Embedded: info.Embedded,
+		Subset:   subset,
+		Pages:    info.Pages,
+	}
+}
+
+// usedCharacters collects the characters shown with each font, keyed by
+// BaseFont.
+func usedCharacters(doc *crazypdf.Document) (map[string]map[rune]bool, error) {
+	used := make(map[string]map[rune]bool)
+	for _, page := range doc.Pages() {
+		texts, err := page.StyledTexts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read text on page %d: %w", page.Number, err)
+		}
+		for _, t := range texts {
+			set := used[t.Font]
+			if set == nil {
+				set = make(map[rune]bool)
+				used[t.Font] = set
+			}
+			for _, r := range t.Text {
+				if !unicode.IsSpace(r) && !unicode.IsControl(r) {
+					set[r] = true
+				}
+			}
+		}
+	}
+	return used, nil
+}
+
+// missing returns the characters of used not in cov, sorted.
+func missing(used map[rune]bool, cov coverage) []rune {
+	if cov == nil {
+		return nil
+	}
+	var out []rune
+	for r := range used {
+		if !cov[r] {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// FontDescriptor flags used to pick a substitute.
+const (
+	flagFixedPitch = 1 << 0
+	flagSerif      = 1 << 1
+	flagSymbolic   = 1 << 2
+	flagItalic     = 1 << 6
+	flagForceBold  = 1 << 18
+)
+
+// fontFamily maps name keywords to the fonts renderers commonly use in
+// their place, in order of preference.
+type fontFamily struct {
+	keywords   []string
+	candidates []string
+}
+
+var (
+	sansFamily  = fontFamily{[]string{"helvetica", "arial"}, []string{"Arial", "Liberation Sans", "Helvetica", "Nimbus Sans", "DejaVu Sans"}}
+	serifFamily = fontFamily{[]string{"times"}, []string{"Times New Roman", "Liberation Serif", "Times", "Nimbus Roman", "DejaVu Serif"}}
+	monoFamily  = fontFamily{[]string{"courier"}, []string{"Courier New", "Liberation Mono", "Courier", "Nimbus Mono PS", "DejaVu Sans Mono"}}
+
+	fontFamilies = []fontFamily{
+		sansFamily,
+		serifFamily,
+		monoFamily,
+		{[]string{"symbol"}, []string{"Symbol", "Standard Symbols PS"}},
+		{[]string{"zapfdingbats", "dingbats"}, []string{"ZapfDingbats", "D050000L"}},
+	}
+)
+
+// substitute picks the font a renderer would likely use for a missing
+// font: the font itself when installed, otherwise the closest standard
+// family by name or descriptor flags, in the same weight and style.
+func substitute(name string, flags int, installed map[string]string) (string, string) {
+	lower := strings.ToLower(name)
+	bold := flags&flagForceBold != 0 || containsAny(lower, "bold", "black", "heavy", "semibold")
+	italic := flags&flagItalic != 0 || containsAny(lower, "italic", "oblique")
+
+	family := sansFamily
+	switch {
+	case flags&flagFixedPitch != 0:
+		family = monoFamily
+	case flags&flagSerif != 0:
+		family = serifFamily
+	}
+	for _, f := range fontFamilies {
+		if containsAny(strings.ReplaceAll(lower, " ", ""), f.keywords...) {
+			family = f
+			break
+		}
+	}
+
+	own := name
+	if i := strings.IndexAny(own, ",-"); i > 0 {
+		own = own[:i]
+	}
+	candidates := append([]string{own}, family.candidates...)
+	for _, c := range candidates {
+		if path := lookupFontFile(installed, c, bold, italic); path != "" {
+			return styledName(c, bold, italic), path
+		}
+	}
+	return styledName(family.candidates[0], bold, italic), ""
+}
+
+// styledName appends the weight and style to a family name.
+func styledName(family string, bold, italic bool) string {
+	switch {
+	case bold && italic:
+		return family + " Bold Italic"
+	case bold:
+		return family + " Bold"
+	case italic:
+		return family + " Italic"
+	}
+	return family
+}
+
+// lookupFontFile finds an installed font file for a family and style by
+// its file name, accepting common naming schemes such as
+// "LiberationSans-BoldItalic.ttf" and "arialbi.ttf".
+func lookupFontFile(installed map[string]string, family string, bold, italic bool) string {
+	var suffixes []string
+	switch {
+	case bold && italic:
+		suffixes = []string{"bolditalic", "boldoblique", "bi", "z"}
+	case bold:
+		suffixes = []string{"bold", "bd", "b"}
+	case italic:
+		suffixes = []string{"italic", "oblique", "it", "i"}
+	default:
+		suffixes = []string{"", "regular", "roman", "book", "r"}
+	}
+	base := normalizeFontName(family)
+	for _, s := range suffixes {
+		if path, ok := installed[base+s]; ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// indexFontFiles maps normalized file names of the fonts found in dirs to
+// their paths.
+func indexFontFiles(dirs []string) map[string]string {
+	index := make(map[string]string)
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if ext != ".ttf" && ext != ".otf" && ext != ".ttc" {
+				return nil
+			}
+			key := normalizeFontName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+			if _, exists := index[key]; !exists {
+				index[key] = path
+			}
+			return nil
+		})
+	}
+	return index
+}
+
+// normalizeFontName lowercases a name and drops everything but letters
+// and digits.
+func normalizeFontName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// systemFontDirs returns the conventional font directories of the host.
+func systemFontDirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		windir := os.Getenv("WINDIR")
+		if windir == "" {
+			windir = `C:\Windows`
+		}
+		return []string{filepath.Join(windir, "Fonts"), filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "Windows", "Fonts")}
+	case "darwin":
+		return []string{"/System/Library/Fonts", "/Library/Fonts", filepath.Join(home, "Library", "Fonts")}
+	default:
+		return []string{"/usr/share/fonts", "/usr/local/share/fonts", filepath.Join(home, ".fonts"), filepath.Join(home, ".local", "share", "fonts")}
+	}
+}
+
+// builtinCoverage is the character set assumed when no substitute font
+// file is available: the Windows-1252 repertoire of the standard Latin
+// fonts, or nothing (unknown) for symbolic fonts.
+func builtinCoverage(name string, flags int) coverage {
+	lower := strings.ToLower(name)
+	if flags&flagSymbolic != 0 && flags&flagSerif == 0 || containsAny(lower, "symbol", "dingbats") {
+		return nil
+	}
+	cov := make(coverage)
+	for r := rune(0x20); r <= 0xFF; r++ {
+		if r < 0x7F || r >= 0xA0 {
+			cov[r] = true
+		}
+	}
+	for _, r := range "€‚ƒ„…†‡ˆ‰Š‹ŒŽ‘’“”•–—˜™š›œžŸ" {
+		cov[r] = true
+	}
+	return cov
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
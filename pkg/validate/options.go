@@ -0,0 +1,42 @@
+package validate
+
+// config holds configuration for validation.
+type config struct {
+	FontDirs    []string // directories searched for substitute fonts; nil for system defaults
+	CheckGlyphs bool     // compare used characters against substitute fonts
+}
+
+// Option is a functional option for configuring validation.
+type Option func(*config)
+
+// WithFontDirs sets the directories searched for installed fonts when
+// choosing substitutes, replacing the system's default font directories.
+func WithFontDirs(dirs ...string) Option {
+	return func(c *config) {
+		c.FontDirs = dirs
+	}
+}
+
+// WithGlyphCheck enables or disables checking that substitute fonts can
+// render every character shown with a non-embedded font. Default is true.
+func WithGlyphCheck(enabled bool) Option {
+	return func(c *config) {
+		c.CheckGlyphs = enabled
+	}
+}
+
+// defaultConfig returns the default validation configuration.
+func defaultConfig() *config {
+	return &config{
+		CheckGlyphs: true,
+	}
+}
+
+// applyOptions creates a config from the given options.
+func applyOptions(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+)
+
+// errNoCmap indicates a font file has no usable Unicode character map.
+var errNoCmap = errors.New("no Unicode cmap table")
+
+// coverage is the set of characters a font file can render.
+type coverage map[rune]bool
+
+// loadCoverage reads the Unicode cmap of a TrueType or OpenType font file.
+// For collections (.ttc) the first font is used.
+func loadCoverage(path string) (coverage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseCoverage(data)
+}
+
+// parseCoverage parses the cmap table of an sfnt font.
+func parseCoverage(data []byte) (coverage, error) {
+	u16 := func(off int) int {
+		if off < 0 || off+2 > len(data) {
+			return 0
+		}
+		return int(binary.BigEndian.Uint16(data[off:]))
+	}
+	u32 := func(off int) int {
+		if off < 0 || off+4 > len(data) {
+			return 0
+		}
+		return int(binary.BigEndian.Uint32(data[off:]))
+	}
+
+	base := 0
+	if string(data[:min(4, len(data))]) == "ttcf" {
+		base = u32(12)
+	}
+
+	// Locate the cmap table in the table directory
+	numTables := u16(base + 4)
+	cmap := -1
+	for i := 0; i < numTables; i++ {
+		rec := base + 12 + 16*i
+		if rec+16 > len(data) {
+			break
+		}
+		if string(data[rec:rec+4]) == "cmap" {
+			cmap = u32(rec + 8)
+			break
+		}
+	}
+	if cmap < 0 {
+		return nil, errNoCmap
+	}
+
+	// Prefer a full-repertoire subtable (format 12), then BMP (format 4)
+	best, bestRank := -1, 0
+	numSubtables := u16(cmap + 2)
+	for i := 0; i < numSubtables; i++ {
+		rec := cmap + 4 + 8*i
+		platform, encoding := u16(rec), u16(rec+2)
+		off := cmap + u32(rec+4)
+		rank := 0
+		switch {
+		case platform == 3 && encoding == 10, platform == 0 && encoding >= 4:
+			rank = 3
+		case platform == 3 && encoding == 1, platform == 0:
+			rank = 2
+		case platform == 3 && encoding == 0:
+			rank = 1 // symbol fonts
+		}
+		if rank > bestRank {
+			best, bestRank = off, rank
+		}
+	}
+	if best < 0 {
+		return nil, errNoCmap
+	}
+
+	cov := make(coverage)
+	switch u16(best) {
+	case 4:
+		segX2 := u16(best + 6)
+		ends := best + 14
+		starts := ends + segX2 + 2
+		for i := 0; i < segX2/2; i++ {
+			start, end := u16(starts+2*i), u16(ends+2*i)
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				cov[rune(c)] = true
+			}
+		}
+	case 12:
+		groups := u32(best + 12)
+		for i := 0; i < groups; i++ {
+			g := best + 16 + 12*i
+			if g+12 > len(data) {
+				break
+			}
+			start, end := u32(g), u32(g+4)
+			if end-start > 0x10FFFF {
+				continue
+			}
+			for c := start; c <= end; c++ {
+				cov[rune(c)] = true
+			}
+		}
+	default:
+		return nil, errNoCmap
+	}
+
+	// Symbol fonts map their characters into the private use area at
+	// U+F000; expose those as the corresponding single-byte codes too.
+	if bestRank == 1 {
+		for c := range cov {
+			if c >= 0xF000 && c <= 0xF0FF {
+				cov[c-0xF000] = true
+			}
+		}
+	}
+	return cov, nil
+}